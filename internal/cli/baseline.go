@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/runstore"
+)
+
+// baselineLast selects the newest saved run of the same collection and
+// environment as the baseline.
+const baselineLast = "last"
+
+// latencyBudget is how much slower than the baseline a request may get
+// before `run --baseline` reports it. A zero budget disables latency gating.
+type latencyBudget struct {
+	pct float64
+	ms  int64
+}
+
+func (b latencyBudget) enabled() bool { return b.pct > 0 || b.ms > 0 }
+
+// exceeded reports whether cur is over budget. With both limits set the
+// growth must exceed both: a percent alone flags 2ms → 4ms, an absolute
+// limit alone ignores a slow endpoint doubling.
+func (b latencyBudget) exceeded(base, cur int64) bool {
+	delta := cur - base
+	if !b.enabled() || delta <= 0 {
+		return false
+	}
+	if b.ms > 0 && delta <= b.ms {
+		return false
+	}
+	if b.pct > 0 && base > 0 && float64(delta)*100/float64(base) <= b.pct {
+		return false
+	}
+	return true
+}
+
+// resolveBaseline loads the run that this run is compared against: the run
+// named by ref (an ID or "last"), or — when ref is empty but the collection
+// has max_ms_regression assertions — the newest matching run. It returns a
+// nil run when there is nothing to compare against.
+func resolveBaseline(ws *workspaceCtx, ref, collectionPath, envArg string) (*domain.RunResult, string, error) {
+	col, colErr := ws.collections.LoadCollection(collectionPath)
+	if ref == "" {
+		if colErr != nil || ws.standalone || !hasLatencyRegressionAssertions(col) {
+			return nil, "", nil
+		}
+		ref = baselineLast
+	}
+	if ws.standalone {
+		return nil, "", fmt.Errorf("--baseline needs a workspace (standalone runs are not saved)")
+	}
+	store := runstore.NewJSONStore(ws.root, ws.cfg)
+
+	id := ref
+	if ref == baselineLast {
+		if colErr != nil {
+			return nil, "", nil // Execute reports the load error.
+		}
+		env, err := ws.envs.LoadEnvironment(envArg)
+		if err != nil {
+			return nil, "", nil
+		}
+		id, err = store.LatestRun(col.Name, env.Name)
+		if domain.IsKind(err, domain.KindNotFound) {
+			// The first run of a pipeline has nothing to compare with.
+			fmt.Fprintf(os.Stderr, "Warning: no saved run of %q against %q to use as baseline; regression checks skipped\n", col.Name, env.Name)
+			return nil, "", nil
+		}
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	run, err := store.LoadRun(id)
+	if err != nil {
+		return nil, "", err
+	}
+	return &run, id, nil
+}
+
+func hasLatencyRegressionAssertions(col domain.Collection) bool {
+	for _, r := range col.Requests {
+		if r.Assert.MaxMSRegression != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// requestRegression lists why one request is worse than in the baseline.
+type requestRegression struct {
+	name    string
+	reasons []string
+}
+
+// findRegressions compares the requests present in both runs: a changed
+// status, an assertion that passed in the baseline and now fails, or latency
+// growth over budget. Requests new to run are not regressions.
+func findRegressions(baseline, run domain.RunResult, budget latencyBudget) []requestRegression {
+	before := make(map[string]domain.RequestResult, len(baseline.Results))
+	for _, r := range baseline.Results {
+		before[r.Name] = r
+	}
+
+	var out []requestRegression
+	for _, r := range run.Results {
+		b, ok := before[r.Name]
+		if !ok {
+			continue
+		}
+		d := compareRequests(b, r)
+		var reasons []string
+		if d.statusA != d.statusB {
+			reasons = append(reasons, fmt.Sprintf("status: %d → %d", d.statusA, d.statusB))
+		}
+		if d.errorA == "" && d.errorB != "" {
+			reasons = append(reasons, fmt.Sprintf("error: %s", d.errorB))
+		}
+		for _, a := range d.newlyFailing {
+			reasons = append(reasons, fmt.Sprintf("assertion %s now fails — %s", assertionID(a), truncateMessage(a.Message)))
+		}
+		if d.latencyComparable && budget.exceeded(d.latencyA, d.latencyB) {
+			reasons = append(reasons, fmt.Sprintf("latency: %dms → %dms (+%dms)", d.latencyA, d.latencyB, d.latencyDelta()))
+		}
+		if len(reasons) > 0 {
+			out = append(out, requestRegression{name: r.Name, reasons: reasons})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out
+}
+
+func printRegressions(w io.Writer, baselineID string, regs []requestRegression, c palette) {
+	if len(regs) == 0 {
+		fmt.Fprintf(w, "No regressions against baseline %s\n", baselineID)
+		return
+	}
+	fmt.Fprintf(w, "%sRegressions against baseline %s:%s\n", c.red, baselineID, c.reset)
+	for _, r := range regs {
+		fmt.Fprintf(w, "%s~ %s%s\n", c.yellow, r.name, c.reset)
+		for _, reason := range r.reasons {
+			fmt.Fprintf(w, "    %s\n", reason)
+		}
+	}
+}
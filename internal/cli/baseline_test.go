@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+func TestLatencyBudget_Exceeded(t *testing.T) {
+	cases := []struct {
+		name      string
+		budget    latencyBudget
+		base, cur int64
+		want      bool
+	}{
+		{"disabled", latencyBudget{}, 100, 900, false},
+		{"faster", latencyBudget{pct: 10}, 100, 90, false},
+		{"pct within", latencyBudget{pct: 10}, 100, 110, false},
+		{"pct over", latencyBudget{pct: 10}, 100, 111, true},
+		{"ms within", latencyBudget{ms: 50}, 100, 150, false},
+		{"ms over", latencyBudget{ms: 50}, 100, 151, true},
+		{"both: only pct over", latencyBudget{pct: 10, ms: 50}, 2, 4, false},
+		{"both: only ms over", latencyBudget{pct: 100, ms: 50}, 1000, 1100, false},
+		{"both over", latencyBudget{pct: 10, ms: 50}, 100, 200, true},
+		{"pct from zero", latencyBudget{pct: 10}, 0, 1, true},
+	}
+	for _, tc := range cases {
+		if got := tc.budget.exceeded(tc.base, tc.cur); got != tc.want {
+			t.Errorf("%s: exceeded(%d, %d) = %v, want %v", tc.name, tc.base, tc.cur, got, tc.want)
+		}
+	}
+}
+
+func TestCompareRequests_KeysAssertionsByKey(t *testing.T) {
+	a := domain.RequestResult{Assertions: []domain.AssertionResult{
+		{Name: "jsonpath.eq", Key: "$.id", Passed: true},
+		{Name: "jsonpath.eq", Key: "$.name", Passed: false, Message: "was broken"},
+	}}
+	b := domain.RequestResult{Assertions: []domain.AssertionResult{
+		{Name: "jsonpath.eq", Key: "$.id", Passed: false, Message: "expected 1"},
+		{Name: "jsonpath.eq", Key: "$.name", Passed: true},
+		{Name: "header.eq", Key: "X-New", Passed: false, Message: "missing"},
+	}}
+
+	d := compareRequests(a, b)
+	if len(d.regressed) != 2 || assertionID(d.regressed[0]) != "jsonpath.eq[$.id]" || assertionID(d.regressed[1]) != "header.eq[X-New]" {
+		t.Errorf("regressed: got %+v", d.regressed)
+	}
+	if len(d.newlyFailing) != 1 || assertionID(d.newlyFailing[0]) != "jsonpath.eq[$.id]" {
+		t.Errorf("only assertions that passed before are newly failing, got %+v", d.newlyFailing)
+	}
+	if len(d.recovered) != 1 || assertionID(d.recovered[0]) != "jsonpath.eq[$.name]" {
+		t.Errorf("recovered: got %+v", d.recovered)
+	}
+}
+
+func TestFindRegressions(t *testing.T) {
+	baseline := domain.RunResult{Results: []domain.RequestResult{
+		{Name: "list", StatusCode: 200, LatencyMS: 100},
+		{Name: "get", StatusCode: 200, LatencyMS: 100, Assertions: []domain.AssertionResult{{Name: "status", Passed: true}}},
+		{Name: "slow", StatusCode: 200, LatencyMS: 100},
+		{Name: "same", StatusCode: 200, LatencyMS: 100},
+	}}
+	run := domain.RunResult{Results: []domain.RequestResult{
+		{Name: "list", StatusCode: 500, LatencyMS: 100},
+		{Name: "get", StatusCode: 200, LatencyMS: 100, Assertions: []domain.AssertionResult{{Name: "status", Passed: false, Message: "expected 201, got 200"}}},
+		{Name: "slow", StatusCode: 200, LatencyMS: 300},
+		{Name: "same", StatusCode: 200, LatencyMS: 105},
+		{Name: "added", StatusCode: 500},
+	}}
+
+	regs := findRegressions(baseline, run, latencyBudget{pct: 20})
+	got := map[string]string{}
+	for _, r := range regs {
+		got[r.name] = strings.Join(r.reasons, "; ")
+	}
+	want := map[string]string{
+		"list": "status: 200 → 500",
+		"get":  "assertion status now fails — expected 201, got 200",
+		"slow": "latency: 100ms → 300ms (+200ms)",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d regressions, got %v", len(want), got)
+	}
+	for name, reason := range want {
+		if got[name] != reason {
+			t.Errorf("%s: got %q, want %q", name, got[name], reason)
+		}
+	}
+
+	if regs := findRegressions(baseline, run, latencyBudget{}); len(regs) != 2 {
+		t.Errorf("latency should not be gated without a budget, got %+v", regs)
+	}
+}
+
+func TestPrintRegressions(t *testing.T) {
+	var buf bytes.Buffer
+	printRegressions(&buf, "run-1", []requestRegression{{name: "list", reasons: []string{"status: 200 → 500"}}}, palette{})
+	want := "Regressions against baseline run-1:\n~ list\n    status: 200 → 500\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	printRegressions(&buf, "run-1", nil, palette{})
+	if !strings.Contains(buf.String(), "No regressions") {
+		t.Errorf("expected a clean report, got %q", buf.String())
+	}
+}
+
+func TestRunCmd_BaselineGatesStatusRegression(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer srv.Close()
+
+	root := t.TempDir()
+	files := map[string]string{
+		"lynix.yaml":           "",
+		"env/dev.yaml":         "vars:\n  base_url: \"" + srv.URL + "\"\n",
+		"collections/api.yaml": "name: api\nrequests:\n  - name: health\n    method: GET\n    url: \"{{base_url}}/health\"\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run := func() error {
+		cmd := runCmd()
+		cmd.SetArgs([]string{"-w", root, "-c", "api", "-e", "dev", "--baseline", "last", "--format", "json"})
+		return cmd.Execute()
+	}
+
+	// No saved run yet: nothing to compare with.
+	if err := run(); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if err := run(); err != nil {
+		t.Fatalf("unchanged run: %v", err)
+	}
+
+	status.Store(http.StatusServiceUnavailable)
+	err := run()
+	var coded *codedError
+	if !errors.As(err, &coded) || coded.code != exitAssertFailed || !strings.Contains(err.Error(), "regressed") {
+		t.Fatalf("expected a regression failure with exit code %d, got %v", exitAssertFailed, err)
+	}
+}
@@ -5,13 +5,20 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/spf13/cobra"
+
 	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/curlparse"
 )
 
 // --- looksLikePath ---
@@ -410,7 +417,7 @@ func TestRootCmd_RegistersSubcommands(t *testing.T) {
 	for _, sub := range cmd.Commands() {
 		names[sub.Use] = true
 	}
-	for _, expected := range []string{"run", "validate", "version", "init", "collections", "envs", "import"} {
+	for _, expected := range []string{"run", "validate", "version", "init", "collections", "envs", "import", "export"} {
 		if !names[expected] {
 			t.Errorf("expected subcommand %q to be registered", expected)
 		}
@@ -422,19 +429,77 @@ func TestRunCmd_Flags(t *testing.T) {
 	if cmd.Use != "run" {
 		t.Errorf("expected Use=run, got %q", cmd.Use)
 	}
-	for _, flag := range []string{"collection", "env", "workspace", "no-save", "format", "report", "report-path", "fail-fast", "only", "tags", "retries", "retry-delay", "retry-5xx"} {
+	for _, flag := range []string{"collection", "env", "workspace", "no-save", "format", "report", "report-path", "fail-fast", "only", "tags", "retries", "retry-delay", "retry-5xx", "otel-endpoint", "baseline", "latency-budget-pct", "latency-budget-ms", "update-snapshots"} {
 		if cmd.Flags().Lookup(flag) == nil {
 			t.Errorf("expected --%s flag on run command", flag)
 		}
 	}
 }
 
+func TestRunCmd_SnapshotWorkflow(t *testing.T) {
+	var name atomic.Value
+	name.Store("alice")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"name":%q,"token":"tok-%d","at":%d}`, name.Load(), time.Now().UnixNano(), time.Now().UnixNano())
+	}))
+	defer srv.Close()
+
+	root := t.TempDir()
+	files := map[string]string{
+		"lynix.yaml":   "",
+		"env/dev.yaml": "vars:\n  base_url: \"" + srv.URL + "\"\n",
+		"collections/api.yaml": "name: api\nrequests:\n  - name: get user\n    method: GET\n    url: \"{{base_url}}/user\"\n" +
+			"    assert:\n      snapshot:\n        ignore: [\"$.at\"]\n",
+	}
+	for f, content := range files {
+		p := filepath.Join(root, f)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := func(args ...string) error {
+		cmd := runCmd()
+		cmd.SetArgs(append([]string{"-w", root, "-c", "api", "-e", "dev", "--no-save", "--format", "json"}, args...))
+		return cmd.Execute()
+	}
+
+	if err := run(); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	stored, err := os.ReadFile(filepath.Join(root, "collections", "__snapshots__", "api", "get_user.json"))
+	if err != nil {
+		t.Fatalf("snapshot not written: %v", err)
+	}
+	if strings.Contains(string(stored), "tok-") || strings.Contains(string(stored), `"at"`) {
+		t.Fatalf("snapshot must be redacted and leave ignored paths out:\n%s", stored)
+	}
+	if err := run(); err != nil {
+		t.Fatalf("unchanged run: %v", err)
+	}
+
+	name.Store("bob")
+	var coded *codedError
+	if err := run(); !errors.As(err, &coded) || coded.code != exitAssertFailed {
+		t.Fatalf("expected an assertion failure, got %v", err)
+	}
+	if err := run("--update-snapshots"); err != nil {
+		t.Fatalf("update run: %v", err)
+	}
+	if err := run(); err != nil {
+		t.Fatalf("run after update: %v", err)
+	}
+}
+
 func TestValidateCmd_Flags(t *testing.T) {
 	cmd := validateCmd()
 	if cmd.Use != "validate" {
 		t.Errorf("expected Use=validate, got %q", cmd.Use)
 	}
-	for _, flag := range []string{"collection", "env", "workspace"} {
+	for _, flag := range []string{"collection", "env", "workspace", "format"} {
 		if cmd.Flags().Lookup(flag) == nil {
 			t.Errorf("expected --%s flag on validate command", flag)
 		}
@@ -507,16 +572,16 @@ func TestImportCmd_Short(t *testing.T) {
 	}
 }
 
-func TestImportCmd_HasTwoSubcommands(t *testing.T) {
+func TestImportCmd_HasFiveSubcommands(t *testing.T) {
 	cmd := importCmd()
-	if len(cmd.Commands()) != 2 {
-		t.Errorf("expected 2 subcommands, got %d", len(cmd.Commands()))
+	if len(cmd.Commands()) != 5 {
+		t.Errorf("expected 5 subcommands, got %d", len(cmd.Commands()))
 	}
 }
 
 func TestImportCurlCmd_Flags(t *testing.T) {
 	cmd := importCurlCmd()
-	for _, flag := range []string{"output", "from-file", "name"} {
+	for _, flag := range []string{"output", "from-file", "name", "workspace", "force"} {
 		if cmd.Flags().Lookup(flag) == nil {
 			t.Errorf("expected --%s flag on import curl command", flag)
 		}
@@ -581,6 +646,56 @@ func TestImportCurlCmd_FromFile(t *testing.T) {
 	}
 }
 
+func TestImportCurlCmd_FromFile_ManyCommands(t *testing.T) {
+	tmp := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmp, "lynix.yaml"), []byte(""), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	curlFile := filepath.Join(tmp, "all.sh")
+	content := "curl 'https://api.example.com/users' -H 'Authorization: Bearer t1' ;\n" +
+		"curl 'https://api.example.com/orders' -H 'Authorization: Bearer t1'\n"
+	if err := os.WriteFile(curlFile, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	outFile := filepath.Join(tmp, "all.yaml")
+
+	cmd := importCurlCmd()
+	cmd.SetArgs([]string{"--from-file", curlFile, "-o", outFile, "-w", tmp})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	b, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"get-users", "get-orders", "Bearer {{token}}"} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("expected %q in output:\n%s", want, b)
+		}
+	}
+	if strings.Contains(string(b), "t1") {
+		t.Errorf("the token value must not be written to the collection:\n%s", b)
+	}
+	secrets, err := os.ReadFile(filepath.Join(tmp, "env", "secrets.local.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(secrets), "token: t1") {
+		t.Errorf("expected the token in the secrets file:\n%s", secrets)
+	}
+
+	// A different token under the same name is not replaced without --force.
+	if err := os.WriteFile(curlFile, []byte(strings.ReplaceAll(content, "t1", "t2")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd = importCurlCmd()
+	cmd.SetArgs([]string{"--from-file", curlFile, "-o", outFile, "-w", tmp})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("expected a conflict refusal, got %v", err)
+	}
+}
+
 func TestImportCurlCmd_FromFile_NotFound(t *testing.T) {
 	cmd := importCurlCmd()
 	cmd.SetArgs([]string{"--from-file", "/nonexistent/curl.txt"})
@@ -748,6 +863,247 @@ func TestImportPostmanCmd_InvalidJSON(t *testing.T) {
 	}
 }
 
+func TestPlanEnvImport_SplitsSecrets(t *testing.T) {
+	vars := domain.Vars{"base_url": "https://e.com", "client_key": "k1", "api_token": "t1"}
+	marked := map[string]bool{"client_key": true}
+	existing := domain.Vars{"other": "x", "api_token": "old"}
+	isSensitive := func(k string) bool { return strings.Contains(k, "token") }
+
+	plan := planEnvImport(vars, marked, existing, isSensitive)
+
+	if len(plan.public) != 1 || plan.public["base_url"] != "https://e.com" {
+		t.Errorf("public: got %v", plan.public)
+	}
+	if len(plan.secret) != 2 || plan.secret["client_key"] != "k1" || plan.secret["api_token"] != "t1" {
+		t.Errorf("secret: got %v", plan.secret)
+	}
+	if plan.secrets["other"] != "x" || plan.secrets["api_token"] != "t1" {
+		t.Errorf("merged secrets: got %v", plan.secrets)
+	}
+	if len(plan.conflicts) != 1 || plan.conflicts[0] != "api_token" {
+		t.Errorf("conflicts: got %v", plan.conflicts)
+	}
+}
+
+func TestImportPostmanEnvCmd_WritesEnvAndSecrets(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "lynix.yaml"), []byte(""), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	envFile := filepath.Join(root, "dev.postman_environment.json")
+	content := `{"name": "Dev", "values": [
+		{"key": "base_url", "value": "https://dev.example.com", "enabled": true},
+		{"key": "password", "value": "hunter22", "enabled": true},
+		{"key": "client", "value": "c-1", "type": "secret", "enabled": true}
+	]}`
+	if err := os.WriteFile(envFile, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := importPostmanEnvCmd()
+	cmd.SetArgs([]string{envFile, "-w", root})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	env, err := os.ReadFile(filepath.Join(root, "env", "dev.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(env), "base_url") || strings.Contains(string(env), "hunter22") || strings.Contains(string(env), "c-1") {
+		t.Errorf("env file should hold only public vars:\n%s", env)
+	}
+	secrets, err := os.ReadFile(filepath.Join(root, "env", "secrets.local.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(secrets), "hunter22") || !strings.Contains(string(secrets), "c-1") {
+		t.Errorf("secrets file should hold secret vars:\n%s", secrets)
+	}
+
+	// A second import must refuse to overwrite without --force.
+	cmd = importPostmanEnvCmd()
+	cmd.SetArgs([]string{envFile, "-w", root})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("expected overwrite refusal, got %v", err)
+	}
+
+	cmd = importPostmanEnvCmd()
+	cmd.SetArgs([]string{envFile, "-w", root, "--force"})
+	if err := cmd.Execute(); err != nil {
+		t.Errorf("expected --force to overwrite: %v", err)
+	}
+}
+
+func TestImportInsomniaAndBrunoCmd_Flags(t *testing.T) {
+	for _, cmd := range []*cobra.Command{importInsomniaCmd(), importBrunoCmd()} {
+		for _, flag := range []string{"output", "name", "workspace", "force", "no-envs"} {
+			if cmd.Flags().Lookup(flag) == nil {
+				t.Errorf("expected --%s flag on import %s command", flag, cmd.Name())
+			}
+		}
+	}
+}
+
+func TestImportBrunoCmd_WritesCollectionAndEnvs(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "lynix.yaml"), []byte(""), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(root, "bruno")
+	files := map[string]string{
+		"bruno.json":           `{"name": "Bruno API"}`,
+		"Health.bru":           "get {\n  url: {{baseUrl}}/health\n}\n\nassert {\n  res.status: eq 200\n}\n",
+		"environments/Dev.bru": "vars {\n  baseUrl: https://dev.example.com\n  api_token: t-1\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(src, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out := filepath.Join(root, "collections", "bruno.yaml")
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := importBrunoCmd()
+	cmd.SetArgs([]string{src, "-w", root, "-o", out})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	col, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(col), "name: Bruno API") || !strings.Contains(string(col), "status: 200") {
+		t.Errorf("unexpected collection:\n%s", col)
+	}
+	env, err := os.ReadFile(filepath.Join(root, "env", "dev.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(env), "baseUrl") || strings.Contains(string(env), "t-1") {
+		t.Errorf("env file should hold only public vars:\n%s", env)
+	}
+
+	// Existing env files are left alone without --force, and --no-envs
+	// skips them entirely.
+	cmd = importBrunoCmd()
+	cmd.SetArgs([]string{src, "-w", root, "-o", out})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("expected overwrite refusal, got %v", err)
+	}
+	cmd = importBrunoCmd()
+	cmd.SetArgs([]string{src, "-w", root, "-o", out, "--no-envs"})
+	if err := cmd.Execute(); err != nil {
+		t.Errorf("expected --no-envs to skip env files: %v", err)
+	}
+}
+
+func TestImportInsomniaCmd_KeepsBaseEnvSecretsOutOfCollection(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "lynix.yaml"), []byte(""), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(root, "insomnia.json")
+	content := `{"_type": "export", "__export_format": 4, "resources": [
+		{"_id": "wrk_1", "_type": "workspace", "name": "Shop"},
+		{"_id": "env_1", "_type": "environment", "parentId": "wrk_1", "name": "Base",
+			"data": {"base_url": "https://api.example.com", "api_token": "t-123"}},
+		{"_id": "req_1", "_type": "request", "parentId": "wrk_1", "name": "Me", "method": "GET",
+			"url": "{{ _.base_url }}/me", "headers": [{"name": "X-Token", "value": "{{ _.api_token }}"}]}
+	]}`
+	if err := os.WriteFile(src, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(root, "shop.yaml")
+
+	cmd := importInsomniaCmd()
+	cmd.SetArgs([]string{src, "-w", root, "-o", out})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	col, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(col), "base_url") || !strings.Contains(string(col), "{{api_token}}") || strings.Contains(string(col), "t-123") {
+		t.Errorf("collection should keep public vars and the {{api_token}} reference only:\n%s", col)
+	}
+	secrets, err := os.ReadFile(filepath.Join(root, "env", "secrets.local.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(secrets), "t-123") {
+		t.Errorf("secrets file should hold the base env secret:\n%s", secrets)
+	}
+
+	// A different value for an existing secret is only replaced with --force.
+	if err := os.WriteFile(src, []byte(strings.Replace(content, "t-123", "t-456", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd = importInsomniaCmd()
+	cmd.SetArgs([]string{src, "-w", root, "-o", out})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("expected secret overwrite refusal, got %v", err)
+	}
+}
+
+func TestExportCmd_HasTwoSubcommands(t *testing.T) {
+	cmd := exportCmd()
+	if len(cmd.Commands()) != 2 {
+		t.Errorf("expected 2 subcommands, got %d", len(cmd.Commands()))
+	}
+}
+
+func TestExportCurlCmd_Flags(t *testing.T) {
+	cmd := exportCurlCmd()
+	for _, flag := range []string{"workspace", "collection", "env", "var", "output", "resolve"} {
+		if cmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected --%s flag on export curl command", flag)
+		}
+	}
+}
+
+func TestExportPostmanCmd_Flags(t *testing.T) {
+	cmd := exportPostmanCmd()
+	for _, flag := range []string{"workspace", "collection", "output"} {
+		if cmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected --%s flag on export postman command", flag)
+		}
+	}
+}
+
+func TestApplyResolved_KeepsTemplateOnError(t *testing.T) {
+	reqs := []domain.RequestSpec{
+		{Name: "login", Method: domain.MethodPost, URL: "{{base_url}}/login"},
+		{Name: "me", Method: domain.MethodGet, URL: "{{base_url}}/me", Headers: domain.Headers{"Authorization": "Bearer {{token}}"}},
+	}
+	cmds := []curlparse.Command{curlparse.CommandFromSpec(reqs[0]), curlparse.CommandFromSpec(reqs[1])}
+	results := []domain.RequestResult{
+		{Name: "login", Method: domain.MethodPost, ResolvedURL: "https://api.example.com/login"},
+		{Name: "me", Method: domain.MethodGet, Error: &domain.RunError{Kind: domain.RunErrorUnknown, Message: "missing var token"}},
+	}
+
+	warnings := applyResolved(cmds, reqs, results)
+
+	if cmds[0].URL != "https://api.example.com/login" {
+		t.Errorf("expected resolved URL, got %q", cmds[0].URL)
+	}
+	if cmds[1].URL != "{{base_url}}/me" {
+		t.Errorf("expected template URL to be kept, got %q", cmds[1].URL)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "missing var token") {
+		t.Errorf("expected one warning about the unresolved request, got %v", warnings)
+	}
+}
+
 func TestInitCmd_Flags(t *testing.T) {
 	cmd := initCmd()
 	if cmd.Flags().Lookup("path") == nil {
@@ -863,3 +1219,75 @@ func TestPrintPrettyRun_FailureShowsResponseExcerpt(t *testing.T) {
 		t.Errorf("expected response excerpt on failure, got:\n%s", buf.String())
 	}
 }
+
+func TestPrintPrettyRun_FailureShowsDiff(t *testing.T) {
+	diff := make([]domain.DiffEntry, 12)
+	for i := range diff {
+		diff[i] = domain.DiffEntry{Path: fmt.Sprintf("$.items[%d]", i), Kind: "changed", Old: "1", New: "2"}
+	}
+	run := domain.RunResult{
+		Results: []domain.RequestResult{{
+			Name:       "fail",
+			StatusCode: 200,
+			Assertions: []domain.AssertionResult{{Name: "json_eq", Passed: false, Message: "12 path(s) differ", Diff: diff}},
+		}},
+	}
+	var buf bytes.Buffer
+	printPrettyRun(&buf, run, "", prettyOpts{})
+	out := buf.String()
+	if !strings.Contains(out, "~ $.items[0]: 1 → 2") || !strings.Contains(out, "~ $.items[9]") {
+		t.Errorf("expected diff entries under the assertion, got:\n%s", out)
+	}
+	if strings.Contains(out, "$.items[10]") || !strings.Contains(out, "… 2 more") {
+		t.Errorf("expected the diff to be capped, got:\n%s", out)
+	}
+}
+
+func TestParseLabelFlags(t *testing.T) {
+	got, err := parseLabelFlags("label", []string{"team=payments", "release=v1=rc"})
+	if err != nil {
+		t.Fatalf("parseLabelFlags: %v", err)
+	}
+	if got["team"] != "payments" || got["release"] != "v1=rc" {
+		t.Errorf("unexpected labels: %v", got)
+	}
+	if _, err := parseLabelFlags("label", []string{"=x"}); err == nil || !strings.Contains(err.Error(), "--label") {
+		t.Errorf("expected an error naming the flag, got %v", err)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	if got, err := parseSince("7d", now); err != nil || !got.Equal(now.Add(-7*24*time.Hour)) {
+		t.Errorf("7d: got %v, %v", got, err)
+	}
+	if got, err := parseSince("2026-03-01T08:00:00Z", now); err != nil || !got.Equal(time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("RFC 3339: got %v, %v", got, err)
+	}
+	if got, err := parseSince("2026-03-01", now); err != nil || got.Day() != 1 || got.Hour() != 0 {
+		t.Errorf("date: got %v, %v", got, err)
+	}
+	if _, err := parseSince("last week", now); err == nil || !strings.Contains(err.Error(), "--since") {
+		t.Errorf("expected a --since error, got %v", err)
+	}
+}
+
+func TestPrintPrettyRun_Metadata(t *testing.T) {
+	run := domain.RunResult{
+		CollectionName: "demo",
+		Metadata:       map[string]string{"git.commit": "abc123", "ci.job": "smoke"},
+	}
+
+	var buf bytes.Buffer
+	printPrettyRun(&buf, run, "run-1", prettyOpts{metadata: true})
+	out := buf.String()
+	if !strings.Contains(out, "Metadata:\n  ci.job      smoke\n  git.commit  abc123\n") {
+		t.Errorf("expected a sorted metadata block, got:\n%s", out)
+	}
+
+	buf.Reset()
+	printPrettyRun(&buf, run, "run-1", prettyOpts{})
+	if strings.Contains(buf.String(), "Metadata:") {
+		t.Error("metadata is only shown when requested")
+	}
+}
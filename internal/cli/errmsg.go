@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"errors"
+	"fmt"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -74,6 +75,10 @@ func userMessage(err error) string {
 			if looksLikeYAMLProblem(err.Error()) {
 				return "Invalid YAML at " + base
 			}
+			var se *domain.SourceError
+			if errors.As(err, &se) && se.Pos.Line > 0 {
+				return fmt.Sprintf("Invalid config at %s line %d", base, se.Pos.Line)
+			}
 			return "Invalid config"
 
 		default:
@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/curlparse"
+	"github.com/aalvaropc/lynix/internal/infra/postmanparse"
+	"github.com/aalvaropc/lynix/internal/infra/wiring"
+	"github.com/aalvaropc/lynix/internal/usecase"
+)
+
+func exportCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "export",
+		Short: "Export collections to external formats",
+	}
+
+	c.AddCommand(exportCurlCmd())
+	c.AddCommand(exportPostmanCmd())
+	return c
+}
+
+func exportCurlCmd() *cobra.Command {
+	var workspace string
+	var collection string
+	var env string
+	var varFlags []string
+	var output string
+	var resolve bool
+
+	c := &cobra.Command{
+		Use:   "curl",
+		Short: "Export a collection as a shell script of curl commands",
+		Long: "Render one curl command per request.\n" +
+			"By default {{vars}} are kept as placeholders. With --resolve, variables are\n" +
+			"resolved exactly like `lynix run --dry-run` (env, secrets and --var included),\n" +
+			"so the script may contain secret values.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cliVars, err := parseVarFlags(varFlags)
+			if err != nil {
+				return err
+			}
+
+			ws, err := loadWorkspaceOrStandalone(cmd.Flags().Changed("workspace"), workspace, wiring.Opts{})
+			if err != nil {
+				return err
+			}
+
+			collectionPath, err := resolveCollectionPath(ws, collection)
+			if err != nil {
+				return err
+			}
+
+			col, err := ws.collections.LoadCollection(collectionPath)
+			if err != nil {
+				return err
+			}
+
+			cmds := make([]curlparse.Command, len(col.Requests))
+			for i, req := range col.Requests {
+				cmds[i] = curlparse.CommandFromSpec(req)
+			}
+
+			var warnings []string
+			if resolve {
+				envArg, err := resolveEnvironmentArg(ws, env)
+				if err != nil {
+					return err
+				}
+
+				uc := usecase.NewRunCollection(ws.collections, ws.envs, ws.runner, nil, usecase.RunOpts{
+					DryRun: true,
+					Vars:   cliVars,
+				})
+				run, _, err := uc.Execute(cmd.Context(), collectionPath, envArg)
+				if err != nil {
+					return err
+				}
+
+				warnings = applyResolved(cmds, col.Requests, run.Results)
+			}
+
+			script := curlparse.FormatScript(col.Name, cmds)
+			if err := writeExport(output, []byte(script)); err != nil {
+				return err
+			}
+			for _, w := range warnings {
+				fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+			}
+			return nil
+		},
+	}
+
+	c.Flags().StringVarP(&workspace, "workspace", "w", "", "Workspace root (optional; autodetected if omitted)")
+	c.Flags().StringVarP(&collection, "collection", "c", "", "Collection name or path (required)")
+	c.Flags().StringVarP(&env, "env", "e", "", "Environment name or path used with --resolve (defaults to workspace default env)")
+	c.Flags().StringArrayVar(&varFlags, "var", nil, "Override a variable used with --resolve (key=value, repeatable)")
+	c.Flags().StringVarP(&output, "output", "o", "", "Write the script to file instead of stdout")
+	c.Flags().BoolVar(&resolve, "resolve", false, "Resolve variables like --dry-run instead of keeping {{vars}}")
+
+	if err := c.MarkFlagRequired("collection"); err != nil {
+		panic(fmt.Sprintf("MarkFlagRequired: %v", err))
+	}
+	return c
+}
+
+// applyResolved swaps templated commands for their dry-run resolution.
+// A dry run does not execute requests, so values extracted from earlier
+// responses are unavailable: those requests keep their template and are
+// reported as warnings instead of failing the whole export.
+func applyResolved(cmds []curlparse.Command, reqs []domain.RequestSpec, results []domain.RequestResult) []string {
+	var warnings []string
+	for i, rr := range results {
+		if i >= len(reqs) || rr.Name != reqs[i].Name {
+			break
+		}
+		if rr.Error != nil {
+			warnings = append(warnings, fmt.Sprintf("request %q kept as template: %s", rr.Name, rr.Error.Message))
+			continue
+		}
+		cmds[i] = curlparse.CommandFromResult(reqs[i], rr)
+	}
+	return warnings
+}
+
+func exportPostmanCmd() *cobra.Command {
+	var workspace string
+	var collection string
+	var output string
+
+	c := &cobra.Command{
+		Use:   "postman",
+		Short: "Export a collection as a Postman v2.1 collection",
+		Long: "Generate a Postman Collection v2.1 JSON file.\n" +
+			"{{vars}} are kept, collection vars become collection variables, requests are\n" +
+			"grouped into folders by their first tag, and assert.status becomes a pm.test.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ws, err := loadWorkspaceOrStandalone(cmd.Flags().Changed("workspace"), workspace, wiring.Opts{})
+			if err != nil {
+				return err
+			}
+
+			collectionPath, err := resolveCollectionPath(ws, collection)
+			if err != nil {
+				return err
+			}
+
+			col, err := ws.collections.LoadCollection(collectionPath)
+			if err != nil {
+				return err
+			}
+
+			result := postmanparse.Export(col)
+			b, err := json.MarshalIndent(result.Collection, "", "  ")
+			if err != nil {
+				return fmt.Errorf("marshal postman collection: %w", err)
+			}
+			b = append(b, '\n')
+
+			if err := writeExport(output, b); err != nil {
+				return err
+			}
+			for _, w := range result.Warnings {
+				fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+			}
+			return nil
+		},
+	}
+
+	c.Flags().StringVarP(&workspace, "workspace", "w", "", "Workspace root (optional; autodetected if omitted)")
+	c.Flags().StringVarP(&collection, "collection", "c", "", "Collection name or path (required)")
+	c.Flags().StringVarP(&output, "output", "o", "", "Write JSON to file instead of stdout")
+
+	if err := c.MarkFlagRequired("collection"); err != nil {
+		panic(fmt.Sprintf("MarkFlagRequired: %v", err))
+	}
+	return c
+}
+
+func writeExport(output string, b []byte) error {
+	if strings.TrimSpace(output) == "" {
+		_, err := os.Stdout.Write(b)
+		return err
+	}
+	if err := os.WriteFile(output, b, 0o644); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Export written to %s\n", output)
+	return nil
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/buildinfo"
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// CTRF ("Common Test Report Format", https://ctrf.io) is a JSON test report
+// understood by a growing set of CI reporters and dashboards.
+
+type ctrfReport struct {
+	ReportFormat string      `json:"reportFormat"`
+	SpecVersion  string      `json:"specVersion"`
+	Results      ctrfResults `json:"results"`
+}
+
+type ctrfResults struct {
+	Tool        ctrfTool         `json:"tool"`
+	Summary     ctrfSummary      `json:"summary"`
+	Tests       []ctrfTest       `json:"tests"`
+	Environment *ctrfEnvironment `json:"environment,omitempty"`
+	Extra       map[string]any   `json:"extra,omitempty"`
+}
+
+type ctrfTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type ctrfSummary struct {
+	Tests   int   `json:"tests"`
+	Passed  int   `json:"passed"`
+	Failed  int   `json:"failed"`
+	Pending int   `json:"pending"`
+	Skipped int   `json:"skipped"`
+	Other   int   `json:"other"`
+	Start   int64 `json:"start"`
+	Stop    int64 `json:"stop"`
+}
+
+type ctrfTest struct {
+	Name      string         `json:"name"`
+	Status    string         `json:"status"`
+	Duration  int64          `json:"duration"`
+	Message   string         `json:"message,omitempty"`
+	Trace     string         `json:"trace,omitempty"`
+	RawStatus string         `json:"rawStatus,omitempty"`
+	Type      string         `json:"type"`
+	Suite     string         `json:"suite,omitempty"`
+	Retries   int            `json:"retries,omitempty"`
+	Extra     map[string]any `json:"extra,omitempty"`
+}
+
+type ctrfEnvironment struct {
+	AppName         string `json:"appName,omitempty"`
+	TestEnvironment string `json:"testEnvironment,omitempty"`
+}
+
+func formatCTRF(w io.Writer, run domain.RunResult, runID string) error {
+	rep := ctrfReport{
+		ReportFormat: "CTRF",
+		SpecVersion:  "0.0.0",
+		Results: ctrfResults{
+			Tool:  ctrfTool{Name: "lynix", Version: buildinfo.Version},
+			Tests: make([]ctrfTest, 0, len(run.Results)),
+		},
+	}
+	if !run.StartedAt.IsZero() {
+		rep.Results.Summary.Start = run.StartedAt.UnixMilli()
+	}
+	if !run.EndedAt.IsZero() {
+		rep.Results.Summary.Stop = run.EndedAt.UnixMilli()
+	}
+	if run.CollectionName != "" || run.EnvironmentName != "" {
+		rep.Results.Environment = &ctrfEnvironment{AppName: run.CollectionName, TestEnvironment: run.EnvironmentName}
+	}
+	if runID != "" {
+		rep.Results.Extra = map[string]any{"runId": runID}
+	}
+
+	for _, r := range run.Results {
+		t := ctrfTest{
+			Name:     r.Name,
+			Status:   "passed",
+			Duration: r.LatencyMS,
+			Type:     "api",
+			Suite:    run.CollectionName,
+			Extra:    map[string]any{"method": string(r.Method)},
+		}
+		if r.StatusCode != 0 {
+			t.Extra["statusCode"] = r.StatusCode
+		}
+		if r.Attempts > 1 {
+			t.Retries = r.Attempts - 1
+		}
+
+		msgs := failureMessages(r)
+		switch {
+		case r.Error != nil:
+			t.Status = "failed"
+			t.RawStatus = "error"
+			t.Message = fmt.Sprintf("%s: %s", r.Error.Kind, r.Error.Message)
+			t.Trace = strings.Join(msgs, "\n")
+		case len(msgs) > 0:
+			t.Status = "failed"
+			t.Message = fmt.Sprintf("%d check(s) failed", len(msgs))
+			t.Trace = strings.Join(msgs, "\n")
+		}
+
+		rep.Results.Summary.Tests++
+		if t.Status == "passed" {
+			rep.Results.Summary.Passed++
+		} else {
+			rep.Results.Summary.Failed++
+		}
+		rep.Results.Tests = append(rep.Results.Tests, t)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestFormatCTRF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := formatCTRF(&buf, htmlTestRun(), "run-1"); err != nil {
+		t.Fatalf("formatCTRF: %v", err)
+	}
+
+	var rep ctrfReport
+	if err := json.Unmarshal(buf.Bytes(), &rep); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if rep.ReportFormat != "CTRF" || rep.Results.Tool.Name != "lynix" {
+		t.Errorf("header: %+v / %+v", rep.ReportFormat, rep.Results.Tool)
+	}
+
+	s := rep.Results.Summary
+	if s.Tests != 3 || s.Passed != 1 || s.Failed != 2 {
+		t.Errorf("summary: %+v", s)
+	}
+	if s.Stop-s.Start != 350 {
+		t.Errorf("start/stop: %d..%d", s.Start, s.Stop)
+	}
+
+	tests := rep.Results.Tests
+	if tests[0].Status != "passed" || tests[0].Duration != 100 {
+		t.Errorf("login: %+v", tests[0])
+	}
+	if tests[1].Status != "failed" || tests[1].Trace != "[status] expected 200, got 500" {
+		t.Errorf("profile: %+v", tests[1])
+	}
+	if tests[2].RawStatus != "error" || tests[2].Message != "timeout: deadline exceeded" {
+		t.Errorf("health: %+v", tests[2])
+	}
+}
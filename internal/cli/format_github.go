@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// diagnostic is one problem located in a file, rendered as a GitHub
+// workflow annotation or a SARIF result.
+type diagnostic struct {
+	Path    string
+	Pos     domain.SourcePos
+	Rule    string
+	Title   string
+	Message string
+}
+
+// errorDiagnostic locates err using a SourceError when there is one, then
+// the OpError path and a "line N" in the message. fallbackPath is used when
+// the error names no file.
+func errorDiagnostic(err error, fallbackPath string) diagnostic {
+	d := diagnostic{Path: fallbackPath, Rule: "invalid-request", Message: err.Error()}
+
+	var oe *domain.OpError
+	if errors.As(err, &oe) {
+		d.Rule = strings.ReplaceAll(string(oe.Kind), "_", "-")
+		if oe.Path != "" {
+			d.Path = oe.Path
+		}
+	}
+	var se *domain.SourceError
+	if errors.As(err, &se) {
+		d.Path, d.Pos = se.Path, se.Pos
+	} else if line, convErr := strconv.Atoi(extractLine(err.Error())); convErr == nil {
+		d.Pos = domain.SourcePos{Line: line}
+	}
+	return d
+}
+
+// runDiagnostics lists every failure of a run, located at the request,
+// assertion or extract that produced it when specs carry positions.
+func runDiagnostics(run domain.RunResult, specs map[string]domain.RequestSpec) []diagnostic {
+	var out []diagnostic
+	for _, r := range run.Results {
+		if !r.Failed() {
+			continue
+		}
+		spec := specs[r.Name]
+		title := fmt.Sprintf("%s %s", r.Method, r.Name)
+		if r.Error != nil {
+			out = append(out, diagnostic{
+				Path:    run.CollectionPath,
+				Pos:     spec.Pos,
+				Rule:    "request-error",
+				Title:   title,
+				Message: fmt.Sprintf("%s: %s", r.Error.Kind, r.Error.Message),
+			})
+		}
+		for _, a := range r.Assertions {
+			if !a.Passed {
+				out = append(out, diagnostic{
+					Path:    run.CollectionPath,
+					Pos:     spec.AssertionPos(a),
+					Rule:    "assertion-failed",
+					Title:   title,
+					Message: fmt.Sprintf("[%s] %s", a.Name, a.Message),
+				})
+			}
+		}
+		for _, e := range r.Extracts {
+			if !e.Success {
+				out = append(out, diagnostic{
+					Path:    run.CollectionPath,
+					Pos:     spec.ExtractPos(e),
+					Rule:    "extract-failed",
+					Title:   title,
+					Message: fmt.Sprintf("[extract:%s] %s", e.Name, e.Message),
+				})
+			}
+		}
+	}
+	return out
+}
+
+// writeGitHubAnnotations emits one ::error workflow command per diagnostic;
+// GitHub shows them inline on the file in the run and pull request views.
+func writeGitHubAnnotations(w io.Writer, diags []diagnostic) error {
+	for _, d := range diags {
+		var props []string
+		if d.Path != "" {
+			props = append(props, "file="+ghEscapeProperty(annotationPath(d.Path)))
+			if d.Pos.Line > 0 {
+				props = append(props, "line="+strconv.Itoa(d.Pos.Line))
+			}
+			if d.Pos.Column > 0 {
+				props = append(props, "col="+strconv.Itoa(d.Pos.Column))
+			}
+		}
+		if d.Title != "" {
+			props = append(props, "title="+ghEscapeProperty(d.Title))
+		}
+		cmd := "::error"
+		if len(props) > 0 {
+			cmd += " " + strings.Join(props, ",")
+		}
+		if _, err := fmt.Fprintf(w, "%s::%s\n", cmd, ghEscapeData(d.Message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// annotationPath makes path relative to the repository checkout, which is
+// what GitHub matches annotations against.
+func annotationPath(path string) string {
+	root := os.Getenv("GITHUB_WORKSPACE")
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	if abs, err := filepath.Abs(path); err == nil && root != "" {
+		if rel, err := filepath.Rel(root, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+func ghEscapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func ghEscapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// requestSpecs loads the collection again to look up where each request is
+// defined. Annotations still work without it, only without line numbers.
+func requestSpecs(ws *workspaceCtx, collectionPath string) map[string]domain.RequestSpec {
+	out := map[string]domain.RequestSpec{}
+	col, err := ws.collections.LoadCollection(collectionPath)
+	if err != nil {
+		return out
+	}
+	for _, r := range col.Requests {
+		out[r.Name] = r
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+func TestRunDiagnostics_LocatesFailures(t *testing.T) {
+	run := htmlTestRun()
+	run.CollectionPath = "collections/demo.yaml"
+	specs := map[string]domain.RequestSpec{
+		"profile": {
+			Pos:      domain.SourcePos{Line: 10, Column: 5},
+			FieldPos: map[string]domain.SourcePos{"assert.status": {Line: 14, Column: 7}},
+		},
+		"health": {Pos: domain.SourcePos{Line: 20, Column: 5}},
+	}
+
+	diags := runDiagnostics(run, specs)
+	if len(diags) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %+v", diags)
+	}
+	if d := diags[0]; d.Rule != "assertion-failed" || d.Pos.Line != 14 || d.Title != "GET profile" {
+		t.Errorf("unexpected assertion diagnostic: %+v", d)
+	}
+	if d := diags[1]; d.Rule != "request-error" || d.Pos.Line != 20 || d.Message != "timeout: deadline exceeded" {
+		t.Errorf("unexpected error diagnostic: %+v", d)
+	}
+}
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	t.Setenv("GITHUB_WORKSPACE", "/work")
+	diags := []diagnostic{
+		{Path: "/work/c/demo.yaml", Pos: domain.SourcePos{Line: 3, Column: 7}, Title: "GET a,b", Message: "100% bad\nnext"},
+		{Message: "no location"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeGitHubAnnotations(&buf, diags); err != nil {
+		t.Fatalf("writeGitHubAnnotations: %v", err)
+	}
+	want := "::error file=c/demo.yaml,line=3,col=7,title=GET a%2Cb::100%25 bad%0Anext\n" +
+		"::error::no location\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestErrorDiagnostic(t *testing.T) {
+	inner := &domain.OpError{Op: "yamlcollection.load", Kind: domain.KindInvalidConfig, Path: "c.yaml", Err: domain.ErrInvalidConfig}
+	located := &domain.SourceError{Path: "c.yaml", Pos: domain.SourcePos{Line: 4, Column: 3}, Err: inner}
+
+	d := errorDiagnostic(fmt.Errorf("validate: %w", located), "fallback.yaml")
+	if d.Path != "c.yaml" || d.Pos.Line != 4 || d.Rule != "invalid-config" {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	}
+
+	d = errorDiagnostic(errors.New("boom"), "fallback.yaml")
+	if d.Path != "fallback.yaml" || !d.Pos.IsZero() || d.Rule != "invalid-request" {
+		t.Errorf("unexpected fallback diagnostic: %+v", d)
+	}
+}
+
+func TestPrintValidation(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printValidation(&buf, "github", "c.yaml", nil); err != nil || buf.String() != "OK\n" {
+		t.Fatalf("success: err=%v out=%q", err, buf.String())
+	}
+
+	buf.Reset()
+	failure := &domain.SourceError{Path: "c.yaml", Pos: domain.SourcePos{Line: 2}, Err: errors.New("bad")}
+	err := printValidation(&buf, "github", "c.yaml", failure)
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected the validation error back, got %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "::error file=c.yaml,line=2::bad") {
+		t.Errorf("unexpected annotation: %q", buf.String())
+	}
+}
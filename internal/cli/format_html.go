@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// The HTML report is a single self-contained file (inline CSS, no scripts,
+// no external assets) so it can be attached to a CI run and opened offline.
+// Bodies are collapsed with <details>; failed requests start expanded.
+
+type htmlReport struct {
+	RunID       string
+	Collection  string
+	Environment string
+	StartedAt   string
+	Duration    string
+	Passed      int
+	Failed      int
+	Errors      int
+	Requests    []htmlRequest
+}
+
+type htmlRequest struct {
+	Name        string
+	Method      string
+	URL         string
+	Outcome     string // "pass", "fail" or "error"
+	StatusCode  int
+	LatencyMS   int64
+	BarPercent  int
+	Attempts    int
+	Error       string
+	Assertions  []domain.AssertionResult
+	Extracts    []domain.ExtractResult
+	Extracted   []htmlKV
+	ReqHeaders  []htmlKV
+	ReqBody     string
+	RespHeaders []htmlKV
+	RespBody    string
+	Truncated   bool
+}
+
+type htmlKV struct {
+	Key   string
+	Value string
+}
+
+func formatHTML(w io.Writer, run domain.RunResult, runID string) error {
+	duration := runDuration(run)
+	passed, failed, errs := summarizeResults(run)
+
+	rep := htmlReport{
+		RunID:       runID,
+		Collection:  run.CollectionName,
+		Environment: run.EnvironmentName,
+		Duration:    duration.Round(time.Millisecond).String(),
+		Passed:      passed,
+		Failed:      failed,
+		Errors:      errs,
+	}
+	if !run.StartedAt.IsZero() {
+		rep.StartedAt = run.StartedAt.UTC().Format(time.RFC3339)
+	}
+
+	var maxLatency int64
+	for _, r := range run.Results {
+		maxLatency = max(maxLatency, r.LatencyMS)
+	}
+
+	for _, r := range run.Results {
+		hr := htmlRequest{
+			Name:       r.Name,
+			Method:     string(r.Method),
+			URL:        r.URL,
+			Outcome:    "pass",
+			StatusCode: r.StatusCode,
+			LatencyMS:  r.LatencyMS,
+			Attempts:   r.Attempts,
+			Assertions: r.Assertions,
+			Extracts:   r.Extracts,
+			Extracted:  sortedKV(r.Extracted),
+			ReqHeaders: sortedKV(r.RequestHeaders),
+			ReqBody:    htmlBody(r.RequestBody),
+			RespBody:   htmlBody(r.Response.Body),
+			Truncated:  r.Response.Truncated,
+		}
+		if r.ResolvedURL != "" {
+			hr.URL = r.ResolvedURL
+		}
+		switch {
+		case r.Error != nil:
+			hr.Outcome = "error"
+			hr.Error = fmt.Sprintf("%s: %s", r.Error.Kind, r.Error.Message)
+		case r.Failed():
+			hr.Outcome = "fail"
+		}
+		if maxLatency > 0 {
+			hr.BarPercent = int(r.LatencyMS * 100 / maxLatency)
+		}
+		for k, vs := range r.Response.Headers {
+			hr.RespHeaders = append(hr.RespHeaders, htmlKV{Key: k, Value: strings.Join(vs, ", ")})
+		}
+		sort.Slice(hr.RespHeaders, func(i, j int) bool { return hr.RespHeaders[i].Key < hr.RespHeaders[j].Key })
+		rep.Requests = append(rep.Requests, hr)
+	}
+
+	return htmlReportTemplate.Execute(w, rep)
+}
+
+func sortedKV[M ~map[string]string](m M) []htmlKV {
+	out := make([]htmlKV, 0, len(m))
+	for k, v := range m {
+		out = append(out, htmlKV{Key: k, Value: v})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// htmlBody renders a body for display: JSON is indented, other text is shown
+// as is, and binary payloads are summarized instead of dumped.
+func htmlBody(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	if !utf8.Valid(b) {
+		return fmt.Sprintf("(%d bytes of binary data)", len(b))
+	}
+	var buf bytes.Buffer
+	if json.Indent(&buf, b, "", "  ") == nil {
+		return buf.String()
+	}
+	return string(b)
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Lynix run · {{.Collection}}</title>
+<style>
+body { font: 14px/1.45 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; color: #1f2328; background: #f6f8fa; }
+main { max-width: 1100px; margin: 0 auto; padding: 24px; }
+h1 { font-size: 20px; margin: 0 0 4px; }
+.meta { color: #59636e; margin-bottom: 16px; }
+.summary { display: flex; gap: 12px; margin-bottom: 20px; }
+.card { background: #fff; border: 1px solid #d1d9e0; border-radius: 6px; padding: 10px 16px; min-width: 90px; }
+.card b { display: block; font-size: 22px; }
+.pass { color: #1a7f37; } .fail { color: #cf222e; } .error { color: #9a6700; }
+main > details { background: #fff; border: 1px solid #d1d9e0; border-left-width: 4px; border-radius: 6px; margin-bottom: 8px; }
+main > details.pass { border-left-color: #1a7f37; } main > details.fail { border-left-color: #cf222e; } main > details.error { border-left-color: #9a6700; }
+summary { cursor: pointer; padding: 6px 0; }
+summary.row { padding: 8px 12px; display: grid; grid-template-columns: 60px 70px 1fr 60px 220px; gap: 8px; align-items: center; }
+summary .name { font-weight: 600; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+.bar { background: #eaeef2; border-radius: 3px; height: 8px; position: relative; }
+.bar span { position: absolute; left: 0; top: 0; bottom: 0; background: #54aeff; border-radius: 3px; }
+.latency { font-size: 12px; color: #59636e; }
+.body { padding: 0 12px 12px; }
+h3 { font-size: 13px; margin: 14px 0 6px; text-transform: uppercase; color: #59636e; }
+table { border-collapse: collapse; width: 100%; }
+td { border-top: 1px solid #eaeef2; padding: 4px 6px; vertical-align: top; word-break: break-all; }
+td.k { width: 28%; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; }
+pre { background: #f6f8fa; border: 1px solid #eaeef2; border-radius: 4px; padding: 8px; overflow: auto; max-height: 420px; margin: 0; white-space: pre-wrap; word-break: break-all; }
+pre.diff { margin-top: 6px; } .diff .added { color: #1a7f37; } .diff .removed { color: #cf222e; } .diff .changed { color: #9a6700; }
+.url { font-family: ui-monospace, SFMono-Regular, Menlo, monospace; word-break: break-all; }
+</style>
+</head>
+<body>
+<main>
+<h1>{{.Collection}}</h1>
+<div class="meta">
+{{- if .Environment}}env <b>{{.Environment}}</b> · {{end -}}
+{{- if .RunID}}run <b>{{.RunID}}</b> · {{end -}}
+{{- if .StartedAt}}{{.StartedAt}} · {{end -}}
+{{.Duration}}
+</div>
+<div class="summary">
+<div class="card"><b>{{len .Requests}}</b>requests</div>
+<div class="card pass"><b>{{.Passed}}</b>passed</div>
+<div class="card fail"><b>{{.Failed}}</b>failed</div>
+<div class="card error"><b>{{.Errors}}</b>errors</div>
+</div>
+{{range .Requests}}
+<details class="{{.Outcome}}"{{if ne .Outcome "pass"}} open{{end}}>
+<summary class="row">
+<span class="{{.Outcome}}">{{if eq .Outcome "pass"}}PASS{{else if eq .Outcome "fail"}}FAIL{{else}}ERROR{{end}}</span>
+<span>{{.Method}}</span>
+<span class="name">{{.Name}}</span>
+<span>{{if .StatusCode}}{{.StatusCode}}{{else}}—{{end}}</span>
+<span><div class="bar"><span style="width: {{.BarPercent}}%"></span></div><span class="latency">{{.LatencyMS}} ms{{if gt .Attempts 1}} · {{.Attempts}} attempts{{end}}</span></span>
+</summary>
+<div class="body">
+<div class="url">{{.URL}}</div>
+{{- if .Error}}
+<h3>Error</h3>
+<pre class="error">{{.Error}}</pre>
+{{- end}}
+{{- if .Assertions}}
+<h3>Assertions</h3>
+<table>{{range .Assertions}}<tr><td class="k {{if .Passed}}pass{{else}}fail{{end}}">{{if .Passed}}✓{{else}}✗{{end}} {{.Name}}</td><td>{{.Message}}{{if .Diff}}<pre class="diff">{{range .Diff}}<span class="{{.Kind}}">{{.String}}</span>
+{{end}}</pre>{{end}}</td></tr>{{end}}</table>
+{{- end}}
+{{- if .Extracts}}
+<h3>Extracts</h3>
+<table>{{range .Extracts}}<tr><td class="k {{if .Success}}pass{{else}}fail{{end}}">{{if .Success}}✓{{else}}✗{{end}} {{.Name}}</td><td>{{.Message}}</td></tr>{{end}}</table>
+{{- end}}
+{{- if .Extracted}}
+<h3>Extracted vars</h3>
+<table>{{range .Extracted}}<tr><td class="k">{{.Key}}</td><td>{{.Value}}</td></tr>{{end}}</table>
+{{- end}}
+{{- if or .ReqHeaders .ReqBody}}
+<details><summary>Request</summary><div class="body">
+{{- if .ReqHeaders}}<table>{{range .ReqHeaders}}<tr><td class="k">{{.Key}}</td><td>{{.Value}}</td></tr>{{end}}</table>{{end}}
+{{- if .ReqBody}}<h3>Body</h3><pre>{{.ReqBody}}</pre>{{end}}
+</div></details>
+{{- end}}
+{{- if or .RespHeaders .RespBody}}
+<details><summary>Response{{if .Truncated}} (truncated){{end}}</summary><div class="body">
+{{- if .RespHeaders}}<table>{{range .RespHeaders}}<tr><td class="k">{{.Key}}</td><td>{{.Value}}</td></tr>{{end}}</table>{{end}}
+{{- if .RespBody}}<h3>Body</h3><pre>{{.RespBody}}</pre>{{end}}
+</div></details>
+{{- end}}
+</div>
+</details>
+{{end}}
+</main>
+</body>
+</html>
+`))
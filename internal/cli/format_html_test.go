@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+func htmlTestRun() domain.RunResult {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	return domain.RunResult{
+		CollectionName:  "demo",
+		EnvironmentName: "dev",
+		StartedAt:       now,
+		EndedAt:         now.Add(350 * time.Millisecond),
+		Results: []domain.RequestResult{
+			{
+				Name:           "login",
+				Method:         domain.MethodPost,
+				URL:            "{{base_url}}/login",
+				ResolvedURL:    "https://api.test/login",
+				RequestHeaders: map[string]string{"Authorization": "Bearer ***"},
+				RequestBody:    domain.BodyBytes(`{"user":"ada"}`),
+				StatusCode:     200,
+				LatencyMS:      100,
+				Assertions:     []domain.AssertionResult{{Name: "status", Passed: true, Message: "status 200"}},
+				Extracts:       []domain.ExtractResult{{Name: "token", Success: true}},
+				Extracted:      domain.Vars{"token": "***"},
+				Response: domain.ResponseSnapshot{
+					Headers: map[string][]string{"Content-Type": {"application/json"}},
+					Body:    domain.BodyBytes(`{"token":"***"}`),
+				},
+			},
+			{
+				Name:       "profile",
+				Method:     domain.MethodGet,
+				URL:        "https://api.test/me",
+				StatusCode: 500,
+				LatencyMS:  200,
+				Assertions: []domain.AssertionResult{{Name: "status", Passed: false, Message: "expected 200, got 500"}},
+				Response:   domain.ResponseSnapshot{Body: domain.BodyBytes(`<script>alert(1)</script>`)},
+			},
+			{
+				Name:   "health",
+				Method: domain.MethodGet,
+				URL:    "https://api.test/health",
+				Error:  &domain.RunError{Kind: domain.RunErrorTimeout, Message: "deadline exceeded"},
+			},
+		},
+	}
+}
+
+func TestFormatHTML_SummaryAndRequests(t *testing.T) {
+	var buf bytes.Buffer
+	if err := formatHTML(&buf, htmlTestRun(), "run-1"); err != nil {
+		t.Fatalf("formatHTML: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<h1>demo</h1>",
+		"run <b>run-1</b>",
+		"<b>1</b>passed", "<b>1</b>failed", "<b>1</b>errors",
+		"https://api.test/login",
+		"expected 200, got 500",
+		"timeout: deadline exceeded",
+		`width: 50%`, `width: 100%`,
+		"&#34;user&#34;: &#34;ada&#34;", // JSON bodies are indented
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in HTML output", want)
+		}
+	}
+}
+
+func TestFormatHTML_EscapesBodies(t *testing.T) {
+	var buf bytes.Buffer
+	if err := formatHTML(&buf, htmlTestRun(), ""); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "<script>") {
+		t.Error("response body must be HTML-escaped")
+	}
+}
+
+func TestFormatHTML_FailedRequestsStartExpanded(t *testing.T) {
+	var buf bytes.Buffer
+	if err := formatHTML(&buf, htmlTestRun(), ""); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `<details class="fail" open>`) || !strings.Contains(out, `<details class="error" open>`) {
+		t.Error("failed and errored requests should be expanded")
+	}
+	if strings.Contains(out, `<details class="pass" open>`) {
+		t.Error("passed requests should be collapsed")
+	}
+}
+
+func TestFormatHTML_SnapshotDiff(t *testing.T) {
+	run := htmlTestRun()
+	run.Results[1].Assertions = append(run.Results[1].Assertions, domain.AssertionResult{
+		Name: "snapshot", Key: "profile", Message: "body differs from snapshot",
+		Diff: []domain.DiffEntry{
+			{Path: "$.name", Kind: "changed", Old: `"ada"`, New: `"<b>"`},
+			{Path: "$.admin", Kind: "added", New: "true"},
+		},
+	})
+	var buf bytes.Buffer
+	if err := formatHTML(&buf, run, ""); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`<span class="changed">~ $.name: &#34;ada&#34; → &#34;&lt;b&gt;&#34;</span>`,
+		`<span class="added">&#43; $.admin: true</span>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+}
+
+func TestFormatHTML_SelfContained(t *testing.T) {
+	var buf bytes.Buffer
+	if err := formatHTML(&buf, htmlTestRun(), ""); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, external := range []string{"<script src", "<link ", "http://", "https://cdn"} {
+		if strings.Contains(out, external) {
+			t.Errorf("report should not reference external assets (%q)", external)
+		}
+	}
+}
+
+func TestHTMLBody_Binary(t *testing.T) {
+	if got := htmlBody([]byte{0xff, 0xfe, 0x00}); got != "(3 bytes of binary data)" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestWriteReport_HTML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.html")
+	if err := writeReport(reportSpec{Type: "html", Path: path}, htmlTestRun(), "run-1"); err != nil {
+		t.Fatalf("writeReport: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "<h1>demo</h1>") {
+		t.Errorf("unexpected report:\n%s", b)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
 	"github.com/aalvaropc/lynix/internal/domain"
@@ -21,15 +22,25 @@ type junitTestSuites struct {
 }
 
 type junitTestSuite struct {
-	XMLName   xml.Name        `xml:"testsuite"`
-	Name      string          `xml:"name,attr"`
-	Tests     int             `xml:"tests,attr"`
-	Failures  int             `xml:"failures,attr"`
-	Errors    int             `xml:"errors,attr"`
-	Time      string          `xml:"time,attr"`
-	Timestamp string          `xml:"timestamp,attr,omitempty"`
-	ID        string          `xml:"id,attr,omitempty"`
-	TestCases []junitTestCase `xml:"testcase"`
+	XMLName    xml.Name         `xml:"testsuite"`
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Errors     int              `xml:"errors,attr"`
+	Time       string           `xml:"time,attr"`
+	Timestamp  string           `xml:"timestamp,attr,omitempty"`
+	ID         string           `xml:"id,attr,omitempty"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	TestCases  []junitTestCase  `xml:"testcase"`
+}
+
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
 }
 
 type junitTestCase struct {
@@ -49,10 +60,7 @@ type junitDetail struct {
 }
 
 func formatJUnit(w io.Writer, run domain.RunResult, runID string) error {
-	duration := run.EndedAt.Sub(run.StartedAt)
-	if run.StartedAt.IsZero() || run.EndedAt.IsZero() {
-		duration = 0
-	}
+	duration := runDuration(run)
 
 	var totalFailures, totalErrors int
 	cases := make([]junitTestCase, 0, len(run.Results))
@@ -64,17 +72,7 @@ func formatJUnit(w io.Writer, run domain.RunResult, runID string) error {
 			Time:      fmt.Sprintf("%.3f", float64(r.LatencyMS)/1000),
 		}
 
-		var failMsgs []string
-		for _, a := range r.Assertions {
-			if !a.Passed {
-				failMsgs = append(failMsgs, fmt.Sprintf("[%s] %s", a.Name, a.Message))
-			}
-		}
-		for _, e := range r.Extracts {
-			if !e.Success {
-				failMsgs = append(failMsgs, fmt.Sprintf("[extract:%s] %s", e.Name, e.Message))
-			}
-		}
+		failMsgs := failureMessages(r)
 
 		// A case is either an error OR a failure, never both: some CI
 		// parsers reject reports where failures+errors exceeds tests.
@@ -123,6 +121,17 @@ func formatJUnit(w io.Writer, run domain.RunResult, runID string) error {
 		ID:        runID,
 		TestCases: cases,
 	}
+	if len(run.Metadata) > 0 {
+		keys := make([]string, 0, len(run.Metadata))
+		for k := range run.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		suite.Properties = &junitProperties{}
+		for _, k := range keys {
+			suite.Properties.Properties = append(suite.Properties.Properties, junitProperty{Name: k, Value: run.Metadata[k]})
+		}
+	}
 
 	root := junitTestSuites{
 		Tests:      suite.Tests,
@@ -298,3 +298,35 @@ func TestFormatJUnit_XMLHeader(t *testing.T) {
 		t.Error("output should start with XML declaration")
 	}
 }
+
+func TestFormatJUnit_MetadataProperties(t *testing.T) {
+	run := domain.RunResult{
+		CollectionName: "meta",
+		Metadata:       map[string]string{"git.commit": "abc123", "ci.provider": "github-actions"},
+	}
+
+	var buf bytes.Buffer
+	if err := formatJUnit(&buf, run, ""); err != nil {
+		t.Fatalf("formatJUnit error: %v", err)
+	}
+
+	var parsed junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	props := parsed.TestSuites[0].Properties
+	if props == nil || len(props.Properties) != 2 {
+		t.Fatalf("expected 2 properties, got %+v", props)
+	}
+	if p := props.Properties[0]; p.Name != "ci.provider" || p.Value != "github-actions" {
+		t.Errorf("properties should be sorted by name, got %+v", props.Properties)
+	}
+
+	buf.Reset()
+	if err := formatJUnit(&buf, domain.RunResult{CollectionName: "plain"}, ""); err != nil {
+		t.Fatalf("formatJUnit error: %v", err)
+	}
+	if strings.Contains(buf.String(), "<properties") {
+		t.Error("runs without metadata should have no properties element")
+	}
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// formatMarkdown writes a GitHub-flavored Markdown summary, sized for
+// $GITHUB_STEP_SUMMARY: a headline, a per-request table, and the failure
+// details in a collapsed section.
+func formatMarkdown(w io.Writer, run domain.RunResult, runID string) error {
+	passed, failed, errs := summarizeResults(run)
+
+	icon := "✅"
+	if failed+errs > 0 {
+		icon = "❌"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "### %s Lynix: %s", icon, mdEscape(run.CollectionName))
+	if run.EnvironmentName != "" {
+		fmt.Fprintf(&b, " (%s)", mdEscape(run.EnvironmentName))
+	}
+	b.WriteString("\n\n")
+	fmt.Fprintf(&b, "**%d requests** · %d passed · %d failed · %d errors · %s",
+		len(run.Results), passed, failed, errs, runDuration(run).Round(time.Millisecond))
+	if runID != "" {
+		fmt.Fprintf(&b, " · run `%s`", runID)
+	}
+	b.WriteString("\n\n")
+
+	if len(run.Results) > 0 {
+		b.WriteString("| | Request | Status | Time |\n")
+		b.WriteString("|---|---|---|---|\n")
+		for _, r := range run.Results {
+			mark := "✅"
+			switch {
+			case r.Error != nil:
+				mark = "⚠️"
+			case r.Failed():
+				mark = "❌"
+			}
+			status := "—"
+			if r.StatusCode != 0 {
+				status = fmt.Sprint(r.StatusCode)
+			}
+			fmt.Fprintf(&b, "| %s | `%s` %s | %s | %d ms |\n", mark, r.Method, mdEscape(r.Name), status, r.LatencyMS)
+		}
+		b.WriteString("\n")
+	}
+
+	if failed+errs > 0 {
+		b.WriteString("<details><summary>Failures</summary>\n\n")
+		for _, r := range run.Results {
+			if !r.Failed() {
+				continue
+			}
+			fmt.Fprintf(&b, "**%s %s**\n\n", r.Method, mdEscape(r.Name))
+			if r.Error != nil {
+				fmt.Fprintf(&b, "- %s: %s\n", r.Error.Kind, mdEscape(r.Error.Message))
+			}
+			for _, m := range failureMessages(r) {
+				fmt.Fprintf(&b, "- %s\n", mdEscape(m))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("</details>\n\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mdEscape keeps user text from breaking table cells or injecting markup.
+func mdEscape(s string) string {
+	return strings.NewReplacer(
+		"\n", " ", "\r", " ",
+		"|", `\|`, "<", "&lt;", ">", "&gt;",
+		"*", `\*`, "_", `\_`, "`", "\\`",
+	).Replace(s)
+}
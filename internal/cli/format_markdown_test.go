@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+func TestFormatMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := formatMarkdown(&buf, htmlTestRun(), "run-1"); err != nil {
+		t.Fatalf("formatMarkdown: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"### ❌ Lynix: demo (dev)",
+		"**3 requests** · 1 passed · 1 failed · 1 errors · 350ms · run `run-1`",
+		"| ✅ | `POST` login | 200 | 100 ms |",
+		"| ❌ | `GET` profile | 500 | 200 ms |",
+		"| ⚠️ | `GET` health | — | 0 ms |",
+		"<details><summary>Failures</summary>",
+		"- [status] expected 200, got 500",
+		"- timeout: deadline exceeded",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in Markdown output:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatMarkdown_AllPassedHasNoFailures(t *testing.T) {
+	run := domain.RunResult{
+		CollectionName: "demo",
+		Results:        []domain.RequestResult{{Name: "ok", Method: domain.MethodGet, StatusCode: 200}},
+	}
+	var buf bytes.Buffer
+	if err := formatMarkdown(&buf, run, ""); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "### ✅ Lynix: demo") || strings.Contains(buf.String(), "Failures") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestMDEscape(t *testing.T) {
+	if got := mdEscape("a|b <x>"); got != `a\|b &lt;x&gt;` {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// ndjsonObserver streams run progress as newline-delimited JSON, one event
+// per line, so dashboards and editor plugins can follow a run live. Every
+// result is redacted before it is written.
+type ndjsonObserver struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	redact func(domain.RequestResult) domain.RequestResult
+	now    func() time.Time
+}
+
+func newNDJSONObserver(w io.Writer, redact func(domain.RequestResult) domain.RequestResult) *ndjsonObserver {
+	if redact == nil {
+		redact = func(rr domain.RequestResult) domain.RequestResult { return rr }
+	}
+	return &ndjsonObserver{enc: json.NewEncoder(w), redact: redact, now: time.Now}
+}
+
+type ndjsonEvent struct {
+	Event string    `json:"event"`
+	Time  time.Time `json:"time"`
+
+	// run_started
+	Collection  string   `json:"collection,omitempty"`
+	Environment string   `json:"environment,omitempty"`
+	Requests    []string `json:"requests,omitempty"`
+
+	// request_started / request_finished
+	Index  *int                  `json:"index,omitempty"`
+	Name   string                `json:"name,omitempty"`
+	Method domain.HTTPMethod     `json:"method,omitempty"`
+	Result *domain.RequestResult `json:"result,omitempty"`
+
+	// run_finished
+	RunID   string         `json:"run_id,omitempty"`
+	Summary map[string]any `json:"summary,omitempty"`
+	Error   string         `json:"error,omitempty"`
+}
+
+func (o *ndjsonObserver) emit(ev ndjsonEvent) {
+	ev.Time = o.now().UTC()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	// A consumer that went away (closed pipe) must not abort the run.
+	_ = o.enc.Encode(ev)
+}
+
+func (o *ndjsonObserver) RunStarted(run domain.RunResult, requests []domain.RequestSpec) {
+	names := make([]string, 0, len(requests))
+	for _, r := range requests {
+		names = append(names, r.Name)
+	}
+	o.emit(ndjsonEvent{
+		Event:       "run_started",
+		Collection:  run.CollectionName,
+		Environment: run.EnvironmentName,
+		Requests:    names,
+	})
+}
+
+func (o *ndjsonObserver) RequestStarted(index int, req domain.RequestSpec) {
+	o.emit(ndjsonEvent{Event: "request_started", Index: &index, Name: req.Name, Method: req.Method})
+}
+
+func (o *ndjsonObserver) RequestFinished(index int, result domain.RequestResult) {
+	redacted := o.redact(result)
+	o.emit(ndjsonEvent{Event: "request_finished", Index: &index, Name: result.Name, Method: result.Method, Result: &redacted})
+}
+
+func (o *ndjsonObserver) RunFinished(run domain.RunResult, runID string, err error) {
+	passed, failed, errs := summarizeResults(run)
+	ev := ndjsonEvent{
+		Event: "run_finished",
+		RunID: runID,
+		Summary: map[string]any{
+			"total":       len(run.Results),
+			"passed":      passed,
+			"failed":      failed,
+			"errors":      errs,
+			"duration_ms": runDuration(run).Milliseconds(),
+		},
+	}
+	if err != nil {
+		ev.Error = err.Error()
+	}
+	o.emit(ev)
+}
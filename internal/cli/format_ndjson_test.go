@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+func TestNDJSONObserver_EmitsOneEventPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	redact := func(rr domain.RequestResult) domain.RequestResult {
+		rr.RequestHeaders = map[string]string{"Authorization": "***"}
+		return rr
+	}
+	obs := newNDJSONObserver(&buf, redact)
+	obs.now = func() time.Time { return time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC) }
+
+	reqs := []domain.RequestSpec{{Name: "login", Method: domain.MethodPost}}
+	run := htmlTestRun()
+	obs.RunStarted(run, reqs)
+	obs.RequestStarted(0, reqs[0])
+	obs.RequestFinished(0, domain.RequestResult{
+		Name:           "login",
+		Method:         domain.MethodPost,
+		StatusCode:     200,
+		RequestHeaders: map[string]string{"Authorization": "Bearer secret-token"},
+	})
+	obs.RunFinished(run, "run-1", errors.New("save failed"))
+
+	var events []map[string]any
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		var ev map[string]any
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			t.Fatalf("line is not JSON: %v\n%s", err, sc.Text())
+		}
+		events = append(events, ev)
+	}
+	if len(events) != 4 {
+		t.Fatalf("expected 4 events, got %d", len(events))
+	}
+
+	for i, want := range []string{"run_started", "request_started", "request_finished", "run_finished"} {
+		if events[i]["event"] != want {
+			t.Errorf("event %d: got %v, want %s", i, events[i]["event"], want)
+		}
+		if events[i]["time"] != "2024-06-01T12:00:00Z" {
+			t.Errorf("event %d: time %v", i, events[i]["time"])
+		}
+	}
+
+	if events[0]["collection"] != "demo" || len(events[0]["requests"].([]any)) != 1 {
+		t.Errorf("run_started: %v", events[0])
+	}
+	if events[1]["index"] != float64(0) || events[1]["name"] != "login" {
+		t.Errorf("request_started: %v", events[1])
+	}
+	result := events[2]["result"].(map[string]any)
+	if result["request_headers"].(map[string]any)["Authorization"] != "***" {
+		t.Errorf("request_finished result was not redacted: %v", result)
+	}
+	summary := events[3]["summary"].(map[string]any)
+	if events[3]["run_id"] != "run-1" || summary["total"] != float64(3) || summary["errors"] != float64(1) || events[3]["error"] != "save failed" {
+		t.Errorf("run_finished: %v", events[3])
+	}
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+func TestFormatSARIF(t *testing.T) {
+	diags := []diagnostic{
+		{Path: "c.yaml", Pos: domain.SourcePos{Line: 5, Column: 9}, Rule: "invalid-config", Message: "bad regex"},
+		{Path: "c.yaml", Rule: "assertion-failed", Message: "no line"},
+	}
+
+	var buf bytes.Buffer
+	if err := formatSARIF(&buf, diags); err != nil {
+		t.Fatalf("formatSARIF: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %+v", log)
+	}
+	run := log.Runs[0]
+	if rules := run.Tool.Driver.Rules; len(rules) != 2 || rules[0].ID != "assertion-failed" {
+		t.Errorf("rules should be sorted and deduplicated: %+v", rules)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(run.Results))
+	}
+	loc := run.Results[0].Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "c.yaml" || loc.Region == nil || loc.Region.StartLine != 5 || loc.Region.StartColumn != 9 {
+		t.Errorf("unexpected location: %+v", loc)
+	}
+	if run.Results[1].Locations[0].PhysicalLocation.Region != nil {
+		t.Error("a diagnostic without a line should have no region")
+	}
+}
+
+func TestFormatSARIF_NoDiagnostics(t *testing.T) {
+	var buf bytes.Buffer
+	if err := formatSARIF(&buf, nil); err != nil {
+		t.Fatalf("formatSARIF: %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if log.Runs[0].Results == nil || len(log.Runs[0].Results) != 0 {
+		t.Errorf("expected an empty results array, got %s", buf.String())
+	}
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// formatTAP writes TAP version 13: one test point per request, with a YAML
+// diagnostic block on failures. Strings in the block are JSON-quoted, which
+// is valid YAML and keeps messages with colons or newlines intact.
+func formatTAP(w io.Writer, run domain.RunResult, runID string) error {
+	var b strings.Builder
+	b.WriteString("TAP version 13\n")
+	fmt.Fprintf(&b, "1..%d\n", len(run.Results))
+	if run.CollectionName != "" {
+		fmt.Fprintf(&b, "# collection: %s\n", run.CollectionName)
+	}
+	if runID != "" {
+		fmt.Fprintf(&b, "# run: %s\n", runID)
+	}
+
+	for i, r := range run.Results {
+		status := "ok"
+		if r.Failed() {
+			status = "not ok"
+		}
+		fmt.Fprintf(&b, "%s %d - %s %s\n", status, i+1, r.Method, tapEscape(r.Name))
+		if !r.Failed() {
+			continue
+		}
+
+		msgs := failureMessages(r)
+		b.WriteString("  ---\n")
+		if r.Error != nil {
+			fmt.Fprintf(&b, "  message: %s\n", tapQuote(r.Error.Message))
+			b.WriteString("  severity: error\n")
+			fmt.Fprintf(&b, "  error_kind: %s\n", tapQuote(string(r.Error.Kind)))
+		} else {
+			fmt.Fprintf(&b, "  message: %s\n", tapQuote(fmt.Sprintf("%d check(s) failed", len(msgs))))
+			b.WriteString("  severity: fail\n")
+		}
+		if len(msgs) > 0 {
+			b.WriteString("  failures:\n")
+			for _, m := range msgs {
+				fmt.Fprintf(&b, "    - %s\n", tapQuote(m))
+			}
+		}
+		if r.StatusCode != 0 {
+			fmt.Fprintf(&b, "  status: %d\n", r.StatusCode)
+		}
+		fmt.Fprintf(&b, "  duration_ms: %d\n", r.LatencyMS)
+		b.WriteString("  ...\n")
+	}
+
+	passed, failed, errs := summarizeResults(run)
+	fmt.Fprintf(&b, "# pass %d\n# fail %d\n", passed, failed+errs)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// tapEscape keeps a description on one line; "#" would start a directive.
+func tapEscape(s string) string {
+	return strings.NewReplacer("\n", " ", "\r", " ", "#", `\#`).Replace(s)
+}
+
+func tapQuote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
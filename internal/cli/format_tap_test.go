@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFormatTAP(t *testing.T) {
+	var buf bytes.Buffer
+	if err := formatTAP(&buf, htmlTestRun(), "run-1"); err != nil {
+		t.Fatalf("formatTAP: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"TAP version 13\n1..3\n",
+		"ok 1 - POST login\n",
+		"not ok 2 - GET profile\n",
+		`    - "[status] expected 200, got 500"`,
+		"  status: 500\n",
+		"not ok 3 - GET health\n",
+		`  message: "deadline exceeded"`,
+		"  severity: error\n",
+		"# pass 1\n# fail 2\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in TAP output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "ok 1 - POST login\n  ---") {
+		t.Error("passing tests should not carry a diagnostic block")
+	}
+}
+
+func TestTAPEscape(t *testing.T) {
+	if got := tapEscape("a # b\nc"); got != `a \# b c` {
+		t.Errorf("got %q", got)
+	}
+}
@@ -3,12 +3,20 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/brunoparse"
 	"github.com/aalvaropc/lynix/internal/infra/curlparse"
+	"github.com/aalvaropc/lynix/internal/infra/insomniaparse"
 	"github.com/aalvaropc/lynix/internal/infra/postmanparse"
+	"github.com/aalvaropc/lynix/internal/infra/wiring"
 	"github.com/aalvaropc/lynix/internal/infra/yamlcollection"
+	"github.com/aalvaropc/lynix/internal/infra/yamlenv"
 )
 
 func importCmd() *cobra.Command {
@@ -19,22 +27,31 @@ func importCmd() *cobra.Command {
 
 	c.AddCommand(importCurlCmd())
 	c.AddCommand(importPostmanCmd())
+	c.AddCommand(importPostmanEnvCmd())
+	c.AddCommand(importInsomniaCmd())
+	c.AddCommand(importBrunoCmd())
 	return c
 }
 
 func importCurlCmd() *cobra.Command {
 	var (
-		output   string
-		fromFile string
-		name     string
+		output    string
+		fromFile  string
+		name      string
+		workspace string
+		force     bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   `curl "<command>"`,
-		Short: "Import a curl command into a Lynix collection",
-		Long:  "Parse a curl command and generate a Lynix YAML collection.\nPass the curl command as a positional argument or use --from-file.",
-		Args:  cobra.MaximumNArgs(1),
-		RunE: func(_ *cobra.Command, args []string) error {
+		Short: "Import curl commands into a Lynix collection",
+		Long: "Parse one or more curl commands and generate a Lynix YAML collection.\n" +
+			"Pass the command as a positional argument or use --from-file. A file may hold many\n" +
+			"commands (e.g. DevTools \"Copy all as cURL\"), separated by newlines or ';'.\n" +
+			"Auth values that repeat across commands become {{vars}} whose values go to\n" +
+			"secrets.local.yaml, not to the collection.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
 			var input string
 
 			switch {
@@ -50,7 +67,7 @@ func importCurlCmd() *cobra.Command {
 				return fmt.Errorf("provide a curl command as argument or use --from-file")
 			}
 
-			result, err := curlparse.Parse(input)
+			result, err := curlparse.ParseAll(input)
 			if err != nil {
 				return fmt.Errorf("parse curl: %w", err)
 			}
@@ -59,6 +76,17 @@ func importCurlCmd() *cobra.Command {
 				result.Collection.Name = name
 			}
 
+			// Secrets first, so a refused overwrite leaves nothing written.
+			if len(result.Secrets) > 0 {
+				ws, err := loadWorkspaceOrStandalone(cmd.Flags().Changed("workspace"), workspace, wiring.Opts{})
+				if err != nil {
+					return err
+				}
+				if err := writeImportedSecrets(ws, result.Secrets, force); err != nil {
+					return err
+				}
+			}
+
 			b, err := yamlcollection.MarshalCollection(result.Collection)
 			if err != nil {
 				return fmt.Errorf("marshal collection: %w", err)
@@ -87,6 +115,8 @@ func importCurlCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&output, "output", "o", "", "Write YAML to file instead of stdout")
 	cmd.Flags().StringVar(&fromFile, "from-file", "", "Read curl command from a file")
 	cmd.Flags().StringVar(&name, "name", "", "Override collection name")
+	cmd.Flags().StringVarP(&workspace, "workspace", "w", "", "Workspace root for secrets.local.yaml (optional; autodetected if omitted)")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite conflicting secrets")
 	return cmd
 }
 
@@ -142,3 +172,378 @@ func importPostmanCmd() *cobra.Command {
 	cmd.Flags().StringVar(&name, "name", "", "Override collection name")
 	return cmd
 }
+
+func importPostmanEnvCmd() *cobra.Command {
+	var (
+		workspace string
+		name      string
+		force     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "postman-env <file.json>",
+		Short: "Import a Postman environment or globals export into env/<name>.yaml",
+		Long: "Write a Postman environment export as a Lynix env file.\n" +
+			"Values marked secret in Postman, or whose key looks sensitive (token, password,\n" +
+			"api_key, ...), go to secrets.local.yaml instead of the committed env file.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("open postman environment: %w", err)
+			}
+			defer f.Close()
+
+			result, err := postmanparse.ParseEnvironment(f)
+			if err != nil {
+				return fmt.Errorf("parse postman environment: %w", err)
+			}
+
+			envName := name
+			if envName == "" {
+				envName = yamlenv.NameFromTitle(result.Name)
+			}
+			if envName == "" && result.Globals {
+				envName = "globals"
+			}
+			if envName == "" {
+				return fmt.Errorf("cannot derive an environment name from %s; use --name", args[0])
+			}
+
+			ws, err := loadWorkspaceOrStandalone(cmd.Flags().Changed("workspace"), workspace, wiring.Opts{})
+			if err != nil {
+				return err
+			}
+			env := importedEnv{name: envName, vars: result.Vars, secret: result.Secret}
+			if _, err := writeImportedEnvs(ws, nil, []importedEnv{env}, force); err != nil {
+				return err
+			}
+
+			for _, w := range result.Warnings {
+				fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+			}
+			if result.Globals {
+				fmt.Fprintf(os.Stderr, "note: Postman globals apply to every environment; they were written as env %q\n", envName)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&workspace, "workspace", "w", "", "Workspace root (optional; autodetected if omitted)")
+	cmd.Flags().StringVar(&name, "name", "", "Environment name (default: derived from the Postman environment name)")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing env file and conflicting secrets")
+	return cmd
+}
+
+func importInsomniaCmd() *cobra.Command {
+	var opts workspaceImportOpts
+
+	cmd := &cobra.Command{
+		Use:   "insomnia <export.json|export.yaml>",
+		Short: "Import an Insomnia v4 export into a Lynix collection",
+		Long: "Convert the first workspace of an Insomnia v4 export (JSON or YAML).\n" +
+			"The base environment becomes collection vars, except secret-looking values, which go\n" +
+			"to secrets.local.yaml; sub-environments are written to env/<name>.yaml unless\n" +
+			"--no-envs is set.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("open insomnia export: %w", err)
+			}
+			defer f.Close()
+
+			result, err := insomniaparse.Parse(f)
+			if err != nil {
+				return fmt.Errorf("parse insomnia: %w", err)
+			}
+
+			envs := make([]importedEnv, 0, len(result.Environments))
+			for _, e := range result.Environments {
+				envs = append(envs, importedEnv{name: e.Name, vars: e.Vars, secret: e.Secret})
+			}
+			opts.workspaceChanged = cmd.Flags().Changed("workspace")
+			opts.baseEnv = true
+			return finishWorkspaceImport(result.Collection, envs, result.Warnings, opts)
+		},
+	}
+
+	addWorkspaceImportFlags(cmd, &opts)
+	return cmd
+}
+
+func importBrunoCmd() *cobra.Command {
+	var opts workspaceImportOpts
+
+	cmd := &cobra.Command{
+		Use:   "bruno <collection-dir>",
+		Short: "Import a Bruno collection directory into a Lynix collection",
+		Long: "Convert a Bruno collection (bruno.json and .bru files). Folders become name\n" +
+			"prefixes and tags, assert blocks become status/jsonpath/header assertions, and\n" +
+			"environments/*.bru are written to env/<name>.yaml unless --no-envs is set.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := brunoparse.Parse(os.DirFS(args[0]))
+			if err != nil {
+				return fmt.Errorf("parse bruno: %w", err)
+			}
+
+			envs := make([]importedEnv, 0, len(result.Environments))
+			for _, e := range result.Environments {
+				envs = append(envs, importedEnv{name: e.Name, vars: e.Vars, secret: e.Secret})
+			}
+			opts.workspaceChanged = cmd.Flags().Changed("workspace")
+			return finishWorkspaceImport(result.Collection, envs, result.Warnings, opts)
+		},
+	}
+
+	addWorkspaceImportFlags(cmd, &opts)
+	return cmd
+}
+
+// workspaceImportOpts are the flags shared by importers that bring
+// environments along with the collection.
+type workspaceImportOpts struct {
+	output           string
+	name             string
+	workspace        string
+	workspaceChanged bool
+	force            bool
+	noEnvs           bool
+	// baseEnv marks the collection vars as coming from the source's base
+	// environment, so secret-looking values are moved to the secrets file.
+	baseEnv bool
+}
+
+func addWorkspaceImportFlags(cmd *cobra.Command, opts *workspaceImportOpts) {
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "Write YAML to file instead of stdout")
+	cmd.Flags().StringVar(&opts.name, "name", "", "Override collection name")
+	cmd.Flags().StringVarP(&opts.workspace, "workspace", "w", "", "Workspace root for env files (optional; autodetected if omitted)")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Overwrite existing env files and conflicting secrets")
+	cmd.Flags().BoolVar(&opts.noEnvs, "no-envs", false, "Do not write environments")
+}
+
+// finishWorkspaceImport writes the environments first, so a refused
+// overwrite leaves nothing half-imported, then emits the collection and the
+// importer's warnings.
+func finishWorkspaceImport(col domain.Collection, envs []importedEnv, warnings []string, opts workspaceImportOpts) error {
+	if opts.name != "" {
+		col.Name = opts.name
+	}
+
+	var named []importedEnv
+	taken := map[string]string{}
+	for _, e := range envs {
+		n := yamlenv.NameFromTitle(e.name)
+		if n == "" {
+			warnings = append(warnings, fmt.Sprintf("environment %q has no usable file name and was skipped", e.name))
+			continue
+		}
+		if prev, ok := taken[n]; ok {
+			warnings = append(warnings, fmt.Sprintf("environment %q maps to the same file as %q and was skipped", e.name, prev))
+			continue
+		}
+		taken[n] = e.name
+		e.name = n
+		named = append(named, e)
+	}
+
+	if opts.noEnvs {
+		named = nil
+	}
+	var base domain.Vars
+	if opts.baseEnv {
+		base = col.Vars
+	}
+
+	if len(named) > 0 || len(base) > 0 {
+		ws, err := loadWorkspaceOrStandalone(opts.workspaceChanged, opts.workspace, wiring.Opts{})
+		if err != nil {
+			return err
+		}
+		public, err := writeImportedEnvs(ws, base, named, opts.force)
+		if err != nil {
+			return err
+		}
+		if opts.baseEnv {
+			col.Vars = public
+			if len(col.Vars) == 0 {
+				col.Vars = nil
+			}
+		}
+	}
+
+	b, err := yamlcollection.MarshalCollection(col)
+	if err != nil {
+		return fmt.Errorf("marshal collection: %w", err)
+	}
+	if opts.output != "" {
+		if err := os.WriteFile(opts.output, b, 0o644); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Collection written to %s\n", opts.output)
+	} else {
+		fmt.Print(string(b))
+	}
+
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	return nil
+}
+
+// importedEnv is an environment produced by an importer, before its vars
+// are split between the env file and the secrets file.
+type importedEnv struct {
+	name   string
+	vars   domain.Vars
+	secret map[string]bool // keys the source tool marks as secret
+}
+
+// writeImportedEnvs writes each env to <env_dir>/<name>.yaml and merges its
+// secret values into the shared secrets file, reporting which keys went
+// where. base holds collection vars taken from a base environment: its
+// secret-looking values join the secrets file too, and the rest is returned
+// to stay in the collection. Every target is checked before anything is
+// written, so a refusal leaves the workspace untouched.
+func writeImportedEnvs(ws *workspaceCtx, base domain.Vars, envs []importedEnv, force bool) (domain.Vars, error) {
+	loader := yamlenv.NewLoader(ws.root, yamlenv.WithEnvDir(ws.cfg.Paths.EnvironmentsDir))
+	secretsPath := relPath(ws.root, loader.SecretsPath())
+
+	secrets, err := loader.ReadSecrets()
+	if err != nil {
+		return nil, err
+	}
+
+	basePlan := planEnvImport(base, nil, secrets, ws.redactor.IsKeySensitive)
+	if len(basePlan.conflicts) > 0 && !force {
+		return nil, fmt.Errorf("%s already defines %s with a different value (use --force to overwrite)",
+			secretsPath, strings.Join(basePlan.conflicts, ", "))
+	}
+	secrets = basePlan.secrets
+
+	plans := make([]envImportPlan, len(envs))
+	for i, env := range envs {
+		envPath := loader.EnvPath(env.name)
+		if fileExists(envPath) && !force {
+			return nil, fmt.Errorf("%s already exists (use --force to overwrite)", relPath(ws.root, envPath))
+		}
+		// Plan against the secrets merged so far: two imported envs that
+		// disagree on a secret collide in the shared file too.
+		plans[i] = planEnvImport(env.vars, env.secret, secrets, ws.redactor.IsKeySensitive)
+		if len(plans[i].conflicts) > 0 && !force {
+			return nil, fmt.Errorf("%s already defines %s with a different value (use --force to overwrite)",
+				secretsPath, strings.Join(plans[i].conflicts, ", "))
+		}
+		secrets = plans[i].secrets
+	}
+
+	wroteSecrets := false
+	if len(basePlan.secret) > 0 {
+		fmt.Fprintf(os.Stderr, "Secrets from the base environment go to %s (%s)\n", secretsPath, describeKeys(basePlan.secret))
+		wroteSecrets = true
+	}
+	for i, env := range envs {
+		if err := loader.WriteEnvironment(env.name, plans[i].public); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "Environment written to %s (%s)\n", relPath(ws.root, loader.EnvPath(env.name)), describeKeys(plans[i].public))
+		if len(plans[i].secret) > 0 {
+			fmt.Fprintf(os.Stderr, "  secrets for %q go to %s (%s)\n", env.name, secretsPath, describeKeys(plans[i].secret))
+			wroteSecrets = true
+		}
+	}
+
+	if wroteSecrets {
+		if err := loader.WriteSecrets(secrets); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "note: %s is shared by every env in the directory; keep it out of version control\n", filepath.Base(secretsPath))
+	}
+	return basePlan.public, nil
+}
+
+// writeImportedSecrets merges values an importer kept out of the collection
+// into the shared secrets file. A key already there with a different value
+// is only replaced with force.
+func writeImportedSecrets(ws *workspaceCtx, vars domain.Vars, force bool) error {
+	loader := yamlenv.NewLoader(ws.root, yamlenv.WithEnvDir(ws.cfg.Paths.EnvironmentsDir))
+	secretsPath := relPath(ws.root, loader.SecretsPath())
+
+	existing, err := loader.ReadSecrets()
+	if err != nil {
+		return err
+	}
+	all := make(map[string]bool, len(vars))
+	for k := range vars {
+		all[k] = true
+	}
+	plan := planEnvImport(vars, all, existing, func(string) bool { return true })
+	if len(plan.conflicts) > 0 && !force {
+		return fmt.Errorf("%s already defines %s with a different value (use --force to overwrite)",
+			secretsPath, strings.Join(plan.conflicts, ", "))
+	}
+	if err := loader.WriteSecrets(plan.secrets); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Secrets written to %s (%s); keep it out of version control\n", secretsPath, describeKeys(plan.secret))
+	return nil
+}
+
+// envImportPlan splits imported vars between the committed env file and the
+// shared secrets file.
+type envImportPlan struct {
+	public    domain.Vars // written to env/<name>.yaml
+	secret    domain.Vars // imported keys routed to the secrets file
+	secrets   domain.Vars // full secrets file contents after the merge
+	conflicts []string    // secret keys already present with a different value
+}
+
+func planEnvImport(vars domain.Vars, marked map[string]bool, existing domain.Vars, isSensitive func(string) bool) envImportPlan {
+	plan := envImportPlan{
+		public:  domain.Vars{},
+		secret:  domain.Vars{},
+		secrets: domain.Vars{},
+	}
+	for k, v := range existing {
+		plan.secrets[k] = v
+	}
+
+	for k, v := range vars {
+		if !marked[k] && !isSensitive(k) {
+			plan.public[k] = v
+			continue
+		}
+		plan.secret[k] = v
+		if prev, ok := existing[k]; ok && prev != v {
+			plan.conflicts = append(plan.conflicts, k)
+		}
+		plan.secrets[k] = v
+	}
+	sort.Strings(plan.conflicts)
+	return plan
+}
+
+// describeKeys renders "2 vars: a, b" for the import report.
+func describeKeys(vars domain.Vars) string {
+	if len(vars) == 0 {
+		return "no vars"
+	}
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	unit := "vars"
+	if len(keys) == 1 {
+		unit = "var"
+	}
+	return fmt.Sprintf("%d %s: %s", len(keys), unit, strings.Join(keys, ", "))
+}
+
+func relPath(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
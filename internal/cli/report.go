@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// reporter renders a finished run into a report file. Reporters always
+// receive the redacted run: report files are CI artifacts and must never
+// carry secrets.
+type reporter interface {
+	Report(w io.Writer, run domain.RunResult, runID string) error
+}
+
+type reporterFunc func(w io.Writer, run domain.RunResult, runID string) error
+
+func (f reporterFunc) Report(w io.Writer, run domain.RunResult, runID string) error {
+	return f(w, run, runID)
+}
+
+// reportTypes lists the --report types in the order shown in help and errors.
+var reportTypes = []string{"junit", "html", "tap", "ctrf", "markdown"}
+
+var reporters = map[string]reporter{
+	"junit":    reporterFunc(formatJUnit),
+	"html":     reporterFunc(formatHTML),
+	"tap":      reporterFunc(formatTAP),
+	"ctrf":     reporterFunc(formatCTRF),
+	"markdown": reporterFunc(formatMarkdown),
+}
+
+// reportSpec is one --report destination.
+type reportSpec struct {
+	Type string
+	Path string
+}
+
+// parseReportFlags turns the repeatable --report values ("type:path") into
+// report specs. The older "--report type --report-path file" form is still
+// accepted: --report-path supplies the path of the one --report without one.
+func parseReportFlags(reports []string, reportPath string) ([]reportSpec, error) {
+	if len(reports) == 0 {
+		if reportPath != "" {
+			return nil, fmt.Errorf("--report is required when --report-path is set")
+		}
+		return nil, nil
+	}
+	if reportPath != "" {
+		bare := 0
+		for _, raw := range reports {
+			if !strings.Contains(raw, ":") {
+				bare++
+			}
+		}
+		switch {
+		case bare == 0:
+			return nil, fmt.Errorf("--report-path is set but every --report already names a path")
+		case bare > 1:
+			return nil, fmt.Errorf("--report-path only applies to a single --report; use --report type:path for each report")
+		}
+	}
+
+	specs := make([]reportSpec, 0, len(reports))
+	byPath := map[string]string{}
+	for _, raw := range reports {
+		typ, path, hasPath := strings.Cut(strings.TrimSpace(raw), ":")
+		if _, ok := reporters[typ]; !ok {
+			return nil, fmt.Errorf("unsupported report type %q (expected %s)", typ, strings.Join(reportTypes, "|"))
+		}
+		if !hasPath {
+			path = reportPath
+		}
+		if path == "" {
+			return nil, fmt.Errorf("--report %s needs a path: use --report %s:PATH", typ, typ)
+		}
+		if other, dup := byPath[path]; dup {
+			return nil, fmt.Errorf("--report %s and --report %s both write to %q", other, typ, path)
+		}
+		byPath[path] = typ
+		specs = append(specs, reportSpec{Type: typ, Path: path})
+	}
+	return specs, nil
+}
+
+// writeReports writes every requested report, stopping at the first error.
+func writeReports(specs []reportSpec, run domain.RunResult, runID string) error {
+	for _, s := range specs {
+		if err := writeReport(s, run, runID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeReport(spec reportSpec, run domain.RunResult, runID string) error {
+	f, err := createReportFile(spec.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := reporters[spec.Type].Report(f, run, runID); err != nil {
+		return fmt.Errorf("failed to write %s report %q: %w", spec.Type, spec.Path, err)
+	}
+	return nil
+}
+
+// createReportFile truncates the target, except for the GitHub step summary:
+// the workflow step may already have written to it, so reports append.
+func createReportFile(path string) (*os.File, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if summary := os.Getenv("GITHUB_STEP_SUMMARY"); summary != "" && path == summary {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	f, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create report file %q: %w", path, err)
+	}
+	return f, nil
+}
+
+// failureMessages lists a request's failed assertions and extracts, one line
+// each, as shown by the text-based reports.
+func failureMessages(r domain.RequestResult) []string {
+	var out []string
+	for _, a := range r.Assertions {
+		if !a.Passed {
+			out = append(out, fmt.Sprintf("[%s] %s", a.Name, a.Message))
+		}
+	}
+	for _, e := range r.Extracts {
+		if !e.Success {
+			out = append(out, fmt.Sprintf("[extract:%s] %s", e.Name, e.Message))
+		}
+	}
+	return out
+}
+
+// runDuration is the wall time of a run, or zero when either end is unknown.
+func runDuration(run domain.RunResult) time.Duration {
+	if run.StartedAt.IsZero() || run.EndedAt.IsZero() {
+		return 0
+	}
+	return run.EndedAt.Sub(run.StartedAt)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/xml"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -34,8 +35,8 @@ func TestWriteJUnitReport_WritesValidXML(t *testing.T) {
 		},
 	}
 
-	if err := writeJUnitReport(path, run, "run-123"); err != nil {
-		t.Fatalf("writeJUnitReport returned error: %v", err)
+	if err := writeReport(reportSpec{Type: "junit", Path: path}, run, "run-123"); err != nil {
+		t.Fatalf("writeReport returned error: %v", err)
 	}
 
 	data, err := os.ReadFile(path)
@@ -58,56 +59,100 @@ func TestWriteJUnitReport_WritesValidXML(t *testing.T) {
 
 func TestWriteJUnitReport_InvalidPath(t *testing.T) {
 	run := domain.RunResult{CollectionName: "demo"}
-	err := writeJUnitReport("/nonexistent/dir/report.xml", run, "")
+	err := writeReport(reportSpec{Type: "junit", Path: "/nonexistent/dir/report.xml"}, run, "")
 	if err == nil {
 		t.Fatal("expected error for invalid path, got nil")
 	}
 }
 
-func TestValidateReportFlags(t *testing.T) {
+func TestParseReportFlags(t *testing.T) {
 	tests := []struct {
 		name       string
-		report     string
+		reports    []string
 		reportPath string
+		want       []reportSpec
 		wantErr    string
 	}{
 		{
-			name:       "both empty is ok",
-			report:     "",
-			reportPath: "",
+			name: "no reports is ok",
 		},
 		{
-			name:       "both set with junit is ok",
-			report:     "junit",
+			name:       "legacy junit with report-path",
+			reports:    []string{"junit"},
 			reportPath: "results.xml",
+			want:       []reportSpec{{Type: "junit", Path: "results.xml"}},
 		},
 		{
-			name:       "report without path",
-			report:     "junit",
-			reportPath: "",
-			wantErr:    "--report-path is required when --report is set",
+			name:    "type:path is repeatable",
+			reports: []string{"junit:results.xml", "tap:results.tap", "ctrf:ctrf.json", "markdown:summary.md", "html:report.html"},
+			want: []reportSpec{
+				{Type: "junit", Path: "results.xml"},
+				{Type: "tap", Path: "results.tap"},
+				{Type: "ctrf", Path: "ctrf.json"},
+				{Type: "markdown", Path: "summary.md"},
+				{Type: "html", Path: "report.html"},
+			},
+		},
+		{
+			name:    "path may contain colons",
+			reports: []string{`junit:C:\ci\results.xml`},
+			want:    []reportSpec{{Type: "junit", Path: `C:\ci\results.xml`}},
+		},
+		{
+			name:    "report without path",
+			reports: []string{"junit"},
+			wantErr: "--report junit needs a path: use --report junit:PATH",
+		},
+		{
+			name:    "empty path after colon",
+			reports: []string{"tap:"},
+			wantErr: "--report tap needs a path: use --report tap:PATH",
 		},
 		{
 			name:       "path without report",
-			report:     "",
 			reportPath: "results.xml",
 			wantErr:    "--report is required when --report-path is set",
 		},
 		{
-			name:       "unsupported report type",
-			report:     "csv",
-			reportPath: "results.csv",
-			wantErr:    `unsupported report type "csv" (expected "junit")`,
+			name:       "report-path with several reports",
+			reports:    []string{"junit", "tap"},
+			reportPath: "results.xml",
+			wantErr:    "--report-path only applies to a single --report; use --report type:path for each report",
+		},
+		{
+			name:       "report-path fills the one bare report",
+			reports:    []string{"junit", "markdown:summary.md"},
+			reportPath: "results.xml",
+			want:       []reportSpec{{Type: "junit", Path: "results.xml"}, {Type: "markdown", Path: "summary.md"}},
+		},
+		{
+			name:       "report-path with no bare report",
+			reports:    []string{"junit:a.xml"},
+			reportPath: "b.xml",
+			wantErr:    "--report-path is set but every --report already names a path",
+		},
+		{
+			name:    "two reports to one file",
+			reports: []string{"junit:out", "tap:out"},
+			wantErr: `--report junit and --report tap both write to "out"`,
+		},
+		{
+			name:    "unsupported report type",
+			reports: []string{"csv:results.csv"},
+			wantErr: `unsupported report type "csv" (expected junit|html|tap|ctrf|markdown)`,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateReportFlags(tt.report, tt.reportPath)
+			got, err := parseReportFlags(tt.reports, tt.reportPath)
 			if tt.wantErr == "" {
 				if err != nil {
 					t.Fatalf("unexpected error: %v", err)
 				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("specs = %+v, want %+v", got, tt.want)
+				}
 				return
 			}
 			if err == nil {
@@ -120,6 +165,42 @@ func TestValidateReportFlags(t *testing.T) {
 	}
 }
 
+func TestWriteReports_AllTypes(t *testing.T) {
+	dir := t.TempDir()
+	var specs []reportSpec
+	for _, typ := range reportTypes {
+		specs = append(specs, reportSpec{Type: typ, Path: filepath.Join(dir, "report."+typ)})
+	}
+	if err := writeReports(specs, htmlTestRun(), "run-1"); err != nil {
+		t.Fatalf("writeReports: %v", err)
+	}
+	for _, s := range specs {
+		b, err := os.ReadFile(s.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(b), "profile") {
+			t.Errorf("%s report does not mention the failed request:\n%s", s.Type, b)
+		}
+	}
+}
+
+func TestWriteReport_AppendsToStepSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.md")
+	if err := os.WriteFile(path, []byte("earlier output\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_STEP_SUMMARY", path)
+
+	if err := writeReport(reportSpec{Type: "markdown", Path: path}, htmlTestRun(), ""); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(b), "earlier output\n") || !strings.Contains(string(b), "Lynix: demo") {
+		t.Errorf("expected summary appended, got:\n%s", b)
+	}
+}
+
 func TestFormatJUnit_ErrorAndFailureNotDoubleCounted(t *testing.T) {
 	run := domain.RunResult{
 		CollectionName: "col",
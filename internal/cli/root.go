@@ -34,6 +34,7 @@ func newRootCmd() *cobra.Command {
 	cmd.AddCommand(collectionsCmd())
 	cmd.AddCommand(envsCmd())
 	cmd.AddCommand(importCmd())
+	cmd.AddCommand(exportCmd())
 	cmd.AddCommand(runsCmd())
 
 	return cmd
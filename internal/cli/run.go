@@ -5,13 +5,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"sort"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/oteltrace"
+	"github.com/aalvaropc/lynix/internal/infra/runmeta"
 	"github.com/aalvaropc/lynix/internal/infra/wiring"
+	"github.com/aalvaropc/lynix/internal/ports"
 	"github.com/aalvaropc/lynix/internal/usecase"
 	"github.com/spf13/cobra"
 )
@@ -22,7 +27,7 @@ func runCmd() *cobra.Command {
 	var env string
 	var noSave bool
 	var format string
-	var report string
+	var reports []string
 	var reportPath string
 	var failFast bool
 	var only string
@@ -37,19 +42,49 @@ func runCmd() *cobra.Command {
 	var varFlags []string
 	var quiet bool
 	var noColor bool
+	var otelEndpoint string
+	var labelFlags []string
+	var baselineRef string
+	var budgetPct float64
+	var budgetMS int
+	var updateSnapshots bool
 
 	c := &cobra.Command{
 		Use:   "run",
 		Short: "Run a collection (functional checks) from a Lynix workspace",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			if err := validateReportFlags(report, reportPath); err != nil {
+			reportSpecs, err := parseReportFlags(reports, reportPath)
+			if err != nil {
 				return err
 			}
+			streaming := format == "ndjson"
+			if streaming && dryRun {
+				return fmt.Errorf("--format ndjson cannot be combined with --dry-run")
+			}
+			// github is the pretty output followed by workflow annotations.
+			printFormat := format
+			if format == "github" {
+				printFormat = "pretty"
+			}
 
 			cliVars, err := parseVarFlags(varFlags)
 			if err != nil {
 				return err
 			}
+			labels, err := parseLabelFlags("label", labelFlags)
+			if err != nil {
+				return err
+			}
+			if baselineRef != "" && dryRun {
+				return fmt.Errorf("--baseline cannot be combined with --dry-run")
+			}
+			if budgetPct < 0 || budgetMS < 0 {
+				return fmt.Errorf("latency budgets must be >= 0")
+			}
+			budget := latencyBudget{pct: budgetPct, ms: int64(budgetMS)}
+			if budget.enabled() && baselineRef == "" {
+				return fmt.Errorf("--latency-budget-pct and --latency-budget-ms require --baseline")
+			}
 
 			wiringOpts := wiring.Opts{
 				Insecure:          insecure,
@@ -84,6 +119,17 @@ func runCmd() *cobra.Command {
 				ws.redactor.AddSecretsFromVars(cliVars)
 			}
 
+			// Resolved before executing: with "last", the run about to be
+			// saved must not become its own baseline.
+			var baseline *domain.RunResult
+			var baselineID string
+			if !dryRun {
+				baseline, baselineID, err = resolveBaseline(ws, baselineRef, collectionPath, envArg)
+				if err != nil {
+					return err
+				}
+			}
+
 			var store = ws.store
 			if noSave || dryRun {
 				store = nil
@@ -99,6 +145,10 @@ func runCmd() *cobra.Command {
 				DryRun:     dryRun,
 				Parallel:   parallel,
 				Vars:       cliVars,
+				Baseline:   baseline,
+
+				Snapshots:       ws.snapshots,
+				UpdateSnapshots: updateSnapshots,
 			}
 			if cmd.Flags().Changed("retries") {
 				retryOpts.Retries = retries
@@ -109,8 +159,38 @@ func runCmd() *cobra.Command {
 			if cmd.Flags().Changed("retry-5xx") {
 				retryOpts.Retry5xx = retry5xx
 			}
+			if !dryRun {
+				// Labels win over collected keys of the same name.
+				retryOpts.Metadata = runmeta.New().Collect(cmd.Context(), ws.root)
+				maps.Copy(retryOpts.Metadata, labels)
+			}
+			var observers []ports.RunObserver
+			// ndjson events go out as the run progresses, so each result is
+			// redacted on its own instead of waiting for the whole run.
+			if streaming {
+				var redact func(domain.RequestResult) domain.RequestResult
+				if ws.redactor != nil {
+					redact = ws.redactor.RedactResult
+				}
+				observers = append(observers, newNDJSONObserver(os.Stdout, redact))
+			}
 
-			uc := usecase.NewRunCollection(ws.collections, ws.envs, ws.runner, store, retryOpts)
+			runner := ws.runner
+			if !cmd.Flags().Changed("otel-endpoint") {
+				otelEndpoint = ws.cfg.Run.OTel.Endpoint
+			}
+			var tracer *oteltrace.Tracer
+			if otelEndpoint != "" && !dryRun {
+				tracer, err = newRunTracer(ws, otelEndpoint)
+				if err != nil {
+					return err
+				}
+				runner = tracer.WrapRunner(runner)
+				observers = append(observers, tracer)
+			}
+			retryOpts.Observer = combineObservers(observers...)
+
+			uc := usecase.NewRunCollection(ws.collections, ws.envs, runner, store, retryOpts)
 
 			// run.timeout_seconds bounds the whole run (parity with the
 			// documented behavior; it was previously ignored by the CLI).
@@ -127,6 +207,9 @@ func runCmd() *cobra.Command {
 			}
 
 			run, runID, err := uc.Execute(ctx, collectionPath, envArg)
+			if tracer != nil {
+				flushTrace(cmd.Context(), os.Stderr, tracer)
+			}
 
 			// Redact BEFORE any output decision: the error path (global
 			// timeout, cancellation, failed save) returns a partial run and
@@ -147,10 +230,14 @@ func runCmd() *cobra.Command {
 				if len(run.Results) > 0 || !run.StartedAt.IsZero() {
 					if dryRun {
 						_ = printDryRun(os.Stdout, run)
-					} else {
-						_ = printRun(os.Stdout, display, runID, format, pretty)
+					} else if !streaming {
+						_ = printRun(os.Stdout, display, runID, printFormat, pretty)
 					}
 				}
+				if format == "github" {
+					diags := runDiagnostics(redacted, requestSpecs(ws, collectionPath))
+					_ = writeGitHubAnnotations(os.Stdout, append(diags, errorDiagnostic(err, collectionPath)))
+				}
 				return err
 			}
 
@@ -167,23 +254,40 @@ func runCmd() *cobra.Command {
 				}
 			}
 
-			if err := printRun(os.Stdout, display, runID, format, pretty); err != nil {
-				return err
+			if !streaming {
+				if err := printRun(os.Stdout, display, runID, printFormat, pretty); err != nil {
+					return err
+				}
+			}
+			if format == "github" {
+				if err := writeGitHubAnnotations(os.Stdout, runDiagnostics(redacted, requestSpecs(ws, collectionPath))); err != nil {
+					return err
+				}
 			}
 
 			// When stdout is redirected (`> report.txt`) but stderr is still a
 			// terminal, echo the one-line summary there. Requiring a stderr
 			// TTY keeps CI logs free of a duplicated summary line.
-			if !isTerminal(os.Stdout) && isTerminal(os.Stderr) && format != "json" {
+			if !isTerminal(os.Stdout) && isTerminal(os.Stderr) && format != "json" && !streaming {
 				total := display.EndedAt.Sub(display.StartedAt)
 				fmt.Fprint(os.Stderr, summaryLine(display, total, palette{}))
 			}
 
-			// The JUnit file is a CI artifact: always redact it, regardless
+			// Report files are CI artifacts: always redact them, regardless
 			// of the CLI-output masking preference.
-			if report == "junit" {
-				if err := writeJUnitReport(reportPath, redacted, runID); err != nil {
-					return err
+			if err := writeReports(reportSpecs, redacted, runID); err != nil {
+				return err
+			}
+
+			var regressions []requestRegression
+			if baselineRef != "" && baseline != nil {
+				regressions = findRegressions(*baseline, run, budget)
+				// Keep stdout machine-readable for json and ndjson.
+				if printFormat == "pretty" && !streaming {
+					fmt.Fprintln(os.Stdout)
+					printRegressions(os.Stdout, baselineID, regressions, pretty.colors)
+				} else {
+					printRegressions(os.Stderr, baselineID, regressions, palette{})
 				}
 			}
 
@@ -201,6 +305,9 @@ func runCmd() *cobra.Command {
 				}
 				return &codedError{code: code, err: fmt.Errorf("run failed (%d failed request(s))", fails)}
 			}
+			if len(regressions) > 0 {
+				return &codedError{code: exitAssertFailed, err: fmt.Errorf("run regressed against baseline %s (%d request(s))", baselineID, len(regressions))}
+			}
 			return nil
 		},
 	}
@@ -209,9 +316,9 @@ func runCmd() *cobra.Command {
 	c.Flags().StringVarP(&collection, "collection", "c", "", "Collection name or path (required)")
 	c.Flags().StringVarP(&env, "env", "e", "", "Environment name or path (optional; defaults to workspace default env)")
 	c.Flags().BoolVar(&noSave, "no-save", false, "Do not save run artifact under runs/")
-	c.Flags().StringVar(&format, "format", "pretty", "Output format: pretty|json")
-	c.Flags().StringVar(&report, "report", "", "Report type to generate (currently only \"junit\")")
-	c.Flags().StringVar(&reportPath, "report-path", "", "File path to write the report to")
+	c.Flags().StringVar(&format, "format", "pretty", "Output format: pretty|json|ndjson|github (ndjson streams events; github adds workflow annotations)")
+	c.Flags().StringArrayVar(&reports, "report", nil, "Write a report as type:path (repeatable; types: "+strings.Join(reportTypes, "|")+")")
+	c.Flags().StringVar(&reportPath, "report-path", "", "File path for a single --report given without :path")
 	c.Flags().BoolVar(&failFast, "fail-fast", false, "Stop execution on the first failed request")
 	c.Flags().StringVar(&only, "only", "", "Run only the named requests (comma-separated)")
 	c.Flags().StringVar(&tags, "tags", "", "Run only requests matching any of these tags (comma-separated)")
@@ -225,6 +332,12 @@ func runCmd() *cobra.Command {
 	c.Flags().StringArrayVar(&varFlags, "var", nil, "Override a variable (key=value, repeatable; wins over env and collection vars)")
 	c.Flags().BoolVarP(&quiet, "quiet", "q", false, "Show only failed requests in pretty output")
 	c.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output (NO_COLOR is also honored)")
+	c.Flags().StringArrayVar(&labelFlags, "label", nil, "Attach a label to the run metadata (key=value, repeatable)")
+	c.Flags().StringVar(&baselineRef, "baseline", "", "Fail on regressions against a saved run (run ID, or \"last\" for the newest run of this collection and env)")
+	c.Flags().Float64Var(&budgetPct, "latency-budget-pct", 0, "With --baseline: fail when a request gets more than this percent slower")
+	c.Flags().IntVar(&budgetMS, "latency-budget-ms", 0, "With --baseline: fail when a request gets more than this many ms slower")
+	c.Flags().BoolVar(&updateSnapshots, "update-snapshots", false, "Rewrite snapshot assertions that differ instead of failing them")
+	c.Flags().StringVar(&otelEndpoint, "otel-endpoint", "", "Export the run as an OpenTelemetry trace to this OTLP/HTTP endpoint (default: run.otel.endpoint)")
 
 	if err := c.MarkFlagRequired("collection"); err != nil {
 		panic(fmt.Sprintf("MarkFlagRequired: %v", err))
@@ -296,8 +409,9 @@ func printDryRun(w io.Writer, run domain.RunResult) error {
 
 // prettyOpts controls the human-readable output.
 type prettyOpts struct {
-	quiet  bool // only failed requests
-	colors palette
+	quiet    bool // only failed requests
+	metadata bool // print the run metadata block
+	colors   palette
 }
 
 func printRun(w io.Writer, run domain.RunResult, runID string, format string, opts prettyOpts) error {
@@ -335,6 +449,10 @@ func printRun(w io.Writer, run domain.RunResult, runID string, format string, op
 const maxMessageLen = 300
 const maxBodyExcerptLen = 200
 
+// maxAssertionDiffLines caps the diff entries printed under a failing
+// assertion; reports carry the full list.
+const maxAssertionDiffLines = 10
+
 func printPrettyRun(w io.Writer, run domain.RunResult, runID string, opts prettyOpts) {
 	c := opts.colors
 	total := run.EndedAt.Sub(run.StartedAt)
@@ -348,6 +466,19 @@ func printPrettyRun(w io.Writer, run domain.RunResult, runID string, opts pretty
 	if runID != "" {
 		fmt.Fprintf(w, "Run ID:     %s\n", runID)
 	}
+	if opts.metadata && len(run.Metadata) > 0 {
+		fmt.Fprintln(w, "Metadata:")
+		keys := make([]string, 0, len(run.Metadata))
+		for k := range run.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, k := range keys {
+			fmt.Fprintf(tw, "  %s\t%s\n", k, run.Metadata[k])
+		}
+		_ = tw.Flush()
+	}
 	fmt.Fprintln(w)
 
 	for _, r := range run.Results {
@@ -383,6 +514,13 @@ func printPrettyRun(w io.Writer, run domain.RunResult, runID string, opts pretty
 					continue
 				}
 				fmt.Fprintf(w, "    %s✗ %s — %s%s\n", c.red, a.Name, truncateMessage(a.Message), c.reset)
+				for i, d := range a.Diff {
+					if i == maxAssertionDiffLines {
+						fmt.Fprintf(w, "        … %d more\n", len(a.Diff)-i)
+						break
+					}
+					fmt.Fprintf(w, "        %s\n", excerptString(d.String(), maxMessageLen))
+				}
 			}
 		}
 
@@ -512,22 +650,6 @@ func countExtractPassFail(in []domain.ExtractResult) (ok int, bad int) {
 	return ok, bad
 }
 
-func validateReportFlags(report, reportPath string) error {
-	if report == "" && reportPath == "" {
-		return nil
-	}
-	if report != "" && reportPath == "" {
-		return fmt.Errorf("--report-path is required when --report is set")
-	}
-	if report == "" && reportPath != "" {
-		return fmt.Errorf("--report is required when --report-path is set")
-	}
-	if report != "junit" {
-		return fmt.Errorf("unsupported report type %q (expected \"junit\")", report)
-	}
-	return nil
-}
-
 func splitCSV(s string) []string {
 	if strings.TrimSpace(s) == "" {
 		return nil
@@ -542,12 +664,3 @@ func splitCSV(s string) []string {
 	}
 	return out
 }
-
-func writeJUnitReport(path string, run domain.RunResult, runID string) error {
-	f, err := os.Create(path)
-	if err != nil {
-		return fmt.Errorf("failed to create report file %q: %w", path, err)
-	}
-	defer f.Close()
-	return formatJUnit(f, run, runID)
-}
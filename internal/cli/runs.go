@@ -1,16 +1,21 @@
 package cli
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"sort"
 	"text/tabwriter"
 	"time"
 
 	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/redaction"
 	"github.com/aalvaropc/lynix/internal/infra/runstore"
 	"github.com/aalvaropc/lynix/internal/infra/wiring"
+	"github.com/aalvaropc/lynix/internal/usecase/bodydiff"
 	"github.com/spf13/cobra"
 )
 
@@ -23,6 +28,8 @@ func runsCmd() *cobra.Command {
 	c.AddCommand(runsListCmd())
 	c.AddCommand(runsShowCmd())
 	c.AddCommand(runsDiffCmd())
+	c.AddCommand(runsTrendCmd())
+	c.AddCommand(runsPruneCmd())
 	return c
 }
 
@@ -38,6 +45,12 @@ func runsListCmd() *cobra.Command {
 	var workspace string
 	var format string
 	var limit int
+	var metaFlags []string
+	var labelFlags []string
+	var collection string
+	var env string
+	var failedOnly bool
+	var since string
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -46,19 +59,42 @@ func runsListCmd() *cobra.Command {
 			if err := validateListFormat(format); err != nil {
 				return err
 			}
+			meta, err := parseLabelFlags("meta", metaFlags)
+			if err != nil {
+				return err
+			}
+			labels, err := parseLabelFlags("label", labelFlags)
+			if err != nil {
+				return err
+			}
+			// Labels are stored as metadata: --label is --meta by another name.
+			if meta == nil {
+				meta = labels
+			} else {
+				maps.Copy(meta, labels)
+			}
+			query := runstore.RunQuery{
+				Collection: collection,
+				Env:        env,
+				Failed:     failedOnly,
+				Metadata:   meta,
+				Limit:      max(limit, 0),
+			}
+			if since != "" {
+				if query.Since, err = parseSince(since, time.Now()); err != nil {
+					return err
+				}
+			}
 
 			store, err := runsStore(workspace)
 			if err != nil {
 				return err
 			}
 
-			summaries, err := store.ListRuns()
+			summaries, err := store.Query(query)
 			if err != nil {
 				return err
 			}
-			if limit > 0 && len(summaries) > limit {
-				summaries = summaries[:limit]
-			}
 
 			if format == "json" {
 				return printJSONList(os.Stdout, summaries)
@@ -69,32 +105,58 @@ func runsListCmd() *cobra.Command {
 				return nil
 			}
 
-			tw := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
-			fmt.Fprintln(tw, "ID\tCOLLECTION\tENV\tSTARTED\tRESULT")
-			for _, s := range summaries {
-				result := fmt.Sprintf("%d passed", s.Passed)
-				if s.Failed > 0 {
-					result += fmt.Sprintf(", %d failed", s.Failed)
-				}
-				if s.Errors > 0 {
-					result += fmt.Sprintf(", %d errors", s.Errors)
-				}
-				started := ""
-				if !s.StartedAt.IsZero() {
-					started = s.StartedAt.Local().Format(time.RFC3339)
-				}
-				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", s.ID, s.Collection, s.Env, started, result)
-			}
-			return tw.Flush()
+			return printRunSummaries(os.Stdout, summaries)
 		},
 	}
 
 	cmd.Flags().StringVarP(&workspace, "workspace", "w", "", "Workspace root (optional; autodetected if omitted)")
 	cmd.Flags().StringVar(&format, "format", "pretty", "Output format: pretty|json")
 	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum runs to list (0 = all)")
+	cmd.Flags().StringVarP(&collection, "collection", "c", "", "Only runs of this collection")
+	cmd.Flags().StringVarP(&env, "env", "e", "", "Only runs against this environment")
+	cmd.Flags().BoolVar(&failedOnly, "failed", false, "Only runs with a failed or errored request")
+	cmd.Flags().StringVar(&since, "since", "", "Only runs started within this age (7d, 12h) or since this date (2026-01-31, RFC 3339)")
+	cmd.Flags().StringArrayVar(&labelFlags, "label", nil, "Only runs labeled key=value with run --label (repeatable; all must match)")
+	cmd.Flags().StringArrayVar(&metaFlags, "meta", nil, "Only runs whose metadata has key=value (repeatable; all must match)")
 	return cmd
 }
 
+func printRunSummaries(w io.Writer, summaries []runstore.RunSummary) error {
+	tw := tabwriter.NewWriter(w, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tCOLLECTION\tENV\tSTARTED\tRESULT")
+	for _, s := range summaries {
+		result := fmt.Sprintf("%d passed", s.Passed)
+		if s.Failed > 0 {
+			result += fmt.Sprintf(", %d failed", s.Failed)
+		}
+		if s.Errors > 0 {
+			result += fmt.Sprintf(", %d errors", s.Errors)
+		}
+		started := ""
+		if !s.StartedAt.IsZero() {
+			started = s.StartedAt.Local().Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", s.ID, s.Collection, s.Env, started, result)
+	}
+	return tw.Flush()
+}
+
+// parseSince accepts an age relative to now ("7d", "12h") or an absolute
+// date or RFC 3339 timestamp.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	age, err := domain.ParseAge(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--since: %w (or a date such as 2026-01-31)", err)
+	}
+	return now.Add(-age), nil
+}
+
 func runsShowCmd() *cobra.Command {
 	var workspace string
 	var format string
@@ -115,13 +177,19 @@ func runsShowCmd() *cobra.Command {
 				return err
 			}
 
-			pretty := prettyOpts{colors: newPalette(colorsEnabled(noColor, os.Stdout))}
+			// The artifact is rendered as stored, so the HTML carries the
+			// same redaction as the saved JSON.
+			if format == "html" {
+				return formatHTML(os.Stdout, run, args[0])
+			}
+
+			pretty := prettyOpts{colors: newPalette(colorsEnabled(noColor, os.Stdout)), metadata: true}
 			return printRun(os.Stdout, run, args[0], format, pretty)
 		},
 	}
 
 	cmd.Flags().StringVarP(&workspace, "workspace", "w", "", "Workspace root (optional; autodetected if omitted)")
-	cmd.Flags().StringVar(&format, "format", "pretty", "Output format: pretty|json")
+	cmd.Flags().StringVar(&format, "format", "pretty", "Output format: pretty|json|html")
 	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
 	return cmd
 }
@@ -129,12 +197,22 @@ func runsShowCmd() *cobra.Command {
 func runsDiffCmd() *cobra.Command {
 	var workspace string
 	var noColor bool
+	var body bool
+	var ignore []string
 
 	cmd := &cobra.Command{
 		Use:   "diff <run-id-a> <run-id-b>",
-		Short: "Compare two saved runs (status, latency, assertions)",
+		Short: "Compare two saved runs (status, latency, assertions, optionally bodies)",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(_ *cobra.Command, args []string) error {
+			if len(ignore) > 0 && !body {
+				return fmt.Errorf("--ignore requires --body")
+			}
+			patterns, err := bodydiff.ParsePatterns(ignore)
+			if err != nil {
+				return fmt.Errorf("--ignore: %w", err)
+			}
+
 			store, err := runsStore(workspace)
 			if err != nil {
 				return err
@@ -150,19 +228,28 @@ func runsDiffCmd() *cobra.Command {
 			}
 
 			c := newPalette(colorsEnabled(noColor, os.Stdout))
-			printRunDiff(os.Stdout, args[0], args[1], runA, runB, c)
+			printRunDiff(os.Stdout, args[0], args[1], runA, runB, runDiffOpts{body: body, ignore: patterns}, c)
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&workspace, "workspace", "w", "", "Workspace root (optional; autodetected if omitted)")
 	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	cmd.Flags().BoolVar(&body, "body", false, "Also diff response bodies (JSON by path, other bodies line by line)")
+	cmd.Flags().StringArrayVar(&ignore, "ignore", nil, "JSONPath to leave out of the body diff, e.g. '$.meta.timestamp' (repeatable)")
 	return cmd
 }
 
+// runDiffOpts selects the optional parts of a run diff.
+type runDiffOpts struct {
+	body   bool
+	ignore []bodydiff.Pattern
+}
+
 // printRunDiff compares two runs request-by-request (matched by name):
-// status changes, latency deltas, and assertion regressions/recoveries.
-func printRunDiff(w io.Writer, idA, idB string, a, b domain.RunResult, c palette) {
+// status changes, latency deltas, assertion regressions/recoveries and, with
+// opts.body, response body changes.
+func printRunDiff(w io.Writer, idA, idB string, a, b domain.RunResult, opts runDiffOpts, c palette) {
 	fmt.Fprintf(w, "Comparing %s → %s\n\n", idA, idB)
 
 	byName := func(run domain.RunResult) map[string]domain.RequestResult {
@@ -198,6 +285,9 @@ func printRunDiff(w io.Writer, idA, idB string, a, b domain.RunResult, c palette
 			fmt.Fprintf(w, "%s- %s%s (only in %s)\n", c.red, name, c.reset, idA)
 		default:
 			changes := diffRequest(ra, rb)
+			if opts.body {
+				changes = append(changes, diffBody(ra.Response, rb.Response, opts.ignore)...)
+			}
 			if len(changes) == 0 {
 				unchanged++
 				continue
@@ -212,59 +302,178 @@ func printRunDiff(w io.Writer, idA, idB string, a, b domain.RunResult, c palette
 	fmt.Fprintf(w, "\n%d request(s) unchanged\n", unchanged)
 }
 
+// requestDelta is what changed in one request between two runs.
+type requestDelta struct {
+	statusA, statusB int
+
+	// latencyA/B are only meaningful when latencyComparable: errored requests
+	// report 0ms, which would produce meaningless deltas — but legitimate
+	// sub-millisecond responses must still compare.
+	latencyA, latencyB int64
+	latencyComparable  bool
+
+	passA, failA, passB, failB int
+
+	// regressed are assertions failing in b that did not fail in a;
+	// newlyFailing is the subset that a ran and passed. recovered are
+	// assertions failing in a that no longer fail in b.
+	regressed    []domain.AssertionResult
+	newlyFailing []domain.AssertionResult
+	recovered    []domain.AssertionResult
+
+	errorA, errorB string // error kinds ("" when none)
+}
+
+func (d requestDelta) latencyDelta() int64 {
+	if !d.latencyComparable {
+		return 0
+	}
+	return d.latencyB - d.latencyA
+}
+
+// assertionID identifies an assertion across runs: jsonpath and header
+// assertions share a Name and differ by Key.
+func assertionID(a domain.AssertionResult) string {
+	if a.Key == "" {
+		return a.Name
+	}
+	return a.Name + "[" + a.Key + "]"
+}
+
+func compareRequests(a, b domain.RequestResult) requestDelta {
+	d := requestDelta{
+		statusA:           a.StatusCode,
+		statusB:           b.StatusCode,
+		latencyA:          a.LatencyMS,
+		latencyB:          b.LatencyMS,
+		latencyComparable: a.Error == nil && b.Error == nil,
+	}
+	d.passA, d.failA = countAssertionPassFail(a.Assertions)
+	d.passB, d.failB = countAssertionPassFail(b.Assertions)
+
+	inA := map[string]bool{} // assertion ID → passed
+	for _, r := range a.Assertions {
+		id := assertionID(r)
+		passed, seen := inA[id]
+		inA[id] = r.Passed && (passed || !seen)
+	}
+	failingB := map[string]bool{}
+	for _, r := range b.Assertions {
+		if r.Passed {
+			continue
+		}
+		id := assertionID(r)
+		failingB[id] = true
+		passed, ranInA := inA[id]
+		if ranInA && !passed {
+			continue
+		}
+		d.regressed = append(d.regressed, r)
+		if ranInA {
+			d.newlyFailing = append(d.newlyFailing, r)
+		}
+	}
+	for _, r := range a.Assertions {
+		if !r.Passed && !failingB[assertionID(r)] {
+			d.recovered = append(d.recovered, r)
+		}
+	}
+
+	errKind := func(e *domain.RunError) string {
+		if e == nil {
+			return ""
+		}
+		return string(e.Kind)
+	}
+	d.errorA, d.errorB = errKind(a.Error), errKind(b.Error)
+	return d
+}
+
 func diffRequest(a, b domain.RequestResult) []string {
+	d := compareRequests(a, b)
 	var out []string
 
-	if a.StatusCode != b.StatusCode {
-		out = append(out, fmt.Sprintf("status: %d → %d", a.StatusCode, b.StatusCode))
+	if d.statusA != d.statusB {
+		out = append(out, fmt.Sprintf("status: %d → %d", d.statusA, d.statusB))
 	}
 
-	// Compare latency only when both requests actually completed: errored
-	// requests report 0ms, which would produce meaningless deltas — but
-	// legitimate sub-millisecond responses must still compare.
-	if delta := b.LatencyMS - a.LatencyMS; delta != 0 && a.Error == nil && b.Error == nil {
+	if delta := d.latencyDelta(); delta != 0 {
 		sign := "+"
 		if delta < 0 {
 			sign = ""
 		}
-		out = append(out, fmt.Sprintf("latency: %dms → %dms (%s%dms)", a.LatencyMS, b.LatencyMS, sign, delta))
+		out = append(out, fmt.Sprintf("latency: %dms → %dms (%s%dms)", d.latencyA, d.latencyB, sign, delta))
 	}
 
-	passA, failA := countAssertionPassFail(a.Assertions)
-	passB, failB := countAssertionPassFail(b.Assertions)
-	if failA != failB || passA != passB {
-		out = append(out, fmt.Sprintf("assertions: %d pass / %d fail → %d pass / %d fail", passA, failA, passB, failB))
-		failedIn := func(rs []domain.AssertionResult) map[string]string {
-			m := map[string]string{}
-			for _, r := range rs {
-				if !r.Passed {
-					m[r.Name] = r.Message
-				}
-			}
-			return m
+	if d.failA != d.failB || d.passA != d.passB {
+		out = append(out, fmt.Sprintf("assertions: %d pass / %d fail → %d pass / %d fail", d.passA, d.failA, d.passB, d.failB))
+		for _, r := range d.regressed {
+			out = append(out, fmt.Sprintf("regressed: %s — %s", assertionID(r), truncateMessage(r.Message)))
 		}
-		fa, fb := failedIn(a.Assertions), failedIn(b.Assertions)
-		for name, msg := range fb {
-			if _, was := fa[name]; !was {
-				out = append(out, fmt.Sprintf("regressed: %s — %s", name, truncateMessage(msg)))
-			}
+		for _, r := range d.recovered {
+			out = append(out, fmt.Sprintf("recovered: %s", assertionID(r)))
 		}
-		for name := range fa {
-			if _, still := fb[name]; !still {
-				out = append(out, fmt.Sprintf("recovered: %s", name))
+	}
+
+	if d.errorA != d.errorB {
+		out = append(out, fmt.Sprintf("error: %q → %q", d.errorA, d.errorB))
+	}
+
+	return out
+}
+
+// maxBodyDiffLines caps the body changes printed per request.
+const maxBodyDiffLines = 40
+
+// diffBody compares two stored response bodies: path by path when both are
+// JSON, line by line otherwise. Stored bodies are redacted, so the mask
+// compares equal to any value.
+func diffBody(a, b domain.ResponseSnapshot, ignore []bodydiff.Pattern) []string {
+	if bytes.Equal(a.Body, b.Body) {
+		return nil
+	}
+	opts := []bodydiff.Option{bodydiff.WithMask(redaction.MaskValue), bodydiff.WithIgnore(ignore...)}
+	suffix := ""
+	if a.Truncated || b.Truncated {
+		suffix = ", truncated"
+	}
+
+	var out []string
+	if changes, err := bodydiff.JSON(a.Body, b.Body, opts...); err == nil {
+		for _, ch := range changes {
+			switch ch.Kind {
+			case bodydiff.Added:
+				out = append(out, fmt.Sprintf("  + %s: %s", ch.Path, bodyValue(ch.New)))
+			case bodydiff.Removed:
+				out = append(out, fmt.Sprintf("  - %s: %s", ch.Path, bodyValue(ch.Old)))
+			default:
+				out = append(out, fmt.Sprintf("  ~ %s: %s → %s", ch.Path, bodyValue(ch.Old), bodyValue(ch.New)))
 			}
 		}
+		return bodyDiffSection("body:", out)
 	}
 
-	errMsg := func(e *domain.RunError) string {
-		if e == nil {
-			return ""
-		}
-		return string(e.Kind)
+	for _, l := range bodydiff.Lines(string(a.Body), string(b.Body), opts...) {
+		out = append(out, fmt.Sprintf("  %c %s", l.Op, excerptString(l.Text, maxMessageLen)))
 	}
-	if errMsg(a.Error) != errMsg(b.Error) {
-		out = append(out, fmt.Sprintf("error: %q → %q", errMsg(a.Error), errMsg(b.Error)))
+	return bodyDiffSection("body (text"+suffix+"):", out)
+}
+
+func bodyDiffSection(header string, lines []string) []string {
+	if len(lines) == 0 {
+		return nil
 	}
+	if len(lines) > maxBodyDiffLines {
+		more := len(lines) - maxBodyDiffLines
+		lines = append(lines[:maxBodyDiffLines:maxBodyDiffLines], fmt.Sprintf("  … %d more", more))
+	}
+	return append([]string{header}, lines...)
+}
 
-	return out
+func bodyValue(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return excerptString(string(b), 60)
 }
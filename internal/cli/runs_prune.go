@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/runstore"
+	"github.com/aalvaropc/lynix/internal/infra/wiring"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func runsPruneCmd() *cobra.Command {
+	var workspace string
+	var format string
+	var dryRun bool
+	var keep int
+	var keepPerCollection int
+	var olderThan string
+	var failedOlderThan string
+	var passedOlderThan string
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete saved runs according to a retention policy (artifacts.retention in lynix.yaml, or flags)",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := validateListFormat(format); err != nil {
+				return err
+			}
+
+			ws, err := loadWorkspace(workspace, wiring.Opts{})
+			if err != nil {
+				return err
+			}
+			policy, err := prunePolicy(ws.cfg.Artifacts.Retention, cmd.Flags())
+			if err != nil {
+				return err
+			}
+			if policy.IsZero() {
+				return fmt.Errorf("no retention policy: set artifacts.retention (or artifacts.max_runs) in lynix.yaml, or pass --keep, --keep-per-collection or --older-than")
+			}
+
+			store := runstore.NewJSONStore(ws.root, ws.cfg)
+			pruned, err := store.Prune(policy, dryRun)
+			if err != nil {
+				return err
+			}
+
+			if format == "json" {
+				return printJSONList(os.Stdout, pruneReport{DryRun: dryRun, Runs: pruned})
+			}
+			return printPruned(os.Stdout, pruned, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVarP(&workspace, "workspace", "w", "", "Workspace root (optional; autodetected if omitted)")
+	cmd.Flags().StringVar(&format, "format", "pretty", "Output format: pretty|json")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the runs that would be deleted without deleting them")
+	cmd.Flags().IntVar(&keep, "keep", 0, "Keep only the newest N runs (overrides artifacts.max_runs; 0 = no limit)")
+	cmd.Flags().IntVar(&keepPerCollection, "keep-per-collection", 0, "Keep only the newest N runs of each collection (0 = no limit)")
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "Delete runs older than this age (e.g. 30d, 2w, 36h)")
+	cmd.Flags().StringVar(&failedOlderThan, "failed-older-than", "", "Delete runs with failures older than this age (overrides --older-than for them)")
+	cmd.Flags().StringVar(&passedOlderThan, "passed-older-than", "", "Delete fully passing runs older than this age (overrides --older-than for them)")
+	return cmd
+}
+
+// prunePolicy starts from the configured retention and replaces each rule
+// whose flag was given.
+func prunePolicy(policy domain.RetentionPolicy, flags *pflag.FlagSet) (domain.RetentionPolicy, error) {
+	counts := []struct {
+		flag string
+		out  *int
+	}{
+		{"keep", &policy.MaxRuns},
+		{"keep-per-collection", &policy.MaxRunsPerCollection},
+	}
+	for _, c := range counts {
+		if !flags.Changed(c.flag) {
+			continue
+		}
+		n, _ := flags.GetInt(c.flag)
+		if n < 0 {
+			return policy, fmt.Errorf("--%s must be >= 0", c.flag)
+		}
+		*c.out = n
+	}
+
+	ages := []struct {
+		flag string
+		out  *time.Duration
+	}{
+		{"older-than", &policy.MaxAge},
+		{"failed-older-than", &policy.MaxAgeFailed},
+		{"passed-older-than", &policy.MaxAgePassed},
+	}
+	for _, a := range ages {
+		if !flags.Changed(a.flag) {
+			continue
+		}
+		s, _ := flags.GetString(a.flag)
+		d, err := domain.ParseAge(s)
+		if err != nil {
+			return policy, fmt.Errorf("--%s: %w", a.flag, err)
+		}
+		*a.out = d
+	}
+	return policy, nil
+}
+
+type pruneReport struct {
+	DryRun bool                  `json:"dry_run"`
+	Runs   []runstore.RunSummary `json:"runs"`
+}
+
+func printPruned(w io.Writer, pruned []runstore.RunSummary, dryRun bool) error {
+	if len(pruned) == 0 {
+		fmt.Fprintln(w, "Nothing to prune.")
+		return nil
+	}
+	if err := printRunSummaries(w, pruned); err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Fprintf(w, "\nWould delete %d run(s) (dry run).\n", len(pruned))
+	} else {
+		fmt.Fprintf(w, "\nDeleted %d run(s).\n", len(pruned))
+	}
+	return nil
+}
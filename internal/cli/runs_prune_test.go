@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/runstore"
+)
+
+func TestPrunePolicy_FlagsOverrideConfig(t *testing.T) {
+	cmd := runsPruneCmd()
+	if err := cmd.ParseFlags([]string{"--keep", "5", "--passed-older-than", "3d"}); err != nil {
+		t.Fatal(err)
+	}
+	configured := domain.RetentionPolicy{MaxRuns: 100, MaxRunsPerCollection: 10, MaxAgePassed: 30 * 24 * time.Hour}
+
+	got, err := prunePolicy(configured, cmd.Flags())
+	if err != nil {
+		t.Fatalf("prunePolicy: %v", err)
+	}
+	want := domain.RetentionPolicy{MaxRuns: 5, MaxRunsPerCollection: 10, MaxAgePassed: 3 * 24 * time.Hour}
+	if got != want {
+		t.Errorf("policy = %+v, want %+v", got, want)
+	}
+
+	cmd = runsPruneCmd()
+	_ = cmd.ParseFlags([]string{"--older-than", "soon"})
+	if _, err := prunePolicy(configured, cmd.Flags()); err == nil || !strings.Contains(err.Error(), "--older-than") {
+		t.Errorf("expected an error naming the flag, got %v", err)
+	}
+}
+
+func TestRunsPruneCmd(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "lynix.yaml"), []byte(""), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := domain.DefaultConfig()
+	cfg.Masking.Enabled = false
+	store := runstore.NewJSONStore(root, cfg)
+	start := time.Now().Add(-time.Hour)
+	for i := range 3 {
+		if _, err := store.SaveRun(domain.RunArtifact{CollectionName: "demo", StartedAt: start.Add(time.Duration(i) * time.Minute)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	prune := func(args ...string) error {
+		cmd := runsPruneCmd()
+		cmd.SetArgs(append([]string{"-w", root}, args...))
+		return cmd.Execute()
+	}
+	count := func() int {
+		runs, _ := store.ListRuns()
+		return len(runs)
+	}
+
+	if err := prune(); err == nil || !strings.Contains(err.Error(), "no retention policy") {
+		t.Fatalf("expected an error without any policy, got %v", err)
+	}
+	if err := prune("--keep", "1", "--dry-run"); err != nil || count() != 3 {
+		t.Fatalf("dry run: err=%v, %d runs left", err, count())
+	}
+	if err := prune("--keep", "1"); err != nil || count() != 1 {
+		t.Fatalf("prune: err=%v, %d runs left", err, count())
+	}
+}
+
+func TestPrintPruned(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printPruned(&buf, nil, false); err != nil || buf.String() != "Nothing to prune.\n" {
+		t.Errorf("got %q, %v", buf.String(), err)
+	}
+
+	buf.Reset()
+	_ = printPruned(&buf, []runstore.RunSummary{{ID: "20260301T000000Z_demo", Collection: "demo", Passed: 2}}, true)
+	out := buf.String()
+	if !strings.Contains(out, "20260301T000000Z_demo") || !strings.Contains(out, "Would delete 1 run(s) (dry run).") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/usecase/bodydiff"
+)
+
+func bodyRun(bodies ...string) domain.RunResult {
+	run := domain.RunResult{}
+	for i, b := range bodies {
+		run.Results = append(run.Results, domain.RequestResult{
+			Name:       fmt.Sprintf("req%d", i),
+			StatusCode: 200,
+			Response:   domain.ResponseSnapshot{Body: domain.BodyBytes(b)},
+		})
+	}
+	return run
+}
+
+func TestPrintRunDiff_Body(t *testing.T) {
+	a := bodyRun(
+		`{"name":"alice","meta":{"timestamp":1},"old":true}`,
+		"line one\nline two\n",
+		`{"token":"********"}`,
+	)
+	b := bodyRun(
+		`{"name":"bob","meta":{"timestamp":2},"new":[1]}`,
+		"line one\nline 2\n",
+		`{"token":"abc"}`,
+	)
+	ignore, err := bodydiff.ParsePatterns([]string{"$.meta.timestamp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	printRunDiff(&buf, "A", "B", a, b, runDiffOpts{body: true, ignore: ignore}, palette{})
+	out := buf.String()
+
+	for _, want := range []string{
+		"~ req0",
+		`  ~ $.name: "alice" → "bob"`,
+		"  + $.new: [1]",
+		"  - $.old: true",
+		"~ req1",
+		"body (text):",
+		"  - line two",
+		"  + line 2",
+		"1 request(s) unchanged",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "timestamp") || strings.Contains(out, "req2") {
+		t.Errorf("ignored path or masked value reported:\n%s", out)
+	}
+
+	buf.Reset()
+	printRunDiff(&buf, "A", "B", a, b, runDiffOpts{}, palette{})
+	if !strings.Contains(buf.String(), "3 request(s) unchanged") {
+		t.Errorf("bodies should only be compared with --body:\n%s", buf.String())
+	}
+}
+
+func TestDiffBody_CapsOutput(t *testing.T) {
+	var a, b strings.Builder
+	for i := range 50 {
+		fmt.Fprintf(&a, "a%d\n", i)
+		fmt.Fprintf(&b, "b%d\n", i)
+	}
+	lines := diffBody(domain.ResponseSnapshot{Body: []byte(a.String()), Truncated: true}, domain.ResponseSnapshot{Body: []byte(b.String())}, nil)
+	if lines[0] != "body (text, truncated):" || len(lines) != maxBodyDiffLines+2 || lines[len(lines)-1] != "  … 60 more" {
+		t.Fatalf("unexpected lines (%d): %q … %q", len(lines), lines[0], lines[len(lines)-1])
+	}
+}
+
+func TestRunsDiffCmd_IgnoreValidation(t *testing.T) {
+	for _, args := range [][]string{
+		{"a", "b", "--ignore", "$.x"},
+		{"a", "b", "--body", "--ignore", "meta.x"},
+	} {
+		cmd := runsDiffCmd()
+		cmd.SetArgs(args)
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--ignore") {
+			t.Errorf("%v: expected an --ignore error, got %v", args, err)
+		}
+	}
+}
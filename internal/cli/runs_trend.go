@@ -0,0 +1,287 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/runstore"
+	"github.com/spf13/cobra"
+)
+
+func runsTrendCmd() *cobra.Command {
+	var workspace string
+	var collection string
+	var env string
+	var last int
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "trend",
+		Short: "Show per-request latency percentiles, pass rate and status codes across saved runs",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := validateListFormat(format); err != nil {
+				return err
+			}
+			if last < 1 {
+				return fmt.Errorf("--last must be at least 1")
+			}
+
+			store, err := runsStore(workspace)
+			if err != nil {
+				return err
+			}
+			summaries, err := store.ListRuns()
+			if err != nil {
+				return err
+			}
+
+			ids := selectTrendRuns(summaries, collection, env, last)
+			if len(ids) == 0 {
+				if format == "json" {
+					return printJSONList(os.Stdout, runTrend{Requests: []requestTrend{}})
+				}
+				fmt.Println("(no saved runs match)")
+				return nil
+			}
+
+			// Oldest first, so sparklines read left to right.
+			runs := make([]domain.RunResult, 0, len(ids))
+			for i := len(ids) - 1; i >= 0; i-- {
+				run, err := store.LoadRun(ids[i])
+				if err != nil {
+					return err
+				}
+				runs = append(runs, run)
+			}
+			trend := computeTrend(runs)
+			for i := len(ids) - 1; i >= 0; i-- {
+				trend.RunIDs = append(trend.RunIDs, ids[i])
+			}
+
+			if format == "json" {
+				return printJSONList(os.Stdout, trend)
+			}
+			printTrend(os.Stdout, trend)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&workspace, "workspace", "w", "", "Workspace root (optional; autodetected if omitted)")
+	cmd.Flags().StringVarP(&collection, "collection", "c", "", "Collection name (default: the collection of the newest run)")
+	cmd.Flags().StringVarP(&env, "env", "e", "", "Only runs against this environment")
+	cmd.Flags().IntVar(&last, "last", 20, "Number of most recent runs to include")
+	cmd.Flags().StringVar(&format, "format", "pretty", "Output format: pretty|json")
+	return cmd
+}
+
+// selectTrendRuns returns the IDs (newest first) of up to last runs of one
+// collection. Without a collection, the newest matching run decides: mixing
+// collections would merge unrelated requests that share a name.
+func selectTrendRuns(summaries []runstore.RunSummary, collection, env string, last int) []string {
+	var ids []string
+	for _, s := range summaries {
+		if s.StartedAt.IsZero() {
+			continue // unreadable artifact
+		}
+		if env != "" && !strings.EqualFold(s.Env, env) {
+			continue
+		}
+		if collection == "" {
+			collection = s.Collection
+		}
+		if !strings.EqualFold(s.Collection, collection) {
+			continue
+		}
+		ids = append(ids, s.ID)
+		if len(ids) == last {
+			break
+		}
+	}
+	return ids
+}
+
+type runTrend struct {
+	Collection  string         `json:"collection"`
+	Environment string         `json:"environment,omitempty"`
+	Runs        int            `json:"runs"`
+	From        time.Time      `json:"from"`
+	To          time.Time      `json:"to"`
+	RunIDs      []string       `json:"run_ids,omitempty"`
+	Requests    []requestTrend `json:"requests"`
+}
+
+type requestTrend struct {
+	Name     string  `json:"name"`
+	Runs     int     `json:"runs"`
+	Passed   int     `json:"passed"`
+	PassRate float64 `json:"pass_rate"`
+	Errors   int     `json:"errors"`
+	P50MS    int64   `json:"p50_ms"`
+	P95MS    int64   `json:"p95_ms"`
+	MaxMS    int64   `json:"max_ms"`
+	// StatusCodes counts responses by HTTP status ("0" is not recorded:
+	// errored requests are counted in Errors).
+	StatusCodes map[string]int `json:"status_codes"`
+	// LatencyMS has one entry per run, oldest first; null where the request
+	// did not run or errored.
+	LatencyMS []*int64 `json:"latency_ms"`
+}
+
+// computeTrend aggregates runs given oldest first. Requests are listed in
+// the order of the newest run, then any that only older runs had.
+func computeTrend(runs []domain.RunResult) runTrend {
+	trend := runTrend{Runs: len(runs), Requests: []requestTrend{}}
+	if len(runs) == 0 {
+		return trend
+	}
+	newest := runs[len(runs)-1]
+	trend.Collection = newest.CollectionName
+	trend.Environment = newest.EnvironmentName
+	for _, run := range runs {
+		if run.EnvironmentName != newest.EnvironmentName {
+			trend.Environment = "(mixed)"
+			break
+		}
+	}
+	trend.From = runs[0].StartedAt
+	trend.To = newest.StartedAt
+
+	var names []string
+	seen := map[string]bool{}
+	for i := len(runs) - 1; i >= 0; i-- {
+		for _, r := range runs[i].Results {
+			if !seen[r.Name] {
+				seen[r.Name] = true
+				names = append(names, r.Name)
+			}
+		}
+	}
+
+	for _, name := range names {
+		rt := requestTrend{Name: name, StatusCodes: map[string]int{}, LatencyMS: make([]*int64, len(runs))}
+		var latencies []int64
+		for i, run := range runs {
+			for _, r := range run.Results {
+				if r.Name != name {
+					continue
+				}
+				rt.Runs++
+				if !r.Failed() {
+					rt.Passed++
+				}
+				if r.Error != nil {
+					rt.Errors++
+					break
+				}
+				if r.StatusCode != 0 {
+					rt.StatusCodes[strconv.Itoa(r.StatusCode)]++
+				}
+				ms := r.LatencyMS
+				rt.LatencyMS[i] = &ms
+				latencies = append(latencies, ms)
+				break
+			}
+		}
+		if rt.Runs > 0 {
+			rt.PassRate = float64(rt.Passed) / float64(rt.Runs)
+		}
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		rt.P50MS = percentile(latencies, 50)
+		rt.P95MS = percentile(latencies, 95)
+		if len(latencies) > 0 {
+			rt.MaxMS = latencies[len(latencies)-1]
+		}
+		trend.Requests = append(trend.Requests, rt)
+	}
+	return trend
+}
+
+// percentile uses the nearest-rank method on sorted values.
+func percentile(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline scales latencies between their own min and max; gaps (request
+// missing or errored) are shown as "·".
+func sparkline(values []*int64) string {
+	lo, hi := int64(math.MaxInt64), int64(math.MinInt64)
+	for _, v := range values {
+		if v != nil {
+			lo = min(lo, *v)
+			hi = max(hi, *v)
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case v == nil:
+			b.WriteRune('·')
+		case hi == lo:
+			b.WriteRune(sparkBlocks[0])
+		default:
+			idx := int(float64(*v-lo) / float64(hi-lo) * float64(len(sparkBlocks)-1))
+			b.WriteRune(sparkBlocks[idx])
+		}
+	}
+	return b.String()
+}
+
+func printTrend(w io.Writer, t runTrend) {
+	env := t.Environment
+	if env == "" {
+		env = "(none)"
+	}
+	fmt.Fprintf(w, "Collection: %s\n", t.Collection)
+	fmt.Fprintf(w, "Env:        %s\n", env)
+	fmt.Fprintf(w, "Runs:       %d (%s → %s)\n\n", t.Runs, t.From.Local().Format(time.RFC3339), t.To.Local().Format(time.RFC3339))
+
+	tw := tabwriter.NewWriter(w, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "REQUEST\tPASS\tP50\tP95\tMAX\tSTATUS\tLATENCY")
+	for _, r := range t.Requests {
+		fmt.Fprintf(tw, "%s\t%d/%d\t%dms\t%dms\t%dms\t%s\t%s\n",
+			r.Name, r.Passed, r.Runs, r.P50MS, r.P95MS, r.MaxMS, statusDistribution(r), sparkline(r.LatencyMS))
+	}
+	_ = tw.Flush()
+}
+
+// statusDistribution renders status counts most frequent first: "200×9 500×1".
+func statusDistribution(r requestTrend) string {
+	codes := make([]string, 0, len(r.StatusCodes))
+	for code := range r.StatusCodes {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if r.StatusCodes[codes[i]] != r.StatusCodes[codes[j]] {
+			return r.StatusCodes[codes[i]] > r.StatusCodes[codes[j]]
+		}
+		return codes[i] < codes[j]
+	})
+	parts := make([]string, 0, len(codes)+1)
+	for _, code := range codes {
+		parts = append(parts, fmt.Sprintf("%s×%d", code, r.StatusCodes[code]))
+	}
+	if r.Errors > 0 {
+		parts = append(parts, fmt.Sprintf("error×%d", r.Errors))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/runstore"
+)
+
+func trendRuns() []domain.RunResult {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ok := func(name string, ms int64) domain.RequestResult {
+		return domain.RequestResult{Name: name, StatusCode: 200, LatencyMS: ms}
+	}
+	return []domain.RunResult{
+		{CollectionName: "demo", EnvironmentName: "dev", StartedAt: start, Results: []domain.RequestResult{
+			ok("login", 100), ok("legacy", 5),
+		}},
+		{CollectionName: "demo", EnvironmentName: "dev", StartedAt: start.Add(time.Hour), Results: []domain.RequestResult{
+			ok("login", 120),
+			{Name: "me", StatusCode: 500, LatencyMS: 40, Assertions: []domain.AssertionResult{{Name: "status", Passed: false}}},
+		}},
+		{CollectionName: "demo", EnvironmentName: "dev", StartedAt: start.Add(2 * time.Hour), Results: []domain.RequestResult{
+			ok("login", 400),
+			{Name: "me", Error: &domain.RunError{Kind: domain.RunErrorTimeout, Message: "deadline"}},
+		}},
+	}
+}
+
+func TestComputeTrend(t *testing.T) {
+	trend := computeTrend(trendRuns())
+
+	if trend.Collection != "demo" || trend.Environment != "dev" || trend.Runs != 3 {
+		t.Fatalf("unexpected header: %+v", trend)
+	}
+	var names []string
+	for _, r := range trend.Requests {
+		names = append(names, r.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"login", "me", "legacy"}) {
+		t.Fatalf("requests should follow the newest run, got %v", names)
+	}
+
+	login := trend.Requests[0]
+	if login.Runs != 3 || login.Passed != 3 || login.PassRate != 1 {
+		t.Errorf("login pass stats: %+v", login)
+	}
+	if login.P50MS != 120 || login.P95MS != 400 || login.MaxMS != 400 {
+		t.Errorf("login latency stats: p50=%d p95=%d max=%d", login.P50MS, login.P95MS, login.MaxMS)
+	}
+	if login.StatusCodes["200"] != 3 {
+		t.Errorf("login status codes: %v", login.StatusCodes)
+	}
+
+	me := trend.Requests[1]
+	if me.Runs != 2 || me.Passed != 0 || me.Errors != 1 || me.StatusCodes["500"] != 1 {
+		t.Errorf("me stats: %+v", me)
+	}
+	if me.LatencyMS[0] != nil || me.LatencyMS[1] == nil || *me.LatencyMS[1] != 40 || me.LatencyMS[2] != nil {
+		t.Errorf("me latency series should have gaps for missing and errored runs")
+	}
+}
+
+func TestComputeTrend_MixedEnvironments(t *testing.T) {
+	runs := trendRuns()
+	runs[0].EnvironmentName = "stg"
+	if got := computeTrend(runs).Environment; got != "(mixed)" {
+		t.Errorf("expected (mixed), got %q", got)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	vals := []int64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}
+	if got := percentile(vals, 50); got != 50 {
+		t.Errorf("p50 = %d", got)
+	}
+	if got := percentile(vals, 95); got != 100 {
+		t.Errorf("p95 = %d", got)
+	}
+	if got := percentile(nil, 95); got != 0 {
+		t.Errorf("empty p95 = %d", got)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	v := func(n int64) *int64 { return &n }
+	if got := sparkline([]*int64{v(0), v(50), nil, v(100)}); got != "▁▄·█" {
+		t.Errorf("got %q", got)
+	}
+	if got := sparkline([]*int64{v(7), v(7)}); got != "▁▁" {
+		t.Errorf("flat series: got %q", got)
+	}
+}
+
+func TestSelectTrendRuns(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	summaries := []runstore.RunSummary{ // newest first, as ListRuns returns them
+		{ID: "5", Collection: "Demo", Env: "dev", StartedAt: at},
+		{ID: "4", Collection: "(unreadable)"},
+		{ID: "3", Collection: "other", Env: "dev", StartedAt: at},
+		{ID: "2", Collection: "demo", Env: "stg", StartedAt: at},
+		{ID: "1", Collection: "demo", Env: "dev", StartedAt: at},
+	}
+
+	if got := selectTrendRuns(summaries, "", "", 10); !reflect.DeepEqual(got, []string{"5", "2", "1"}) {
+		t.Errorf("default collection: got %v", got)
+	}
+	if got := selectTrendRuns(summaries, "", "dev", 10); !reflect.DeepEqual(got, []string{"5", "1"}) {
+		t.Errorf("env filter: got %v", got)
+	}
+	if got := selectTrendRuns(summaries, "other", "", 10); !reflect.DeepEqual(got, []string{"3"}) {
+		t.Errorf("explicit collection: got %v", got)
+	}
+	if got := selectTrendRuns(summaries, "demo", "", 2); !reflect.DeepEqual(got, []string{"5", "2"}) {
+		t.Errorf("last: got %v", got)
+	}
+}
+
+func TestPrintTrend(t *testing.T) {
+	var buf bytes.Buffer
+	printTrend(&buf, computeTrend(trendRuns()))
+	out := buf.String()
+
+	for _, want := range []string{
+		"Collection: demo\n",
+		"REQUEST",
+		"login    3/3",
+		"200×3",
+		"500×1 error×1",
+		"▁▁█",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
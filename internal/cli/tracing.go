@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/oteltrace"
+	"github.com/aalvaropc/lynix/internal/ports"
+)
+
+// traceExportTimeout bounds the span export after a run; a slow or missing
+// collector must not hold up CI.
+const traceExportTimeout = 10 * time.Second
+
+// newRunTracer builds the OTLP tracer for a run. Export headers come from
+// run.otel.headers, then the standard OTEL_EXPORTER_OTLP_HEADERS variable,
+// which keeps collector API keys out of lynix.yaml.
+func newRunTracer(ws *workspaceCtx, endpoint string) (*oteltrace.Tracer, error) {
+	headers, err := parseOTLPHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	if err != nil {
+		return nil, err
+	}
+	opts := []oteltrace.Option{
+		oteltrace.WithHeaders(ws.cfg.Run.OTel.Headers),
+		oteltrace.WithHeaders(headers),
+		oteltrace.WithServiceName(ws.cfg.Run.OTel.ServiceName),
+	}
+	if ws.redactor != nil {
+		opts = append(opts, oteltrace.WithRedact(ws.redactor.RedactResult), oteltrace.WithRedactText(ws.redactor.RedactText))
+	}
+	return oteltrace.New(endpoint, opts...)
+}
+
+// parseOTLPHeaders parses the OTEL_EXPORTER_OTLP_HEADERS format:
+// comma-separated key=value pairs with URL-encoded values.
+func parseOTLPHeaders(s string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid OTEL_EXPORTER_OTLP_HEADERS entry %q (expected key=value)", pair)
+		}
+		if dec, err := url.QueryUnescape(strings.TrimSpace(v)); err == nil {
+			v = dec
+		}
+		out[k] = strings.TrimSpace(v)
+	}
+	return out, nil
+}
+
+// flushTrace exports the run's spans. Failing to export is reported but never
+// changes the run's outcome.
+func flushTrace(ctx context.Context, w io.Writer, tracer *oteltrace.Tracer) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), traceExportTimeout)
+	defer cancel()
+	id := tracer.TraceID()
+	if err := tracer.Flush(ctx); err != nil {
+		fmt.Fprintf(w, "Warning: could not export trace: %v\n", err)
+		return
+	}
+	if id != "" {
+		fmt.Fprintf(w, "Trace ID: %s\n", id)
+	}
+}
+
+// multiObserver fans run events out to several observers.
+type multiObserver []ports.RunObserver
+
+func (m multiObserver) RunStarted(run domain.RunResult, requests []domain.RequestSpec) {
+	for _, o := range m {
+		o.RunStarted(run, requests)
+	}
+}
+
+func (m multiObserver) RequestStarted(index int, req domain.RequestSpec) {
+	for _, o := range m {
+		o.RequestStarted(index, req)
+	}
+}
+
+func (m multiObserver) RequestFinished(index int, result domain.RequestResult) {
+	for _, o := range m {
+		o.RequestFinished(index, result)
+	}
+}
+
+func (m multiObserver) RunFinished(run domain.RunResult, runID string, err error) {
+	for _, o := range m {
+		o.RunFinished(run, runID, err)
+	}
+}
+
+// combineObservers returns nil for no observers (the use case then uses its
+// no-op default) and the observer itself when there is only one.
+func combineObservers(observers ...ports.RunObserver) ports.RunObserver {
+	switch len(observers) {
+	case 0:
+		return nil
+	case 1:
+		return observers[0]
+	}
+	return multiObserver(observers)
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/oteltrace"
+)
+
+func TestParseOTLPHeaders(t *testing.T) {
+	got, err := parseOTLPHeaders("x-api-key=abc, authorization=Basic%20dXNlcg==,")
+	if err != nil {
+		t.Fatalf("parseOTLPHeaders: %v", err)
+	}
+	want := map[string]string{"x-api-key": "abc", "authorization": "Basic dXNlcg=="}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := parseOTLPHeaders("novalue"); err == nil {
+		t.Error("expected an error for an entry without '='")
+	}
+}
+
+type countingObserver struct{ started, finished int }
+
+func (c *countingObserver) RunStarted(domain.RunResult, []domain.RequestSpec) { c.started++ }
+func (c *countingObserver) RequestStarted(int, domain.RequestSpec)            {}
+func (c *countingObserver) RequestFinished(int, domain.RequestResult)         {}
+func (c *countingObserver) RunFinished(domain.RunResult, string, error)       { c.finished++ }
+
+func TestCombineObservers(t *testing.T) {
+	if combineObservers() != nil {
+		t.Error("no observers should combine to nil")
+	}
+	a, b := &countingObserver{}, &countingObserver{}
+	if combineObservers(a) != a {
+		t.Error("a single observer should be returned as is")
+	}
+	o := combineObservers(a, b)
+	o.RunStarted(domain.RunResult{}, nil)
+	o.RunFinished(domain.RunResult{}, "", nil)
+	if a.started != 1 || b.started != 1 || a.finished != 1 || b.finished != 1 {
+		t.Errorf("events not fanned out: a=%+v b=%+v", a, b)
+	}
+}
+
+func TestFlushTrace_WarnsOnExportFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	tr, err := oteltrace.New(srv.URL)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	tr.RunStarted(domain.RunResult{}, nil)
+	tr.RunFinished(domain.RunResult{}, "", nil)
+
+	var buf bytes.Buffer
+	flushTrace(context.Background(), &buf, tr)
+	if !strings.Contains(buf.String(), "Warning: could not export trace") {
+		t.Errorf("expected a warning, got %q", buf.String())
+	}
+}
@@ -2,6 +2,8 @@ package cli
 
 import (
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/aalvaropc/lynix/internal/infra/wiring"
 	"github.com/aalvaropc/lynix/internal/usecase"
@@ -13,38 +15,45 @@ func validateCmd() *cobra.Command {
 	var collection string
 	var env string
 	var varFlags []string
+	var format string
 
 	c := &cobra.Command{
 		Use:   "validate",
 		Short: "Validate a collection and environment (no HTTP)",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			cliVars, err := parseVarFlags(varFlags)
-			if err != nil {
-				return err
+			switch format {
+			case "pretty", "github", "sarif":
+			default:
+				return fmt.Errorf("unsupported format %q (expected pretty|github|sarif)", format)
 			}
 
-			ws, err := loadWorkspaceOrStandalone(cmd.Flags().Changed("workspace"), workspace, wiring.Opts{})
-			if err != nil {
-				return err
-			}
+			var collectionPath string
+			err := func() error {
+				cliVars, err := parseVarFlags(varFlags)
+				if err != nil {
+					return err
+				}
 
-			collectionPath, err := resolveCollectionPath(ws, collection)
-			if err != nil {
-				return err
-			}
+				ws, err := loadWorkspaceOrStandalone(cmd.Flags().Changed("workspace"), workspace, wiring.Opts{})
+				if err != nil {
+					return err
+				}
 
-			envArg, err := resolveEnvironmentArg(ws, env)
-			if err != nil {
-				return err
-			}
+				collectionPath, err = resolveCollectionPath(ws, collection)
+				if err != nil {
+					return err
+				}
 
-			uc := usecase.NewValidateCollection(ws.collections, ws.envs, usecase.WithVars(cliVars))
-			if err := uc.Execute(cmd.Context(), collectionPath, envArg); err != nil {
-				return err
-			}
+				envArg, err := resolveEnvironmentArg(ws, env)
+				if err != nil {
+					return err
+				}
+
+				uc := usecase.NewValidateCollection(ws.collections, ws.envs, usecase.WithVars(cliVars))
+				return uc.Execute(cmd.Context(), collectionPath, envArg)
+			}()
 
-			fmt.Println("OK")
-			return nil
+			return printValidation(os.Stdout, format, collectionPath, err)
 		},
 	}
 
@@ -52,9 +61,37 @@ func validateCmd() *cobra.Command {
 	c.Flags().StringVarP(&collection, "collection", "c", "", "Collection name or path (required)")
 	c.Flags().StringVarP(&env, "env", "e", "", "Environment name or path (optional; defaults to workspace default env)")
 	c.Flags().StringArrayVar(&varFlags, "var", nil, "Override a variable (key=value, repeatable)")
+	c.Flags().StringVar(&format, "format", "pretty", "Output format: pretty|github|sarif")
 
 	if err := c.MarkFlagRequired("collection"); err != nil {
 		panic(fmt.Sprintf("MarkFlagRequired: %v", err))
 	}
 	return c
 }
+
+// printValidation reports the outcome of validate in the chosen format and
+// passes err through, so the exit code does not depend on the format.
+func printValidation(w io.Writer, format, collectionPath string, err error) error {
+	var diags []diagnostic
+	if err != nil {
+		diags = append(diags, errorDiagnostic(err, collectionPath))
+	}
+
+	switch format {
+	case "sarif":
+		if werr := formatSARIF(w, diags); werr != nil {
+			return werr
+		}
+	case "github":
+		if werr := writeGitHubAnnotations(w, diags); werr != nil {
+			return werr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if format != "sarif" {
+		fmt.Fprintln(w, "OK")
+	}
+	return nil
+}
@@ -29,6 +29,23 @@ func parseVarFlags(flags []string) (domain.Vars, error) {
 	return vars, nil
 }
 
+// parseLabelFlags turns repeated key=value flags (--label, --meta) into a map.
+func parseLabelFlags(flag string, flags []string) (map[string]string, error) {
+	if len(flags) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]string, len(flags))
+	for _, f := range flags {
+		key, value, found := strings.Cut(f, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid --%s %q (expected key=value)", flag, f)
+		}
+		out[key] = value
+	}
+	return out, nil
+}
+
 // validateListFormat rejects unknown --format values on list commands so a
 // typo fails loudly (exit 2) instead of silently printing the pretty output.
 func validateListFormat(format string) error {
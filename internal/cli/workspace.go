@@ -24,9 +24,10 @@ type workspaceCtx struct {
 	envs       ports.EnvironmentLoader
 	envCatalog ports.EnvironmentCatalog
 
-	runner   ports.RequestRunner
-	store    ports.ArtifactStore
-	redactor *redaction.Redactor
+	runner    ports.RequestRunner
+	store     ports.ArtifactStore
+	snapshots ports.SnapshotStore
+	redactor  *redaction.Redactor
 }
 
 func loadWorkspace(workspaceFlag string, opts wiring.Opts) (*workspaceCtx, error) {
@@ -54,6 +55,7 @@ func loadWorkspace(workspaceFlag string, opts wiring.Opts) (*workspaceCtx, error
 		envCatalog:  adapters.Envs,
 		runner:      adapters.Runner,
 		store:       adapters.Store,
+		snapshots:   adapters.Snapshots,
 		redactor:    adapters.Redactor,
 	}, nil
 }
@@ -97,6 +99,7 @@ func loadStandalone(opts wiring.Opts) (*workspaceCtx, error) {
 		collections: adapters.Collections,
 		envs:        adapters.Envs,
 		runner:      adapters.Runner,
+		snapshots:   adapters.Snapshots,
 		// Standalone still loads secrets.local.yaml relative to the env
 		// file, so the redactor must be wired here too.
 		redactor: adapters.Redactor,
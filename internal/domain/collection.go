@@ -2,7 +2,11 @@ package domain
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/url"
+	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -105,8 +109,48 @@ type ValueAssertion struct {
 	NotEq       *string  // toStr(value) != *NotEq
 	NotContains *string  // toStr(value) does not contain substring
 	Len         *int     // length of array/object/string == *Len
+	Type        *string  // JSON type of value: string, number, integer, boolean, array, object, null
+	In          []string // toStr(value) is one of In
+	NotIn       []string // toStr(value) is none of NotIn
+	StartsWith  *string  // toStr(value) has prefix
+	EndsWith    *string  // toStr(value) has suffix
+	EqCI        *string  // toStr(value) equals *EqCI, ignoring case
+
+	// Date checks; the value is parsed as a timestamp (see ParseTime).
+	Format *string // rfc3339, rfc1123, date, unix, unix_ms or a Go layout; the value must parse with it
+	Before *string // timestamp is before a time expression (see ParseTimeExpr)
+	After  *string // timestamp is after a time expression
+	Within *string // timestamp is within a window of a time expression: "5m" or "1h of {{created_at}}"
+
+	Approx *ApproxAssertion // numeric value is within Tolerance of Value
+
+	// Array checks; the value must be an array (e.g. a $.items[*].id result).
+	All         *ValueAssertion // every element passes the nested assertion
+	Any         *ValueAssertion // at least one element passes
+	None        *ValueAssertion // no element passes
+	Count       *CountAssertion // number of (matching) elements
+	Unique      *bool           // true: no two elements are equal; false: some are
+	Sorted      *string         // "asc" or "desc"
+	ContainsAll []string        // every listed value equals toStr of some element
+}
+
+// CountAssertion checks how many elements of an array pass Where (all of
+// them when Where is nil) with the numeric operators of the embedded
+// assertion, e.g. {where: {eq: active}, gte: 3}.
+type CountAssertion struct {
+	Where *ValueAssertion
+	ValueAssertion
+}
+
+// ApproxAssertion checks a number against Value, allowing |value-Value| <= Tolerance.
+type ApproxAssertion struct {
+	Value     float64
+	Tolerance float64
 }
 
+// ValueTypes are the JSON type names accepted by ValueAssertion.Type.
+var ValueTypes = []string{"string", "number", "integer", "boolean", "array", "object", "null"}
+
 // BodyAssertion defines checks against the raw response body, regardless of
 // content type (JSON, HTML, plain text, CSV, ...).
 type BodyAssertion struct {
@@ -129,6 +173,10 @@ type AssertionsSpec struct {
 	// MaxLatencyMS is a maximum allowed latency in milliseconds (optional).
 	MaxLatencyMS *int
 
+	// MaxMSRegression is how many milliseconds slower than in the baseline
+	// run the request may get (optional). Without a baseline it is skipped.
+	MaxMSRegression *int
+
 	// Body contains assertions on the raw response body (optional).
 	Body *BodyAssertion
 
@@ -139,6 +187,14 @@ type AssertionsSpec struct {
 	// Headers contains response header assertions keyed by header name (case-insensitive).
 	Headers map[string]ValueAssertion
 
+	// XPath contains assertions on an XML body keyed by XPath expression,
+	// e.g. "//Order/@status", "count(//Item)" (optional).
+	XPath map[string]ValueAssertion
+
+	// CSS contains assertions on an HTML body keyed by CSS selector,
+	// optionally followed by @attr: "h1.title", "a.next @href" (optional).
+	CSS map[string]ValueAssertion
+
 	// Schema is a file path to a JSON Schema file (relative to collection dir).
 	// The response body is validated against this schema.
 	Schema *string
@@ -146,16 +202,134 @@ type AssertionsSpec struct {
 	// SchemaInline is an inline JSON Schema definition.
 	// Cannot be used together with Schema.
 	SchemaInline map[string]any
+
+	// Snapshot compares the whole body with a stored copy (optional).
+	Snapshot *SnapshotAssertion
+
+	// JSONEq compares the body, or a subtree of it, with an expected JSON
+	// document (optional).
+	JSONEq []JSONEqAssertion
+
+	// Expr lists boolean expressions relating values of the response, e.g.
+	// "$.total == length($.items)" (optional).
+	Expr []ExprAssertion
+}
+
+// ExprAssertion is an assert.expr entry: comparisons over JSONPath
+// references, {{var}} references and literals, combined with arithmetic,
+// length() and and/or/not.
+type ExprAssertion struct {
+	// Source is the expression as written.
+	Source string
+
+	// Vars holds the values of its {{var}} references; the run fills it in
+	// when resolving assertion values.
+	Vars Vars
+}
+
+// JSONEqAssertion checks that a JSON value in the response deeply equals an
+// expected document, given inline or as a fixture file.
+type JSONEqAssertion struct {
+	// Path selects the compared subtree; empty means the whole body.
+	Path string
+
+	// Value is the expected document. When File is set, the run loads the
+	// fixture into Value before evaluating.
+	Value any
+
+	// File is a fixture path (relative to collection dir). Mutually
+	// exclusive with an inline value at load time.
+	File *string
+
+	// Ignore lists JSONPaths left out of the comparison.
+	Ignore []string
+
+	// IgnoreOrder compares arrays as multisets.
+	IgnoreOrder bool
+
+	// Tolerance is the largest absolute difference at which two numbers
+	// still compare equal.
+	Tolerance float64
+}
+
+// SnapshotAssertion compares the response body with a snapshot stored next
+// to the collection, which the first run (or an update) writes.
+type SnapshotAssertion struct {
+	// Name identifies the snapshot within its collection; it defaults to the
+	// request name, and requests may share one.
+	Name string
+
+	// Ignore lists JSONPaths of volatile fields ($.meta.timestamp,
+	// $..id) left out of the comparison and of the stored copy.
+	Ignore []string
 }
 
 // ExtractSpec defines variable extraction from responses.
-// Map: variableName -> jsonpathExpression
+// Map: variableName -> jsonpathExpression (an XPath expression or CSS
+// selector for RequestSpec.ExtractXPath and ExtractCSS)
 type ExtractSpec map[string]string
 
+// ExtractOptions refine an Extract (JSONPath) rule.
+type ExtractOptions struct {
+	Type     VarType // VarJSON stores the value as JSON text and keeps its type in json bodies
+	Optional bool    // a missing value is skipped instead of failing the request
+	Default  *string // stored when the value is missing (JSON text for VarJSON)
+}
+
 // ExtractHeaderSpec defines variable extraction from response headers.
 // Map: variableName -> headerName
 type ExtractHeaderSpec map[string]string
 
+// ExtractRegexRule extracts one variable per named capture group of Pattern,
+// matched against the body or, when Header is set, that response header.
+type ExtractRegexRule struct {
+	Pattern string
+	Header  string
+}
+
+// Vars returns the names of the capture groups of the pattern, i.e. the
+// variables the rule produces. It is empty when the pattern does not compile.
+func (r ExtractRegexRule) Vars() []string {
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, n := range re.SubexpNames() {
+		if n != "" {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// CookieAttrs are the attributes an ExtractCookies rule can read.
+var CookieAttrs = []string{"value", "domain", "path", "expires", "max-age", "secure", "httponly", "samesite"}
+
+// ParseCookieRule splits an ExtractCookies rule, "name" or "name @attr", into
+// the cookie name and the attribute to read ("value" by default).
+func ParseCookieRule(rule string) (name, attr string, err error) {
+	name, attr, found := strings.Cut(strings.TrimSpace(rule), "@")
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", "", errors.New("empty cookie name")
+	}
+	attr = strings.ToLower(strings.TrimSpace(attr))
+	if !found {
+		attr = "value"
+	}
+	if !slices.Contains(CookieAttrs, attr) {
+		return "", "", fmt.Errorf("unknown cookie attribute %q (expected one of: %s)", attr, strings.Join(CookieAttrs, ", "))
+	}
+	return name, attr, nil
+}
+
+// Sources of RequestSpec.ExtractResponse.
+const (
+	ResponseStatus = "status" // status code
+	ResponseURL    = "url"    // final URL, after redirects
+)
+
 // RequestSpec describes a single API request and its validation/extraction rules.
 type RequestSpec struct {
 	Name    string
@@ -169,9 +343,21 @@ type RequestSpec struct {
 	TimeoutMS       *int  // per-request timeout in ms (nil = use global client timeout)
 	FollowRedirects *bool // nil = follow (Go default), false = stop at redirect
 
-	Assert         AssertionsSpec
-	Extract        ExtractSpec
-	ExtractHeaders ExtractHeaderSpec
+	Assert          AssertionsSpec
+	Extract         ExtractSpec
+	ExtractOptions  map[string]ExtractOptions // by Extract variable name
+	ExtractHeaders  ExtractHeaderSpec
+	ExtractXPath    ExtractSpec // from an XML body
+	ExtractCSS      ExtractSpec // from an HTML body; selectors may end in @attr
+	ExtractRegex    []ExtractRegexRule
+	ExtractCookies  ExtractSpec // from Set-Cookie: cookie name, optionally followed by @attr
+	ExtractResponse ExtractSpec // ResponseStatus or ResponseURL
+
+	// Pos is where the request starts in its collection file, and FieldPos
+	// indexes its fields by path (see PosOf). Both are empty for requests
+	// that were not loaded from a file.
+	Pos      SourcePos
+	FieldPos map[string]SourcePos
 }
 
 // Collection groups multiple requests under one logical unit (Git-friendly).
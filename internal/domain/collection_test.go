@@ -1,6 +1,10 @@
 package domain
 
-import "testing"
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
 
 func boolPtr(b bool) *bool { return &b }
 
@@ -70,3 +74,37 @@ func TestValidateJSONBody_InvalidType(t *testing.T) {
 		t.Fatal("expected error for string body")
 	}
 }
+
+func TestExtractRegexRule_Vars(t *testing.T) {
+	rule := ExtractRegexRule{Pattern: `(?P<id>\d+)-(\w+)-(?P<rev>\d+)`}
+	if got := rule.Vars(); !reflect.DeepEqual(got, []string{"id", "rev"}) {
+		t.Fatalf("Vars() = %v", got)
+	}
+	if got := (ExtractRegexRule{Pattern: `(?P<id>`}).Vars(); got != nil {
+		t.Fatalf("expected no vars for an invalid pattern, got %v", got)
+	}
+}
+
+func TestParseCookieRule(t *testing.T) {
+	tests := []struct {
+		rule, name, attr, err string
+	}{
+		{"sid", "sid", "value", ""},
+		{" sid @ Max-Age ", "sid", "max-age", ""},
+		{"__Host-token @httponly", "__Host-token", "httponly", ""},
+		{"@path", "", "", "empty cookie name"},
+		{"sid @size", "", "", `unknown cookie attribute "size"`},
+	}
+	for _, tt := range tests {
+		name, attr, err := ParseCookieRule(tt.rule)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("ParseCookieRule(%q): expected error %q, got %v", tt.rule, tt.err, err)
+			}
+			continue
+		}
+		if err != nil || name != tt.name || attr != tt.attr {
+			t.Errorf("ParseCookieRule(%q) = %q, %q, %v", tt.rule, name, attr, err)
+		}
+	}
+}
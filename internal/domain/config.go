@@ -22,6 +22,15 @@ type RunConfig struct {
 	Cookies    bool // enable an in-memory cookie jar for the run
 	MaxBodyKB  int  // response body cap in KB (0 = default 256)
 	TLS        TLSConfig
+	OTel       OTelConfig
+}
+
+// OTelConfig enables OpenTelemetry trace export for runs. Tracing is off
+// while Endpoint is empty.
+type OTelConfig struct {
+	Endpoint    string            // OTLP/HTTP endpoint, e.g. http://localhost:4318
+	Headers     map[string]string // extra export headers (collector API keys)
+	ServiceName string            // service.name resource attribute (default "lynix")
 }
 
 // TLSConfig holds TLS trust settings.
@@ -51,6 +60,9 @@ type RedactionRule struct {
 	Scope RedactionScope
 }
 
+// RedactedValue is what masking writes in place of a sensitive value.
+const RedactedValue = "********"
+
 type MaskingConfig struct {
 	Enabled bool
 
@@ -86,7 +98,8 @@ type PathsConfig struct {
 type ArtifactsConfig struct {
 	SaveResponseHeaders bool
 	SaveResponseBody    bool
-	MaxRuns             int // 0 = unlimited; when set, oldest runs are pruned after each save
+	// Retention is applied after each save and by `lynix runs prune`.
+	Retention RetentionPolicy
 }
 
 // DefaultConfig provides sane defaults if lynix.yaml is partially missing.
@@ -20,7 +20,7 @@ func BuildDepGraph(requests []RequestSpec, seedVars Vars) DepGraph {
 	produced := make([]map[string]bool, len(requests))
 	for i, req := range requests {
 		consumed[i] = requestConsumedVars(req)
-		produced[i] = requestProducedVars(req)
+		produced[i] = req.ProducedVars()
 	}
 
 	available := make(map[string]bool, len(seedVars))
@@ -98,18 +98,49 @@ func assertVarRefs(spec AssertionsSpec) []string {
 			refs = append(refs, extractVarRefs(*p)...)
 		}
 	}
+	var scanOne func(a ValueAssertion)
+	scanOne = func(a ValueAssertion) {
+		scan(a.Eq)
+		scan(a.NotEq)
+		scan(a.Contains)
+		scan(a.NotContains)
+		scan(a.Matches)
+		scan(a.NotMatches)
+		scan(a.StartsWith)
+		scan(a.EndsWith)
+		scan(a.EqCI)
+		scan(a.Before)
+		scan(a.After)
+		scan(a.Within)
+		for _, list := range [][]string{a.In, a.NotIn, a.ContainsAll} {
+			for i := range list {
+				scan(&list[i])
+			}
+		}
+		for _, nested := range []*ValueAssertion{a.All, a.Any, a.None} {
+			if nested != nil {
+				scanOne(*nested)
+			}
+		}
+		if a.Count != nil {
+			if a.Count.Where != nil {
+				scanOne(*a.Count.Where)
+			}
+			scanOne(a.Count.ValueAssertion)
+		}
+	}
+	for _, e := range spec.Expr {
+		refs = append(refs, extractVarRefs(e.Source)...)
+	}
 	scanVA := func(m map[string]ValueAssertion) {
 		for _, a := range m {
-			scan(a.Eq)
-			scan(a.NotEq)
-			scan(a.Contains)
-			scan(a.NotContains)
-			scan(a.Matches)
-			scan(a.NotMatches)
+			scanOne(a)
 		}
 	}
 	scanVA(spec.JSONPath)
 	scanVA(spec.Headers)
+	scanVA(spec.XPath)
+	scanVA(spec.CSS)
 	if b := spec.Body; b != nil {
 		scan(b.Eq)
 		scan(b.Contains)
@@ -117,10 +148,15 @@ func assertVarRefs(spec AssertionsSpec) []string {
 		scan(b.Matches)
 		scan(b.NotMatches)
 	}
+	for _, a := range spec.JSONEq {
+		refs = append(refs, extractJSONVarRefs(a.Value)...)
+	}
 	return refs
 }
 
-func requestProducedVars(req RequestSpec) map[string]bool {
+// ProducedVars returns the names of the variables req extracts, from every
+// extract block.
+func (req RequestSpec) ProducedVars() map[string]bool {
 	vars := make(map[string]bool)
 	for k := range req.Extract {
 		vars[k] = true
@@ -128,17 +164,47 @@ func requestProducedVars(req RequestSpec) map[string]bool {
 	for k := range req.ExtractHeaders {
 		vars[k] = true
 	}
+	for k := range req.ExtractXPath {
+		vars[k] = true
+	}
+	for k := range req.ExtractCSS {
+		vars[k] = true
+	}
+	for _, rule := range req.ExtractRegex {
+		for _, k := range rule.Vars() {
+			vars[k] = true
+		}
+	}
+	for k := range req.ExtractCookies {
+		vars[k] = true
+	}
+	for k := range req.ExtractResponse {
+		vars[k] = true
+	}
 	return vars
 }
 
-// extractVarRefs scans a string for {{name}} placeholders and returns
-// referenced variable names, excluding $-prefixed builtins.
+// extractVarRefs scans a string for {{name}} (or {{name:type}}) placeholders
+// and returns referenced variable names, excluding $-prefixed builtins.
 func extractVarRefs(s string) []string {
+	var refs []string
+	for _, name := range placeholderNames(s) {
+		name, _ = SplitPlaceholder(name)
+		if name != "" && !strings.HasPrefix(name, "$") {
+			refs = append(refs, name)
+		}
+	}
+	return refs
+}
+
+// placeholderNames returns the trimmed names of the {{...}} placeholders in
+// s, builtins included.
+func placeholderNames(s string) []string {
 	if !strings.Contains(s, "{{") {
 		return nil
 	}
 
-	var refs []string
+	var names []string
 	for i := 0; i < len(s); {
 		if i+1 < len(s) && s[i] == '{' && s[i+1] == '{' {
 			start := i + 2
@@ -147,16 +213,15 @@ func extractVarRefs(s string) []string {
 				break
 			}
 			end = start + end
-			name := strings.TrimSpace(s[start:end])
-			if name != "" && !strings.HasPrefix(name, "$") {
-				refs = append(refs, name)
+			if name := strings.TrimSpace(s[start:end]); name != "" {
+				names = append(names, name)
 			}
 			i = end + 2
 			continue
 		}
 		i++
 	}
-	return refs
+	return names
 }
 
 func extractJSONVarRefs(v any) []string {
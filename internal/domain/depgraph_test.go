@@ -86,6 +86,30 @@ func TestBuildDepGraph_BuiltinsNotDeps(t *testing.T) {
 	}
 }
 
+func TestBuildDepGraph_TypedPlaceholders(t *testing.T) {
+	reqs := []RequestSpec{
+		{Name: "list", URL: "http://e.com/items", Extract: ExtractSpec{"limit": "$.limit"}},
+		{Name: "page", URL: "http://e.com/items", Body: BodySpec{Type: BodyJSON, JSON: map[string]any{"limit": "{{ limit:int }}"}}},
+	}
+	g := BuildDepGraph(reqs, Vars{})
+
+	if len(g.Levels) != 2 {
+		t.Fatalf("expected the typed reference to depend on its extractor, got levels %v", g.Levels)
+	}
+}
+
+func TestBuildDepGraph_ColonInVariableName(t *testing.T) {
+	reqs := []RequestSpec{
+		{Name: "discover", URL: "http://e.com/svc", Extract: ExtractSpec{"svc:port": "$.port"}},
+		{Name: "call", URL: "http://localhost:{{svc:port}}/"},
+	}
+	g := BuildDepGraph(reqs, Vars{})
+
+	if len(g.Levels) != 2 {
+		t.Fatalf("expected {{svc:port}} to depend on its extractor, got levels %v", g.Levels)
+	}
+}
+
 func TestBuildDepGraph_SeedVarsSatisfy(t *testing.T) {
 	reqs := []RequestSpec{
 		{Name: "a", URL: "{{base_url}}/path"},
@@ -140,6 +164,80 @@ func TestBuildDepGraph_ExtractHeaders(t *testing.T) {
 	}
 }
 
+func TestBuildDepGraph_MarkupExtraction(t *testing.T) {
+	orderID := "{{order_id}}"
+	reqs := []RequestSpec{
+		{Name: "form", URL: "http://e.com", ExtractCSS: ExtractSpec{"csrf": `input[name="csrf"] @value`}},
+		{Name: "order", URL: "http://e.com", ExtractXPath: ExtractSpec{"order_id": "//Order/@id"}},
+		{Name: "submit", URL: "http://e.com", Headers: Headers{"X-CSRF": "{{csrf}}"}, Assert: AssertionsSpec{
+			XPath: map[string]ValueAssertion{"//Order/@id": {Eq: &orderID}},
+		}},
+	}
+	g := BuildDepGraph(reqs, Vars{})
+
+	if len(g.Levels) != 2 || len(g.Levels[0]) != 2 {
+		t.Fatalf("expected [[form order] [submit]], got %v", g.Levels)
+	}
+}
+
+func TestBuildDepGraph_RegexCookieAndResponseExtraction(t *testing.T) {
+	reqs := []RequestSpec{
+		{Name: "create", URL: "http://e.com", ExtractRegex: []ExtractRegexRule{{Pattern: `/orders/(?P<order_id>\d+)`, Header: "Location"}}},
+		{Name: "login", URL: "http://e.com", ExtractCookies: ExtractSpec{"session": "sid"}},
+		{Name: "redirect", URL: "http://e.com", ExtractResponse: ExtractSpec{"landing": "url"}},
+		{Name: "get", URL: "http://e.com/orders/{{order_id}}", Headers: Headers{"Cookie": "sid={{session}}"}},
+		{Name: "follow", URL: "{{landing}}"},
+	}
+	g := BuildDepGraph(reqs, Vars{})
+
+	if len(g.Levels) != 2 || len(g.Levels[0]) != 3 || len(g.Levels[1]) != 2 {
+		t.Fatalf("expected [[create login redirect] [get follow]], got %v", g.Levels)
+	}
+}
+
+func TestBuildDepGraph_JSONEqVarRefs(t *testing.T) {
+	reqs := []RequestSpec{
+		{Name: "create", URL: "http://e.com", Extract: ExtractSpec{"id": "$.id"}},
+		{Name: "get", URL: "http://e.com", Assert: AssertionsSpec{
+			JSONEq: []JSONEqAssertion{{Value: map[string]any{"items": []any{map[string]any{"id": "{{id}}"}}}}},
+		}},
+	}
+	g := BuildDepGraph(reqs, Vars{})
+
+	if len(g.Levels) != 2 {
+		t.Fatalf("expected 2 levels (json_eq ref), got %d: %v", len(g.Levels), g.Levels)
+	}
+}
+
+func TestBuildDepGraph_TimeExpressionVarRefs(t *testing.T) {
+	within := "1m of {{created_at}}"
+	reqs := []RequestSpec{
+		{Name: "create", URL: "http://e.com", Extract: ExtractSpec{"created_at": "$.created_at"}},
+		{Name: "get", URL: "http://e.com", Assert: AssertionsSpec{
+			JSONPath: map[string]ValueAssertion{"$.updated_at": {Within: &within}},
+		}},
+	}
+	g := BuildDepGraph(reqs, Vars{})
+
+	if len(g.Levels) != 2 {
+		t.Fatalf("expected 2 levels (within ref), got %d: %v", len(g.Levels), g.Levels)
+	}
+}
+
+func TestBuildDepGraph_ExprVarRefs(t *testing.T) {
+	reqs := []RequestSpec{
+		{Name: "create", URL: "http://e.com", Extract: ExtractSpec{"total": "$.total"}},
+		{Name: "get", URL: "http://e.com", Assert: AssertionsSpec{
+			Expr: []ExprAssertion{{Source: "$.total == {{total}}"}},
+		}},
+	}
+	g := BuildDepGraph(reqs, Vars{})
+
+	if len(g.Levels) != 2 {
+		t.Fatalf("expected 2 levels (expr ref), got %d: %v", len(g.Levels), g.Levels)
+	}
+}
+
 func TestExtractVarRefs_Basic(t *testing.T) {
 	refs := extractVarRefs("{{base_url}}/users/{{user_id}}")
 	if !reflect.DeepEqual(refs, []string{"base_url", "user_id"}) {
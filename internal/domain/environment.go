@@ -1,8 +1,60 @@
 package domain
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
 // Vars is a key/value store used for templating and runtime variable resolution.
 type Vars map[string]string
 
+// VarType says how a variable fills a json body string that consists of its
+// placeholder alone ("{{name}}"). Untyped variables are substituted as strings.
+type VarType string
+
+const (
+	VarString VarType = "string" // the default
+	VarInt    VarType = "int"    // substituted as an integer
+	VarNumber VarType = "number" // substituted as a number
+	VarBool   VarType = "bool"   // substituted as true or false
+	VarJSON   VarType = "json"   // the value is JSON text, substituted decoded
+)
+
+// VarTypes lists the valid variable types, in the order messages show them.
+var VarTypes = []VarType{VarString, VarInt, VarNumber, VarBool, VarJSON}
+
+// ParseVarType validates a type name as written in a collection, an
+// environment or a "{{name:type}}" placeholder.
+func ParseVarType(s string) (VarType, error) {
+	t := VarType(strings.TrimSpace(s))
+	if slices.Contains(VarTypes, t) {
+		return t, nil
+	}
+	names := make([]string, len(VarTypes))
+	for i, vt := range VarTypes {
+		names[i] = string(vt)
+	}
+	return "", fmt.Errorf("unknown type %q (expected one of: %s)", s, strings.Join(names, ", "))
+}
+
+// SplitPlaceholder splits the trimmed text of a placeholder, "name" or
+// "name:type", into the variable name and its type. The suffix is a type only
+// when it names one; otherwise, as in {{svc:port}}, the whole text is the
+// name and the type is "".
+func SplitPlaceholder(inner string) (string, VarType) {
+	inner = strings.TrimSpace(inner)
+	i := strings.LastIndex(inner, ":")
+	if i < 0 {
+		return inner, ""
+	}
+	t, err := ParseVarType(inner[i+1:])
+	if err != nil {
+		return inner, ""
+	}
+	return strings.TrimSpace(inner[:i]), t
+}
+
 // Environment defines variables for a given runtime context (dev/stg/prod).
 // Secrets may be merged on top by infrastructure implementations.
 type Environment struct {
@@ -10,11 +62,22 @@ type Environment struct {
 	Name          string
 	Vars          Vars
 
+	// Types annotates variables with the type they take in json bodies.
+	Types map[string]VarType
+
 	// SecretValues holds the raw values that came from the secrets file.
 	// Redaction uses them as literal scrub targets on every output surface.
 	SecretValues []string
 }
 
+// ApplyTypes records the environment's type annotations in types, the map
+// carried next to the vars of a run.
+func (e Environment) ApplyTypes(types map[string]VarType) {
+	for name, t := range e.Types {
+		types[name] = t
+	}
+}
+
 // Get returns a value for the given key and a boolean indicating if it exists.
 func Get(vars Vars, key string) (string, bool) {
 	if vars == nil {
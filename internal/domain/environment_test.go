@@ -1,6 +1,10 @@
 package domain
 
-import "testing"
+import (
+	"maps"
+	"strings"
+	"testing"
+)
 
 func TestGetSetVars(t *testing.T) {
 	vars := Vars{}
@@ -45,3 +49,26 @@ func TestMergeVars(t *testing.T) {
 		t.Fatalf("expected base to remain unchanged")
 	}
 }
+
+func TestParseVarType(t *testing.T) {
+	for _, s := range []string{"string", "int", "number", "bool", " json "} {
+		if _, err := ParseVarType(s); err != nil {
+			t.Errorf("ParseVarType(%q): %v", s, err)
+		}
+	}
+	_, err := ParseVarType("integer")
+	if err == nil || !strings.Contains(err.Error(), `unknown type "integer" (expected one of: string, int, number, bool, json)`) {
+		t.Fatalf("expected an unknown type error, got %v", err)
+	}
+}
+
+func TestEnvironment_ApplyTypes(t *testing.T) {
+	env := Environment{Types: map[string]VarType{"limit": VarInt, "debug": VarBool}}
+	types := map[string]VarType{"limit": VarString, "page": VarNumber}
+	env.ApplyTypes(types)
+
+	want := map[string]VarType{"limit": VarInt, "debug": VarBool, "page": VarNumber}
+	if !maps.Equal(types, want) {
+		t.Fatalf("expected annotated types %v, got %v", want, types)
+	}
+}
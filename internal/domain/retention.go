@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetentionPolicy decides which saved runs are deleted, after each save and
+// by `lynix runs prune`. A zero field disables its rule; a run is deleted as
+// soon as any rule selects it.
+type RetentionPolicy struct {
+	MaxRuns              int // newest N runs overall are kept (artifacts.max_runs)
+	MaxRunsPerCollection int // newest N runs of each collection are kept
+
+	MaxAge       time.Duration // any run older than this
+	MaxAgeFailed time.Duration // runs with a failed or errored request; overrides MaxAge
+	MaxAgePassed time.Duration // runs where every request passed; overrides MaxAge
+}
+
+func (p RetentionPolicy) IsZero() bool {
+	return p == RetentionPolicy{}
+}
+
+// AgeLimit returns the maximum age for a run with the given outcome (0 when
+// unlimited).
+func (p RetentionPolicy) AgeLimit(failed bool) time.Duration {
+	if failed && p.MaxAgeFailed > 0 {
+		return p.MaxAgeFailed
+	}
+	if !failed && p.MaxAgePassed > 0 {
+		return p.MaxAgePassed
+	}
+	return p.MaxAge
+}
+
+// ParseAge parses a retention age: a Go duration ("36h", "90m") or a whole
+// number of days or weeks ("7d", "2w").
+func ParseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("invalid age %q (expected e.g. 7d, 2w or 36h)", s)
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q (expected e.g. 7d, 2w or 36h)", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"7d", 7 * 24 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"36h", 36 * time.Hour},
+		{" 90m ", 90 * time.Minute},
+		{"0d", 0},
+	}
+	for _, tt := range tests {
+		got, err := ParseAge(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseAge(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "d", "1.5d", "-3d", "-1h", "week"} {
+		if _, err := ParseAge(bad); err == nil {
+			t.Errorf("ParseAge(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestRetentionPolicy_AgeLimit(t *testing.T) {
+	p := RetentionPolicy{MaxAge: 10 * time.Hour, MaxAgePassed: time.Hour}
+	if got := p.AgeLimit(false); got != time.Hour {
+		t.Errorf("passing runs: got %v", got)
+	}
+	if got := p.AgeLimit(true); got != 10*time.Hour {
+		t.Errorf("failed runs fall back to MaxAge, got %v", got)
+	}
+	if !(RetentionPolicy{}).IsZero() || p.IsZero() {
+		t.Error("IsZero")
+	}
+}
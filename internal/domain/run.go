@@ -40,9 +40,43 @@ type RunError struct {
 
 // AssertionResult is the output of a single assertion.
 type AssertionResult struct {
-	Name    string `json:"name"`
+	Name string `json:"name"`
+	// Key is the JSONPath expression or header name a jsonpath/header
+	// assertion checked; empty for other assertions.
+	Key     string `json:"key,omitempty"`
 	Passed  bool   `json:"passed"`
 	Message string `json:"message,omitempty"`
+
+	// Diff lists what differed for assertions that compare whole bodies
+	// (snapshot); empty otherwise.
+	Diff []DiffEntry `json:"diff,omitempty"`
+}
+
+// DiffEntry is one difference between an expected and an actual body: a
+// value at a JSONPath for JSON bodies, or a line (Path empty) for text.
+// Old is what was expected, New what was received; values of JSON entries
+// are JSON-encoded.
+type DiffEntry struct {
+	Path string `json:"path,omitempty"`
+	Kind string `json:"kind"` // added | removed | changed
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+// String renders the entry as one line: "~ $.a: 1 → 2", "+ $.b: true",
+// "- some line".
+func (d DiffEntry) String() string {
+	sign, val := "~", d.Old+" → "+d.New
+	switch d.Kind {
+	case "added":
+		sign, val = "+", d.New
+	case "removed":
+		sign, val = "-", d.Old
+	}
+	if d.Path == "" {
+		return sign + " " + val
+	}
+	return sign + " " + d.Path + ": " + val
 }
 
 // BodyBytes is a byte slice that serializes as readable text when it is valid
@@ -89,6 +123,10 @@ type ResponseSnapshot struct {
 	Headers   map[string][]string `json:"headers,omitempty"`
 	Body      BodyBytes           `json:"body,omitempty"`
 	Truncated bool                `json:"truncated,omitempty"`
+
+	// FinalURL is the URL of the response when redirects were followed
+	// (empty when it is the request URL).
+	FinalURL string `json:"final_url,omitempty"`
 }
 
 // RequestResult represents the result of executing a single request.
@@ -119,6 +157,15 @@ type RequestResult struct {
 	Attempts int              `json:"attempts,omitempty"`
 }
 
+// FinalURL returns the URL the response came from: the last redirect
+// target, or the resolved request URL.
+func (r RequestResult) FinalURL() string {
+	if r.Response.FinalURL != "" {
+		return r.Response.FinalURL
+	}
+	return r.ResolvedURL
+}
+
 // Failed reports whether this request should be considered failed:
 // runner error, any assertion failure, or any extract failure.
 func (r RequestResult) Failed() bool {
@@ -154,6 +201,10 @@ type RunResult struct {
 	StartedAt time.Time `json:"started_at"`
 	EndedAt   time.Time `json:"ended_at"`
 
+	// Metadata describes what was tested and where: git commit/branch, CI
+	// job, host, lynix version and user labels.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
 	Results []RequestResult `json:"results"`
 }
 
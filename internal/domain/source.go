@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SourcePos is a 1-based line/column in a collection file. The zero value
+// means the position is unknown (e.g. the collection was built in memory).
+type SourcePos struct {
+	Line   int
+	Column int
+}
+
+func (p SourcePos) IsZero() bool { return p.Line == 0 }
+
+// SourceError locates an error in a source file. Its message is the wrapped
+// error's: the position is meant for tools (CI annotations, SARIF), and
+// errors.As still reaches the underlying error.
+type SourceError struct {
+	Path string
+	Pos  SourcePos
+	Err  error
+}
+
+func (e *SourceError) Error() string {
+	if e == nil || e.Err == nil {
+		return "<nil>"
+	}
+	return e.Err.Error()
+}
+
+func (e *SourceError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// PosOf returns the position of a field of the request, given as a path
+// relative to the request ("assert.status", "assert.jsonpath[$.id].eq",
+// `extract["token"]`). Unknown fields fall back to their closest known
+// parent, and finally to the request itself.
+func (r RequestSpec) PosOf(field string) SourcePos {
+	if p, ok := LookupPos(r.FieldPos, field); ok {
+		return p
+	}
+	return r.Pos
+}
+
+// AssertionPos returns the position of the assertion that produced a.
+func (r RequestSpec) AssertionPos(a AssertionResult) SourcePos {
+	kind, op, _ := strings.Cut(a.Name, ".")
+	switch kind {
+	case "status", "max_ms", "max_ms_regression", "snapshot", "json_eq":
+		return r.PosOf("assert." + kind)
+	case "schema":
+		if p, ok := r.FieldPos["assert.schema"]; ok {
+			return p
+		}
+		return r.PosOf("assert.schema_inline")
+	case "body":
+		return r.PosOf("assert.body." + op)
+	case "jsonpath":
+		return r.PosOf("assert.jsonpath[" + a.Key + "]." + op)
+	case "header":
+		return r.PosOf("assert.headers[" + a.Key + "]." + op)
+	case "xpath", "css":
+		return r.PosOf("assert." + kind + "[" + a.Key + "]." + op)
+	case "expr":
+		for i, e := range r.Assert.Expr {
+			if e.Source == a.Key {
+				return r.PosOf("assert.expr[" + strconv.Itoa(i) + "]")
+			}
+		}
+		return r.PosOf("assert.expr")
+	}
+	return r.PosOf("assert")
+}
+
+// ExtractPos returns the position of the extract rule that produced e.
+func (r RequestSpec) ExtractPos(e ExtractResult) SourcePos {
+	for _, block := range []string{"extract_headers", "extract_xpath", "extract_css", "extract_cookies", "extract_response"} {
+		if _, ok := r.FieldPos[block+"["+e.Name+"]"]; ok {
+			return r.PosOf(block + "[" + e.Name + "]")
+		}
+	}
+	for i, rule := range r.ExtractRegex {
+		if slices.Contains(rule.Vars(), e.Name) {
+			return r.PosOf("extract_regex[" + strconv.Itoa(i) + "]")
+		}
+	}
+	return r.PosOf("extract[" + e.Name + "]")
+}
+
+var quotedKey = regexp.MustCompile(`\["((?:[^"\\]|\\.)*)"\]`)
+
+// LookupPos finds field in an index of field paths. Map keys are written
+// [key]; a quoted ["key"] form is accepted too. When the exact field is not
+// indexed, the closest indexed parent is returned.
+func LookupPos(index map[string]SourcePos, field string) (SourcePos, bool) {
+	if len(index) == 0 {
+		return SourcePos{}, false
+	}
+	field = quotedKey.ReplaceAllStringFunc(field, func(m string) string {
+		k, err := strconv.Unquote(m[1 : len(m)-1])
+		if err != nil {
+			return m
+		}
+		return "[" + k + "]"
+	})
+	for field != "" {
+		if p, ok := index[field]; ok {
+			return p, true
+		}
+		field = parentField(field)
+	}
+	return SourcePos{}, false
+}
+
+// parentField drops the last segment of a field path: "a.b[c.d]" → "a.b",
+// "a.b" → "a". Brackets may nest (JSONPath keys such as $.items[0]).
+func parentField(field string) string {
+	if strings.HasSuffix(field, "]") {
+		depth := 0
+		for i := len(field) - 1; i >= 0; i-- {
+			switch field[i] {
+			case ']':
+				depth++
+			case '[':
+				depth--
+				if depth == 0 {
+					return field[:i]
+				}
+			}
+		}
+		return ""
+	}
+	i := strings.LastIndexAny(field, ".]")
+	if i < 0 {
+		return ""
+	}
+	if field[i] == ']' {
+		return field[:i+1]
+	}
+	return field[:i]
+}
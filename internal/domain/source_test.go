@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLookupPos(t *testing.T) {
+	index := map[string]SourcePos{
+		"requests[0]":        {Line: 3, Column: 5},
+		"requests[0].assert": {Line: 7, Column: 7},
+		"requests[0].assert.jsonpath[$.items[0]]":  {Line: 9, Column: 11},
+		"requests[0].assert.jsonpath[$.a.b].eq":    {Line: 12, Column: 13},
+		"requests[0].extract[token]":               {Line: 15, Column: 9},
+		"requests[0].assert.headers[Content-Type]": {Line: 17, Column: 11},
+	}
+
+	tests := []struct {
+		field string
+		want  int
+	}{
+		{"requests[0].assert.jsonpath[$.a.b].eq", 12},
+		{`requests[0].assert.jsonpath["$.a.b"].eq`, 12},
+		{"requests[0].assert.jsonpath[$.items[0]].len", 9},
+		{"requests[0].assert.status", 7},
+		{`requests[0].extract["token"]`, 15},
+		{"requests[0].url", 3},
+		{"requests[1].url", 0},
+	}
+	for _, tt := range tests {
+		got, ok := LookupPos(index, tt.field)
+		if got.Line != tt.want || ok != (tt.want != 0) {
+			t.Errorf("LookupPos(%q) = %v, %v; want line %d", tt.field, got, ok, tt.want)
+		}
+	}
+}
+
+func TestRequestSpec_AssertionAndExtractPos(t *testing.T) {
+	req := RequestSpec{
+		Pos: SourcePos{Line: 1},
+		FieldPos: map[string]SourcePos{
+			"assert":                     {Line: 4},
+			"assert.status":              {Line: 5},
+			"assert.jsonpath[$.id]":      {Line: 7},
+			"assert.jsonpath[$.id].eq":   {Line: 8},
+			"assert.headers[X-Trace]":    {Line: 10},
+			"extract_headers[trace]":     {Line: 12},
+			"assert.schema_inline":       {Line: 14},
+			"assert.body":                {Line: 16},
+			"assert.body.contains":       {Line: 17},
+			"assert.headers[X-Trace].eq": {Line: 11},
+			"assert.expr":                {Line: 19},
+			"assert.expr[1]":             {Line: 21},
+			"assert.xpath[//id].eq":      {Line: 24},
+			"assert.css[h1]":             {Line: 26},
+			"extract_css[title]":         {Line: 28},
+			"extract_regex[1]":           {Line: 31},
+			"extract_cookies[session]":   {Line: 34},
+		},
+		Assert: AssertionsSpec{Expr: []ExprAssertion{{Source: "$.a == 1"}, {Source: "$.b > $.a"}}},
+		ExtractRegex: []ExtractRegexRule{
+			{Pattern: `id=(?P<id>\d+)`},
+			{Pattern: `v=(?P<version>\d+)`, Header: "Location"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		got  SourcePos
+		want int
+	}{
+		{"status", req.AssertionPos(AssertionResult{Name: "status"}), 5},
+		{"jsonpath op", req.AssertionPos(AssertionResult{Name: "jsonpath.eq", Key: "$.id"}), 8},
+		{"jsonpath other op", req.AssertionPos(AssertionResult{Name: "jsonpath.exists", Key: "$.id"}), 7},
+		{"header", req.AssertionPos(AssertionResult{Name: "header.eq", Key: "X-Trace"}), 11},
+		{"schema inline", req.AssertionPos(AssertionResult{Name: "schema"}), 14},
+		{"body", req.AssertionPos(AssertionResult{Name: "body.contains"}), 17},
+		{"max_ms falls back to assert", req.AssertionPos(AssertionResult{Name: "max_ms"}), 4},
+		{"expr", req.AssertionPos(AssertionResult{Name: "expr", Key: "$.b > $.a"}), 21},
+		{"xpath op", req.AssertionPos(AssertionResult{Name: "xpath.eq", Key: "//id"}), 24},
+		{"css other op", req.AssertionPos(AssertionResult{Name: "css.len", Key: "h1"}), 26},
+		{"header extract", req.ExtractPos(ExtractResult{Name: "trace"}), 12},
+		{"css extract", req.ExtractPos(ExtractResult{Name: "title"}), 28},
+		{"regex extract", req.ExtractPos(ExtractResult{Name: "version"}), 31},
+		{"cookie extract", req.ExtractPos(ExtractResult{Name: "session"}), 34},
+		{"unknown extract", req.ExtractPos(ExtractResult{Name: "nope"}), 1},
+	}
+	for _, tt := range tests {
+		if tt.got.Line != tt.want {
+			t.Errorf("%s: line %d, want %d", tt.name, tt.got.Line, tt.want)
+		}
+	}
+}
+
+func TestSourceError_KeepsMessageAndUnwraps(t *testing.T) {
+	inner := &OpError{Op: "x", Kind: KindInvalidConfig, Err: ErrInvalidConfig}
+	err := error(&SourceError{Path: "c.yaml", Pos: SourcePos{Line: 3}, Err: inner})
+
+	if err.Error() != inner.Error() {
+		t.Errorf("message: got %q", err.Error())
+	}
+	var oe *OpError
+	if !errors.As(err, &oe) || !errors.Is(err, ErrInvalidConfig) {
+		t.Error("SourceError should unwrap to the OpError")
+	}
+}
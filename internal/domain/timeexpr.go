@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeFormats are the named formats of ValueAssertion.Format. Any other
+// format is a Go reference layout ("2006-01-02 15:04").
+var TimeFormats = map[string]string{
+	"rfc3339": time.RFC3339,
+	"rfc1123": time.RFC1123,
+	"date":    time.DateOnly,
+	"unix":    "",
+	"unix_ms": "",
+}
+
+// unixMillisThreshold separates Unix seconds from milliseconds when the
+// unit is not given: 1e11 seconds is the year 5138, 1e11 ms is 1973.
+const unixMillisThreshold = 1e11
+
+// ParseTime parses a timestamp in format. Without a format it accepts
+// RFC 3339, "2006-01-02 15:04:05", a date, RFC 1123 and Unix seconds or
+// milliseconds (told apart by magnitude).
+func ParseTime(s, format string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	switch format {
+	case "":
+		for _, layout := range []string{time.RFC3339, time.DateTime, time.DateOnly, time.RFC1123, time.RFC1123Z} {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		if t, ok := parseUnix(s, 0); ok {
+			return t, nil
+		}
+		return time.Time{}, fmt.Errorf("%q is not a recognized timestamp", s)
+	case "unix":
+		if t, ok := parseUnix(s, time.Second); ok {
+			return t, nil
+		}
+	case "unix_ms":
+		if t, ok := parseUnix(s, time.Millisecond); ok {
+			return t, nil
+		}
+	default:
+		layout := format
+		if named, ok := TimeFormats[format]; ok {
+			layout = named
+		}
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a %s timestamp", s, format)
+}
+
+// parseUnix parses a (possibly fractional) Unix time. A zero unit picks
+// seconds or milliseconds by magnitude.
+func parseUnix(s string, unit time.Duration) (time.Time, bool) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return time.Time{}, false
+	}
+	if unit == 0 {
+		unit = time.Second
+		if math.Abs(f) >= unixMillisThreshold {
+			unit = time.Millisecond
+		}
+	}
+	ns := f * float64(unit)
+	return time.Unix(0, int64(ns)).UTC(), true
+}
+
+// ParseTimeExpr parses the expected side of a time comparison: "now",
+// "now-1h", "now+30m", "now-7d", or a timestamp (in format, or any
+// recognized one).
+func ParseTimeExpr(expr, format string, now time.Time) (time.Time, error) {
+	expr = strings.TrimSpace(expr)
+	if rest, ok := strings.CutPrefix(expr, "now"); ok {
+		rest = strings.TrimSpace(rest)
+		if rest == "" {
+			return now, nil
+		}
+		sign := rest[0]
+		if sign != '+' && sign != '-' {
+			return time.Time{}, fmt.Errorf("invalid time expression %q (expected now, now-1h, now+30m or a timestamp)", expr)
+		}
+		age, err := ParseAge(strings.TrimSpace(rest[1:]))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time expression %q: %w", expr, err)
+		}
+		if sign == '-' {
+			age = -age
+		}
+		return now.Add(age), nil
+	}
+	if format != "" {
+		if t, err := ParseTime(expr, format); err == nil {
+			return t, nil
+		}
+	}
+	return ParseTime(expr, "")
+}
+
+// ParseWithin splits a within expression, "5m" or "5m of <time expr>", into
+// the window and the reference time expression ("now" when omitted).
+func ParseWithin(s string) (time.Duration, string, error) {
+	window, ref, found := strings.Cut(strings.TrimSpace(s), " of ")
+	if !found {
+		ref = "now"
+	}
+	d, err := ParseAge(strings.TrimSpace(window))
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid within %q: %w", s, err)
+	}
+	return d, strings.TrimSpace(ref), nil
+}
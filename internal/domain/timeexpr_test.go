@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	want := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		in, format string
+	}{
+		{"2026-03-01T12:30:00Z", ""},
+		{"2026-03-01T14:30:00+02:00", ""},
+		{"2026-03-01 12:30:00", ""},
+		{"Sun, 01 Mar 2026 12:30:00 UTC", ""},
+		{"1772368200", ""},
+		{"1772368200000", ""},
+		{"1772368200", "unix"},
+		{"1772368200000", "unix_ms"},
+		{"2026-03-01T12:30:00Z", "rfc3339"},
+		{"01/03/2026 12:30", "02/01/2006 15:04"},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.in, tt.format)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseTime(%q, %q) = %v, %v; want %v", tt.in, tt.format, got, err, want)
+		}
+	}
+
+	if got, err := ParseTime("2026-03-01", "date"); err != nil || !got.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("date = %v, %v", got, err)
+	}
+
+	for _, bad := range []struct{ in, format string }{
+		{"yesterday", ""},
+		{"2026-03-01", "rfc3339"},
+		{"2026-03-01T12:30:00Z", "unix"},
+		{"12:30", "2006-01-02"},
+	} {
+		if _, err := ParseTime(bad.in, bad.format); err == nil {
+			t.Errorf("ParseTime(%q, %q): expected an error", bad.in, bad.format)
+		}
+	}
+}
+
+func TestParseTimeExpr(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"now", now},
+		{"now-1h", now.Add(-time.Hour)},
+		{"now + 30m", now.Add(30 * time.Minute)},
+		{"now-7d", now.AddDate(0, 0, -7)},
+		{"2026-01-01", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimeExpr(tt.in, "", now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseTimeExpr(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	// An expected timestamp may use another format than the value.
+	if got, err := ParseTimeExpr("2026-01-01T00:00:00Z", "unix", now); err != nil || got.Year() != 2026 {
+		t.Errorf("fallback = %v, %v", got, err)
+	}
+
+	for _, bad := range []string{"now*2", "now-", "now-1y", "tomorrow"} {
+		if _, err := ParseTimeExpr(bad, "", now); err == nil {
+			t.Errorf("ParseTimeExpr(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestParseWithin(t *testing.T) {
+	d, ref, err := ParseWithin("5m")
+	if err != nil || d != 5*time.Minute || ref != "now" {
+		t.Errorf("5m = %v, %q, %v", d, ref, err)
+	}
+	d, ref, err = ParseWithin("2d of {{created_at}}")
+	if err != nil || d != 48*time.Hour || ref != "{{created_at}}" {
+		t.Errorf("2d of var = %v, %q, %v", d, ref, err)
+	}
+	if _, _, err := ParseWithin("soon of now"); err == nil {
+		t.Error("expected an error for a bad window")
+	}
+}
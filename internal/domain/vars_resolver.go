@@ -4,9 +4,11 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"strconv"
 	"strings"
@@ -22,6 +24,7 @@ type VarResolver struct {
 	uuidV4     func() (string, error)
 	randSource io.Reader
 	lookupEnv  func(string) (string, bool)
+	deferred   map[string]bool
 }
 
 // VarResolverOption configures VarResolver.
@@ -60,17 +63,30 @@ func NewVarResolver(opts ...VarResolverOption) *VarResolver {
 	return r
 }
 
+// Deferring returns a copy of r for which the named variables exist but have
+// no value yet, like those a request extracts for later ones. Their
+// placeholders stay as written and skip type conversion; a value in vars
+// still wins. Validation uses it to check a collection before it runs.
+func (r *VarResolver) Deferring(names map[string]bool) *VarResolver {
+	cp := *r
+	cp.deferred = names
+	return &cp
+}
+
 // RuntimeResolver caches built-ins for a single "resolution session" (e.g., one request run)
 // so repeated {{$uuid}} inside multiple fields stays consistent.
 type RuntimeResolver struct {
 	base     Vars
+	types    map[string]VarType
 	builtins Vars
 	inner    *VarResolver
 }
 
 const alphanumeric = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 
-func (r *VarResolver) NewRuntime(vars Vars) (*RuntimeResolver, error) {
+// NewRuntime starts a resolution session over vars. types holds the types of
+// the variables that are not strings; it may be nil.
+func (r *VarResolver) NewRuntime(vars Vars, types map[string]VarType) (*RuntimeResolver, error) {
 	now := r.now()
 	ts := strconv.FormatInt(now.Unix(), 10)
 	isoTS := now.UTC().Format(time.RFC3339)
@@ -91,7 +107,8 @@ func (r *VarResolver) NewRuntime(vars Vars) (*RuntimeResolver, error) {
 	}
 
 	return &RuntimeResolver{
-		base: baseCopy,
+		base:  baseCopy,
+		types: maps.Clone(types),
 		builtins: Vars{
 			"$timestamp":    ts,
 			"$uuid":         u,
@@ -251,9 +268,15 @@ func (rr *RuntimeResolver) ResolveRequest(req RequestSpec) (RequestSpec, error)
 
 // ResolveJSONValue recursively resolves string values inside JSON-like structures.
 // Supported types: map[string]any, []any, string, numbers/bools/nil (left unchanged).
+// A string that is a single typed placeholder ("{{limit:int}}", or a
+// variable with a type) becomes the converted value, so a number is sent
+// as a number and an extracted object as an object.
 func (rr *RuntimeResolver) ResolveJSONValue(v any) (any, error) {
 	switch t := v.(type) {
 	case string:
+		if v, ok, err := rr.inner.resolveTyped(rr.base, rr.types, rr.builtins, t); ok {
+			return v, err
+		}
 		return rr.ResolveString(t)
 
 	case map[string]any:
@@ -285,21 +308,13 @@ func (rr *RuntimeResolver) ResolveJSONValue(v any) (any, error) {
 }
 
 // ResolveAssertionValues resolves {{var}} references in the string-typed
-// expected values of an assertion spec (eq, contains, matches, ... and the
-// body block), enabling comparisons against previously extracted variables.
+// expected values of an assertion spec (eq, contains, matches, in, ... and the
+// body block) and in the strings of json_eq documents, enabling comparisons against previously extracted variables.
 // Builtins are intentionally unavailable: a freshly generated {{$uuid}} could
 // never match the one sent with the request. {{$env.NAME}} still works.
-func (r *VarResolver) ResolveAssertionValues(vars Vars, spec AssertionsSpec) (AssertionsSpec, error) {
-	resolve := func(p *string) (*string, error) {
-		if p == nil {
-			return nil, nil
-		}
-		s, err := r.resolveStringWith(vars, Vars{}, *p)
-		if err != nil {
-			return nil, err
-		}
-		return &s, nil
-	}
+// types, which may be nil, gives json_eq placeholders their variable types.
+func (r *VarResolver) ResolveAssertionValues(vars Vars, types map[string]VarType, spec AssertionsSpec) (AssertionsSpec, error) {
+	resolve := func(p *string) (*string, error) { return r.resolveStringPtr(vars, p) }
 
 	resolveVA := func(in map[string]ValueAssertion) (map[string]ValueAssertion, error) {
 		if len(in) == 0 {
@@ -307,26 +322,11 @@ func (r *VarResolver) ResolveAssertionValues(vars Vars, spec AssertionsSpec) (As
 		}
 		out := make(map[string]ValueAssertion, len(in))
 		for k, a := range in {
-			var err error
-			if a.Eq, err = resolve(a.Eq); err != nil {
-				return nil, err
-			}
-			if a.NotEq, err = resolve(a.NotEq); err != nil {
-				return nil, err
-			}
-			if a.Contains, err = resolve(a.Contains); err != nil {
-				return nil, err
-			}
-			if a.NotContains, err = resolve(a.NotContains); err != nil {
-				return nil, err
-			}
-			if a.Matches, err = resolve(a.Matches); err != nil {
-				return nil, err
-			}
-			if a.NotMatches, err = resolve(a.NotMatches); err != nil {
+			resolved, err := r.resolveValueAssertion(vars, a)
+			if err != nil {
 				return nil, err
 			}
-			out[k] = a
+			out[k] = resolved
 		}
 		return out, nil
 	}
@@ -339,6 +339,12 @@ func (r *VarResolver) ResolveAssertionValues(vars Vars, spec AssertionsSpec) (As
 	if out.Headers, err = resolveVA(spec.Headers); err != nil {
 		return AssertionsSpec{}, err
 	}
+	if out.XPath, err = resolveVA(spec.XPath); err != nil {
+		return AssertionsSpec{}, err
+	}
+	if out.CSS, err = resolveVA(spec.CSS); err != nil {
+		return AssertionsSpec{}, err
+	}
 	if spec.Body != nil {
 		b := *spec.Body
 		if b.Eq, err = resolve(b.Eq); err != nil {
@@ -358,9 +364,148 @@ func (r *VarResolver) ResolveAssertionValues(vars Vars, spec AssertionsSpec) (As
 		}
 		out.Body = &b
 	}
+	if len(spec.JSONEq) > 0 {
+		out.JSONEq = make([]JSONEqAssertion, len(spec.JSONEq))
+		for i, a := range spec.JSONEq {
+			if a.Value, err = r.resolveDocument(vars, types, a.Value); err != nil {
+				return AssertionsSpec{}, err
+			}
+			out.JSONEq[i] = a
+		}
+	}
+	if len(spec.Expr) > 0 {
+		out.Expr = make([]ExprAssertion, len(spec.Expr))
+		for i, e := range spec.Expr {
+			if e.Vars, err = r.resolveExprVars(vars, e.Source); err != nil {
+				return AssertionsSpec{}, err
+			}
+			out.Expr[i] = e
+		}
+	}
+	return out, nil
+}
+
+// resolveExprVars resolves each {{var}} reference of an expression on its
+// own: the values are operands, never spliced into the source.
+func (r *VarResolver) resolveExprVars(vars Vars, src string) (Vars, error) {
+	names := placeholderNames(src)
+	if len(names) == 0 {
+		return nil, nil
+	}
+	out := make(Vars, len(names))
+	for _, name := range names {
+		v, err := r.resolveStringWith(vars, Vars{}, "{{"+name+"}}")
+		if err != nil {
+			return nil, err
+		}
+		out[name] = v
+	}
 	return out, nil
 }
 
+// resolveStringPtr resolves an optional expected value without builtins.
+func (r *VarResolver) resolveStringPtr(vars Vars, p *string) (*string, error) {
+	if p == nil {
+		return nil, nil
+	}
+	s, err := r.resolveStringWith(vars, Vars{}, *p)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// resolveValueAssertion resolves the expected values of a, including those
+// of its nested array assertions, which are copied.
+func (r *VarResolver) resolveValueAssertion(vars Vars, a ValueAssertion) (ValueAssertion, error) {
+	resolve := func(p *string) (*string, error) { return r.resolveStringPtr(vars, p) }
+	resolveList := func(in []string) ([]string, error) {
+		if len(in) == 0 {
+			return in, nil
+		}
+		out := make([]string, len(in))
+		for i, s := range in {
+			var err error
+			if out[i], err = r.resolveStringWith(vars, Vars{}, s); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	resolveNested := func(p *ValueAssertion) (*ValueAssertion, error) {
+		if p == nil {
+			return nil, nil
+		}
+		resolved, err := r.resolveValueAssertion(vars, *p)
+		if err != nil {
+			return nil, err
+		}
+		return &resolved, nil
+	}
+
+	var err error
+	for _, p := range []**string{&a.Eq, &a.NotEq, &a.Contains, &a.NotContains, &a.Matches, &a.NotMatches,
+		&a.StartsWith, &a.EndsWith, &a.EqCI, &a.Before, &a.After, &a.Within} {
+		if *p, err = resolve(*p); err != nil {
+			return ValueAssertion{}, err
+		}
+	}
+	for _, p := range []*[]string{&a.In, &a.NotIn, &a.ContainsAll} {
+		if *p, err = resolveList(*p); err != nil {
+			return ValueAssertion{}, err
+		}
+	}
+	for _, p := range []**ValueAssertion{&a.All, &a.Any, &a.None} {
+		if *p, err = resolveNested(*p); err != nil {
+			return ValueAssertion{}, err
+		}
+	}
+	if a.Count != nil {
+		c := *a.Count
+		if c.Where, err = resolveNested(c.Where); err != nil {
+			return ValueAssertion{}, err
+		}
+		if c.ValueAssertion, err = r.resolveValueAssertion(vars, c.ValueAssertion); err != nil {
+			return ValueAssertion{}, err
+		}
+		a.Count = &c
+	}
+	return a, nil
+}
+
+// resolveDocument resolves the strings of a decoded document, copying maps
+// and slices so the spec it came from stays untouched.
+func (r *VarResolver) resolveDocument(vars Vars, types map[string]VarType, v any) (any, error) {
+	switch t := v.(type) {
+	case string:
+		if typed, ok, err := r.resolveTyped(vars, types, Vars{}, t); ok {
+			return typed, err
+		}
+		return r.resolveStringWith(vars, Vars{}, t)
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			resolved, err := r.resolveDocument(vars, types, val)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = resolved
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(t))
+		for i, val := range t {
+			resolved, err := r.resolveDocument(vars, types, val)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
 func (r *VarResolver) resolveStringWith(vars Vars, builtins Vars, s string) (string, error) {
 	// Fast path: no token start.
 	if !strings.Contains(s, "{{") {
@@ -386,39 +531,24 @@ func (r *VarResolver) resolveStringWith(vars Vars, builtins Vars, s string) (str
 			}
 			end = start + end
 
-			name := strings.TrimSpace(s[start:end])
-			if name == "" {
-				return "", &OpError{
-					Op:   "vars.resolve",
-					Kind: KindInvalidConfig,
-					Err:  errors.New("empty placeholder"),
-				}
+			name, typ, err := splitPlaceholder(s[start:end])
+			if err != nil {
+				return "", err
 			}
-
-			// {{$env.NAME}} reads the process environment directly.
-			if envName, isEnv := strings.CutPrefix(name, "$env."); isEnv {
-				val, found := r.lookupEnv(envName)
-				if !found {
-					return "", &OpError{
-						Op:   "vars.resolve",
-						Kind: KindMissingVar,
-						Err:  fmt.Errorf("missing variable: %s (environment variable %q is not set)", name, envName),
-					}
-				}
-				b.WriteString(val)
+			if r.isDeferred(vars, name) {
+				b.WriteString(s[i : end+2])
 				i = end + 2
 				continue
 			}
-
-			val, ok := builtins[name]
-			if !ok {
-				val, ok = vars[name]
+			val, err := r.lookup(vars, builtins, name)
+			if err != nil {
+				return "", err
 			}
-			if !ok {
-				return "", &OpError{
-					Op:   "vars.resolve",
-					Kind: KindMissingVar,
-					Err:  fmt.Errorf("missing variable: %s", name),
+			// A typed placeholder inside text is still checked, so a value
+			// that cannot convert fails wherever it is used.
+			if typ != "" {
+				if _, err := convertVar(name, val, typ); err != nil {
+					return "", err
 				}
 			}
 
@@ -434,6 +564,156 @@ func (r *VarResolver) resolveStringWith(vars Vars, builtins Vars, s string) (str
 	return b.String(), nil
 }
 
+// lookup returns the value of the variable name: a {{$env.NAME}} process
+// environment variable, a builtin or one of vars.
+func (r *VarResolver) lookup(vars Vars, builtins Vars, name string) (string, error) {
+	if envName, isEnv := strings.CutPrefix(name, "$env."); isEnv {
+		val, found := r.lookupEnv(envName)
+		if !found {
+			return "", &OpError{
+				Op:   "vars.resolve",
+				Kind: KindMissingVar,
+				Err:  fmt.Errorf("missing variable: %s (environment variable %q is not set)", name, envName),
+			}
+		}
+		return val, nil
+	}
+
+	val, ok := builtins[name]
+	if !ok {
+		val, ok = vars[name]
+	}
+	if !ok {
+		return "", &OpError{
+			Op:   "vars.resolve",
+			Kind: KindMissingVar,
+			Err:  fmt.Errorf("missing variable: %s", name),
+		}
+	}
+	return val, nil
+}
+
+// isDeferred reports whether name is a deferred variable vars has no value for.
+func (r *VarResolver) isDeferred(vars Vars, name string) bool {
+	if !r.deferred[name] {
+		return false
+	}
+	_, known := vars[name]
+	return !known
+}
+
+// resolveTyped resolves s when it is a single placeholder whose type, from
+// a ":type" suffix or else types, is not a string: the result is the
+// converted value. ok is false for any other string.
+func (r *VarResolver) resolveTyped(vars Vars, types map[string]VarType, builtins Vars, s string) (v any, ok bool, err error) {
+	inner, whole := wholePlaceholder(s)
+	if !whole {
+		return nil, false, nil
+	}
+	name, typ, err := splitPlaceholder(inner)
+	if err != nil {
+		return nil, true, err
+	}
+	if typ == "" {
+		typ = types[name]
+	}
+	if typ == "" || typ == VarString {
+		return nil, false, nil
+	}
+	if r.isDeferred(vars, name) {
+		return s, true, nil
+	}
+	val, err := r.lookup(vars, builtins, name)
+	if err != nil {
+		return nil, true, err
+	}
+	v, err = convertVar(name, val, typ)
+	return v, true, err
+}
+
+// wholePlaceholder reports whether s is exactly one placeholder, "{{...}}",
+// and returns the text between the braces.
+func wholePlaceholder(s string) (string, bool) {
+	inner, ok := strings.CutPrefix(s, "{{")
+	if !ok {
+		return "", false
+	}
+	inner, ok = strings.CutSuffix(inner, "}}")
+	if !ok || strings.Contains(inner, "{{") || strings.Contains(inner, "}}") {
+		return "", false
+	}
+	return inner, true
+}
+
+// splitPlaceholder splits the text of a placeholder with SplitPlaceholder,
+// rejecting an empty name.
+func splitPlaceholder(inner string) (string, VarType, error) {
+	name, t := SplitPlaceholder(inner)
+	if name == "" {
+		return "", "", &OpError{
+			Op:   "vars.resolve",
+			Kind: KindInvalidConfig,
+			Err:  errors.New("empty placeholder"),
+		}
+	}
+	return name, t, nil
+}
+
+// convertVar converts the value of the variable name to a JSON value of type
+// t. Numbers stay json.Number so large integers are sent exactly.
+func convertVar(name, val string, t VarType) (any, error) {
+	invalid := func(what string) error {
+		return &OpError{
+			Op:   "vars.resolve",
+			Kind: KindInvalidConfig,
+			Err:  fmt.Errorf("variable %s is typed %s but its value %s", name, t, what),
+		}
+	}
+
+	switch t {
+	case VarInt, VarNumber:
+		v, err := decodeJSON(val)
+		n, isNum := v.(json.Number)
+		if err != nil || !isNum {
+			return nil, invalid(fmt.Sprintf("%q is not a number", val))
+		}
+		if t == VarInt && strings.ContainsAny(string(n), ".eE") {
+			return nil, invalid(fmt.Sprintf("%q is not an integer", val))
+		}
+		return n, nil
+
+	case VarBool:
+		b, err := strconv.ParseBool(strings.TrimSpace(val))
+		if err != nil {
+			return nil, invalid(fmt.Sprintf("%q is not a boolean", val))
+		}
+		return b, nil
+
+	case VarJSON:
+		v, err := decodeJSON(val)
+		if err != nil {
+			return nil, invalid("is not JSON: " + err.Error())
+		}
+		return v, nil
+	}
+	return val, nil
+}
+
+// decodeJSON decodes text holding a single JSON value, keeping numbers as
+// json.Number.
+func decodeJSON(val string) (any, error) {
+	dec := json.NewDecoder(strings.NewReader(val))
+	dec.UseNumber()
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("more than one JSON value")
+	}
+	return out, nil
+}
+
 func wrapField(err error, field string) error {
 	// Keep Kind information, but add context about which field was being resolved.
 	return &OpError{
@@ -2,8 +2,11 @@ package domain
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 )
@@ -11,6 +14,17 @@ import (
 // --- helpers ---
 
 func testRuntime(t *testing.T, vars Vars, now func() time.Time, uuidFn func() (string, error)) *RuntimeResolver {
+	t.Helper()
+	return newTestRuntime(t, vars, nil, now, uuidFn)
+}
+
+// testTypedRuntime is testRuntime with variable types, as a run carries them.
+func testTypedRuntime(t *testing.T, vars Vars, types map[string]VarType) *RuntimeResolver {
+	t.Helper()
+	return newTestRuntime(t, vars, types, nil, nil)
+}
+
+func newTestRuntime(t *testing.T, vars Vars, types map[string]VarType, now func() time.Time, uuidFn func() (string, error)) *RuntimeResolver {
 	t.Helper()
 	if now == nil {
 		now = func() time.Time { return time.Unix(1700000000, 0) }
@@ -19,7 +33,7 @@ func testRuntime(t *testing.T, vars Vars, now func() time.Time, uuidFn func() (s
 		uuidFn = func() (string, error) { return "00000000-0000-0000-0000-000000000000", nil }
 	}
 	vr := NewVarResolver(WithNow(now), WithUUID(uuidFn), WithRand(bytes.NewReader(make([]byte, 64))))
-	rt, err := vr.NewRuntime(vars)
+	rt, err := vr.NewRuntime(vars, types)
 	if err != nil {
 		t.Fatalf("NewRuntime: %v", err)
 	}
@@ -57,7 +71,7 @@ func TestResolveString_MissingVar(t *testing.T) {
 		WithUUID(func() (string, error) { return "00000000-0000-0000-0000-000000000000", nil }),
 	)
 
-	rt, err := r.NewRuntime(Vars{"base_url": "http://x"})
+	rt, err := r.NewRuntime(Vars{"base_url": "http://x"}, nil)
 	if err != nil {
 		t.Fatalf("NewRuntime: %v", err)
 	}
@@ -92,7 +106,7 @@ func TestResolveString_Builtins(t *testing.T) {
 		WithUUID(func() (string, error) { return "11111111-1111-1111-1111-111111111111", nil }),
 	)
 
-	rt, err := r.NewRuntime(Vars{})
+	rt, err := r.NewRuntime(Vars{}, nil)
 	if err != nil {
 		t.Fatalf("NewRuntime: %v", err)
 	}
@@ -109,7 +123,7 @@ func TestResolveString_Builtins(t *testing.T) {
 
 func TestResolveString_UnclosedPlaceholder(t *testing.T) {
 	r := NewVarResolver()
-	rt, err := r.NewRuntime(Vars{"x": "y"})
+	rt, err := r.NewRuntime(Vars{"x": "y"}, nil)
 	if err != nil {
 		t.Fatalf("NewRuntime: %v", err)
 	}
@@ -285,7 +299,7 @@ func TestResolveJSON_Nested(t *testing.T) {
 		WithNow(func() time.Time { return time.Unix(170, 0) }),
 		WithUUID(func() (string, error) { return "22222222-2222-2222-2222-222222222222", nil }),
 	)
-	rt, err := r.NewRuntime(Vars{"base_url": "http://example"})
+	rt, err := r.NewRuntime(Vars{"base_url": "http://example"}, nil)
 	if err != nil {
 		t.Fatalf("NewRuntime: %v", err)
 	}
@@ -338,6 +352,184 @@ func TestResolveJSONValue_NumberPassthrough(t *testing.T) {
 	}
 }
 
+func TestResolveJSONValue_TypedJSONVar(t *testing.T) {
+	vars := Vars{
+		"items": `[{"id":1},{"id":2}]`,
+		"big":   "12345678901234567890",
+		"name":  `"alice"`,
+		"plain": "[1,2]",
+	}
+	types := map[string]VarType{"items": VarJSON, "big": VarJSON, "name": VarJSON}
+	rt := testTypedRuntime(t, vars, types)
+
+	out, err := rt.ResolveJSONValue(map[string]any{
+		"items":  "{{ items }}",
+		"big":    "{{big}}",
+		"name":   "{{name}}",
+		"plain":  "{{plain}}",
+		"inline": "ids: {{items}}",
+	})
+	if err != nil {
+		t.Fatalf("ResolveJSONValue: %v", err)
+	}
+	want := map[string]any{
+		"items":  []any{map[string]any{"id": json.Number("1")}, map[string]any{"id": json.Number("2")}},
+		"big":    json.Number("12345678901234567890"),
+		"name":   "alice",
+		"plain":  "[1,2]",
+		"inline": `ids: [{"id":1},{"id":2}]`,
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("got %#v\nwant %#v", out, want)
+	}
+}
+
+func TestResolveJSONValue_TypedJSONVarInvalid(t *testing.T) {
+	rt := testTypedRuntime(t, Vars{"items": "[1,"}, map[string]VarType{"items": VarJSON})
+
+	_, err := rt.ResolveJSONValue("{{items}}")
+	if !IsKind(err, KindInvalidConfig) || !strings.Contains(err.Error(), "variable items is typed json") {
+		t.Fatalf("expected an invalid config error, got %v", err)
+	}
+}
+
+func TestResolveJSONValue_TypedPlaceholders(t *testing.T) {
+	vars := Vars{
+		"limit":   "10",
+		"big":     "12345678901234567890",
+		"price":   "9.5",
+		"flag":    "true",
+		"payload": `{"a":[1,2]}`,
+		"page":    "3",
+	}
+	rt := testTypedRuntime(t, vars, map[string]VarType{"page": VarInt}) // as annotated by an environment
+
+	out, err := rt.ResolveJSONValue(map[string]any{
+		"limit":   "{{limit:int}}",
+		"big":     "{{ big : int }}",
+		"price":   "{{price:number}}",
+		"flag":    "{{flag:bool}}",
+		"payload": "{{payload:json}}",
+		"page":    "{{page}}",
+		"text":    "{{page:string}}",
+		"random":  "{{$randomInt:int}}",
+		"inline":  "limit={{limit:int}}",
+	})
+	if err != nil {
+		t.Fatalf("ResolveJSONValue: %v", err)
+	}
+	want := map[string]any{
+		"limit":   json.Number("10"),
+		"big":     json.Number("12345678901234567890"),
+		"price":   json.Number("9.5"),
+		"flag":    true,
+		"payload": map[string]any{"a": []any{json.Number("1"), json.Number("2")}},
+		"page":    json.Number("3"),
+		"text":    "3",
+		"random":  json.Number("0"),
+		"inline":  "limit=10",
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("got %#v\nwant %#v", out, want)
+	}
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(b), `"big":12345678901234567890`) || !strings.Contains(string(b), `"flag":true`) {
+		t.Fatalf("expected typed values in the encoded body, got %s", b)
+	}
+}
+
+func TestResolveTypedPlaceholder_Errors(t *testing.T) {
+	vars := Vars{"limit": "ten", "ratio": "1.5", "flag": "yes", "page": "x"}
+	rt := testTypedRuntime(t, vars, map[string]VarType{"page": VarNumber})
+
+	cases := []struct {
+		in   any
+		want string
+	}{
+		{"{{limit:int}}", `variable limit is typed int but its value "ten" is not a number`},
+		{"{{ratio:int}}", `variable ratio is typed int but its value "1.5" is not an integer`},
+		{"{{flag:bool}}", `variable flag is typed bool but its value "yes" is not a boolean`},
+		{"{{page}}", `variable page is typed number but its value "x" is not a number`},
+		// A suffix that names no type is part of the variable name.
+		{"{{limit:integer}}", "missing variable: limit:integer"},
+		{"{{missing:int}}", "missing variable: missing"},
+		// Typed placeholders are checked inside text too.
+		{"/items?limit={{limit:int}}", "is not a number"},
+	}
+	for _, tc := range cases {
+		_, err := rt.ResolveJSONValue(tc.in)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%v: expected error containing %q, got %v", tc.in, tc.want, err)
+		}
+	}
+
+	// An untyped placeholder of a typed variable inside text is plain text.
+	if got, err := rt.ResolveString("page {{page}}"); err != nil || got != "page x" {
+		t.Fatalf("ResolveString = %q, %v", got, err)
+	}
+}
+
+func TestResolve_ColonInVariableName(t *testing.T) {
+	rt := testRuntime(t, Vars{"svc:port": "8080", "svc:port:int": "x"}, nil, nil)
+
+	got, err := rt.ResolveString("http://localhost:{{svc:port}}/")
+	if err != nil || got != "http://localhost:8080/" {
+		t.Fatalf("ResolveString = %q, %v", got, err)
+	}
+	out, err := rt.ResolveJSONValue(map[string]any{"port": "{{ svc:port }}", "typed": "{{svc:port:int}}"})
+	if err != nil {
+		t.Fatalf("ResolveJSONValue: %v", err)
+	}
+	if want := map[string]any{"port": "8080", "typed": json.Number("8080")}; !reflect.DeepEqual(out, want) {
+		t.Fatalf("got %#v, want %#v", out, want)
+	}
+}
+
+func TestDeferring_KeepsPlaceholdersOfUnknownValues(t *testing.T) {
+	vr := NewVarResolver().Deferring(map[string]bool{"token": true, "page": true, "limit": true})
+	rt, err := vr.NewRuntime(Vars{"limit": "ten"}, map[string]VarType{"page": VarInt})
+	if err != nil {
+		t.Fatalf("NewRuntime: %v", err)
+	}
+
+	if got, err := rt.ResolveString("Bearer {{token}} {{page:int}}"); err != nil || got != "Bearer {{token}} {{page:int}}" {
+		t.Fatalf("ResolveString = %q, %v", got, err)
+	}
+	if got, err := rt.ResolveJSONValue("{{page}}"); err != nil || got != "{{page}}" {
+		t.Fatalf("ResolveJSONValue = %#v, %v", got, err)
+	}
+	// A known value is still checked, and other variables must still exist.
+	if _, err := rt.ResolveString("{{limit:int}}"); err == nil || !strings.Contains(err.Error(), "is not a number") {
+		t.Fatalf("expected a conversion error, got %v", err)
+	}
+	if _, err := rt.ResolveString("{{missing}}"); !IsKind(err, KindMissingVar) {
+		t.Fatalf("expected a missing variable, got %v", err)
+	}
+}
+
+func TestSplitPlaceholder(t *testing.T) {
+	cases := []struct {
+		in   string
+		name string
+		typ  VarType
+	}{
+		{"limit", "limit", ""},
+		{" limit : int ", "limit", VarInt},
+		{"svc:port", "svc:port", ""},
+		{"svc:port:json", "svc:port", VarJSON},
+		{"limit:integer", "limit:integer", ""},
+	}
+	for _, tc := range cases {
+		if name, typ := SplitPlaceholder(tc.in); name != tc.name || typ != tc.typ {
+			t.Errorf("SplitPlaceholder(%q) = %q, %q; want %q, %q", tc.in, name, typ, tc.name, tc.typ)
+		}
+	}
+}
+
 // --- WithNow / WithUUID options ---
 
 func TestWithNow(t *testing.T) {
@@ -346,7 +538,7 @@ func TestWithNow(t *testing.T) {
 		WithNow(func() time.Time { return fixed }),
 		WithUUID(func() (string, error) { return "x", nil }),
 	)
-	rt, err := vr.NewRuntime(Vars{})
+	rt, err := vr.NewRuntime(Vars{}, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -365,7 +557,7 @@ func TestWithUUID_Error(t *testing.T) {
 		WithNow(func() time.Time { return time.Unix(0, 0) }),
 		WithUUID(func() (string, error) { return "", uuidErr }),
 	)
-	_, err := vr.NewRuntime(Vars{})
+	_, err := vr.NewRuntime(Vars{}, nil)
 	if err == nil {
 		t.Fatal("expected error from uuid generator")
 	}
@@ -459,7 +651,7 @@ func TestResolveString_EnvBuiltin(t *testing.T) {
 		}
 		return "", false
 	}))
-	rt, err := r.NewRuntime(Vars{})
+	rt, err := r.NewRuntime(Vars{}, nil)
 	if err != nil {
 		t.Fatalf("NewRuntime: %v", err)
 	}
@@ -475,7 +667,7 @@ func TestResolveString_EnvBuiltin(t *testing.T) {
 
 func TestResolveString_EnvBuiltin_UnsetFails(t *testing.T) {
 	r := NewVarResolver(WithLookupEnv(func(string) (string, bool) { return "", false }))
-	rt, err := r.NewRuntime(Vars{})
+	rt, err := r.NewRuntime(Vars{}, nil)
 	if err != nil {
 		t.Fatalf("NewRuntime: %v", err)
 	}
@@ -488,3 +680,79 @@ func TestResolveString_EnvBuiltin_UnsetFails(t *testing.T) {
 		t.Fatalf("expected KindMissingVar, got: %v", err)
 	}
 }
+
+// --- ResolveAssertionValues ---
+
+func TestResolveAssertionValues_JSONEqDocument(t *testing.T) {
+	doc := map[string]any{"id": "{{user_id}}", "tags": []any{"{{tag}}", 1}, "n": 2}
+	spec := AssertionsSpec{JSONEq: []JSONEqAssertion{{Value: doc}}}
+
+	out, err := NewVarResolver().ResolveAssertionValues(Vars{"user_id": "42", "tag": "new"}, nil, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := out.JSONEq[0].Value.(map[string]any)
+	if got["id"] != "42" || got["tags"].([]any)[0] != "new" || got["tags"].([]any)[1] != 1 || got["n"] != 2 {
+		t.Fatalf("resolved = %#v", got)
+	}
+	if doc["id"] != "{{user_id}}" || doc["tags"].([]any)[0] != "{{tag}}" {
+		t.Error("the spec's document was modified")
+	}
+
+	if _, err := NewVarResolver().ResolveAssertionValues(Vars{}, nil, spec); err == nil {
+		t.Error("expected an error for an unknown variable")
+	}
+}
+
+func TestResolveAssertionValues_ListsAndStringOperators(t *testing.T) {
+	prefix := "{{prefix}}"
+	spec := AssertionsSpec{JSONPath: map[string]ValueAssertion{
+		"$.status": {In: []string{"{{want}}", "pending"}, StartsWith: &prefix},
+	}}
+	out, err := NewVarResolver().ResolveAssertionValues(Vars{"want": "active", "prefix": "usr_"}, nil, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := out.JSONPath["$.status"]
+	if a.In[0] != "active" || a.In[1] != "pending" || *a.StartsWith != "usr_" {
+		t.Fatalf("resolved = %+v", a)
+	}
+	if spec.JSONPath["$.status"].In[0] != "{{want}}" {
+		t.Error("the spec's list was modified")
+	}
+}
+
+func TestResolveAssertionValues_TimeExpressions(t *testing.T) {
+	within := "1h of {{created_at}}"
+	before := "{{deadline}}"
+	spec := AssertionsSpec{JSONPath: map[string]ValueAssertion{
+		"$.updated_at": {Within: &within, Before: &before},
+	}}
+	out, err := NewVarResolver().ResolveAssertionValues(Vars{"created_at": "2026-03-01T12:00:00Z", "deadline": "now+1d"}, nil, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := out.JSONPath["$.updated_at"]
+	if *a.Within != "1h of 2026-03-01T12:00:00Z" || *a.Before != "now+1d" {
+		t.Fatalf("resolved = %+v", a)
+	}
+}
+
+func TestResolveAssertionValues_ExprVars(t *testing.T) {
+	spec := AssertionsSpec{Expr: []ExprAssertion{{Source: `$.total == {{ total }} and $.name == {{name}}`}}}
+	out, err := NewVarResolver().ResolveAssertionValues(Vars{"total": "3", "name": `a "quoted" name`}, nil, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := out.Expr[0]
+	if e.Source != spec.Expr[0].Source || e.Vars["total"] != "3" || e.Vars["name"] != `a "quoted" name` {
+		t.Fatalf("resolved = %+v", e)
+	}
+	if spec.Expr[0].Vars != nil {
+		t.Error("the spec was modified")
+	}
+
+	if _, err := NewVarResolver().ResolveAssertionValues(Vars{}, nil, spec); !IsKind(err, KindMissingVar) {
+		t.Fatalf("expected KindMissingVar, got %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package brunoparse
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// mapPostResponseVars turns `name: res.body.path` and `name: res.headers.x`
+// into extract and extract_headers. Anything computed (function calls,
+// expressions) is reported.
+func (p *parser) mapPostResponseVars(reqName string, b bruBlock, req *domain.RequestSpec) {
+	for _, v := range b.pairs {
+		if v.disabled {
+			continue
+		}
+		if path, ok := bodyPath(v.value); ok {
+			if req.Extract == nil {
+				req.Extract = domain.ExtractSpec{}
+			}
+			req.Extract[v.key] = path
+			continue
+		}
+		if header, ok := headerName(v.value); ok {
+			if req.ExtractHeaders == nil {
+				req.ExtractHeaders = domain.ExtractHeaderSpec{}
+			}
+			req.ExtractHeaders[v.key] = header
+			continue
+		}
+		p.warn("request %q: post-response var %q (%s) was not converted", reqName, v.key, v.value)
+	}
+}
+
+// mapAsserts maps Bruno's declarative assertions ("res.status: eq 200",
+// "res.body.id: isDefined") onto status, max_ms, jsonpath and header
+// assertions. Operators without a Lynix counterpart are reported.
+func (p *parser) mapAsserts(reqName string, b bruBlock, req *domain.RequestSpec) {
+	for _, a := range b.pairs {
+		if a.disabled {
+			continue
+		}
+		op, arg := splitOperator(a.value)
+		if !p.mapAssert(a.key, op, arg, &req.Assert) {
+			p.warn("request %q: assertion \"%s: %s\" was not converted", reqName, a.key, a.value)
+		}
+	}
+}
+
+func (p *parser) mapAssert(target, op, arg string, spec *domain.AssertionsSpec) bool {
+	switch target {
+	case "res.status":
+		switch op {
+		case "eq":
+			code, err := strconv.Atoi(arg)
+			if err != nil || spec.Status != nil || spec.StatusIn != nil {
+				return false
+			}
+			spec.Status = &code
+			return true
+		case "in":
+			var codes []int
+			for _, s := range strings.Split(strings.Trim(arg, "[]"), ",") {
+				code, err := strconv.Atoi(strings.TrimSpace(s))
+				if err != nil {
+					return false
+				}
+				codes = append(codes, code)
+			}
+			if len(codes) == 0 || spec.Status != nil || spec.StatusIn != nil {
+				return false
+			}
+			spec.StatusIn = codes
+			return true
+		}
+		return false
+
+	case "res.responseTime":
+		n, err := strconv.Atoi(arg)
+		if err != nil || spec.MaxLatencyMS != nil {
+			return false
+		}
+		switch op {
+		case "lt":
+			n--
+		case "lte":
+		default:
+			return false
+		}
+		spec.MaxLatencyMS = &n
+		return true
+	}
+
+	if path, ok := bodyPath(target); ok {
+		if spec.JSONPath == nil {
+			spec.JSONPath = map[string]domain.ValueAssertion{}
+		}
+		va := spec.JSONPath[path]
+		if !applyOperator(&va, op, arg) {
+			return false
+		}
+		spec.JSONPath[path] = va
+		return true
+	}
+
+	if header, ok := headerName(target); ok {
+		if spec.Headers == nil {
+			spec.Headers = map[string]domain.ValueAssertion{}
+		}
+		va := spec.Headers[header]
+		if !applyOperator(&va, op, arg) {
+			return false
+		}
+		spec.Headers[header] = va
+		return true
+	}
+
+	return false
+}
+
+// applyOperator sets the field of va matching a Bruno operator. It refuses
+// to overwrite a field an earlier assertion on the same target already set.
+func applyOperator(va *domain.ValueAssertion, op, arg string) bool {
+	str := func(dst **string, v string) bool {
+		if *dst != nil {
+			return false
+		}
+		*dst = &v
+		return true
+	}
+	num := func(dst **float64) bool {
+		f, err := strconv.ParseFloat(arg, 64)
+		if err != nil || *dst != nil {
+			return false
+		}
+		*dst = &f
+		return true
+	}
+	exists := func(v bool) bool {
+		if va.Exists != nil {
+			return false
+		}
+		va.Exists = &v
+		return true
+	}
+
+	switch op {
+	case "eq":
+		return str(&va.Eq, arg)
+	case "neq":
+		return str(&va.NotEq, arg)
+	case "contains":
+		return str(&va.Contains, arg)
+	case "notContains":
+		return str(&va.NotContains, arg)
+	case "matches":
+		return str(&va.Matches, arg)
+	case "notMatches":
+		return str(&va.NotMatches, arg)
+	case "startsWith":
+		return str(&va.Matches, "^"+regexp.QuoteMeta(arg))
+	case "endsWith":
+		return str(&va.Matches, regexp.QuoteMeta(arg)+"$")
+	case "gt":
+		return num(&va.Gt)
+	case "gte":
+		return num(&va.Gte)
+	case "lt":
+		return num(&va.Lt)
+	case "lte":
+		return num(&va.Lte)
+	case "length", "isEmpty":
+		n := 0
+		if op == "length" {
+			var err error
+			if n, err = strconv.Atoi(arg); err != nil {
+				return false
+			}
+		}
+		if va.Len != nil {
+			return false
+		}
+		va.Len = &n
+		return true
+	case "isDefined":
+		return exists(true)
+	case "isUndefined":
+		return exists(false)
+	case "isNull":
+		// A real null check: an absent value fails, as in Bruno. isNotNull
+		// has no counterpart (exists: true would fail on an absent value
+		// Bruno accepts) and is reported.
+		return str(&va.Type, "null")
+	}
+	return false
+}
+
+var bruOperators = map[string]bool{
+	"eq": true, "neq": true, "gt": true, "gte": true, "lt": true, "lte": true,
+	"in": true, "notIn": true, "contains": true, "notContains": true,
+	"length": true, "matches": true, "notMatches": true,
+	"startsWith": true, "endsWith": true, "between": true,
+	"isEmpty": true, "isNotEmpty": true, "isNull": true, "isNotNull": true,
+	"isUndefined": true, "isDefined": true, "isTruthy": true, "isFalsy": true,
+	"isJson": true, "isNumber": true, "isString": true, "isBoolean": true, "isArray": true,
+}
+
+// splitOperator splits "eq 200" into ("eq", "200"). A value without a known
+// operator is an implicit eq, as in Bruno.
+func splitOperator(v string) (op, arg string) {
+	op, arg, _ = strings.Cut(strings.TrimSpace(v), " ")
+	if !bruOperators[op] {
+		return "eq", unquote(v)
+	}
+	return op, unquote(arg)
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+var (
+	reBodyPath   = regexp.MustCompile(`^res\.body((?:\.[A-Za-z_$][\w$]*|\[\d+\]|\["[^"]*"\]|\['[^']*'\])*)$`)
+	reHeaderName = regexp.MustCompile(`^res\.headers(?:\.([A-Za-z_][\w]*)|\[["']([^"']+)["']\])$`)
+)
+
+// bodyPath converts res.body.a.b[0] into the JSONPath $.a.b[0].
+func bodyPath(expr string) (string, bool) {
+	m := reBodyPath.FindStringSubmatch(strings.TrimSpace(expr))
+	if m == nil {
+		return "", false
+	}
+	return "$" + strings.ReplaceAll(m[1], "'", `"`), true
+}
+
+// headerName extracts x-token from res.headers['x-token'] or res.headers.etag.
+func headerName(expr string) (string, bool) {
+	m := reHeaderName.FindStringSubmatch(strings.TrimSpace(expr))
+	if m == nil {
+		return "", false
+	}
+	if m[1] != "" {
+		return m[1], true
+	}
+	return m[2], true
+}
@@ -0,0 +1,154 @@
+package brunoparse
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// bruFile is a parsed .bru file: a sequence of named blocks. Dictionary
+// blocks (meta, headers, assert, ...) hold key/value pairs, list blocks
+// (vars:secret [...]) hold names, and text blocks (body:json, tests, ...)
+// keep their content with the two-space indent removed.
+type bruFile struct {
+	blocks []bruBlock
+}
+
+type bruBlock struct {
+	name  string
+	pairs []bruPair
+	items []string
+	text  string
+}
+
+// bruPair is one "key: value" line. Disabled pairs are prefixed with ~ in
+// the file; Bruno keeps them for reference but does not send them.
+type bruPair struct {
+	key      string
+	value    string
+	disabled bool
+}
+
+// textBlocks are stored verbatim instead of as key/value pairs.
+func isTextBlock(name string) bool {
+	switch {
+	case strings.HasPrefix(name, "body:") && name != "body:form-urlencoded" && name != "body:multipart-form":
+		return true
+	case strings.HasPrefix(name, "script:"), name == "tests", name == "docs":
+		return true
+	}
+	return false
+}
+
+func parseBru(r io.Reader) (bruFile, error) {
+	var (
+		f     bruFile
+		cur   *bruBlock
+		close string
+		text  []string
+		line  int
+	)
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		line++
+		raw := strings.TrimRight(sc.Text(), " \t\r")
+
+		if cur == nil {
+			if strings.TrimSpace(raw) == "" {
+				continue
+			}
+			name, open, ok := blockHeader(raw)
+			if !ok {
+				return bruFile{}, fmt.Errorf("line %d: expected a block, got %q", line, raw)
+			}
+			cur = &bruBlock{name: name}
+			close = "}"
+			if open == "[" {
+				close = "]"
+			}
+			text = nil
+			continue
+		}
+
+		if raw == close {
+			if isTextBlock(cur.name) {
+				cur.text = strings.Join(text, "\n")
+			}
+			f.blocks = append(f.blocks, *cur)
+			cur = nil
+			continue
+		}
+
+		content := strings.TrimPrefix(raw, "  ")
+		switch {
+		case isTextBlock(cur.name):
+			text = append(text, content)
+		case close == "]":
+			for _, item := range strings.Split(content, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					cur.items = append(cur.items, item)
+				}
+			}
+		default:
+			content = strings.TrimSpace(content)
+			if content == "" {
+				continue
+			}
+			p := bruPair{}
+			if strings.HasPrefix(content, "~") {
+				p.disabled = true
+				content = content[1:]
+			}
+			key, value, ok := strings.Cut(content, ":")
+			if !ok {
+				return bruFile{}, fmt.Errorf("line %d: expected \"key: value\" in %s block, got %q", line, cur.name, content)
+			}
+			p.key = strings.TrimSpace(key)
+			p.value = strings.TrimSpace(value)
+			cur.pairs = append(cur.pairs, p)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return bruFile{}, err
+	}
+	if cur != nil {
+		return bruFile{}, fmt.Errorf("block %q is not closed", cur.name)
+	}
+	return f, nil
+}
+
+// blockHeader recognizes "name {" and "name [" lines.
+func blockHeader(line string) (name, open string, ok bool) {
+	for _, open := range []string{"{", "["} {
+		if head, found := strings.CutSuffix(line, open); found {
+			name = strings.TrimSpace(head)
+			if name != "" && !strings.ContainsAny(name, " \t") {
+				return name, open, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// block returns the first block with the given name.
+func (f bruFile) block(name string) (bruBlock, bool) {
+	for _, b := range f.blocks {
+		if b.name == name {
+			return b, true
+		}
+	}
+	return bruBlock{}, false
+}
+
+// get returns the value of the first enabled pair with the given key.
+func (b bruBlock) get(key string) string {
+	for _, p := range b.pairs {
+		if p.key == key && !p.disabled {
+			return p.value
+		}
+	}
+	return ""
+}
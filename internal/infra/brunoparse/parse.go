@@ -0,0 +1,504 @@
+package brunoparse
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/importkit"
+)
+
+// Result holds the parsed collection, the collection's environments and any
+// warnings about unsupported features.
+type Result struct {
+	Collection   domain.Collection
+	Environments []Environment
+	Warnings     []string
+}
+
+// Environment is a file from the collection's environments/ directory.
+// Secret lists the keys declared in vars:secret that also carry a value.
+type Environment struct {
+	Name   string
+	Vars   domain.Vars
+	Secret map[string]bool
+}
+
+var methods = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+// Parse reads a Bruno collection directory (bruno.json, .bru request files,
+// folder.bru/collection.bru settings and environments/*.bru).
+func Parse(fsys fs.FS) (Result, error) {
+	b, err := fs.ReadFile(fsys, "bruno.json")
+	if err != nil {
+		return Result{}, fmt.Errorf("not a Bruno collection (bruno.json not found): %w", err)
+	}
+	var meta struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(b, &meta); err != nil {
+		return Result{}, fmt.Errorf("decode bruno.json: %w", err)
+	}
+
+	p := &parser{fsys: fsys, vars: domain.Vars{}, seen: map[string]bool{}, noted: map[string]bool{}}
+
+	root := folderSettings{}
+	if f, ok, err := p.readOptional("collection.bru"); err != nil {
+		return Result{}, err
+	} else if ok {
+		root = p.settings("collection.bru", f, root)
+	}
+
+	if err := p.walk(".", "", nil, root); err != nil {
+		return Result{}, err
+	}
+
+	envs, err := p.environments()
+	if err != nil {
+		return Result{}, err
+	}
+
+	col := domain.Collection{
+		SchemaVersion: 1,
+		Name:          meta.Name,
+		Vars:          p.vars,
+		Requests:      p.reqs,
+	}
+	if len(col.Vars) == 0 {
+		col.Vars = nil
+	}
+
+	return Result{Collection: col, Environments: envs, Warnings: p.warnings}, nil
+}
+
+type parser struct {
+	fsys     fs.FS
+	vars     domain.Vars
+	reqs     []domain.RequestSpec
+	seen     map[string]bool
+	noted    map[string]bool // dynamic-variable warnings already emitted
+	warnings []string
+}
+
+// folderSettings is what a request inherits from collection.bru and the
+// folder.bru files above it.
+type folderSettings struct {
+	headers domain.Headers
+	auth    *bruBlock // auth:<mode> block; nil when no auth is set
+}
+
+func (p *parser) warn(format string, args ...any) {
+	p.warnings = append(p.warnings, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) read(name string) (bruFile, error) {
+	f, err := p.fsys.Open(name)
+	if err != nil {
+		return bruFile{}, fmt.Errorf("open %s: %w", name, err)
+	}
+	defer f.Close()
+
+	bf, err := parseBru(f)
+	if err != nil {
+		return bruFile{}, fmt.Errorf("parse %s: %w", name, err)
+	}
+	return bf, nil
+}
+
+func (p *parser) readOptional(name string) (bruFile, bool, error) {
+	if _, err := fs.Stat(p.fsys, name); err != nil {
+		return bruFile{}, false, nil
+	}
+	f, err := p.read(name)
+	return f, err == nil, err
+}
+
+// entry is a request file or sub-folder, ordered the way Bruno shows them.
+type entry struct {
+	name   string // file or directory name
+	title  string // meta name
+	seq    float64
+	folder bool
+	file   bruFile
+}
+
+// walk reads one directory. Request names get the folder path as a dotted
+// prefix and every enclosing folder becomes a tag, so `--tag` can still
+// select a folder.
+func (p *parser) walk(dir, prefix string, tags []string, inherited folderSettings) error {
+	dirEntries, err := fs.ReadDir(p.fsys, dir)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", dir, err)
+	}
+
+	var entries []entry
+	for _, de := range dirEntries {
+		name := de.Name()
+		full := path.Join(dir, name)
+		switch {
+		case de.IsDir():
+			if strings.HasPrefix(name, ".") || name == "node_modules" || (dir == "." && name == "environments") {
+				continue
+			}
+			e := entry{name: name, title: name, seq: math.Inf(1), folder: true}
+			if f, ok, err := p.readOptional(path.Join(full, "folder.bru")); err != nil {
+				return err
+			} else if ok {
+				e.file = f
+				if m, ok := f.block("meta"); ok {
+					if t := m.get("name"); t != "" {
+						e.title = t
+					}
+					e.seq = parseSeq(m.get("seq"))
+				}
+			}
+			entries = append(entries, e)
+
+		case strings.HasSuffix(name, ".bru") && name != "folder.bru" && name != "collection.bru":
+			f, err := p.read(full)
+			if err != nil {
+				return err
+			}
+			m, _ := f.block("meta")
+			e := entry{name: name, title: m.get("name"), seq: parseSeq(m.get("seq")), file: f}
+			if e.title == "" {
+				e.title = strings.TrimSuffix(name, ".bru")
+			}
+			entries = append(entries, e)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.seq != b.seq {
+			return a.seq < b.seq
+		}
+		if a.folder != b.folder {
+			return !a.folder
+		}
+		return a.name < b.name
+	})
+
+	for _, e := range entries {
+		if !e.folder {
+			p.mapRequest(e, prefix, tags, inherited)
+			continue
+		}
+
+		slug := importkit.Slugify(e.title)
+		folderPrefix := slug
+		if prefix != "" {
+			folderPrefix = prefix + "." + slug
+		}
+		settings := p.settings(path.Join(dir, e.name, "folder.bru"), e.file, inherited)
+		folderTags := append(append([]string(nil), tags...), slug)
+		if err := p.walk(path.Join(dir, e.name), folderPrefix, folderTags, settings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// settings applies the headers and auth of a folder.bru or collection.bru
+// on top of what the parent provides. Scripts, tests and vars at that level
+// have no Lynix equivalent and are reported.
+func (p *parser) settings(file string, f bruFile, parent folderSettings) folderSettings {
+	out := folderSettings{headers: domain.Headers{}, auth: parent.auth}
+	for k, v := range parent.headers {
+		out.headers[k] = v
+	}
+
+	for _, b := range f.blocks {
+		switch {
+		case b.name == "meta", b.name == "docs":
+		case b.name == "headers":
+			for _, h := range b.pairs {
+				if !h.disabled {
+					out.headers[h.key] = p.template(file, h.value)
+				}
+			}
+		case b.name == "auth":
+			mode := b.get("mode")
+			if mode == "inherit" {
+				continue
+			}
+			out.auth = nil
+			if ab, ok := f.block("auth:" + mode); ok {
+				out.auth = &ab
+			} else if mode != "" && mode != "none" {
+				out.auth = &bruBlock{name: "auth:" + mode}
+			}
+		case strings.HasPrefix(b.name, "auth:"):
+			// Read through the auth { mode } block above.
+		default:
+			p.warn("%s: %q block was ignored", file, b.name)
+		}
+	}
+	return out
+}
+
+func (p *parser) mapRequest(e entry, prefix string, tags []string, inherited folderSettings) {
+	f := e.file
+	display := e.title
+
+	var (
+		method string
+		mb     bruBlock
+	)
+	for _, m := range methods {
+		if b, ok := f.block(m); ok {
+			method, mb = strings.ToUpper(m), b
+			break
+		}
+	}
+	if method == "" {
+		p.warn("%s: no HTTP method block found; file was skipped", e.name)
+		return
+	}
+
+	name := importkit.Slugify(display)
+	if prefix != "" {
+		name = prefix + "." + name
+	}
+	if p.seen[name] {
+		base := name
+		for i := 2; p.seen[name]; i++ {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+		p.warn("request name %q is used more than once; renamed to %q", base, name)
+	}
+	p.seen[name] = true
+
+	rawURL := p.template(display, mb.get("url"))
+
+	headers := domain.Headers{}
+	for k, v := range inherited.headers {
+		headers[k] = v
+	}
+
+	req := domain.RequestSpec{
+		Name:   name,
+		Method: domain.HTTPMethod(method),
+		Tags:   tags,
+	}
+
+	for _, b := range f.blocks {
+		switch b.name {
+		case "meta", "docs", "settings", strings.ToLower(method),
+			"body:json", "body:text", "body:xml", "body:form-urlencoded", "body:multipart-form", "body:graphql", "body:graphql:vars":
+			// Handled below or not relevant.
+		case "headers":
+			for _, h := range b.pairs {
+				if !h.disabled {
+					headers[h.key] = p.template(display, h.value)
+				}
+			}
+		case "params:query", "query":
+			if !strings.Contains(rawURL, "?") {
+				for _, q := range b.pairs {
+					if !q.disabled {
+						rawURL = importkit.AppendQuery(rawURL, q.key, p.template(display, q.value))
+					}
+				}
+			}
+		case "params:path":
+			for _, pp := range b.pairs {
+				rawURL = replacePathParam(rawURL, pp.key, p.template(display, pp.value))
+			}
+		case "vars:pre-request":
+			for _, v := range b.pairs {
+				if v.disabled {
+					continue
+				}
+				val := p.template(display, v.value)
+				if prev, ok := p.vars[v.key]; ok && prev != val {
+					p.warn("request %q: variable %q conflicts with an existing value and was skipped", display, v.key)
+					continue
+				}
+				p.vars[v.key] = val
+			}
+		case "vars:post-response":
+			p.mapPostResponseVars(display, b, &req)
+		case "assert":
+			p.mapAsserts(display, b, &req)
+		default:
+			if strings.HasPrefix(b.name, "auth:") {
+				continue
+			}
+			p.warn("request %q: %q block was ignored", display, b.name)
+		}
+	}
+
+	auth := inherited.auth
+	if mode := mb.get("auth"); mode != "" && mode != "inherit" {
+		auth = nil
+		if ab, ok := f.block("auth:" + mode); ok {
+			auth = &ab
+		} else if mode != "none" {
+			auth = &bruBlock{name: "auth:" + mode}
+		}
+	}
+	if auth != nil {
+		rawURL = p.applyAuth(display, auth, headers, rawURL)
+	}
+
+	req.URL = rawURL
+	req.Body = p.mapBody(display, f, mb.get("body"), headers)
+	req.Headers = headers
+	if len(req.Headers) == 0 {
+		req.Headers = nil
+	}
+	p.reqs = append(p.reqs, req)
+}
+
+func (p *parser) mapBody(reqName string, f bruFile, mode string, headers domain.Headers) domain.BodySpec {
+	switch mode {
+	case "", "none":
+		return domain.BodySpec{Type: domain.BodyNone}
+
+	case "json":
+		b, _ := f.block("body:json")
+		text := p.template(reqName, b.text)
+		var v any
+		if err := json.Unmarshal([]byte(text), &v); err == nil {
+			switch v.(type) {
+			case map[string]any, []any:
+				return domain.BodySpec{Type: domain.BodyJSON, JSON: v}
+			}
+		}
+		// Templated or invalid JSON is sent verbatim.
+		importkit.SetDefaultHeader(headers, "Content-Type", "application/json")
+		return domain.BodySpec{Type: domain.BodyRaw, Raw: text}
+
+	case "text", "xml":
+		b, _ := f.block("body:" + mode)
+		ct := "text/plain"
+		if mode == "xml" {
+			ct = "application/xml"
+		}
+		importkit.SetDefaultHeader(headers, "Content-Type", ct)
+		return domain.BodySpec{Type: domain.BodyRaw, Raw: p.template(reqName, b.text)}
+
+	case "formUrlEncoded", "form-urlencoded":
+		b, _ := f.block("body:form-urlencoded")
+		form := map[string]string{}
+		for _, kv := range b.pairs {
+			if !kv.disabled {
+				form[kv.key] = p.template(reqName, kv.value)
+			}
+		}
+		return domain.BodySpec{Type: domain.BodyForm, Form: form}
+
+	case "graphql":
+		// Sent as the JSON envelope GraphQL servers expect.
+		q, _ := f.block("body:graphql")
+		body := map[string]any{"query": p.template(reqName, q.text)}
+		if vb, ok := f.block("body:graphql:vars"); ok && strings.TrimSpace(vb.text) != "" {
+			var vars any
+			if err := json.Unmarshal([]byte(p.template(reqName, vb.text)), &vars); err != nil {
+				p.warn("request %q: graphql variables are not valid JSON and were dropped", reqName)
+			} else {
+				body["variables"] = vars
+			}
+		}
+		return domain.BodySpec{Type: domain.BodyJSON, JSON: body}
+
+	case "multipartForm", "multipart-form":
+		p.warn("request %q: multipart form-data body is not supported", reqName)
+	default:
+		p.warn("request %q: body mode %q is not supported", reqName, mode)
+	}
+	return domain.BodySpec{Type: domain.BodyNone}
+}
+
+// applyAuth maps an auth:bearer, auth:basic or auth:apikey block onto the
+// shared importer policy. An apikey with placement queryparams goes into the
+// query string.
+func (p *parser) applyAuth(reqName string, auth *bruBlock, headers domain.Headers, rawURL string) string {
+	a := importkit.Auth{Type: strings.TrimPrefix(auth.name, "auth:")}
+	switch a.Type {
+	case "bearer":
+		a.Token = p.template(reqName, auth.get("token"))
+	case "apikey":
+		a.Key = auth.get("key")
+		a.Value = p.template(reqName, auth.get("value"))
+		a.InQuery = auth.get("placement") == "queryparams"
+	}
+	rawURL, warnings := importkit.ApplyAuth(reqName, a, headers, rawURL)
+	p.warnings = append(p.warnings, warnings...)
+	return rawURL
+}
+
+// environments reads environments/*.bru. Values of vars:secret live in
+// Bruno's local storage, not in the file, so they cannot be imported.
+func (p *parser) environments() ([]Environment, error) {
+	files, err := fs.Glob(p.fsys, "environments/*.bru")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var envs []Environment
+	for _, file := range files {
+		f, err := p.read(file)
+		if err != nil {
+			return nil, err
+		}
+		env := Environment{
+			Name:   strings.TrimSuffix(path.Base(file), ".bru"),
+			Vars:   domain.Vars{},
+			Secret: map[string]bool{},
+		}
+		if b, ok := f.block("vars"); ok {
+			for _, v := range b.pairs {
+				if v.disabled {
+					p.warn("environment %q: variable %q is disabled and was skipped", env.Name, v.key)
+					continue
+				}
+				env.Vars[v.key] = p.template(env.Name, v.value)
+			}
+		}
+		if b, ok := f.block("vars:secret"); ok {
+			for _, k := range b.items {
+				k = strings.TrimPrefix(k, "~")
+				if _, ok := env.Vars[k]; ok {
+					env.Secret[k] = true
+					continue
+				}
+				p.warn("environment %q: secret %q has no value in the collection; set it in secrets.local.yaml", env.Name, k)
+			}
+		}
+		envs = append(envs, env)
+	}
+	return envs, nil
+}
+
+func parseSeq(s string) float64 {
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return v
+	}
+	return math.Inf(1)
+}
+
+// replacePathParam substitutes a :name segment, Bruno's path parameter
+// syntax, with its value.
+func replacePathParam(rawURL, name, value string) string {
+	base, query, hasQuery := strings.Cut(rawURL, "?")
+	segs := strings.Split(base, "/")
+	for i, s := range segs {
+		if s == ":"+name {
+			segs[i] = value
+		}
+	}
+	out := strings.Join(segs, "/")
+	if hasQuery {
+		out += "?" + query
+	}
+	return out
+}
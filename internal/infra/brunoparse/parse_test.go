@@ -0,0 +1,364 @@
+package brunoparse
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/yamlcollection"
+)
+
+func file(s string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(s)} }
+
+func hasWarning(warnings []string, substr string) bool {
+	for _, w := range warnings {
+		if strings.Contains(w, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// shopFS is a small collection covering folders, inherited auth, bodies,
+// post-response vars, assertions and environments.
+func shopFS() fstest.MapFS {
+	return fstest.MapFS{
+		"bruno.json": file(`{"version": "1", "name": "Shop API", "type": "collection"}`),
+		"collection.bru": file(`headers {
+  Accept: application/json
+}
+
+auth {
+  mode: bearer
+}
+
+auth:bearer {
+  token: {{token}}
+}
+`),
+		"Login.bru": file(`meta {
+  name: Login
+  type: http
+  seq: 1
+}
+
+post {
+  url: {{baseUrl}}/login
+  body: formUrlEncoded
+  auth: none
+}
+
+body:form-urlencoded {
+  user: demo
+  ~remember: 1
+}
+
+vars:post-response {
+  token: res.body.access_token
+  session: res.headers['x-session']
+}
+
+assert {
+  res.status: eq 200
+  res.body.access_token: isDefined
+  res.responseTime: lt 500
+}
+`),
+		"orders/folder.bru": file(`meta {
+  name: Orders
+  seq: 2
+}
+`),
+		"orders/Create Order.bru": file(`meta {
+  name: Create Order
+  type: http
+  seq: 2
+}
+
+post {
+  url: {{baseUrl}}/orders
+  body: json
+  auth: inherit
+}
+
+headers {
+  X-Trace: {{process.env.TRACE_ID}}
+  ~X-Debug: 1
+}
+
+body:json {
+  {
+    "sku": "A-1",
+    "qty": 2
+  }
+}
+
+assert {
+  res.status: in [200, 201]
+  res.body.items: length 1
+  res.body.items[0].sku: eq "A-1"
+  res.body.total: gte 10
+  res.headers.location: contains /orders/
+}
+
+tests {
+  test("custom", function() {});
+}
+`),
+		"orders/List Orders.bru": file(`meta {
+  name: List Orders
+  type: http
+  seq: 1
+}
+
+get {
+  url: {{baseUrl}}/orders/:status?limit=10
+  body: none
+  auth: inherit
+}
+
+params:query {
+  limit: 10
+}
+
+params:path {
+  status: open
+}
+`),
+		"environments/Staging.bru": file(`vars {
+  baseUrl: https://stg.shop.test
+  token: stg-token
+}
+vars:secret [
+  token,
+  clientSecret
+]
+`),
+		"environments/Local.bru": file(`vars {
+  baseUrl: http://localhost:8080
+}
+`),
+	}
+}
+
+func TestParse_OrderAndFolders(t *testing.T) {
+	r, err := Parse(shopFS())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Collection.Name != "Shop API" {
+		t.Errorf("name: got %q", r.Collection.Name)
+	}
+	var names []string
+	for _, req := range r.Collection.Requests {
+		names = append(names, req.Name)
+	}
+	if got := strings.Join(names, ","); got != "login,orders.list-orders,orders.create-order" {
+		t.Fatalf("requests: got %s", got)
+	}
+	if tags := r.Collection.Requests[1].Tags; len(tags) != 1 || tags[0] != "orders" {
+		t.Errorf("folder tags: got %v", tags)
+	}
+}
+
+func TestParse_RequestsBodiesAndAuth(t *testing.T) {
+	r, err := Parse(shopFS())
+	if err != nil {
+		t.Fatal(err)
+	}
+	login, list, create := r.Collection.Requests[0], r.Collection.Requests[1], r.Collection.Requests[2]
+
+	if login.Method != domain.MethodPost || login.URL != "{{baseUrl}}/login" {
+		t.Errorf("login: got %s %s", login.Method, login.URL)
+	}
+	if login.Body.Type != domain.BodyForm || login.Body.Form["user"] != "demo" || len(login.Body.Form) != 1 {
+		t.Errorf("form body: got %+v", login.Body)
+	}
+	if _, ok := login.Headers["Authorization"]; ok {
+		t.Error("auth: none should not inherit the collection auth")
+	}
+	if login.Headers["Accept"] != "application/json" {
+		t.Errorf("collection header: got %q", login.Headers["Accept"])
+	}
+
+	if list.URL != "{{baseUrl}}/orders/open?limit=10" {
+		t.Errorf("list url: got %q", list.URL)
+	}
+
+	if create.Headers["Authorization"] != "Bearer {{token}}" {
+		t.Errorf("inherited bearer: got %q", create.Headers["Authorization"])
+	}
+	if create.Headers["X-Trace"] != "{{$env.TRACE_ID}}" {
+		t.Errorf("process.env: got %q", create.Headers["X-Trace"])
+	}
+	if _, ok := create.Headers["X-Debug"]; ok {
+		t.Error("disabled header should be skipped")
+	}
+	if create.Body.Type != domain.BodyJSON {
+		t.Fatalf("json body: got %+v", create.Body)
+	}
+	if m := create.Body.JSON.(map[string]any); m["sku"] != "A-1" {
+		t.Errorf("json body: got %v", m)
+	}
+}
+
+func TestParse_PostResponseVarsAndAsserts(t *testing.T) {
+	r, err := Parse(shopFS())
+	if err != nil {
+		t.Fatal(err)
+	}
+	login, create := r.Collection.Requests[0], r.Collection.Requests[2]
+
+	if login.Extract["token"] != "$.access_token" {
+		t.Errorf("extract: got %v", login.Extract)
+	}
+	if login.ExtractHeaders["session"] != "x-session" {
+		t.Errorf("extract_headers: got %v", login.ExtractHeaders)
+	}
+	if login.Assert.Status == nil || *login.Assert.Status != 200 {
+		t.Errorf("status: got %v", login.Assert.Status)
+	}
+	if va := login.Assert.JSONPath["$.access_token"]; va.Exists == nil || !*va.Exists {
+		t.Errorf("isDefined: got %+v", va)
+	}
+	if login.Assert.MaxLatencyMS == nil || *login.Assert.MaxLatencyMS != 499 {
+		t.Errorf("responseTime lt 500: got %v", login.Assert.MaxLatencyMS)
+	}
+
+	a := create.Assert
+	if len(a.StatusIn) != 2 || a.StatusIn[0] != 200 || a.StatusIn[1] != 201 {
+		t.Errorf("status in: got %v", a.StatusIn)
+	}
+	if va := a.JSONPath["$.items"]; va.Len == nil || *va.Len != 1 {
+		t.Errorf("length: got %+v", va)
+	}
+	if va := a.JSONPath["$.items[0].sku"]; va.Eq == nil || *va.Eq != "A-1" {
+		t.Errorf("eq: got %+v", va)
+	}
+	if va := a.JSONPath["$.total"]; va.Gte == nil || *va.Gte != 10 {
+		t.Errorf("gte: got %+v", va)
+	}
+	if va := a.Headers["location"]; va.Contains == nil || *va.Contains != "/orders/" {
+		t.Errorf("header contains: got %+v", va)
+	}
+
+	if !hasWarning(r.Warnings, `request "Create Order": "tests" block was ignored`) {
+		t.Errorf("missing tests warning: %v", r.Warnings)
+	}
+}
+
+func TestParse_Environments(t *testing.T) {
+	r, err := Parse(shopFS())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Environments) != 2 {
+		t.Fatalf("environments: got %d", len(r.Environments))
+	}
+	local, stg := r.Environments[0], r.Environments[1]
+	if local.Name != "Local" || local.Vars["baseUrl"] != "http://localhost:8080" {
+		t.Errorf("local: got %+v", local)
+	}
+	if stg.Vars["token"] != "stg-token" || !stg.Secret["token"] || stg.Secret["baseUrl"] {
+		t.Errorf("staging: got %+v", stg)
+	}
+	if !hasWarning(r.Warnings, `secret "clientSecret" has no value`) {
+		t.Errorf("missing secret warning: %v", r.Warnings)
+	}
+}
+
+func TestParse_UnconvertedAsserts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"bruno.json": file(`{"name": "A"}`),
+		"Get.bru": file(`meta {
+  name: Get
+  seq: 1
+}
+
+get {
+  url: https://x.test
+}
+
+vars:post-response {
+  count: res.body.items.length + 1
+}
+
+assert {
+  res.body.ok: isTruthy
+  res.status: neq 500
+  ~res.body.skip: eq 1
+  res.body.deleted_at: isNull
+  res.body.owner: isNotNull
+}
+`),
+	}
+	r, err := Parse(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	va := r.Collection.Requests[0].Assert.JSONPath["$.deleted_at"]
+	if va.Type == nil || *va.Type != "null" || va.Exists != nil {
+		t.Errorf("isNull should be a null type check, got %+v", va)
+	}
+	if _, ok := r.Collection.Requests[0].Assert.JSONPath["$.owner"]; ok {
+		t.Errorf("isNotNull has no equivalent and should not be mapped")
+	}
+	for _, want := range []string{
+		`assertion "res.body.ok: isTruthy" was not converted`,
+		`assertion "res.status: neq 500" was not converted`,
+		`assertion "res.body.owner: isNotNull" was not converted`,
+		`post-response var "count"`,
+	} {
+		if !hasWarning(r.Warnings, want) {
+			t.Errorf("missing warning %q in %v", want, r.Warnings)
+		}
+	}
+	if hasWarning(r.Warnings, "skip") {
+		t.Errorf("disabled assertion should be skipped silently: %v", r.Warnings)
+	}
+}
+
+func TestParse_MissingBrunoJSON(t *testing.T) {
+	_, err := Parse(fstest.MapFS{"Get.bru": file("get {\n  url: x\n}\n")})
+	if err == nil || !strings.Contains(err.Error(), "bruno.json") {
+		t.Fatalf("expected bruno.json error, got %v", err)
+	}
+}
+
+func TestParseBru_UnclosedBlock(t *testing.T) {
+	_, err := parseBru(strings.NewReader("meta {\n  name: x\n"))
+	if err == nil {
+		t.Fatal("expected error for unclosed block")
+	}
+}
+
+func TestParse_RoundTrip(t *testing.T) {
+	r, err := Parse(shopFS())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := yamlcollection.MarshalCollection(r.Collection)
+	if err != nil {
+		t.Fatalf("MarshalCollection: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "shop.yaml")
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := yamlcollection.NewLoader().LoadCollection(path)
+	if err != nil {
+		t.Fatalf("LoadCollection: %v\nYAML:\n%s", err, string(b))
+	}
+	if len(loaded.Requests) != 3 {
+		t.Fatalf("round-trip requests: got %d", len(loaded.Requests))
+	}
+	if got := loaded.Requests[2].Assert.StatusIn; len(got) != 2 {
+		t.Errorf("round-trip status in: got %v", got)
+	}
+}
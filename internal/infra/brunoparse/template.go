@@ -0,0 +1,46 @@
+package brunoparse
+
+import (
+	"regexp"
+	"strings"
+)
+
+var rePlaceholder = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+// builtins maps Bruno's dynamic variables onto Lynix built-ins.
+var builtins = map[string]string{
+	"$timestamp":     "$timestamp",
+	"$isoTimestamp":  "$isoTimestamp",
+	"$randomUUID":    "$uuid",
+	"$guid":          "$uuid",
+	"$randomInt":     "$randomInt",
+	"$randomEmail":   "$randomEmail",
+	"$randomBoolean": "$randomBool",
+}
+
+// template rewrites Bruno placeholders: {{process.env.X}} reads the process
+// environment like {{$env.X}}, and dynamic variables are mapped where Lynix
+// has an equivalent. Unknown dynamic variables are kept and reported once
+// per request.
+func (p *parser) template(context, s string) string {
+	if !strings.Contains(s, "{{") {
+		return s
+	}
+	return rePlaceholder.ReplaceAllStringFunc(s, func(m string) string {
+		name := rePlaceholder.FindStringSubmatch(m)[1]
+		if env, ok := strings.CutPrefix(name, "process.env."); ok {
+			return "{{$env." + env + "}}"
+		}
+		if !strings.HasPrefix(name, "$") {
+			return "{{" + name + "}}"
+		}
+		if lynix, ok := builtins[name]; ok {
+			return "{{" + lynix + "}}"
+		}
+		if key := context + "\x00" + name; !p.noted[key] {
+			p.noted[key] = true
+			p.warn("request %q: dynamic variable {{%s}} is not supported", context, name)
+		}
+		return m
+	})
+}
@@ -0,0 +1,288 @@
+package curlparse
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// ParseAll converts one or more curl commands into a single collection, as
+// produced by "Copy all as cURL" in browser DevTools. Commands are separated
+// by `;`, `&&` or a newline followed by another `curl`.
+//
+// The most common scheme+host becomes base_url, auth header values that
+// repeat across requests become vars, request names are made unique, and
+// exact duplicates are dropped with a warning. A single command yields the
+// same result as Parse.
+func ParseAll(input string) (Result, error) {
+	cmds, err := splitCommands(input)
+	if err != nil {
+		return Result{}, err
+	}
+	if len(cmds) == 0 {
+		return Result{}, fmt.Errorf("empty curl command")
+	}
+	if len(cmds) == 1 {
+		return Parse(cmds[0])
+	}
+
+	type parsed struct {
+		req  domain.RequestSpec
+		base string
+		cmd  int // 1-based position in the input, for messages
+	}
+
+	var (
+		out      Result
+		reqs     []parsed
+		seen     = map[string]int{}
+		hostUses = map[string]int{}
+		hosts    []string
+	)
+	for i, cmd := range cmds {
+		r, err := Parse(cmd)
+		if err != nil {
+			return Result{}, fmt.Errorf("command %d: %w", i+1, err)
+		}
+		for _, w := range r.Warnings {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("command %d: %s", i+1, w))
+		}
+		out.Insecure = out.Insecure || r.Insecure
+
+		p := parsed{req: r.Collection.Requests[0], base: r.Collection.Vars["base_url"], cmd: i + 1}
+		key := requestKey(p.base, p.req)
+		if first, dup := seen[key]; dup {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("command %d duplicates command %d (%s %s) and was skipped", i+1, first, p.req.Method, p.base+strings.TrimPrefix(p.req.URL, "{{base_url}}")))
+			continue
+		}
+		seen[key] = i + 1
+
+		if hostUses[p.base] == 0 {
+			hosts = append(hosts, p.base)
+		}
+		hostUses[p.base]++
+		reqs = append(reqs, p)
+	}
+
+	// base_url is the host most requests go to (first seen on a tie);
+	// requests to any other host keep their full URL.
+	base := hosts[0]
+	for _, h := range hosts[1:] {
+		if hostUses[h] > hostUses[base] {
+			base = h
+		}
+	}
+	if len(hosts) > 1 {
+		out.Warnings = append(out.Warnings, fmt.Sprintf("requests target %d hosts; base_url is %s and the others keep their full URL", len(hosts), base))
+	}
+
+	vars := domain.Vars{"base_url": base}
+	names := map[string]bool{}
+	requests := make([]domain.RequestSpec, 0, len(reqs))
+	for _, p := range reqs {
+		req := p.req
+		if p.base != base {
+			req.URL = p.base + strings.TrimPrefix(req.URL, "{{base_url}}")
+		}
+		req.Name = uniqueName(req.Name, names)
+		requests = append(requests, req)
+	}
+
+	if secrets := factorAuthHeaders(requests, vars); len(secrets) > 0 {
+		out.Secrets = secrets
+	}
+
+	out.Collection = domain.Collection{
+		SchemaVersion: 1,
+		Name:          "Imported from " + strings.TrimPrefix(strings.TrimPrefix(base, "https://"), "http://"),
+		Vars:          vars,
+		Requests:      requests,
+	}
+	return out, nil
+}
+
+// splitCommands cuts the input at unquoted `;`, `&&` and newlines. A line
+// that does not start with curl continues the previous command, so a single
+// command pasted without backslash continuations still parses as one.
+func splitCommands(input string) ([]string, error) {
+	type segment struct {
+		text    string
+		newline bool // separated from the previous segment by a newline
+	}
+
+	var (
+		segs     []segment
+		cur      strings.Builder
+		inSingle bool
+		inDouble bool
+		newline  bool
+	)
+	flush := func(nextNewline bool) {
+		if s := strings.TrimSpace(cur.String()); s != "" && !strings.HasPrefix(s, "#") {
+			segs = append(segs, segment{text: s, newline: newline})
+		}
+		cur.Reset()
+		newline = nextNewline
+	}
+
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case c == '\\' && !inSingle && i+1 < len(input):
+			cur.WriteByte(c)
+			i++
+			cur.WriteByte(input[i])
+			continue
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+		case c == '"' && !inSingle:
+			inDouble = !inDouble
+		case inSingle || inDouble:
+		case c == ';':
+			flush(false)
+			continue
+		case c == '&' && i+1 < len(input) && input[i+1] == '&':
+			i++
+			flush(false)
+			continue
+		case c == '\n':
+			flush(true)
+			continue
+		}
+		cur.WriteByte(c)
+	}
+	if inSingle || inDouble {
+		return nil, fmt.Errorf("tokenize: unterminated quote in input")
+	}
+	flush(false)
+
+	var cmds []string
+	for _, s := range segs {
+		if !startsWithCurl(s.text) {
+			if s.newline && len(cmds) > 0 {
+				cmds[len(cmds)-1] += " " + s.text
+				continue
+			}
+			return nil, fmt.Errorf("command %d does not start with curl: %q", len(cmds)+1, s.text)
+		}
+		cmds = append(cmds, s.text)
+	}
+	return cmds, nil
+}
+
+func startsWithCurl(s string) bool {
+	first, _, _ := strings.Cut(s, " ")
+	return first == "curl" || strings.HasSuffix(first, "/curl")
+}
+
+// requestKey identifies requests that would send the exact same thing.
+func requestKey(base string, req domain.RequestSpec) string {
+	var b strings.Builder
+	b.WriteString(string(req.Method) + " " + base + req.URL + "\n")
+	keys := make([]string, 0, len(req.Headers))
+	for k := range req.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString(strings.ToLower(k) + ": " + req.Headers[k] + "\n")
+	}
+	b.Write(req.Body.Serialize())
+	return b.String()
+}
+
+func uniqueName(name string, taken map[string]bool) string {
+	out := name
+	for i := 2; taken[out]; i++ {
+		out = fmt.Sprintf("%s-%d", name, i)
+	}
+	taken[out] = true
+	return out
+}
+
+// authHeaders are the headers whose repeated values are factored into vars.
+var authHeaders = map[string]string{
+	"authorization":       "",
+	"proxy-authorization": "proxy_auth",
+	"cookie":              "cookie",
+	"x-api-key":           "api_key",
+	"api-key":             "api_key",
+	"x-auth-token":        "auth_token",
+}
+
+// factorAuthHeaders replaces auth header values used by more than one
+// request with a {{var}}. Authorization keeps its scheme in the header
+// ("Bearer {{token}}", "Basic {{basic_auth}}") like the other importers.
+// The values are returned, by var name, rather than added to vars: they are
+// credentials and do not belong in the collection file.
+func factorAuthHeaders(reqs []domain.RequestSpec, vars domain.Vars) domain.Vars {
+	type use struct {
+		header string // lower-cased header name
+		value  string
+	}
+	counts := map[use]int{}
+	var order []use
+	for _, r := range reqs {
+		for k, v := range r.Headers {
+			u := use{strings.ToLower(k), v}
+			if _, ok := authHeaders[u.header]; !ok {
+				continue
+			}
+			if counts[u] == 0 {
+				order = append(order, u)
+			}
+			counts[u]++
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].header != order[j].header {
+			return order[i].header < order[j].header
+		}
+		return order[i].value < order[j].value
+	})
+
+	replacement := map[use]string{}
+	secrets := domain.Vars{}
+	for _, u := range order {
+		if counts[u] < 2 {
+			continue
+		}
+		prefix, secret := "", u.value
+		varName := authHeaders[u.header]
+		if u.header == "authorization" {
+			scheme, rest, ok := strings.Cut(u.value, " ")
+			switch {
+			case ok && strings.EqualFold(scheme, "Bearer"):
+				prefix, secret, varName = scheme+" ", rest, "token"
+			case ok && strings.EqualFold(scheme, "Basic"):
+				prefix, secret, varName = scheme+" ", rest, "basic_auth"
+			default:
+				varName = "authorization"
+			}
+		}
+		varName = uniqueName(varName, varTaken(vars, secrets))
+		secrets[varName] = secret
+		replacement[u] = prefix + "{{" + varName + "}}"
+	}
+
+	for _, r := range reqs {
+		for k, v := range r.Headers {
+			if rep, ok := replacement[use{strings.ToLower(k), v}]; ok {
+				r.Headers[k] = rep
+			}
+		}
+	}
+	return secrets
+}
+
+func varTaken(sets ...domain.Vars) map[string]bool {
+	taken := map[string]bool{}
+	for _, vars := range sets {
+		for k := range vars {
+			taken[k] = true
+		}
+	}
+	return taken
+}
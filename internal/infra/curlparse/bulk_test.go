@@ -0,0 +1,174 @@
+package curlparse
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAll_SingleCommandMatchesParse(t *testing.T) {
+	cmd := `curl -H 'Authorization: Bearer abc' https://api.example.com/users`
+	one, err := Parse(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := ParseAll(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all.Collection.Requests[0].Headers["Authorization"] != one.Collection.Requests[0].Headers["Authorization"] ||
+		all.Collection.Vars["base_url"] != one.Collection.Vars["base_url"] {
+		t.Errorf("ParseAll(single) = %+v, want %+v", all.Collection, one.Collection)
+	}
+}
+
+func TestParseAll_DevToolsCopyAll(t *testing.T) {
+	input := `curl 'https://api.example.com/users?page=1' \
+  -H 'accept: application/json' \
+  -H 'authorization: Bearer tok-123' ;
+curl 'https://api.example.com/users' \
+  -H 'authorization: Bearer tok-123' \
+  --data-raw '{"name":"ada"}' ;
+curl 'https://api.example.com/users?page=2' \
+  -H 'authorization: Bearer tok-123'`
+
+	r, err := ParseAll(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := r.Collection
+	if len(col.Requests) != 3 {
+		t.Fatalf("requests: got %d", len(col.Requests))
+	}
+	if col.Vars["base_url"] != "https://api.example.com" {
+		t.Errorf("base_url: got %q", col.Vars["base_url"])
+	}
+	if r.Secrets["token"] != "tok-123" {
+		t.Errorf("token secret: got %q", r.Secrets["token"])
+	}
+	if _, ok := col.Vars["token"]; ok {
+		t.Errorf("the token value must stay out of the collection vars: %v", col.Vars)
+	}
+	for _, req := range col.Requests {
+		if req.Headers["authorization"] != "Bearer {{token}}" {
+			t.Errorf("%s: authorization got %q", req.Name, req.Headers["authorization"])
+		}
+		if !strings.HasPrefix(req.URL, "{{base_url}}/users") {
+			t.Errorf("%s: url got %q", req.Name, req.URL)
+		}
+	}
+
+	names := map[string]bool{}
+	for _, req := range col.Requests {
+		if names[req.Name] {
+			t.Errorf("duplicate request name %q", req.Name)
+		}
+		names[req.Name] = true
+	}
+	if col.Requests[1].Method != "POST" || col.Requests[2].Name != "get-users-2" {
+		t.Errorf("unexpected requests: %s %s, %s", col.Requests[1].Method, col.Requests[1].Name, col.Requests[2].Name)
+	}
+}
+
+func TestParseAll_NewlineAndAmpersandSeparated(t *testing.T) {
+	input := "curl https://a.test/one\ncurl https://a.test/two && curl https://a.test/three"
+	r, err := ParseAll(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Collection.Requests) != 3 {
+		t.Fatalf("requests: got %d", len(r.Collection.Requests))
+	}
+}
+
+func TestParseAll_LineWithoutCurlContinuesCommand(t *testing.T) {
+	input := "curl -X POST\n  -H 'X-A: 1'\n  https://a.test/items"
+	r, err := ParseAll(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Collection.Requests) != 1 || r.Collection.Requests[0].Headers["X-A"] != "1" {
+		t.Errorf("got %+v", r.Collection.Requests)
+	}
+}
+
+func TestParseAll_QuotedSeparatorsAreKept(t *testing.T) {
+	input := `curl https://a.test/a -d 'x=1;y=2' ; curl https://a.test/b -H "X-Q: a && b"`
+	r, err := ParseAll(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Collection.Requests) != 2 {
+		t.Fatalf("requests: got %d", len(r.Collection.Requests))
+	}
+	if r.Collection.Requests[0].Body.Raw != "x=1;y=2" {
+		t.Errorf("body: got %q", r.Collection.Requests[0].Body.Raw)
+	}
+	if r.Collection.Requests[1].Headers["X-Q"] != "a && b" {
+		t.Errorf("header: got %q", r.Collection.Requests[1].Headers["X-Q"])
+	}
+}
+
+func TestParseAll_DuplicatesCollapsed(t *testing.T) {
+	input := "curl https://a.test/ping\ncurl https://a.test/ping\ncurl https://a.test/ping -H 'X-A: 1'"
+	r, err := ParseAll(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Collection.Requests) != 2 {
+		t.Fatalf("requests: got %d", len(r.Collection.Requests))
+	}
+	found := false
+	for _, w := range r.Warnings {
+		if strings.Contains(w, "command 2 duplicates command 1") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("missing duplicate warning: %v", r.Warnings)
+	}
+}
+
+func TestParseAll_MixedHosts(t *testing.T) {
+	input := "curl https://a.test/x\ncurl https://b.test/y\ncurl https://b.test/z"
+	r, err := ParseAll(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Collection.Vars["base_url"] != "https://b.test" {
+		t.Errorf("base_url: got %q", r.Collection.Vars["base_url"])
+	}
+	if r.Collection.Requests[0].URL != "https://a.test/x" {
+		t.Errorf("other host url: got %q", r.Collection.Requests[0].URL)
+	}
+	if r.Collection.Requests[1].URL != "{{base_url}}/y" {
+		t.Errorf("base host url: got %q", r.Collection.Requests[1].URL)
+	}
+	if r.Collection.Name != "Imported from b.test" {
+		t.Errorf("name: got %q", r.Collection.Name)
+	}
+}
+
+func TestParseAll_SingleUseAuthStaysInline(t *testing.T) {
+	input := "curl https://a.test/x -H 'X-Api-Key: k1'\ncurl https://a.test/y -H 'X-Api-Key: k2'"
+	r, err := ParseAll(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Collection.Vars) != 1 {
+		t.Errorf("vars: got %v", r.Collection.Vars)
+	}
+	if r.Collection.Requests[0].Headers["X-Api-Key"] != "k1" {
+		t.Errorf("header: got %q", r.Collection.Requests[0].Headers["X-Api-Key"])
+	}
+}
+
+func TestParseAll_ErrorNamesCommand(t *testing.T) {
+	_, err := ParseAll("curl https://a.test/x ; curl -X")
+	if err == nil || !strings.Contains(err.Error(), "command 2") {
+		t.Fatalf("expected error for command 2, got %v", err)
+	}
+	_, err = ParseAll("curl https://a.test/x ; echo done")
+	if err == nil || !strings.Contains(err.Error(), "does not start with curl") {
+		t.Fatalf("expected non-curl error, got %v", err)
+	}
+}
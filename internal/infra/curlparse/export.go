@@ -0,0 +1,182 @@
+package curlparse
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// Command is the wire-level view of a request rendered as a curl command.
+// It is built either from a templated RequestSpec ({{vars}} left in place)
+// or from a dry-run RequestResult (everything resolved).
+type Command struct {
+	Name            string
+	Method          domain.HTTPMethod
+	URL             string
+	Headers         map[string]string
+	Body            []byte
+	FollowRedirects bool
+	TimeoutMS       *int
+}
+
+// CommandFromSpec renders a request exactly as written in the collection,
+// keeping {{var}} placeholders for the reader to fill in.
+func CommandFromSpec(req domain.RequestSpec) Command {
+	return Command{
+		Name:            req.Name,
+		Method:          req.Method,
+		URL:             req.URL,
+		Headers:         withContentType(req.Headers, req.Body.Type),
+		Body:            specBody(req.Body),
+		FollowRedirects: req.FollowRedirects == nil || *req.FollowRedirects,
+		TimeoutMS:       req.TimeoutMS,
+	}
+}
+
+// specBody serializes a templated body. Form bodies are encoded here rather
+// than by Serialize, which would percent-encode {{var}} placeholders into
+// %7B%7Bvar%7D%7D and send that literally.
+func specBody(b domain.BodySpec) []byte {
+	if b.Type != domain.BodyForm || b.Form == nil {
+		return b.Serialize()
+	}
+	keys := make([]string, 0, len(b.Form))
+	for k := range b.Form {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = escapeTemplate(k) + "=" + escapeTemplate(b.Form[k])
+	}
+	return []byte(strings.Join(pairs, "&"))
+}
+
+// escapeTemplate form-encodes s like url.QueryEscape, leaving its {{...}}
+// placeholders as written.
+func escapeTemplate(s string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "{{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(s[start:], "}}")
+		if end < 0 {
+			break
+		}
+		end += start + 2
+		b.WriteString(url.QueryEscape(s[:start]))
+		b.WriteString(s[start:end])
+		s = s[end:]
+	}
+	b.WriteString(url.QueryEscape(s))
+	return b.String()
+}
+
+// CommandFromResult renders a resolved (dry-run) request. The spec supplies
+// what the result does not carry: body type, redirect and timeout settings.
+func CommandFromResult(req domain.RequestSpec, rr domain.RequestResult) Command {
+	return Command{
+		Name:            rr.Name,
+		Method:          rr.Method,
+		URL:             rr.ResolvedURL,
+		Headers:         withContentType(rr.RequestHeaders, req.Body.Type),
+		Body:            rr.RequestBody,
+		FollowRedirects: req.FollowRedirects == nil || *req.FollowRedirects,
+		TimeoutMS:       req.TimeoutMS,
+	}
+}
+
+// withContentType mirrors httpclient.BuildRequest: JSON and form bodies get
+// an implicit Content-Type unless the request sets one explicitly. Without
+// it, the exported command would not send what Lynix sends.
+func withContentType(h map[string]string, bodyType domain.BodyType) map[string]string {
+	out := make(map[string]string, len(h)+1)
+	hasCT := false
+	for k, v := range h {
+		out[k] = v
+		if strings.EqualFold(k, "Content-Type") {
+			hasCT = true
+		}
+	}
+	if hasCT {
+		return out
+	}
+	switch bodyType {
+	case domain.BodyJSON:
+		out["Content-Type"] = "application/json"
+	case domain.BodyForm:
+		out["Content-Type"] = "application/x-www-form-urlencoded"
+	}
+	return out
+}
+
+// Format renders a single curl command with one flag per continuation line.
+// Headers are sorted so the output is stable and diffable.
+func Format(c Command) string {
+	var b strings.Builder
+	b.WriteString("curl")
+	switch c.Method {
+	case "", domain.MethodGet:
+	case domain.MethodHead:
+		// -X HEAD would make curl wait for a body that never comes.
+		b.WriteString(" -I")
+	default:
+		b.WriteString(" -X " + string(c.Method))
+	}
+	// Lynix follows redirects by default; curl does not.
+	if c.FollowRedirects {
+		b.WriteString(" -L")
+	}
+	b.WriteString(" " + shellQuote(c.URL))
+
+	keys := make([]string, 0, len(c.Headers))
+	for k := range c.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString(" \\\n  -H " + shellQuote(k+": "+c.Headers[k]))
+	}
+
+	if c.TimeoutMS != nil && *c.TimeoutMS > 0 {
+		fmt.Fprintf(&b, " \\\n  --max-time %s", formatSeconds(*c.TimeoutMS))
+	}
+
+	if len(c.Body) > 0 {
+		b.WriteString(" \\\n  --data-raw " + shellQuote(string(c.Body)))
+	}
+	return b.String()
+}
+
+// FormatScript renders commands as a POSIX shell script, one commented
+// command per request in collection order.
+func FormatScript(collectionName string, cmds []Command) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "# Exported from Lynix collection %q\n", collectionName)
+	for _, c := range cmds {
+		fmt.Fprintf(&b, "\n# %s\n", c.Name)
+		b.WriteString(Format(c))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// shellQuote wraps s in single quotes. Embedded single quotes close the
+// quoted string, add an escaped quote and reopen it, the only POSIX-safe way.
+// The result round-trips through tokenize.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func formatSeconds(ms int) string {
+	if ms%1000 == 0 {
+		return fmt.Sprintf("%d", ms/1000)
+	}
+	return fmt.Sprintf("%.3f", float64(ms)/1000)
+}
@@ -0,0 +1,144 @@
+package curlparse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+func TestFormat_GetOmitsMethodAndFollowsRedirects(t *testing.T) {
+	out := Format(CommandFromSpec(domain.RequestSpec{
+		Name:   "health",
+		Method: domain.MethodGet,
+		URL:    "{{base_url}}/health",
+	}))
+	if strings.Contains(out, "-X") {
+		t.Errorf("GET should not emit -X:\n%s", out)
+	}
+	if !strings.Contains(out, " -L ") {
+		t.Errorf("expected -L by default:\n%s", out)
+	}
+	if !strings.Contains(out, "'{{base_url}}/health'") {
+		t.Errorf("expected templated URL to be kept:\n%s", out)
+	}
+}
+
+func TestFormat_NoRedirectsAndTimeout(t *testing.T) {
+	f := false
+	ms := 1500
+	out := Format(CommandFromSpec(domain.RequestSpec{
+		Method:          domain.MethodDelete,
+		URL:             "https://api.example.com/x",
+		FollowRedirects: &f,
+		TimeoutMS:       &ms,
+	}))
+	if strings.Contains(out, "-L") {
+		t.Errorf("follow_redirects: false should drop -L:\n%s", out)
+	}
+	if !strings.Contains(out, "-X DELETE") || !strings.Contains(out, "--max-time 1.500") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestFormat_HeadUsesHeadFlag(t *testing.T) {
+	out := Format(CommandFromSpec(domain.RequestSpec{
+		Method: domain.MethodHead,
+		URL:    "https://api.example.com/x",
+	}))
+	if strings.Contains(out, "-X") || !strings.Contains(out, "curl -I -L ") {
+		t.Errorf("HEAD should emit -I, not -X HEAD:\n%s", out)
+	}
+
+	res, err := Parse(out)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if res.Collection.Requests[0].Method != domain.MethodHead {
+		t.Errorf("round trip method = %s, want HEAD", res.Collection.Requests[0].Method)
+	}
+}
+
+func TestFormat_ImplicitContentType(t *testing.T) {
+	out := Format(CommandFromSpec(domain.RequestSpec{
+		Method: domain.MethodPost,
+		URL:    "https://api.example.com/login",
+		Body:   domain.BodySpec{Type: domain.BodyForm, Form: map[string]string{"user": "a"}},
+	}))
+	if !strings.Contains(out, "'Content-Type: application/x-www-form-urlencoded'") {
+		t.Errorf("expected implicit form Content-Type:\n%s", out)
+	}
+	if !strings.Contains(out, "--data-raw 'user=a'") {
+		t.Errorf("expected form body:\n%s", out)
+	}
+}
+
+func TestFormat_TemplatedFormKeepsPlaceholders(t *testing.T) {
+	out := Format(CommandFromSpec(domain.RequestSpec{
+		Method: domain.MethodPost,
+		URL:    "{{base_url}}/login",
+		Body:   domain.BodySpec{Type: domain.BodyForm, Form: map[string]string{"user": "a b", "pw": "{{pw}}", "note": "x&{{tag}}"}},
+	}))
+	if !strings.Contains(out, "--data-raw 'note=x%26{{tag}}&pw={{pw}}&user=a+b'") {
+		t.Errorf("expected placeholders left intact and text encoded:\n%s", out)
+	}
+}
+
+func TestFormat_RoundTripThroughParse(t *testing.T) {
+	spec := domain.RequestSpec{
+		Method:  domain.MethodPost,
+		URL:     "https://api.example.com/v1/users",
+		Headers: domain.Headers{"Authorization": "Bearer abc"},
+		Body:    domain.BodySpec{Type: domain.BodyJSON, JSON: map[string]any{"name": "O'Brien"}},
+	}
+
+	res, err := Parse(Format(CommandFromSpec(spec)))
+	if err != nil {
+		t.Fatalf("parse exported command: %v", err)
+	}
+	req := res.Collection.Requests[0]
+	if req.Method != domain.MethodPost {
+		t.Errorf("method: got %q", req.Method)
+	}
+	if res.Collection.Vars["base_url"] != "https://api.example.com" || req.URL != "{{base_url}}/v1/users" {
+		t.Errorf("url: got %q (base_url %q)", req.URL, res.Collection.Vars["base_url"])
+	}
+	if req.Headers["Authorization"] != "Bearer abc" || req.Headers["Content-Type"] != "application/json" {
+		t.Errorf("headers: got %v", req.Headers)
+	}
+	m, ok := req.Body.JSON.(map[string]any)
+	if !ok || m["name"] != "O'Brien" {
+		t.Errorf("body: got %#v", req.Body.JSON)
+	}
+}
+
+func TestCommandFromResult_UsesResolvedValues(t *testing.T) {
+	spec := domain.RequestSpec{
+		Name:   "me",
+		Method: domain.MethodGet,
+		URL:    "{{base_url}}/me",
+	}
+	rr := domain.RequestResult{
+		Name:           "me",
+		Method:         domain.MethodGet,
+		ResolvedURL:    "https://api.example.com/me",
+		RequestHeaders: map[string]string{"Authorization": "Bearer xyz"},
+	}
+	out := Format(CommandFromResult(spec, rr))
+	if !strings.Contains(out, "'https://api.example.com/me'") || !strings.Contains(out, "'Authorization: Bearer xyz'") {
+		t.Errorf("expected resolved values:\n%s", out)
+	}
+}
+
+func TestFormatScript_CommentsEachRequest(t *testing.T) {
+	out := FormatScript("demo", []Command{
+		{Name: "a", Method: domain.MethodGet, URL: "https://e.com/a"},
+		{Name: "b", Method: domain.MethodGet, URL: "https://e.com/b"},
+	})
+	if !strings.HasPrefix(out, "#!/bin/sh\n") {
+		t.Errorf("expected shebang:\n%s", out)
+	}
+	if !strings.Contains(out, "\n# a\ncurl") || !strings.Contains(out, "\n# b\ncurl") {
+		t.Errorf("expected a comment before each command:\n%s", out)
+	}
+}
@@ -15,6 +15,11 @@ type Result struct {
 	Collection domain.Collection
 	Warnings   []string
 	Insecure   bool // curl -k/--insecure was present
+
+	// Secrets holds the auth values ParseAll moved out of the collection,
+	// by the var name the requests now reference. Callers store them
+	// outside the collection file (secrets.local.yaml).
+	Secrets domain.Vars
 }
 
 // Parse converts a curl command string into a domain.Collection.
@@ -75,6 +80,14 @@ func Parse(input string) (Result, error) {
 			continue
 		}
 
+		// -I / --head
+		if tok == "-I" || tok == "--head" {
+			if method == "" {
+				method = string(domain.MethodHead)
+			}
+			continue
+		}
+
 		// -H / --header
 		if tok == "-H" || tok == "--header" {
 			i++
@@ -64,8 +64,8 @@ func New(client *http.Client, opts ...Option) *Runner {
 
 var _ ports.RequestRunner = (*Runner)(nil)
 
-func (r *Runner) Run(ctx context.Context, req domain.RequestSpec, vars domain.Vars) (domain.RequestResult, error) {
-	rt, err := r.resolver.NewRuntime(vars)
+func (r *Runner) Run(ctx context.Context, req domain.RequestSpec, vars domain.Vars, types map[string]domain.VarType) (domain.RequestResult, error) {
+	rt, err := r.resolver.NewRuntime(vars, types)
 	if err != nil {
 		return domain.RequestResult{}, err
 	}
@@ -137,6 +137,9 @@ func (r *Runner) Run(ctx context.Context, req domain.RequestSpec, vars domain.Va
 
 	result.StatusCode = resp.StatusCode
 	result.Response.Headers = cloneHeaders(resp.Header)
+	if resp.Request != nil && resp.Request.URL.String() != httpReq.URL.String() {
+		result.Response.FinalURL = resp.Request.URL.String()
+	}
 
 	body, truncated, readErr := readBounded(resp.Body, r.maxBodyBytes)
 	if readErr != nil {
@@ -34,7 +34,7 @@ func TestRunner_TruncatesBody(t *testing.T) {
 		Body: domain.BodySpec{Type: domain.BodyNone},
 	}
 
-	res, err := r.Run(context.Background(), req, domain.Vars{})
+	res, err := r.Run(context.Background(), req, domain.Vars{}, nil)
 	if err != nil {
 		t.Fatalf("Run error: %v", err)
 	}
@@ -84,13 +84,16 @@ func TestRunner_FollowRedirects_Nil_FollowsByDefault(t *testing.T) {
 		Headers: domain.Headers{},
 	}
 
-	res, err := r.Run(context.Background(), req, domain.Vars{})
+	res, err := r.Run(context.Background(), req, domain.Vars{}, nil)
 	if err != nil {
 		t.Fatalf("Run error: %v", err)
 	}
 	if res.StatusCode != http.StatusOK {
 		t.Errorf("expected 200 (followed redirect), got %d", res.StatusCode)
 	}
+	if res.Response.FinalURL != srv.URL+"/target" || res.FinalURL() != srv.URL+"/target" {
+		t.Errorf("expected the final URL to be the redirect target, got %q", res.Response.FinalURL)
+	}
 }
 
 func TestRunner_FollowRedirects_False_StopsAtRedirect(t *testing.T) {
@@ -116,13 +119,16 @@ func TestRunner_FollowRedirects_False_StopsAtRedirect(t *testing.T) {
 		FollowRedirects: &noFollow,
 	}
 
-	res, err := r.Run(context.Background(), req, domain.Vars{})
+	res, err := r.Run(context.Background(), req, domain.Vars{}, nil)
 	if err != nil {
 		t.Fatalf("Run error: %v", err)
 	}
 	if res.StatusCode != http.StatusFound {
 		t.Errorf("expected 302 (stopped at redirect), got %d", res.StatusCode)
 	}
+	if res.Response.FinalURL != "" || res.FinalURL() != srv.URL+"/redirect" {
+		t.Errorf("expected no redirect target, got %q", res.Response.FinalURL)
+	}
 }
 
 func TestRunner_ClassifiesTimeout(t *testing.T) {
@@ -145,7 +151,7 @@ func TestRunner_ClassifiesTimeout(t *testing.T) {
 		Headers: domain.Headers{},
 	}
 
-	res, err := r.Run(context.Background(), req, domain.Vars{})
+	res, err := r.Run(context.Background(), req, domain.Vars{}, nil)
 	if err != nil {
 		t.Fatalf("Run error: %v", err)
 	}
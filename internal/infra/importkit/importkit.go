@@ -0,0 +1,108 @@
+// Package importkit holds what the collection importers (Postman, Bruno,
+// Insomnia) share: request naming, header and query helpers, and the policy
+// for translating auth settings, so the importers cannot drift apart.
+package importkit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// Auth is a request's auth in the terms the importers have in common.
+// Values are already in Lynix template syntax.
+type Auth struct {
+	Type    string // "bearer", "basic" or "apikey"; "" means no auth
+	Token   string // bearer token
+	Prefix  string // bearer scheme, "Bearer" when empty
+	Key     string // apikey header or query parameter name
+	Value   string // apikey value
+	InQuery bool   // apikey is sent as a query parameter rather than a header
+}
+
+// ApplyAuth translates bearer, basic and apikey auth into headers, or into a
+// query parameter of rawURL for an apikey with InQuery, and returns the URL.
+// Credentials are always written as {{vars}}: a literal token is replaced by
+// a placeholder so it never lands in a committed collection file. A header
+// the request sets explicitly wins. Warnings name the request reqName.
+func ApplyAuth(reqName string, a Auth, headers domain.Headers, rawURL string) (string, []string) {
+	var warnings []string
+
+	switch a.Type {
+	case "":
+
+	case "bearer":
+		token, ok := CredentialRef(a.Token, "token")
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("request %q: bearer auth token is not a {{variable}}; mapped to %s (define it in your env or secrets)", reqName, token))
+		}
+		prefix := a.Prefix
+		if prefix == "" {
+			prefix = "Bearer"
+		}
+		SetDefaultHeader(headers, "Authorization", prefix+" "+token)
+
+	case "basic":
+		// Lynix has no base64 templating, so the encoded pair has to be
+		// provided as a single variable.
+		SetDefaultHeader(headers, "Authorization", "Basic {{basic_auth}}")
+		warnings = append(warnings, fmt.Sprintf("request %q: basic auth mapped to \"Basic {{basic_auth}}\"; set basic_auth to base64(\"username:password\")", reqName))
+
+	case "apikey":
+		if a.Key == "" {
+			warnings = append(warnings, fmt.Sprintf("request %q: apikey auth has no key name and was ignored", reqName))
+			break
+		}
+		value, ok := CredentialRef(a.Value, "api_key")
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("request %q: apikey auth value is not a {{variable}}; mapped to %s (define it in your env or secrets)", reqName, value))
+		}
+		if a.InQuery {
+			rawURL = AppendQuery(rawURL, a.Key, value)
+		} else {
+			SetDefaultHeader(headers, a.Key, value)
+		}
+
+	default:
+		warnings = append(warnings, fmt.Sprintf("request %q: auth type %q was ignored", reqName, a.Type))
+	}
+
+	return rawURL, warnings
+}
+
+// CredentialRef returns v when it is a single {{var}} reference, otherwise a
+// placeholder named after fallback. The bool reports whether v was kept.
+func CredentialRef(v, fallback string) (string, bool) {
+	v = strings.TrimSpace(v)
+	if strings.HasPrefix(v, "{{") && strings.HasSuffix(v, "}}") && strings.Count(v, "{{") == 1 {
+		return v, true
+	}
+	return "{{" + fallback + "}}", false
+}
+
+// SetDefaultHeader adds a header unless the request already sets it
+// explicitly (in any letter case).
+func SetDefaultHeader(headers domain.Headers, name, value string) {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			return
+		}
+	}
+	headers[name] = value
+}
+
+// AppendQuery adds name=value to the query string of rawURL. Both are
+// appended as written so {{var}} placeholders stay intact.
+func AppendQuery(rawURL, name, value string) string {
+	sep := "?"
+	if strings.Contains(rawURL, "?") {
+		sep = "&"
+	}
+	return rawURL + sep + name + "=" + value
+}
+
+// Slugify turns a display name into a YAML-friendly request or folder name.
+func Slugify(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "-"))
+}
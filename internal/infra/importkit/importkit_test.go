@@ -0,0 +1,49 @@
+package importkit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+func TestApplyAuth_KeepsCredentialsOutOfTheCollection(t *testing.T) {
+	headers := domain.Headers{}
+	_, warnings := ApplyAuth("login", Auth{Type: "bearer", Token: "eyJhbGciOi"}, headers, "https://x")
+	if headers["Authorization"] != "Bearer {{token}}" {
+		t.Errorf("literal token must become a placeholder, got %q", headers["Authorization"])
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `request "login": bearer auth token is not a {{variable}}`) {
+		t.Errorf("expected a warning, got %v", warnings)
+	}
+
+	headers = domain.Headers{}
+	_, warnings = ApplyAuth("me", Auth{Type: "bearer", Token: "{{jwt}}", Prefix: "JWT"}, headers, "https://x")
+	if headers["Authorization"] != "JWT {{jwt}}" || len(warnings) != 0 {
+		t.Errorf("got %q, %v", headers["Authorization"], warnings)
+	}
+}
+
+func TestApplyAuth_APIKey(t *testing.T) {
+	headers := domain.Headers{"x-api-key": "explicit"}
+	_, _ = ApplyAuth("r", Auth{Type: "apikey", Key: "X-Api-Key", Value: "{{key}}"}, headers, "https://x")
+	if len(headers) != 1 || headers["x-api-key"] != "explicit" {
+		t.Errorf("an explicit header should win, got %v", headers)
+	}
+
+	rawURL, _ := ApplyAuth("r", Auth{Type: "apikey", Key: "key", Value: "{{key}}", InQuery: true}, domain.Headers{}, "https://x/?a=1")
+	if rawURL != "https://x/?a=1&key={{key}}" {
+		t.Errorf("url: got %q", rawURL)
+	}
+
+	_, warnings := ApplyAuth("r", Auth{Type: "digest"}, domain.Headers{}, "https://x")
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `auth type "digest" was ignored`) {
+		t.Errorf("expected an ignored auth warning, got %v", warnings)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	if got := Slugify(" Get User "); got != "get-user" {
+		t.Errorf("Slugify = %q", got)
+	}
+}
@@ -0,0 +1,375 @@
+package insomniaparse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/importkit"
+)
+
+// Result holds the parsed collection, the sub-environments of the workspace
+// and any warnings about unsupported features.
+type Result struct {
+	Collection   domain.Collection
+	Environments []Environment
+	Warnings     []string
+}
+
+// Environment is an Insomnia sub-environment. Secret lists the keys that
+// should not land in a committed env file: every key of a private
+// environment is marked.
+type Environment struct {
+	Name   string
+	Vars   domain.Vars
+	Secret map[string]bool
+}
+
+// Parse reads an Insomnia v4 export, JSON or YAML, and converts its first
+// workspace to a domain.Collection. The base environment becomes collection
+// vars; sub-environments are returned separately.
+func Parse(r io.Reader) (Result, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return Result{}, fmt.Errorf("read insomnia export: %w", err)
+	}
+
+	var ex Export
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = json.Unmarshal(b, &ex)
+	} else {
+		err = yaml.Unmarshal(b, &ex)
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("decode insomnia export: %w", err)
+	}
+	if ex.Format != 4 {
+		return Result{}, fmt.Errorf("unsupported insomnia export format %d (expected 4)", ex.Format)
+	}
+
+	children := map[string][]Resource{}
+	var workspaces []Resource
+	for _, res := range ex.Resources {
+		if res.Type == "workspace" {
+			workspaces = append(workspaces, res)
+			continue
+		}
+		children[res.ParentID] = append(children[res.ParentID], res)
+	}
+	if len(workspaces) == 0 {
+		return Result{}, fmt.Errorf("insomnia export contains no workspace")
+	}
+
+	p := &parser{children: children, vars: domain.Vars{}, seen: map[string]bool{}}
+	ws := workspaces[0]
+	for _, other := range workspaces[1:] {
+		p.warn("workspace %q was skipped; only the first workspace (%q) is imported", other.Name, ws.Name)
+	}
+
+	var envs []Environment
+	for i, base := range p.sorted(ws.ID, "environment") {
+		if i > 0 {
+			p.warn("extra base environment %q was skipped", base.Name)
+			continue
+		}
+		p.flatten("", base.Data, p.vars)
+		for _, sub := range p.sorted(base.ID, "environment") {
+			env := Environment{Name: sub.Name, Vars: domain.Vars{}, Secret: map[string]bool{}}
+			p.flatten("", sub.Data, env.Vars)
+			if sub.IsPrivate {
+				for k := range env.Vars {
+					env.Secret[k] = true
+				}
+			}
+			envs = append(envs, env)
+		}
+	}
+
+	p.walk(ws.ID, "", nil, nil)
+
+	col := domain.Collection{
+		SchemaVersion: 1,
+		Name:          ws.Name,
+		Vars:          p.vars,
+		Requests:      p.reqs,
+	}
+	if len(col.Vars) == 0 {
+		col.Vars = nil
+	}
+
+	return Result{Collection: col, Environments: envs, Warnings: p.warnings}, nil
+}
+
+type parser struct {
+	children map[string][]Resource
+	vars     domain.Vars
+	reqs     []domain.RequestSpec
+	seen     map[string]bool
+	warnings []string
+}
+
+func (p *parser) warn(format string, args ...any) {
+	p.warnings = append(p.warnings, fmt.Sprintf(format, args...))
+}
+
+// sorted returns the children of parent with the given types in the order
+// Insomnia displays them (metaSortKey, then export order).
+func (p *parser) sorted(parent string, types ...string) []Resource {
+	var out []Resource
+	for _, res := range p.children[parent] {
+		for _, t := range types {
+			if res.Type == t {
+				out = append(out, res)
+				break
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].MetaSortKey < out[j].MetaSortKey })
+	return out
+}
+
+// walk flattens folders depth-first. Request names get the folder path as a
+// dotted prefix and every enclosing folder becomes a tag, so `--tag` can
+// still select a folder.
+func (p *parser) walk(parent, prefix string, tags []string, auth *Auth) {
+	for _, res := range p.children[parent] {
+		switch res.Type {
+		case "grpc_request", "websocket_request":
+			p.warn("%s %q is not supported and was skipped", strings.ReplaceAll(res.Type, "_", " "), res.Name)
+		}
+	}
+
+	for _, res := range p.sorted(parent, "request_group", "request") {
+		if res.Type == "request" {
+			p.mapRequest(res, prefix, tags, auth)
+			continue
+		}
+
+		slug := importkit.Slugify(res.Name)
+		folderPrefix := slug
+		if prefix != "" {
+			folderPrefix = prefix + "." + slug
+		}
+
+		// Folder environments are merged into the single collection
+		// scope; a clash keeps the outer value.
+		folderVars := domain.Vars{}
+		p.flatten("", res.Environment, folderVars)
+		for _, k := range sortedKeys(folderVars) {
+			if prev, ok := p.vars[k]; ok && prev != folderVars[k] {
+				p.warn("folder %q: variable %q conflicts with an existing value and was skipped", res.Name, k)
+				continue
+			}
+			p.vars[k] = folderVars[k]
+		}
+
+		folderAuth := auth
+		if !inherits(res.Authentication) {
+			folderAuth = res.Authentication
+		}
+		folderTags := append(append([]string(nil), tags...), slug)
+		p.walk(res.ID, folderPrefix, folderTags, folderAuth)
+	}
+}
+
+func (p *parser) mapRequest(res Resource, prefix string, tags []string, inheritedAuth *Auth) {
+	name := importkit.Slugify(res.Name)
+	if prefix != "" {
+		name = prefix + "." + name
+	}
+	if p.seen[name] {
+		base := name
+		for i := 2; p.seen[name]; i++ {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+		p.warn("request name %q is used more than once; renamed to %q", base, name)
+	}
+	p.seen[name] = true
+
+	method := strings.ToUpper(res.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	tpl := &templater{}
+	rawURL := tpl.convert(res.URL)
+	for _, q := range res.Parameters {
+		if q.Disabled || q.Name == "" {
+			continue
+		}
+		rawURL = importkit.AppendQuery(rawURL, tpl.convert(q.Name), tpl.convert(q.Value))
+	}
+
+	headers := domain.Headers{}
+	for _, h := range res.Headers {
+		if h.Disabled || h.Name == "" {
+			continue
+		}
+		headers[h.Name] = tpl.convert(h.Value)
+	}
+
+	auth := inheritedAuth
+	if !inherits(res.Authentication) {
+		auth = res.Authentication
+	}
+	if auth != nil {
+		rawURL = p.applyAuth(res.Name, auth, tpl, headers, rawURL)
+	}
+
+	req := domain.RequestSpec{
+		Name:    name,
+		Method:  domain.HTTPMethod(method),
+		URL:     rawURL,
+		Headers: headers,
+		Body:    p.mapBody(res, tpl, headers),
+		Tags:    tags,
+	}
+	if len(req.Headers) == 0 {
+		req.Headers = nil
+	}
+	if res.FollowRedirect == "off" {
+		off := false
+		req.FollowRedirects = &off
+	}
+
+	for _, tag := range tpl.unsupported {
+		p.warn("request %q: template tag {%% %s %%} is not supported and was kept as text", res.Name, tag)
+	}
+	p.reqs = append(p.reqs, req)
+}
+
+func (p *parser) mapBody(res Resource, tpl *templater, headers domain.Headers) domain.BodySpec {
+	b := res.Body
+	mime, _, _ := strings.Cut(b.MimeType, ";")
+	mime = strings.TrimSpace(mime)
+
+	switch {
+	case mime == "" && b.Text == "":
+		return domain.BodySpec{Type: domain.BodyNone}
+
+	case mime == "application/x-www-form-urlencoded":
+		form := map[string]string{}
+		for _, f := range b.Params {
+			if f.Disabled || f.Name == "" {
+				continue
+			}
+			form[tpl.convert(f.Name)] = tpl.convert(f.Value)
+		}
+		return domain.BodySpec{Type: domain.BodyForm, Form: form}
+
+	case mime == "multipart/form-data":
+		p.warn("request %q: multipart form-data body is not supported", res.Name)
+		return domain.BodySpec{Type: domain.BodyNone}
+
+	case mime == "application/json" || mime == "application/graphql" || strings.HasSuffix(mime, "+json"):
+		// GraphQL bodies are stored as the JSON envelope Insomnia sends.
+		text := tpl.convert(b.Text)
+		var v any
+		if err := json.Unmarshal([]byte(text), &v); err == nil {
+			switch v.(type) {
+			case map[string]any, []any:
+				if mime != "application/json" && mime != "application/graphql" {
+					importkit.SetDefaultHeader(headers, "Content-Type", mime)
+				}
+				return domain.BodySpec{Type: domain.BodyJSON, JSON: v}
+			}
+		}
+		// Templated or invalid JSON is sent verbatim.
+		importkit.SetDefaultHeader(headers, "Content-Type", "application/json")
+		return domain.BodySpec{Type: domain.BodyRaw, Raw: text}
+
+	default:
+		if mime != "" {
+			importkit.SetDefaultHeader(headers, "Content-Type", mime)
+		}
+		return domain.BodySpec{Type: domain.BodyRaw, Raw: tpl.convert(b.Text)}
+	}
+}
+
+// applyAuth maps an Insomnia authentication object onto the shared importer
+// policy. A disabled one is skipped, a bearer keeps its custom prefix and an
+// apikey with addTo queryParams goes into the query string.
+func (p *parser) applyAuth(reqName string, auth *Auth, tpl *templater, headers domain.Headers, rawURL string) string {
+	if auth.Disabled {
+		return rawURL
+	}
+	a := importkit.Auth{Type: auth.Type}
+	switch auth.Type {
+	case "none", "inherit":
+		a.Type = ""
+	case "bearer":
+		a.Token = tpl.convert(auth.Token)
+		a.Prefix = auth.Prefix
+	case "apikey":
+		a.Key = tpl.convert(auth.Key)
+		a.Value = tpl.convert(auth.Value)
+		a.InQuery = auth.AddTo == "queryParams"
+	}
+	rawURL, warnings := importkit.ApplyAuth(reqName, a, headers, rawURL)
+	p.warnings = append(p.warnings, warnings...)
+	return rawURL
+}
+
+// flatten turns nested environment data into dotted keys, which is how
+// Insomnia templates address them ({{ _.api.host }} → {{api.host}}).
+func (p *parser) flatten(prefix string, data map[string]any, out domain.Vars) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := data[k]
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch t := v.(type) {
+		case map[string]any:
+			p.flatten(key, t, out)
+		case string:
+			tpl := &templater{}
+			out[key] = tpl.convert(t)
+			for _, tag := range tpl.unsupported {
+				p.warn("variable %q: template tag {%% %s %%} is not supported and was kept as text", key, tag)
+			}
+		default:
+			out[key] = scalarString(t)
+		}
+	}
+}
+
+func inherits(auth *Auth) bool {
+	return auth == nil || auth.Type == "" || auth.Type == "inherit"
+}
+
+func scalarString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case int, int64, bool:
+		return fmt.Sprint(t)
+	default:
+		b, _ := json.Marshal(t)
+		return string(b)
+	}
+}
+
+func sortedKeys(vars domain.Vars) []string {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,284 @@
+package insomniaparse
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/yamlcollection"
+)
+
+func parseTestdata(t *testing.T, name string) Result {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	r, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return r
+}
+
+func requestNames(col domain.Collection) []string {
+	var names []string
+	for _, r := range col.Requests {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+func hasWarning(warnings []string, substr string) bool {
+	for _, w := range warnings {
+		if strings.Contains(w, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestParse_Testdata_OrderAndFolders(t *testing.T) {
+	r := parseTestdata(t, "workspace.json")
+
+	if r.Collection.Name != "Shop API" {
+		t.Errorf("name: got %q", r.Collection.Name)
+	}
+	got := strings.Join(requestNames(r.Collection), ",")
+	want := "login,orders.list-orders,orders.create-order,ping"
+	if got != want {
+		t.Fatalf("requests:\n got %s\nwant %s", got, want)
+	}
+
+	list := r.Collection.Requests[1]
+	if len(list.Tags) != 1 || list.Tags[0] != "orders" {
+		t.Errorf("folder tags: got %v", list.Tags)
+	}
+	if r.Collection.Requests[0].Tags != nil {
+		t.Errorf("top-level request tags: got %v", r.Collection.Requests[0].Tags)
+	}
+}
+
+func TestParse_Testdata_Vars(t *testing.T) {
+	r := parseTestdata(t, "workspace.json")
+
+	want := domain.Vars{
+		"base_url":    "https://api.shop.test",
+		"api.version": "v2",
+		"page_size":   "20",
+		"orders_path": "/orders",
+	}
+	for k, v := range want {
+		if r.Collection.Vars[k] != v {
+			t.Errorf("var %s: got %q, want %q", k, r.Collection.Vars[k], v)
+		}
+	}
+
+	login := r.Collection.Requests[0]
+	if login.URL != "{{base_url}}/{{api.version}}/login" {
+		t.Errorf("login url: got %q", login.URL)
+	}
+	list := r.Collection.Requests[1]
+	if list.URL != "{{base_url}}{{orders_path}}?limit={{page_size}}" {
+		t.Errorf("list url: got %q", list.URL)
+	}
+}
+
+func TestParse_Testdata_Environments(t *testing.T) {
+	r := parseTestdata(t, "workspace.json")
+
+	if len(r.Environments) != 2 {
+		t.Fatalf("environments: got %d", len(r.Environments))
+	}
+	stg, me := r.Environments[0], r.Environments[1]
+	if stg.Name != "Staging (EU)" || stg.Vars["base_url"] != "https://stg.shop.test" || len(stg.Secret) != 0 {
+		t.Errorf("staging: got %+v", stg)
+	}
+	if me.Name != "Personal" || me.Vars["user_pin"] != "1234" || !me.Secret["user_pin"] {
+		t.Errorf("private env: got %+v", me)
+	}
+}
+
+func TestParse_Testdata_BodiesAndAuth(t *testing.T) {
+	r := parseTestdata(t, "workspace.json")
+	reqs := r.Collection.Requests
+
+	login := reqs[0]
+	if login.Body.Type != domain.BodyForm || login.Body.Form["user"] != "demo" || len(login.Body.Form) != 1 {
+		t.Errorf("form body: got %+v", login.Body)
+	}
+	if login.FollowRedirects == nil || *login.FollowRedirects {
+		t.Errorf("follow_redirects: got %v", login.FollowRedirects)
+	}
+
+	create := reqs[2]
+	if create.Method != domain.MethodPost {
+		t.Errorf("method: got %q", create.Method)
+	}
+	if create.Body.Type != domain.BodyJSON {
+		t.Fatalf("json body: got %+v", create.Body)
+	}
+	if m := create.Body.JSON.(map[string]any); m["sku"] != "A-1" {
+		t.Errorf("json body: got %v", m)
+	}
+	if create.Headers["Authorization"] != "Bearer {{token}}" {
+		t.Errorf("inherited bearer: got %q", create.Headers["Authorization"])
+	}
+	if create.Headers["X-Request-Id"] != "{{$uuid}}" {
+		t.Errorf("uuid tag: got %q", create.Headers["X-Request-Id"])
+	}
+	if _, ok := create.Headers["X-Debug"]; ok {
+		t.Error("disabled header should be skipped")
+	}
+
+	ping := reqs[3]
+	if ping.Body.Type != domain.BodyRaw || ping.Body.Raw != "<ping/>" {
+		t.Errorf("xml body: got %+v", ping.Body)
+	}
+	if ping.Headers["Content-Type"] != "application/xml" {
+		t.Errorf("xml content type: got %q", ping.Headers["Content-Type"])
+	}
+	if ping.Headers["X-Api-Key"] != "{{api_key}}" {
+		t.Errorf("apikey header: got %q", ping.Headers["X-Api-Key"])
+	}
+	if _, ok := ping.Headers["Authorization"]; ok {
+		t.Error("top-level request should not inherit folder auth")
+	}
+
+	if len(r.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", r.Warnings)
+	}
+}
+
+func TestParse_YAML(t *testing.T) {
+	input := `_type: export
+__export_format: 4
+resources:
+  - _id: wrk_1
+    _type: workspace
+    name: YAML API
+  - _id: req_1
+    _type: request
+    parentId: wrk_1
+    name: Health
+    method: GET
+    url: "{{ base_url }}/health"
+`
+	r, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Collection.Name != "YAML API" || len(r.Collection.Requests) != 1 {
+		t.Fatalf("got %+v", r.Collection)
+	}
+	if r.Collection.Requests[0].URL != "{{base_url}}/health" {
+		t.Errorf("url: got %q", r.Collection.Requests[0].URL)
+	}
+}
+
+func TestParse_UnsupportedFormat(t *testing.T) {
+	_, err := Parse(strings.NewReader(`{"_type": "export", "__export_format": 3, "resources": []}`))
+	if err == nil || !strings.Contains(err.Error(), "format 3") {
+		t.Fatalf("expected format error, got %v", err)
+	}
+}
+
+func TestParse_NoWorkspace(t *testing.T) {
+	_, err := Parse(strings.NewReader(`{"__export_format": 4, "resources": []}`))
+	if err == nil {
+		t.Fatal("expected error for export without workspace")
+	}
+}
+
+func TestParse_Warnings(t *testing.T) {
+	input := `{
+		"__export_format": 4,
+		"resources": [
+			{"_id": "wrk_1", "_type": "workspace", "name": "A"},
+			{"_id": "wrk_2", "_type": "workspace", "name": "B"},
+			{"_id": "r1", "_type": "request", "parentId": "wrk_1", "name": "Chained", "method": "GET",
+			 "url": "https://x.test/{% response 'body', 'req_0', 'b64::JC5pZA==::46b', 'never', 60 %}"},
+			{"_id": "r2", "_type": "request", "parentId": "wrk_1", "name": "Upload", "method": "POST", "url": "https://x.test",
+			 "body": {"mimeType": "multipart/form-data", "params": [{"name": "f", "value": "x"}]}},
+			{"_id": "r3", "_type": "request", "parentId": "wrk_1", "name": "Digest", "method": "GET", "url": "https://x.test",
+			 "authentication": {"type": "digest", "username": "u", "password": "p"}},
+			{"_id": "r4", "_type": "request", "parentId": "wrk_1", "name": "Literal", "method": "GET", "url": "https://x.test",
+			 "authentication": {"type": "bearer", "token": "abc123"}},
+			{"_id": "g1", "_type": "grpc_request", "parentId": "wrk_1", "name": "Stream"}
+		]
+	}`
+	r, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`workspace "B" was skipped`,
+		`template tag {% response %} is not supported`,
+		`"Upload": multipart form-data body is not supported`,
+		`auth type "digest" was ignored`,
+		`bearer auth token is not a {{variable}}`,
+		`grpc request "Stream" is not supported`,
+	} {
+		if !hasWarning(r.Warnings, want) {
+			t.Errorf("missing warning %q in %v", want, r.Warnings)
+		}
+	}
+	for _, w := range r.Warnings {
+		if strings.Contains(w, "abc123") {
+			t.Errorf("warning leaks the literal token: %s", w)
+		}
+	}
+	if got := r.Collection.Requests[3].Headers["Authorization"]; got != "Bearer {{token}}" {
+		t.Errorf("literal token should be replaced, got %q", got)
+	}
+}
+
+func TestParse_DuplicateNamesAreSuffixed(t *testing.T) {
+	input := `{
+		"__export_format": 4,
+		"resources": [
+			{"_id": "wrk_1", "_type": "workspace", "name": "Dups"},
+			{"_id": "r1", "_type": "request", "parentId": "wrk_1", "name": "Get user", "metaSortKey": 1, "method": "GET", "url": "https://x.test/1"},
+			{"_id": "r2", "_type": "request", "parentId": "wrk_1", "name": "Get User", "metaSortKey": 2, "method": "GET", "url": "https://x.test/2"}
+		]
+	}`
+	r, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(requestNames(r.Collection), ","); got != "get-user,get-user-2" {
+		t.Errorf("names: got %s", got)
+	}
+	if !hasWarning(r.Warnings, `renamed to "get-user-2"`) {
+		t.Errorf("missing rename warning: %v", r.Warnings)
+	}
+}
+
+func TestParse_Testdata_RoundTrip(t *testing.T) {
+	r := parseTestdata(t, "workspace.json")
+
+	b, err := yamlcollection.MarshalCollection(r.Collection)
+	if err != nil {
+		t.Fatalf("MarshalCollection: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "shop.yaml")
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := yamlcollection.NewLoader().LoadCollection(path)
+	if err != nil {
+		t.Fatalf("LoadCollection: %v\nYAML:\n%s", err, string(b))
+	}
+	if len(loaded.Requests) != 4 {
+		t.Fatalf("round-trip requests: got %d", len(loaded.Requests))
+	}
+	if loaded.Requests[1].Tags[0] != "orders" {
+		t.Errorf("round-trip tags: got %v", loaded.Requests[1].Tags)
+	}
+}
@@ -0,0 +1,49 @@
+package insomniaparse
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// {{ _.name }}, {{ _.nested.key }} and the older {{ name }} form.
+	reVarTag = regexp.MustCompile(`\{\{\s*(?:_\.)?([A-Za-z_][\w.-]*)\s*\}\}`)
+	// {% tag args %}: template functions such as uuid, now or response.
+	reBlockTag = regexp.MustCompile(`\{%\s*(\w+)\s*([^%]*?)\s*%\}`)
+)
+
+// templater rewrites Insomnia's Nunjucks templates into Lynix placeholders
+// and remembers the template functions it could not map.
+type templater struct {
+	unsupported []string
+}
+
+func (t *templater) convert(s string) string {
+	if !strings.Contains(s, "{{") && !strings.Contains(s, "{%") {
+		return s
+	}
+	s = reVarTag.ReplaceAllString(s, "{{$1}}")
+	return reBlockTag.ReplaceAllStringFunc(s, func(m string) string {
+		sub := reBlockTag.FindStringSubmatch(m)
+		tag, args := sub[1], strings.Trim(sub[2], `'" `)
+		switch {
+		case tag == "uuid":
+			return "{{$uuid}}"
+		case tag == "now" && (args == "" || args == "iso-8601"):
+			return "{{$isoTimestamp}}"
+		case tag == "now" && args == "unix":
+			return "{{$timestamp}}"
+		}
+		t.note(tag)
+		return m
+	})
+}
+
+func (t *templater) note(tag string) {
+	for _, seen := range t.unsupported {
+		if seen == tag {
+			return
+		}
+	}
+	t.unsupported = append(t.unsupported, tag)
+}
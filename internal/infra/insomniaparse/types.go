@@ -0,0 +1,64 @@
+package insomniaparse
+
+// Export is an Insomnia v4 export. The same shape is written as JSON or YAML;
+// every workspace, folder, request and environment is a flat resource linked
+// to its parent by id.
+type Export struct {
+	Type      string     `json:"_type" yaml:"_type"`
+	Format    int        `json:"__export_format" yaml:"__export_format"`
+	Resources []Resource `json:"resources" yaml:"resources"`
+}
+
+// Resource is one entry of the export. Only the fields Lynix can map are
+// decoded; the meaning of most of them depends on Type.
+type Resource struct {
+	ID          string  `json:"_id" yaml:"_id"`
+	Type        string  `json:"_type" yaml:"_type"` // workspace, request_group, request, environment, ...
+	ParentID    string  `json:"parentId" yaml:"parentId"`
+	Name        string  `json:"name" yaml:"name"`
+	MetaSortKey float64 `json:"metaSortKey" yaml:"metaSortKey"`
+
+	// request_group
+	Environment map[string]any `json:"environment" yaml:"environment"`
+
+	// request
+	Method         string  `json:"method" yaml:"method"`
+	URL            string  `json:"url" yaml:"url"`
+	Body           Body    `json:"body" yaml:"body"`
+	Headers        []Param `json:"headers" yaml:"headers"`
+	Parameters     []Param `json:"parameters" yaml:"parameters"`
+	Authentication *Auth   `json:"authentication" yaml:"authentication"`                 // also set on request_group
+	FollowRedirect string  `json:"settingFollowRedirects" yaml:"settingFollowRedirects"` // "global", "on" or "off"
+
+	// environment
+	Data      map[string]any `json:"data" yaml:"data"`
+	IsPrivate bool           `json:"isPrivate" yaml:"isPrivate"`
+}
+
+// Body is a request body. Text carries raw, JSON, XML and GraphQL payloads;
+// Params carries form fields.
+type Body struct {
+	MimeType string  `json:"mimeType" yaml:"mimeType"`
+	Text     string  `json:"text" yaml:"text"`
+	Params   []Param `json:"params" yaml:"params"`
+}
+
+// Param is a header, query parameter or form field.
+type Param struct {
+	Name     string `json:"name" yaml:"name"`
+	Value    string `json:"value" yaml:"value"`
+	Disabled bool   `json:"disabled" yaml:"disabled"`
+}
+
+// Auth is the authentication block of a request or folder.
+type Auth struct {
+	Type     string `json:"type" yaml:"type"` // bearer, basic, apikey, none, inherit, ...
+	Disabled bool   `json:"disabled" yaml:"disabled"`
+	Token    string `json:"token" yaml:"token"`
+	Prefix   string `json:"prefix" yaml:"prefix"`
+	Username string `json:"username" yaml:"username"`
+	Password string `json:"password" yaml:"password"`
+	Key      string `json:"key" yaml:"key"`
+	Value    string `json:"value" yaml:"value"`
+	AddTo    string `json:"addTo" yaml:"addTo"` // "header" or "queryParams"
+}
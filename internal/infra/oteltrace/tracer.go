@@ -0,0 +1,447 @@
+// Package oteltrace exports lynix runs as OpenTelemetry traces over OTLP/HTTP
+// (JSON encoding): one span for the run and one child span per request. The
+// runner wrapper injects a W3C traceparent header into every outgoing request
+// so server-side traces join the test's trace.
+package oteltrace
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aalvaropc/lynix/internal/buildinfo"
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/ports"
+)
+
+const (
+	defaultServiceName = "lynix"
+	scopeName          = "github.com/aalvaropc/lynix"
+	tracesPath         = "/v1/traces"
+)
+
+// Tracer records spans while a run progresses (it is a ports.RunObserver)
+// and sends them to the collector on Flush.
+type Tracer struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	client      *http.Client
+	redact      func(domain.RequestResult) domain.RequestResult
+	redactText  func(string) string
+	now         func() time.Time
+
+	mu      sync.Mutex
+	traceID [16]byte
+	root    *span
+	open    map[int]*span // request index → span in flight
+	done    []*span
+}
+
+type span struct {
+	id     [8]byte
+	parent [8]byte
+	name   string
+	kind   int
+	start  time.Time
+	end    time.Time
+	attrs  []otlpKeyValue
+	status otlpStatus
+}
+
+type Option func(*Tracer)
+
+// WithHeaders adds HTTP headers to export requests (e.g. collector API keys).
+func WithHeaders(h map[string]string) Option {
+	return func(t *Tracer) {
+		for k, v := range h {
+			t.headers[k] = v
+		}
+	}
+}
+
+// WithServiceName sets the service.name resource attribute (default "lynix").
+func WithServiceName(name string) Option {
+	return func(t *Tracer) {
+		if name != "" {
+			t.serviceName = name
+		}
+	}
+}
+
+// WithHTTPClient sets the client used to export spans.
+func WithHTTPClient(c *http.Client) Option {
+	return func(t *Tracer) { t.client = c }
+}
+
+// WithRedact scrubs each request result before its URL and failure messages
+// become span attributes.
+func WithRedact(fn func(domain.RequestResult) domain.RequestResult) Option {
+	return func(t *Tracer) { t.redact = fn }
+}
+
+// WithRedactText scrubs free text, such as the run's error, before it
+// becomes a span status.
+func WithRedactText(fn func(string) string) Option {
+	return func(t *Tracer) { t.redactText = fn }
+}
+
+// WithNow is useful for tests.
+func WithNow(now func() time.Time) Option {
+	return func(t *Tracer) { t.now = now }
+}
+
+// New creates a tracer exporting to an OTLP/HTTP endpoint. A bare collector
+// address (http://localhost:4318) gets the standard /v1/traces path; any other
+// path is used as is.
+func New(endpoint string, opts ...Option) (*Tracer, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, &domain.OpError{
+			Op:   "oteltrace.new",
+			Kind: domain.KindInvalidConfig,
+			Err:  fmt.Errorf("%w: otel endpoint must be an http(s) URL, got %q", domain.ErrInvalidConfig, endpoint),
+		}
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = tracesPath
+	}
+
+	t := &Tracer{
+		endpoint:    u.String(),
+		headers:     map[string]string{},
+		serviceName: defaultServiceName,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		redact:      func(rr domain.RequestResult) domain.RequestResult { return rr },
+		redactText:  func(s string) string { return s },
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t, nil
+}
+
+func (t *Tracer) RunStarted(run domain.RunResult, requests []domain.RequestSpec) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.traceID = newTraceID()
+	t.open = map[int]*span{}
+	t.done = nil
+	t.root = &span{
+		id:    newSpanID(),
+		name:  "lynix run " + run.CollectionName,
+		kind:  spanKindInternal,
+		start: run.StartedAt,
+		attrs: []otlpKeyValue{
+			stringAttr("lynix.collection.name", run.CollectionName),
+			stringAttr("lynix.collection.path", run.CollectionPath),
+			stringAttr("lynix.environment", run.EnvironmentName),
+			intAttr("lynix.requests", int64(len(requests))),
+		},
+	}
+}
+
+func (t *Tracer) RequestStarted(index int, req domain.RequestSpec) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.root == nil {
+		return
+	}
+	t.open[index] = t.requestSpan(string(req.Method), req.Name)
+}
+
+// requestSpan starts a child of the run span. Callers hold t.mu.
+func (t *Tracer) requestSpan(method, name string) *span {
+	return &span{
+		id:     newSpanID(),
+		parent: t.root.id,
+		name:   strings.TrimSpace(method + " " + name),
+		kind:   spanKindClient,
+		start:  t.now(),
+	}
+}
+
+func (t *Tracer) RequestFinished(index int, result domain.RequestResult) {
+	rr := t.redact(result)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.root == nil {
+		return
+	}
+	s := t.open[index]
+	if s == nil {
+		// Requests interrupted before they were sent still get a span.
+		s = t.requestSpan(string(rr.Method), rr.Name)
+	}
+	delete(t.open, index)
+	s.end = t.now()
+
+	s.attrs = append(s.attrs,
+		stringAttr("lynix.request.name", rr.Name),
+		stringAttr("http.request.method", string(rr.Method)),
+	)
+	if rr.ResolvedURL != "" {
+		s.attrs = append(s.attrs, stringAttr("url.full", rr.ResolvedURL))
+	}
+	if rr.StatusCode != 0 {
+		s.attrs = append(s.attrs, intAttr("http.response.status_code", int64(rr.StatusCode)))
+	}
+	if rr.Attempts > 1 {
+		s.attrs = append(s.attrs, intAttr("lynix.retries", int64(rr.Attempts-1)))
+	}
+
+	var failures []string
+	for _, a := range rr.Assertions {
+		if !a.Passed {
+			failures = append(failures, fmt.Sprintf("[%s] %s", a.Name, a.Message))
+		}
+	}
+	extractFailures := 0
+	for _, e := range rr.Extracts {
+		if !e.Success {
+			extractFailures++
+			failures = append(failures, fmt.Sprintf("[extract:%s] %s", e.Name, e.Message))
+		}
+	}
+	s.attrs = append(s.attrs,
+		intAttr("lynix.assertions.total", int64(len(rr.Assertions))),
+		intAttr("lynix.assertions.failed", int64(len(failures)-extractFailures)),
+	)
+	if extractFailures > 0 {
+		s.attrs = append(s.attrs, intAttr("lynix.extracts.failed", int64(extractFailures)))
+	}
+	if len(failures) > 0 {
+		s.attrs = append(s.attrs, stringsAttr("lynix.failures", failures))
+	}
+
+	switch {
+	case rr.Error != nil:
+		s.attrs = append(s.attrs, stringAttr("error.type", string(rr.Error.Kind)))
+		s.status = otlpStatus{Code: statusError, Message: rr.Error.Message}
+	case len(failures) > 0:
+		s.status = otlpStatus{Code: statusError, Message: fmt.Sprintf("%d check(s) failed", len(failures))}
+	}
+	t.done = append(t.done, s)
+}
+
+func (t *Tracer) RunFinished(run domain.RunResult, runID string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.root == nil {
+		return
+	}
+	t.root.end = run.EndedAt
+	if t.root.end.IsZero() {
+		t.root.end = t.now()
+	}
+
+	passed, failed, errs := 0, 0, 0
+	for _, r := range run.Results {
+		switch {
+		case r.Error != nil:
+			errs++
+		case r.Failed():
+			failed++
+		default:
+			passed++
+		}
+	}
+	if runID != "" {
+		t.root.attrs = append(t.root.attrs, stringAttr("lynix.run.id", runID))
+	}
+	t.root.attrs = append(t.root.attrs,
+		intAttr("lynix.requests.passed", int64(passed)),
+		intAttr("lynix.requests.failed", int64(failed)),
+		intAttr("lynix.requests.errors", int64(errs)),
+	)
+	switch {
+	case err != nil:
+		t.root.status = otlpStatus{Code: statusError, Message: t.redactText(err.Error())}
+	case failed+errs > 0:
+		t.root.status = otlpStatus{Code: statusError, Message: fmt.Sprintf("%d request(s) failed", failed+errs)}
+	}
+	t.done = append(t.done, t.root)
+}
+
+// TraceID returns the hex trace ID of the current run ("" before RunStarted).
+func (t *Tracer) TraceID() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.root == nil {
+		return ""
+	}
+	return hex.EncodeToString(t.traceID[:])
+}
+
+// Flush sends every finished span to the collector. It is a no-op when the
+// run never started.
+func (t *Tracer) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	if len(t.done) == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	payload := t.payload()
+	t.done = nil
+	t.mu.Unlock()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return &domain.OpError{Op: "oteltrace.flush", Kind: domain.KindExecution, Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &domain.OpError{
+			Op:   "oteltrace.flush",
+			Kind: domain.KindExecution,
+			Err:  fmt.Errorf("collector returned HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(msg))),
+		}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// payload encodes the finished spans. Callers hold t.mu.
+func (t *Tracer) payload() otlpTraces {
+	traceID := hex.EncodeToString(t.traceID[:])
+	spans := make([]otlpSpan, 0, len(t.done))
+	for _, s := range t.done {
+		out := otlpSpan{
+			TraceID:           traceID,
+			SpanID:            hex.EncodeToString(s.id[:]),
+			Name:              s.name,
+			Kind:              s.kind,
+			StartTimeUnixNano: unixNano(s.start),
+			EndTimeUnixNano:   unixNano(s.end),
+			Attributes:        s.attrs,
+			Status:            s.status,
+		}
+		if s.parent != ([8]byte{}) {
+			out.ParentSpanID = hex.EncodeToString(s.parent[:])
+		}
+		spans = append(spans, out)
+	}
+	return otlpTraces{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpKeyValue{
+			stringAttr("service.name", t.serviceName),
+			stringAttr("service.version", buildinfo.Version),
+		}},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: scopeName, Version: buildinfo.Version},
+			Spans: spans,
+		}},
+	}}}
+}
+
+// traceparent returns the W3C trace context header for the request at index
+// while it is in flight, or "" when it is not traced.
+func (t *Tracer) traceparent(index int) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.open[index]
+	if s == nil {
+		return ""
+	}
+	return "00-" + hex.EncodeToString(t.traceID[:]) + "-" + hex.EncodeToString(s.id[:]) + "-01"
+}
+
+// WrapRunner returns a runner that adds a traceparent header pointing at the
+// request's span. A traceparent set in the collection is left untouched.
+func (t *Tracer) WrapRunner(next ports.RequestRunner) ports.RequestRunner {
+	return &tracingRunner{next: next, tracer: t}
+}
+
+type tracingRunner struct {
+	next   ports.RequestRunner
+	tracer *Tracer
+}
+
+func (r *tracingRunner) Run(ctx context.Context, req domain.RequestSpec, vars domain.Vars, types map[string]domain.VarType) (domain.RequestResult, error) {
+	index, ok := ports.RequestIndexFromContext(ctx)
+	if !ok {
+		return r.next.Run(ctx, req, vars, types)
+	}
+	tp := r.tracer.traceparent(index)
+	if tp == "" || hasHeader(req.Headers, "traceparent") {
+		return r.next.Run(ctx, req, vars, types)
+	}
+	headers := make(domain.Headers, len(req.Headers)+1)
+	for k, v := range req.Headers {
+		headers[k] = v
+	}
+	headers["traceparent"] = tp
+	req.Headers = headers
+	return r.next.Run(ctx, req, vars, types)
+}
+
+func hasHeader(h domain.Headers, name string) bool {
+	for k := range h {
+		if strings.EqualFold(k, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func newTraceID() [16]byte {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+func newSpanID() [8]byte {
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+func unixNano(t time.Time) string {
+	if t.IsZero() {
+		return "0"
+	}
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func stringAttr(key, v string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: &v}}
+}
+
+func intAttr(key string, v int64) otlpKeyValue {
+	s := strconv.FormatInt(v, 10)
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{IntValue: &s}}
+}
+
+func stringsAttr(key string, vs []string) otlpKeyValue {
+	values := make([]otlpAnyValue, len(vs))
+	for i := range vs {
+		values[i] = otlpAnyValue{StringValue: &vs[i]}
+	}
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}}
+}
@@ -0,0 +1,284 @@
+package oteltrace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/ports"
+)
+
+// receiver is an in-process OTLP/HTTP collector that keeps what it receives.
+type receiver struct {
+	mu      sync.Mutex
+	paths   []string
+	headers []http.Header
+	batches []otlpTraces
+}
+
+func newReceiver(t *testing.T) (*receiver, *httptest.Server) {
+	t.Helper()
+	rcv := &receiver{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch otlpTraces
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rcv.mu.Lock()
+		rcv.paths = append(rcv.paths, r.URL.Path)
+		rcv.headers = append(rcv.headers, r.Header.Clone())
+		rcv.batches = append(rcv.batches, batch)
+		rcv.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+	return rcv, srv
+}
+
+func (r *receiver) spans() []otlpSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []otlpSpan
+	for _, b := range r.batches {
+		for _, rs := range b.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				out = append(out, ss.Spans...)
+			}
+		}
+	}
+	return out
+}
+
+// headerRunner records the headers of every request it is asked to send.
+type headerRunner struct {
+	mu   sync.Mutex
+	seen map[string]domain.Headers
+}
+
+func (h *headerRunner) Run(_ context.Context, req domain.RequestSpec, _ domain.Vars, _ map[string]domain.VarType) (domain.RequestResult, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.seen == nil {
+		h.seen = map[string]domain.Headers{}
+	}
+	h.seen[req.Name] = req.Headers
+	return domain.RequestResult{Name: req.Name, Method: req.Method, StatusCode: 200}, nil
+}
+
+func attr(s otlpSpan, key string) (otlpAnyValue, bool) {
+	for _, kv := range s.Attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return otlpAnyValue{}, false
+}
+
+func TestTracer_ExportsRunAndRequestSpans(t *testing.T) {
+	rcv, srv := newReceiver(t)
+	tr, err := New(srv.URL, WithHeaders(map[string]string{"X-Api-Key": "k"}), WithServiceName("api-tests"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	requests := []domain.RequestSpec{
+		{Name: "login", Method: domain.MethodPost, Headers: domain.Headers{"Accept": "application/json"}},
+		{Name: "me", Method: domain.MethodGet, Headers: domain.Headers{"traceparent": "00-custom"}},
+	}
+	runner := &headerRunner{}
+	wrapped := tr.WrapRunner(runner)
+
+	start := time.Now()
+	run := domain.RunResult{CollectionName: "demo", EnvironmentName: "dev", StartedAt: start}
+	tr.RunStarted(run, requests)
+	for i, req := range requests {
+		tr.RequestStarted(i, req)
+		rr, err := wrapped.Run(ports.ContextWithRequestIndex(context.Background(), i), req, nil, nil)
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		if i == 1 {
+			rr.Attempts = 3
+			rr.Assertions = []domain.AssertionResult{{Name: "status", Passed: false, Message: "expected 201, got 200"}}
+		}
+		run.Results = append(run.Results, rr)
+		tr.RequestFinished(i, rr)
+	}
+	run.EndedAt = time.Now()
+	tr.RunFinished(run, "run-1", nil)
+	traceID := tr.TraceID()
+
+	if err := tr.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if rcv.paths[0] != "/v1/traces" {
+		t.Errorf("expected the default traces path, got %q", rcv.paths[0])
+	}
+	if got := rcv.headers[0].Get("X-Api-Key"); got != "k" {
+		t.Errorf("export header missing, got %q", got)
+	}
+	res := rcv.batches[0].ResourceSpans[0].Resource.Attributes
+	if res[0].Key != "service.name" || *res[0].Value.StringValue != "api-tests" {
+		t.Errorf("unexpected resource: %+v", res)
+	}
+
+	spans := rcv.spans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+	byName := map[string]otlpSpan{}
+	for _, s := range spans {
+		if s.TraceID != traceID {
+			t.Errorf("span %q has trace %s, want %s", s.Name, s.TraceID, traceID)
+		}
+		byName[s.Name] = s
+	}
+	root, login, me := byName["lynix run demo"], byName["POST login"], byName["GET me"]
+	if root.ParentSpanID != "" || login.ParentSpanID != root.SpanID || me.ParentSpanID != root.SpanID {
+		t.Fatalf("request spans should be children of the run span: %+v", spans)
+	}
+	if root.Status.Code != statusError || me.Status.Code != statusError || login.Status.Code != 0 {
+		t.Errorf("unexpected statuses: run=%+v login=%+v me=%+v", root.Status, login.Status, me.Status)
+	}
+	if v, _ := attr(me, "lynix.retries"); v.IntValue == nil || *v.IntValue != "2" {
+		t.Errorf("expected lynix.retries=2 on me")
+	}
+	if v, _ := attr(me, "lynix.failures"); v.ArrayValue == nil || *v.ArrayValue.Values[0].StringValue != "[status] expected 201, got 200" {
+		t.Errorf("expected failure messages on me, got %+v", v)
+	}
+	if v, _ := attr(root, "lynix.run.id"); v.StringValue == nil || *v.StringValue != "run-1" {
+		t.Errorf("expected run id on the run span")
+	}
+
+	// traceparent points the backend at the request's own span; a header
+	// set in the collection wins.
+	want := "00-" + traceID + "-" + login.SpanID + "-01"
+	if got := runner.seen["login"]["traceparent"]; got != want {
+		t.Errorf("traceparent = %q, want %q", got, want)
+	}
+	if requests[0].Headers["traceparent"] != "" {
+		t.Error("the collection's headers must not be modified")
+	}
+	if got := runner.seen["me"]["traceparent"]; got != "00-custom" {
+		t.Errorf("explicit traceparent should be kept, got %q", got)
+	}
+}
+
+func TestTracer_SameNamedRequestsGetOwnSpans(t *testing.T) {
+	rcv, srv := newReceiver(t)
+	tr, _ := New(srv.URL)
+
+	requests := []domain.RequestSpec{
+		{Name: "ping", Method: domain.MethodGet},
+		{Name: "ping", Method: domain.MethodGet},
+	}
+	var sent []string
+	wrapped := tr.WrapRunner(runnerFunc(func(_ context.Context, r domain.RequestSpec, _ domain.Vars) (domain.RequestResult, error) {
+		sent = append(sent, r.Headers["traceparent"])
+		return domain.RequestResult{Name: r.Name, Method: r.Method, StatusCode: 200}, nil
+	}))
+	tr.RunStarted(domain.RunResult{StartedAt: time.Now()}, requests)
+	for i, req := range requests {
+		tr.RequestStarted(i, req)
+		rr, _ := wrapped.Run(ports.ContextWithRequestIndex(context.Background(), i), req, nil, nil)
+		tr.RequestFinished(i, rr)
+	}
+	tr.RunFinished(domain.RunResult{EndedAt: time.Now()}, "", nil)
+	if err := tr.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	var ids []string
+	for _, s := range rcv.spans() {
+		if s.Name == "GET ping" {
+			ids = append(ids, s.SpanID)
+		}
+	}
+	if len(ids) != 2 || ids[0] == ids[1] {
+		t.Fatalf("expected two distinct request spans, got %v", ids)
+	}
+	if len(sent) != 2 || sent[0] == sent[1] || !strings.Contains(sent[0], ids[0]) || !strings.Contains(sent[1], ids[1]) {
+		t.Errorf("each request should carry its own span in traceparent: sent=%v spans=%v", sent, ids)
+	}
+}
+
+type runnerFunc func(context.Context, domain.RequestSpec, domain.Vars) (domain.RequestResult, error)
+
+func (f runnerFunc) Run(ctx context.Context, req domain.RequestSpec, vars domain.Vars, _ map[string]domain.VarType) (domain.RequestResult, error) {
+	return f(ctx, req, vars)
+}
+
+func TestTracer_RedactsAttributes(t *testing.T) {
+	rcv, srv := newReceiver(t)
+	redact := func(rr domain.RequestResult) domain.RequestResult {
+		rr.ResolvedURL = strings.Replace(rr.ResolvedURL, "s3cret", "***", 1)
+		return rr
+	}
+	redactText := func(s string) string { return strings.ReplaceAll(s, "s3cret", "***") }
+	tr, _ := New(srv.URL+"/custom/path", WithRedact(redact), WithRedactText(redactText))
+
+	req := domain.RequestSpec{Name: "r", Method: domain.MethodGet}
+	tr.RunStarted(domain.RunResult{StartedAt: time.Now()}, []domain.RequestSpec{req})
+	tr.RequestStarted(0, req)
+	tr.RequestFinished(0, domain.RequestResult{Name: "r", Method: domain.MethodGet, ResolvedURL: "https://x/?token=s3cret"})
+	tr.RunFinished(domain.RunResult{EndedAt: time.Now()}, "", errors.New("GET https://x/?token=s3cret: timeout"))
+	if err := tr.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if rcv.paths[0] != "/custom/path" {
+		t.Errorf("an explicit path should be kept, got %q", rcv.paths[0])
+	}
+	for _, s := range rcv.spans() {
+		if v, ok := attr(s, "url.full"); ok && *v.StringValue != "https://x/?token=***" {
+			t.Errorf("url.full not redacted: %s", *v.StringValue)
+		}
+		if strings.Contains(s.Status.Message, "s3cret") {
+			t.Errorf("span status not redacted: %q", s.Status.Message)
+		}
+	}
+}
+
+func TestTracer_FlushWithoutRunIsNoop(t *testing.T) {
+	rcv, srv := newReceiver(t)
+	tr, _ := New(srv.URL)
+	if err := tr.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if len(rcv.batches) != 0 {
+		t.Error("nothing should be exported before a run starts")
+	}
+}
+
+func TestTracer_FlushReportsCollectorErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	tr, _ := New(srv.URL)
+	tr.RunStarted(domain.RunResult{StartedAt: time.Now()}, nil)
+	tr.RunFinished(domain.RunResult{}, "", nil)
+	err := tr.Flush(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "429") || !domain.IsKind(err, domain.KindExecution) {
+		t.Fatalf("expected an execution error with the status, got %v", err)
+	}
+}
+
+func TestNew_RejectsInvalidEndpoint(t *testing.T) {
+	for _, ep := range []string{"localhost:4318", "grpc://collector:4317", "http://"} {
+		if _, err := New(ep); !domain.IsKind(err, domain.KindInvalidConfig) {
+			t.Errorf("New(%q): expected invalid config, got %v", ep, err)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package postmanparse
+
+import (
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/importkit"
+)
+
+// applyAuth maps a Postman auth block (bearer, basic or apikey, with "in"
+// placing an apikey in the query) onto the shared importer policy.
+func applyAuth(reqName string, auth *PostmanAuth, headers domain.Headers, rawURL string) (string, []string) {
+	a := importkit.Auth{Type: auth.Type}
+	switch auth.Type {
+	case "noauth", "inherit":
+		a.Type = ""
+	case "bearer":
+		a.Token = authParam(auth.Bearer, "token")
+	case "apikey":
+		a.Key = authParam(auth.APIKey, "key")
+		a.Value = authParam(auth.APIKey, "value")
+		a.InQuery = authParam(auth.APIKey, "in") == "query"
+	}
+	return importkit.ApplyAuth(reqName, a, headers, rawURL)
+}
+
+func authParam(params []PostmanKV, key string) string {
+	for _, kv := range params {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package postmanparse
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// PostmanEnvironment is a Postman environment or globals export.
+type PostmanEnvironment struct {
+	Name   string            `json:"name"`
+	Scope  string            `json:"_postman_variable_scope"`
+	Values []PostmanEnvValue `json:"values"`
+}
+
+// PostmanEnvValue is a single environment variable. Value is decoded loosely
+// because exports occasionally carry numbers or booleans.
+type PostmanEnvValue struct {
+	Key     string `json:"key"`
+	Value   any    `json:"value"`
+	Type    string `json:"type"` // "default" or "secret"
+	Enabled *bool  `json:"enabled"`
+}
+
+// EnvResult holds the parsed environment. Secret lists the keys Postman
+// marks as secret; deciding where they are written is up to the caller.
+type EnvResult struct {
+	Name     string
+	Globals  bool
+	Vars     domain.Vars
+	Secret   map[string]bool
+	Warnings []string
+}
+
+// ParseEnvironment reads a Postman environment (or globals) JSON export.
+func ParseEnvironment(r io.Reader) (EnvResult, error) {
+	var pe PostmanEnvironment
+	if err := json.NewDecoder(r).Decode(&pe); err != nil {
+		return EnvResult{}, fmt.Errorf("decode postman environment: %w", err)
+	}
+
+	res := EnvResult{
+		Name:    pe.Name,
+		Globals: pe.Scope == "globals",
+		Vars:    domain.Vars{},
+		Secret:  map[string]bool{},
+	}
+
+	for _, v := range pe.Values {
+		if strings.TrimSpace(v.Key) == "" {
+			continue
+		}
+		if v.Enabled != nil && !*v.Enabled {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("variable %q is disabled and was skipped", v.Key))
+			continue
+		}
+
+		var value string
+		switch t := v.Value.(type) {
+		case nil:
+		case string:
+			value = t
+		default:
+			b, _ := json.Marshal(t)
+			value = string(b)
+		}
+
+		res.Vars[v.Key] = value
+		if v.Type == "secret" {
+			res.Secret[v.Key] = true
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,56 @@
+package postmanparse
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseEnvironment_ValuesAndSecrets(t *testing.T) {
+	input := `{
+		"name": "Staging (EU)",
+		"_postman_variable_scope": "environment",
+		"values": [
+			{"key": "base_url", "value": "https://stg.example.com", "type": "default", "enabled": true},
+			{"key": "client_secret", "value": "s3cr3t", "type": "secret", "enabled": true},
+			{"key": "page_size", "value": 20, "enabled": true},
+			{"key": "old", "value": "x", "enabled": false},
+			{"key": "", "value": "ignored"}
+		]
+	}`
+
+	r, err := ParseEnvironment(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Name != "Staging (EU)" || r.Globals {
+		t.Errorf("name/scope: got %q globals=%v", r.Name, r.Globals)
+	}
+	if len(r.Vars) != 3 {
+		t.Fatalf("vars: got %v", r.Vars)
+	}
+	if r.Vars["page_size"] != "20" {
+		t.Errorf("numeric value: got %q", r.Vars["page_size"])
+	}
+	if !r.Secret["client_secret"] || r.Secret["base_url"] {
+		t.Errorf("secret keys: got %v", r.Secret)
+	}
+	if len(r.Warnings) != 1 || !strings.Contains(r.Warnings[0], "old") {
+		t.Errorf("expected disabled warning, got %v", r.Warnings)
+	}
+}
+
+func TestParseEnvironment_Globals(t *testing.T) {
+	r, err := ParseEnvironment(strings.NewReader(`{"name": "My Workspace Globals", "_postman_variable_scope": "globals", "values": []}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Globals {
+		t.Error("expected globals scope")
+	}
+}
+
+func TestParseEnvironment_InvalidJSON(t *testing.T) {
+	if _, err := ParseEnvironment(strings.NewReader("not json")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
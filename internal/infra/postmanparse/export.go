@@ -0,0 +1,233 @@
+package postmanparse
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// SchemaV21 is the schema URL Postman uses to recognize v2.1 collections.
+const SchemaV21 = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// ExportResult holds the generated Postman collection and any warnings about
+// Lynix features that have no Postman equivalent.
+type ExportResult struct {
+	Collection PostmanCollection
+	Warnings   []string
+}
+
+// Export converts a domain.Collection into a Postman Collection v2.1.
+// {{vars}} are kept as-is, requests are grouped into folders by their first
+// tag, and assert.status / assert.status_in become pm.test scripts.
+func Export(col domain.Collection) ExportResult {
+	var warnings []string
+
+	pc := PostmanCollection{
+		Info: PostmanInfo{Name: col.Name, Schema: SchemaV21},
+		Item: []PostmanItem{},
+	}
+
+	keys := make([]string, 0, len(col.Vars))
+	for k := range col.Vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, ws := exportTemplate(col.Vars[k], fmt.Sprintf("variable %q", k))
+		warnings = append(warnings, ws...)
+		pc.Variable = append(pc.Variable, PostmanKV{Key: k, Value: v})
+	}
+
+	// Folders keep first-seen order so the export reads like the collection.
+	folderIdx := map[string]int{}
+	for _, req := range col.Requests {
+		item, ws := exportRequest(req)
+		warnings = append(warnings, ws...)
+
+		if len(req.Tags) == 0 {
+			pc.Item = append(pc.Item, item)
+			continue
+		}
+		folder := req.Tags[0]
+		idx, ok := folderIdx[folder]
+		if !ok {
+			idx = len(pc.Item)
+			folderIdx[folder] = idx
+			pc.Item = append(pc.Item, PostmanItem{Name: folder})
+		}
+		pc.Item[idx].Item = append(pc.Item[idx].Item, item)
+	}
+
+	return ExportResult{Collection: pc, Warnings: warnings}
+}
+
+func exportRequest(req domain.RequestSpec) (PostmanItem, []string) {
+	var warnings []string
+	where := fmt.Sprintf("request %q", req.Name)
+	tmpl := func(s string) string {
+		out, ws := exportTemplate(s, where)
+		warnings = append(warnings, ws...)
+		return out
+	}
+
+	method := string(req.Method)
+	if method == "" {
+		method = string(domain.MethodGet)
+	}
+
+	pr := &PostmanRequest{
+		Method: method,
+		URL:    PostmanURL{Raw: tmpl(req.URL)},
+		Header: []PostmanKV{},
+	}
+
+	hkeys := make([]string, 0, len(req.Headers))
+	for k := range req.Headers {
+		hkeys = append(hkeys, k)
+	}
+	sort.Strings(hkeys)
+	for _, k := range hkeys {
+		pr.Header = append(pr.Header, PostmanKV{Key: k, Value: tmpl(req.Headers[k])})
+	}
+
+	switch req.Body.Type {
+	case domain.BodyJSON:
+		if req.Body.JSON != nil {
+			raw, err := json.MarshalIndent(req.Body.JSON, "", "  ")
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: json body could not be encoded: %v", where, err))
+				break
+			}
+			pr.Body = &PostmanBody{Mode: "raw", Raw: tmpl(string(raw)), Options: &PostmanBodyOptions{}}
+			pr.Body.Options.Raw.Language = "json"
+		}
+	case domain.BodyForm:
+		if req.Body.Form != nil {
+			fkeys := make([]string, 0, len(req.Body.Form))
+			for k := range req.Body.Form {
+				fkeys = append(fkeys, k)
+			}
+			sort.Strings(fkeys)
+			pr.Body = &PostmanBody{Mode: "urlencoded"}
+			for _, k := range fkeys {
+				pr.Body.URLEncoded = append(pr.Body.URLEncoded, PostmanKV{Key: k, Value: tmpl(req.Body.Form[k])})
+			}
+		}
+	case domain.BodyRaw:
+		if req.Body.Raw != "" {
+			pr.Body = &PostmanBody{Mode: "raw", Raw: tmpl(req.Body.Raw)}
+		}
+	}
+
+	item := PostmanItem{Name: req.Name, Request: pr}
+	if exec := statusTestScript(req.Assert); exec != nil {
+		item.Event = []PostmanEvent{{
+			Listen: "test",
+			Script: &PostmanScript{Type: "text/javascript", Exec: exec},
+		}}
+	}
+
+	if hasNonStatusAssertions(req.Assert) {
+		warnings = append(warnings, fmt.Sprintf("%s: only status assertions are exported; other assertions were dropped", where))
+	}
+	if len(req.Extract) > 0 || len(req.ExtractHeaders) > 0 || len(req.ExtractXPath) > 0 || len(req.ExtractCSS) > 0 ||
+		len(req.ExtractRegex) > 0 || len(req.ExtractCookies) > 0 || len(req.ExtractResponse) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%s: extract rules were not exported", where))
+	}
+
+	return item, warnings
+}
+
+// statusTestScript renders assert.status / assert.status_in as a pm.test
+// block. It returns nil when the request has no status assertion.
+func statusTestScript(a domain.AssertionsSpec) []string {
+	switch {
+	case a.Status != nil:
+		return []string{
+			fmt.Sprintf("pm.test(\"status is %d\", function () {", *a.Status),
+			fmt.Sprintf("    pm.response.to.have.status(%d);", *a.Status),
+			"});",
+		}
+	case len(a.StatusIn) > 0:
+		codes := make([]string, len(a.StatusIn))
+		for i, c := range a.StatusIn {
+			codes[i] = fmt.Sprintf("%d", c)
+		}
+		list := strings.Join(codes, ", ")
+		return []string{
+			fmt.Sprintf("pm.test(\"status is one of [%s]\", function () {", list),
+			fmt.Sprintf("    pm.expect(pm.response.code).to.be.oneOf([%s]);", list),
+			"});",
+		}
+	}
+	return nil
+}
+
+func hasNonStatusAssertions(a domain.AssertionsSpec) bool {
+	return a.MaxLatencyMS != nil || a.MaxMSRegression != nil || a.Body != nil || len(a.JSONPath) > 0 ||
+		len(a.Headers) > 0 || a.Schema != nil || a.SchemaInline != nil || a.Snapshot != nil ||
+		len(a.JSONEq) > 0 || len(a.Expr) > 0 || len(a.XPath) > 0 || len(a.CSS) > 0
+}
+
+// builtinToPostman maps Lynix builtins to their Postman dynamic variable.
+// Builtins missing here have no equivalent and are reported as warnings.
+var builtinToPostman = map[string]string{
+	"$uuid":         "$guid",
+	"$timestamp":    "$timestamp",
+	"$isoTimestamp": "$isoTimestamp",
+	"$randomInt":    "$randomInt",
+	"$randomEmail":  "$randomEmail",
+	"$randomBool":   "$randomBoolean",
+}
+
+// exportTemplate rewrites {{$builtin}} placeholders into Postman's dynamic
+// variables and drops the type of {{name:type}} ones, since Postman variables
+// are untyped. Regular {{vars}} already use Postman syntax and pass through.
+func exportTemplate(s, where string) (string, []string) {
+	if !strings.Contains(s, "{{$") && !strings.Contains(s, ":") {
+		return s, nil
+	}
+
+	var (
+		b        strings.Builder
+		warnings []string
+	)
+	for {
+		start := strings.Index(s, "{{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(s[start+2:], "}}")
+		if end < 0 {
+			break
+		}
+		end += start + 2
+		b.WriteString(s[:start])
+
+		name, typ := domain.SplitPlaceholder(s[start+2 : end])
+		placeholder := s[start : end+2]
+		if typ != "" {
+			placeholder = "{{" + name + "}}"
+			warnings = append(warnings, fmt.Sprintf("%s: {{%s:%s}} is typed; Postman substitutes it as text, exported as {{%s}}", where, name, typ, name))
+		}
+		switch {
+		case builtinToPostman[name] != "":
+			b.WriteString("{{" + builtinToPostman[name] + "}}")
+		case strings.HasPrefix(name, "$env."):
+			env := strings.TrimPrefix(name, "$env.")
+			warnings = append(warnings, fmt.Sprintf("%s: {{%s}} reads the process environment; exported as {{%s}}", where, name, env))
+			b.WriteString("{{" + env + "}}")
+		default:
+			if strings.HasPrefix(name, "$") {
+				warnings = append(warnings, fmt.Sprintf("%s: builtin {{%s}} has no Postman equivalent and was kept as-is", where, name))
+			}
+			b.WriteString(placeholder)
+		}
+		s = s[end+2:]
+	}
+	b.WriteString(s)
+	return b.String(), warnings
+}
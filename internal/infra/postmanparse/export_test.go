@@ -0,0 +1,171 @@
+package postmanparse
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestExport_FoldersFromFirstTag(t *testing.T) {
+	col := domain.Collection{
+		Name: "demo",
+		Requests: []domain.RequestSpec{
+			{Name: "health", Method: domain.MethodGet, URL: "{{base_url}}/health"},
+			{Name: "list-users", Method: domain.MethodGet, URL: "{{base_url}}/users", Tags: []string{"users", "smoke"}},
+			{Name: "login", Method: domain.MethodPost, URL: "{{base_url}}/login", Tags: []string{"auth"}},
+			{Name: "get-user", Method: domain.MethodGet, URL: "{{base_url}}/users/1", Tags: []string{"users"}},
+		},
+	}
+
+	pc := Export(col).Collection
+
+	if pc.Info.Schema != SchemaV21 {
+		t.Errorf("schema: got %q", pc.Info.Schema)
+	}
+	if len(pc.Item) != 3 {
+		t.Fatalf("top-level items: got %d, want 3", len(pc.Item))
+	}
+	if pc.Item[0].Name != "health" || pc.Item[0].Request == nil {
+		t.Errorf("untagged request should stay at top level, got %+v", pc.Item[0])
+	}
+	if pc.Item[1].Name != "users" || len(pc.Item[1].Item) != 2 {
+		t.Errorf("users folder: got %+v", pc.Item[1])
+	}
+	if pc.Item[2].Name != "auth" || len(pc.Item[2].Item) != 1 {
+		t.Errorf("auth folder: got %+v", pc.Item[2])
+	}
+}
+
+func TestExport_StatusBecomesPMTest(t *testing.T) {
+	col := domain.Collection{
+		Name: "demo",
+		Requests: []domain.RequestSpec{
+			{Name: "one", Method: domain.MethodGet, URL: "/a", Assert: domain.AssertionsSpec{Status: intPtr(201)}},
+			{Name: "many", Method: domain.MethodGet, URL: "/b", Assert: domain.AssertionsSpec{StatusIn: []int{200, 204}}},
+			{Name: "none", Method: domain.MethodGet, URL: "/c"},
+		},
+	}
+
+	pc := Export(col).Collection
+
+	script := strings.Join(pc.Item[0].Event[0].Script.Exec, "\n")
+	if pc.Item[0].Event[0].Listen != "test" || !strings.Contains(script, "pm.response.to.have.status(201)") {
+		t.Errorf("status test: got %q", script)
+	}
+	script = strings.Join(pc.Item[1].Event[0].Script.Exec, "\n")
+	if !strings.Contains(script, "oneOf([200, 204])") {
+		t.Errorf("status_in test: got %q", script)
+	}
+	if len(pc.Item[2].Event) != 0 {
+		t.Errorf("expected no events without status assertion, got %+v", pc.Item[2].Event)
+	}
+}
+
+func TestExport_BuiltinsAndWarnings(t *testing.T) {
+	col := domain.Collection{
+		Name: "demo",
+		Requests: []domain.RequestSpec{
+			{
+				Name:    "create",
+				Method:  domain.MethodPost,
+				URL:     "{{base_url}}/items?id={{$uuid}}",
+				Headers: domain.Headers{"X-Key": "{{$env.API_KEY}}"},
+				Assert: domain.AssertionsSpec{
+					Status:   intPtr(201),
+					JSONPath: map[string]domain.ValueAssertion{"$.id": {}},
+				},
+				Extract: domain.ExtractSpec{"id": "$.id"},
+			},
+		},
+	}
+
+	res := Export(col)
+	req := res.Collection.Item[0].Request
+
+	if req.URL.Raw != "{{base_url}}/items?id={{$guid}}" {
+		t.Errorf("url: got %q", req.URL.Raw)
+	}
+	if req.Header[0].Value != "{{API_KEY}}" {
+		t.Errorf("header: got %q", req.Header[0].Value)
+	}
+	joined := strings.Join(res.Warnings, "\n")
+	for _, want := range []string{"$env.API_KEY", "only status assertions", "extract rules"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected warning containing %q, got:\n%s", want, joined)
+		}
+	}
+}
+
+func TestExport_TypedPlaceholdersLoseTheirType(t *testing.T) {
+	col := domain.Collection{
+		Name: "typed",
+		Requests: []domain.RequestSpec{
+			{Name: "list", Method: domain.MethodGet, URL: "https://api.example.com/items?limit={{limit:int}}&q={{ q }}&port={{svc:port}}"},
+		},
+	}
+
+	res := Export(col)
+	// {{svc:port}} names a variable, not a type, and is kept as written.
+	if got := res.Collection.Item[0].Request.URL.Raw; got != "https://api.example.com/items?limit={{limit}}&q={{ q }}&port={{svc:port}}" {
+		t.Errorf("url: got %q", got)
+	}
+	if len(res.Warnings) != 1 || !strings.Contains(res.Warnings[0], "{{limit:int}} is typed") {
+		t.Errorf("expected a typed placeholder warning, got %v", res.Warnings)
+	}
+}
+
+func TestExport_RoundTripThroughParse(t *testing.T) {
+	col := domain.Collection{
+		Name: "Round Trip",
+		Vars: domain.Vars{"base_url": "https://api.example.com"},
+		Requests: []domain.RequestSpec{
+			{
+				Name:    "create",
+				Method:  domain.MethodPost,
+				URL:     "{{base_url}}/users",
+				Headers: domain.Headers{"Accept": "application/json"},
+				Body:    domain.BodySpec{Type: domain.BodyJSON, JSON: map[string]any{"name": "{{name}}"}},
+			},
+			{
+				Name:   "login",
+				Method: domain.MethodPost,
+				URL:    "{{base_url}}/login",
+				Body:   domain.BodySpec{Type: domain.BodyForm, Form: map[string]string{"user": "a"}},
+			},
+		},
+	}
+
+	b, err := json.Marshal(Export(col).Collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Parse(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("parse exported collection: %v", err)
+	}
+
+	got := res.Collection
+	if got.Name != "Round Trip" || got.Vars["base_url"] != "https://api.example.com" {
+		t.Errorf("collection: got name=%q vars=%v", got.Name, got.Vars)
+	}
+	if len(got.Requests) != 2 {
+		t.Fatalf("requests: got %d", len(got.Requests))
+	}
+	if got.Requests[0].Body.Type != domain.BodyJSON {
+		t.Errorf("json body type: got %q", got.Requests[0].Body.Type)
+	}
+	if m, ok := got.Requests[0].Body.JSON.(map[string]any); !ok || m["name"] != "{{name}}" {
+		t.Errorf("json body: got %#v", got.Requests[0].Body.JSON)
+	}
+	if got.Requests[1].Body.Type != domain.BodyForm || got.Requests[1].Body.Form["user"] != "a" {
+		t.Errorf("form body: got %+v", got.Requests[1].Body)
+	}
+	if got.Requests[0].Headers["Accept"] != "application/json" {
+		t.Errorf("headers: got %v", got.Requests[0].Headers)
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"reflect"
 	"strings"
 
 	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/importkit"
 )
 
 // Result holds the parsed collection and any warnings about unsupported features.
@@ -35,9 +37,9 @@ func Parse(r io.Reader) (Result, error) {
 		vars[v.Key] = v.Value
 	}
 
-	// Flatten items.
+	// Flatten items; folder variables are merged into vars.
 	var requests []domain.RequestSpec
-	requests, warnings = flattenItems(pc.Item, "", requests, warnings)
+	requests, warnings = flattenItems(pc.Item, "", pc.Auth, vars, requests, warnings)
 
 	col := domain.Collection{
 		SchemaVersion: 1,
@@ -52,7 +54,9 @@ func Parse(r io.Reader) (Result, error) {
 	return Result{Collection: col, Warnings: warnings}, nil
 }
 
-func flattenItems(items []PostmanItem, prefix string, reqs []domain.RequestSpec, warnings []string) ([]domain.RequestSpec, []string) {
+// flattenItems walks folders depth-first. auth is the auth inherited from the
+// enclosing folder (or collection); requests without their own auth use it.
+func flattenItems(items []PostmanItem, prefix string, auth *PostmanAuth, vars domain.Vars, reqs []domain.RequestSpec, warnings []string) ([]domain.RequestSpec, []string) {
 	for _, item := range items {
 		// Folder — recurse with prefix.
 		if item.Request == nil && len(item.Item) > 0 {
@@ -61,7 +65,25 @@ func flattenItems(items []PostmanItem, prefix string, reqs []domain.RequestSpec,
 				folderPrefix = prefix + "." + item.Name
 			}
 			warnings = append(warnings, fmt.Sprintf("folder %q was flattened", item.Name))
-			reqs, warnings = flattenItems(item.Item, folderPrefix, reqs, warnings)
+
+			// Lynix has a single variable scope per collection: folder
+			// variables are merged, and a clash keeps the outer value.
+			for _, v := range item.Variable {
+				if prev, ok := vars[v.Key]; ok && prev != v.Value {
+					warnings = append(warnings, fmt.Sprintf("folder %q: variable %q conflicts with an existing value and was skipped", item.Name, v.Key))
+					continue
+				}
+				vars[v.Key] = v.Value
+			}
+			for _, ev := range item.Event {
+				warnings = append(warnings, fmt.Sprintf("folder %q: %q script was ignored", item.Name, ev.Listen))
+			}
+
+			folderAuth := auth
+			if item.Auth != nil && item.Auth.Type != "inherit" {
+				folderAuth = item.Auth
+			}
+			reqs, warnings = flattenItems(item.Item, folderPrefix, folderAuth, vars, reqs, warnings)
 			continue
 		}
 
@@ -69,19 +91,14 @@ func flattenItems(items []PostmanItem, prefix string, reqs []domain.RequestSpec,
 			continue
 		}
 
-		// Item-level events.
-		for _, ev := range item.Event {
-			warnings = append(warnings, fmt.Sprintf("request %q: %q script was ignored", item.Name, ev.Listen))
-		}
-
-		req, ws := mapRequest(item, prefix)
+		req, ws := mapRequest(item, prefix, auth)
 		warnings = append(warnings, ws...)
 		reqs = append(reqs, req)
 	}
 	return reqs, warnings
 }
 
-func mapRequest(item PostmanItem, prefix string) (domain.RequestSpec, []string) {
+func mapRequest(item PostmanItem, prefix string, inheritedAuth *PostmanAuth) (domain.RequestSpec, []string) {
 	var warnings []string
 	pr := item.Request
 
@@ -89,9 +106,7 @@ func mapRequest(item PostmanItem, prefix string) (domain.RequestSpec, []string)
 	if prefix != "" {
 		name = prefix + "." + item.Name
 	}
-	// Sanitize name for YAML friendliness.
-	name = strings.ReplaceAll(name, " ", "-")
-	name = strings.ToLower(name)
+	name = importkit.Slugify(name)
 
 	// Method.
 	method := strings.ToUpper(pr.Method)
@@ -108,9 +123,15 @@ func mapRequest(item PostmanItem, prefix string) (domain.RequestSpec, []string)
 		headers[h.Key] = h.Value
 	}
 
-	// Auth — warn.
-	if pr.Auth != nil {
-		warnings = append(warnings, fmt.Sprintf("request %q: auth type %q was ignored", item.Name, pr.Auth.Type))
+	// Auth — the request's own, else inherited from folder/collection.
+	auth := inheritedAuth
+	if pr.Auth != nil && pr.Auth.Type != "inherit" {
+		auth = pr.Auth
+	}
+	if auth != nil {
+		var ws []string
+		rawURL, ws = applyAuth(item.Name, auth, headers, rawURL)
+		warnings = append(warnings, ws...)
 	}
 
 	// Body.
@@ -166,5 +187,63 @@ func mapRequest(item PostmanItem, prefix string) (domain.RequestSpec, []string)
 		Body:    body,
 	}
 
+	// Item-level events: test scripts are translated where they follow the
+	// common idioms; everything else is reported.
+	for _, ev := range item.Event {
+		if ev.Listen != "test" {
+			warnings = append(warnings, fmt.Sprintf("request %q: %q script was ignored", item.Name, ev.Listen))
+			continue
+		}
+		if ev.Script == nil {
+			continue
+		}
+		sr := translateTestScript(ev.Script.Exec)
+		warnings = append(warnings, mergeScriptResult(&req, item.Name, sr)...)
+		for _, stmt := range sr.Unconverted {
+			warnings = append(warnings, fmt.Sprintf("request %q: test script statement not converted: %s", item.Name, stmt))
+		}
+	}
+
 	return req, warnings
 }
+
+// mergeScriptResult adds what one test script of the item itemName
+// recovered to req, so several test events add up. On a conflict the first
+// script wins and a warning names what was dropped.
+func mergeScriptResult(req *domain.RequestSpec, itemName string, sr scriptResult) []string {
+	var warnings []string
+	if sr.Status != nil {
+		switch {
+		case req.Assert.Status == nil:
+			req.Assert.Status = sr.Status
+		case *req.Assert.Status != *sr.Status:
+			warnings = append(warnings, fmt.Sprintf("request %q: test scripts expect status %d and %d; kept %d",
+				itemName, *req.Assert.Status, *sr.Status, *req.Assert.Status))
+		}
+	}
+	for path, a := range sr.JSONPath {
+		prev, ok := req.Assert.JSONPath[path]
+		switch {
+		case !ok:
+			if req.Assert.JSONPath == nil {
+				req.Assert.JSONPath = map[string]domain.ValueAssertion{}
+			}
+			req.Assert.JSONPath[path] = a
+		case !reflect.DeepEqual(prev, a):
+			warnings = append(warnings, fmt.Sprintf("request %q: test scripts assert %s differently; kept the first", itemName, path))
+		}
+	}
+	for name, expr := range sr.Extract {
+		prev, ok := req.Extract[name]
+		switch {
+		case !ok:
+			if req.Extract == nil {
+				req.Extract = domain.ExtractSpec{}
+			}
+			req.Extract[name] = expr
+		case prev != expr:
+			warnings = append(warnings, fmt.Sprintf("request %q: test scripts set %q from %s and %s; kept %s", itemName, name, prev, expr, prev))
+		}
+	}
+	return warnings
+}
@@ -1016,7 +1016,7 @@ func TestParse_Scripts_Warning(t *testing.T) {
 				},
 				"event": [
 					{"listen": "prerequest"},
-					{"listen": "test"}
+					{"listen": "test", "script": {"exec": ["if (pm.response.code > 299) { throw new Error(); }"]}}
 				]
 			}
 		]
@@ -1542,3 +1542,378 @@ func TestParse_CombinedWarnings(t *testing.T) {
 		t.Errorf("expected at least 5 warnings, got %d: %v", len(r.Warnings), r.Warnings)
 	}
 }
+
+// ========================
+// Parse — auth
+// ========================
+
+func TestParse_BearerAuth_VarToken(t *testing.T) {
+	input := `{
+		"info": {"name": "Auth", "schema": ""},
+		"item": [
+			{"name": "R", "request": {"method": "GET", "url": "https://e.com/",
+				"auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{access_token}}", "type": "string"}]}}}
+		]
+	}`
+	r, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Collection.Requests[0].Headers["Authorization"]; got != "Bearer {{access_token}}" {
+		t.Errorf("Authorization: got %q", got)
+	}
+	if len(r.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", r.Warnings)
+	}
+}
+
+func TestParse_BearerAuth_LiteralTokenNotImported(t *testing.T) {
+	input := `{
+		"info": {"name": "Auth", "schema": ""},
+		"item": [
+			{"name": "R", "request": {"method": "GET", "url": "https://e.com/",
+				"auth": {"type": "bearer", "bearer": [{"key": "token", "value": "eyJhbGciOi.secret"}]}}}
+		]
+	}`
+	r, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Collection.Requests[0].Headers["Authorization"]; got != "Bearer {{token}}" {
+		t.Errorf("Authorization: got %q", got)
+	}
+	for _, w := range r.Warnings {
+		if strings.Contains(w, "eyJhbGciOi") {
+			t.Errorf("warning leaks the literal token: %q", w)
+		}
+	}
+	if len(r.Warnings) != 1 {
+		t.Errorf("expected 1 warning, got %v", r.Warnings)
+	}
+}
+
+func TestParse_BasicAuth(t *testing.T) {
+	input := `{
+		"info": {"name": "Auth", "schema": ""},
+		"item": [
+			{"name": "R", "request": {"method": "GET", "url": "https://e.com/",
+				"auth": {"type": "basic", "basic": [{"key": "username", "value": "{{user}}"}, {"key": "password", "value": "{{pass}}"}]}}}
+		]
+	}`
+	r, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Collection.Requests[0].Headers["Authorization"]; got != "Basic {{basic_auth}}" {
+		t.Errorf("Authorization: got %q", got)
+	}
+	if len(r.Warnings) != 1 || !strings.Contains(r.Warnings[0], "basic_auth") {
+		t.Errorf("expected basic_auth warning, got %v", r.Warnings)
+	}
+}
+
+func TestParse_APIKeyAuth_HeaderAndQuery(t *testing.T) {
+	input := `{
+		"info": {"name": "Auth", "schema": ""},
+		"item": [
+			{"name": "H", "request": {"method": "GET", "url": "https://e.com/",
+				"auth": {"type": "apikey", "apikey": [{"key": "key", "value": "X-API-Key"}, {"key": "value", "value": "{{api_key}}"}]}}},
+			{"name": "Q", "request": {"method": "GET", "url": "https://e.com/items?page=1",
+				"auth": {"type": "apikey", "apikey": [{"key": "key", "value": "api_key"}, {"key": "value", "value": "{{key}}"}, {"key": "in", "value": "query"}]}}}
+		]
+	}`
+	r, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Collection.Requests[0].Headers["X-API-Key"]; got != "{{api_key}}" {
+		t.Errorf("header: got %q", got)
+	}
+	if got := r.Collection.Requests[1].URL; got != "https://e.com/items?page=1&api_key={{key}}" {
+		t.Errorf("query: got %q", got)
+	}
+}
+
+func TestParse_Auth_InheritedFromCollectionAndFolder(t *testing.T) {
+	input := `{
+		"info": {"name": "Auth", "schema": ""},
+		"auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{col_token}}"}]},
+		"item": [
+			{"name": "Top", "request": {"method": "GET", "url": "https://e.com/a"}},
+			{
+				"name": "Admin",
+				"auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{admin_token}}"}]},
+				"item": [
+					{"name": "Inherits", "request": {"method": "GET", "url": "https://e.com/b"}},
+					{"name": "Public", "request": {"method": "GET", "url": "https://e.com/c", "auth": {"type": "noauth"}}}
+				]
+			}
+		]
+	}`
+	r, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqs := r.Collection.Requests
+	if got := reqs[0].Headers["Authorization"]; got != "Bearer {{col_token}}" {
+		t.Errorf("collection auth: got %q", got)
+	}
+	if got := reqs[1].Headers["Authorization"]; got != "Bearer {{admin_token}}" {
+		t.Errorf("folder auth: got %q", got)
+	}
+	if _, ok := reqs[2].Headers["Authorization"]; ok {
+		t.Errorf("noauth should not set Authorization, got %v", reqs[2].Headers)
+	}
+}
+
+func TestParse_Auth_ExplicitHeaderWins(t *testing.T) {
+	input := `{
+		"info": {"name": "Auth", "schema": ""},
+		"item": [
+			{"name": "R", "request": {"method": "GET", "url": "https://e.com/",
+				"header": [{"key": "authorization", "value": "Token {{t}}"}],
+				"auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{x}}"}]}}}
+		]
+	}`
+	r, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := r.Collection.Requests[0].Headers
+	if len(h) != 1 || h["authorization"] != "Token {{t}}" {
+		t.Errorf("headers: got %v", h)
+	}
+}
+
+// ========================
+// Parse — folder variables
+// ========================
+
+func TestParse_FolderVariables_Merged(t *testing.T) {
+	input := `{
+		"info": {"name": "Vars", "schema": ""},
+		"variable": [{"key": "base_url", "value": "https://e.com"}],
+		"item": [
+			{
+				"name": "Users",
+				"variable": [{"key": "page_size", "value": "20"}, {"key": "base_url", "value": "https://other.com"}],
+				"item": [{"name": "List", "request": {"method": "GET", "url": "{{base_url}}/users"}}]
+			}
+		]
+	}`
+	r, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Collection.Vars["page_size"] != "20" {
+		t.Errorf("folder var: got %v", r.Collection.Vars)
+	}
+	if r.Collection.Vars["base_url"] != "https://e.com" {
+		t.Errorf("collection var should win, got %q", r.Collection.Vars["base_url"])
+	}
+	found := false
+	for _, w := range r.Warnings {
+		if strings.Contains(w, "base_url") && strings.Contains(w, "conflicts") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected conflict warning, got %v", r.Warnings)
+	}
+}
+
+// ========================
+// Parse — test scripts
+// ========================
+
+func TestParse_TestScript_StatusEqAndSet(t *testing.T) {
+	input := `{
+		"info": {"name": "Scripts", "schema": ""},
+		"item": [
+			{
+				"name": "Login",
+				"request": {"method": "POST", "url": "https://e.com/login"},
+				"event": [{"listen": "test", "script": {"type": "text/javascript", "exec": [
+					"pm.test(\"Status code is 200\", function () {",
+					"    pm.response.to.have.status(200);",
+					"});",
+					"var jsonData = pm.response.json();",
+					"pm.test(\"user\", function () {",
+					"    pm.expect(jsonData.user.name).to.eql(\"alice\");",
+					"    pm.expect(jsonData.items[0].id).to.equal(42);",
+					"    pm.expect(pm.response.json().active).to.eql(true);",
+					"});",
+					"pm.environment.set(\"token\", jsonData.token);",
+					"pm.collectionVariables.set('user_id', jsonData.user.id);"
+				]}}]
+			}
+		]
+	}`
+	r, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Warnings) != 0 {
+		t.Errorf("expected every statement to convert, got %v", r.Warnings)
+	}
+
+	req := r.Collection.Requests[0]
+	if req.Assert.Status == nil || *req.Assert.Status != 200 {
+		t.Errorf("status: got %v", req.Assert.Status)
+	}
+	for path, want := range map[string]string{
+		"$.user.name":   "alice",
+		"$.items[0].id": "42",
+		"$.active":      "true",
+	} {
+		got := req.Assert.JSONPath[path].Eq
+		if got == nil || *got != want {
+			t.Errorf("jsonpath %s: got %v, want %q", path, got, want)
+		}
+	}
+	if req.Extract["token"] != "$.token" || req.Extract["user_id"] != "$.user.id" {
+		t.Errorf("extract: got %v", req.Extract)
+	}
+}
+
+func TestParse_TestScript_SeveralTestEventsMerge(t *testing.T) {
+	input := `{
+		"info": {"name": "Scripts", "schema": ""},
+		"item": [
+			{
+				"name": "Login",
+				"request": {"method": "POST", "url": "https://e.com/login"},
+				"event": [
+					{"listen": "test", "script": {"exec": [
+						"pm.response.to.have.status(200);",
+						"pm.expect(pm.response.json().user.name).to.eql(\"alice\");",
+						"pm.environment.set(\"token\", pm.response.json().token);"
+					]}},
+					{"listen": "test", "script": {"exec": [
+						"pm.response.to.have.status(201);",
+						"pm.expect(pm.response.json().active).to.eql(true);",
+						"pm.environment.set(\"token\", pm.response.json().auth.token);",
+						"pm.environment.set(\"user_id\", pm.response.json().user.id);"
+					]}}
+				]
+			}
+		]
+	}`
+	r, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := r.Collection.Requests[0]
+	if req.Assert.Status == nil || *req.Assert.Status != 200 {
+		t.Errorf("status: got %v, want the first script's 200", req.Assert.Status)
+	}
+	if len(req.Assert.JSONPath) != 2 || req.Assert.JSONPath["$.user.name"].Eq == nil || req.Assert.JSONPath["$.active"].Eq == nil {
+		t.Errorf("expected the jsonpath assertions of both scripts, got %v", req.Assert.JSONPath)
+	}
+	if req.Extract["token"] != "$.token" || req.Extract["user_id"] != "$.user.id" {
+		t.Errorf("extract: got %v", req.Extract)
+	}
+
+	joined := strings.Join(r.Warnings, "\n")
+	for _, want := range []string{"expect status 200 and 201; kept 200", `set "token" from $.token and $.auth.token`} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected warning containing %q, got:\n%s", want, joined)
+		}
+	}
+}
+
+func TestParse_TestScript_ExecAsStringAndOneLiner(t *testing.T) {
+	input := `{
+		"info": {"name": "Scripts", "schema": ""},
+		"item": [
+			{
+				"name": "R",
+				"request": {"method": "GET", "url": "https://e.com/"},
+				"event": [{"listen": "test", "script": {"exec": "pm.test(\"ok\", () => { pm.expect(pm.response.code).to.eql(201); });\nconsole.log(pm.response.text());"}}]
+			}
+		]
+	}`
+	r, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := r.Collection.Requests[0]
+	if req.Assert.Status == nil || *req.Assert.Status != 201 {
+		t.Errorf("status: got %v", req.Assert.Status)
+	}
+	if len(r.Warnings) != 1 || !strings.Contains(r.Warnings[0], "console.log") {
+		t.Errorf("expected one warning for the unconverted statement, got %v", r.Warnings)
+	}
+}
+
+func TestParse_TestScript_UnknownAliasNotConverted(t *testing.T) {
+	input := `{
+		"info": {"name": "Scripts", "schema": ""},
+		"item": [
+			{
+				"name": "R",
+				"request": {"method": "GET", "url": "https://e.com/"},
+				"event": [{"listen": "test", "script": {"exec": [
+					"pm.expect(other.id).to.eql(1);",
+					"pm.expect(pm.response.json().tags).to.eql([\"a\"]);"
+				]}}]
+			}
+		]
+	}`
+	r, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Collection.Requests[0].Assert.JSONPath) != 0 {
+		t.Errorf("expected no jsonpath assertions, got %v", r.Collection.Requests[0].Assert.JSONPath)
+	}
+	if len(r.Warnings) != 2 {
+		t.Errorf("expected 2 warnings, got %v", r.Warnings)
+	}
+}
+
+func TestParse_TestScript_RoundTripThroughWriter(t *testing.T) {
+	input := `{
+		"info": {"name": "Scripts RT", "schema": ""},
+		"item": [
+			{
+				"name": "Get",
+				"request": {"method": "GET", "url": "https://e.com/users/1"},
+				"event": [{"listen": "test", "script": {"exec": [
+					"pm.response.to.have.status(200);",
+					"pm.expect(pm.response.json().name).to.eql(\"alice\");",
+					"pm.environment.set(\"uid\", pm.response.json().id);"
+				]}}]
+			}
+		]
+	}`
+	r, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := yamlcollection.MarshalCollection(r.Collection)
+	if err != nil {
+		t.Fatalf("MarshalCollection: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "scripts.yaml")
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := yamlcollection.NewLoader().LoadCollection(path)
+	if err != nil {
+		t.Fatalf("LoadCollection: %v\nYAML:\n%s", err, string(b))
+	}
+
+	req := loaded.Requests[0]
+	if req.Assert.Status == nil || *req.Assert.Status != 200 {
+		t.Errorf("status: got %v", req.Assert.Status)
+	}
+	if got := req.Assert.JSONPath["$.name"].Eq; got == nil || *got != "alice" {
+		t.Errorf("jsonpath eq: got %v\nYAML:\n%s", got, string(b))
+	}
+	if req.Extract["uid"] != "$.id" {
+		t.Errorf("extract: got %v", req.Extract)
+	}
+}
@@ -0,0 +1,184 @@
+package postmanparse
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// scriptResult is what could be recovered from a Postman test script.
+// Statements that match none of the known idioms are kept in unconverted so
+// the caller can warn about them one by one.
+type scriptResult struct {
+	Status      *int
+	JSONPath    map[string]domain.ValueAssertion
+	Extract     domain.ExtractSpec
+	Unconverted []string
+}
+
+const (
+	jsIdent    = `[A-Za-z_$][\w$]*`
+	jsAccessor = `((?:\.` + jsIdent + `|\[(?:\d+|"[^"]*"|'[^']*')\])+)`
+	// jsonSource is either a direct pm.response.json() call or an alias
+	// assigned from it (var jsonData = pm.response.json()).
+	jsonSource = `(pm\.response\.json\(\)|` + jsIdent + `)`
+	testFunc   = `(?:function\s*\(\s*\)|\(\s*\)\s*=>)\s*\{`
+)
+
+var (
+	reStatus       = regexp.MustCompile(`^pm\.response\.to\.have\.status\(\s*(\d{3})\s*\)$`)
+	reStatusExpect = regexp.MustCompile(`^pm\.expect\(\s*pm\.response\.code\s*\)\.to\.(?:eql|equal|eq)\(\s*(\d{3})\s*\)$`)
+	reJSONAlias    = regexp.MustCompile(`^(?:var|let|const)\s+(` + jsIdent + `)\s*=\s*(?:pm\.response\.json\(\)|JSON\.parse\(\s*responseBody\s*\))$`)
+	reExpectEq     = regexp.MustCompile(`^pm\.expect\(\s*` + jsonSource + jsAccessor + `\s*\)\.to\.(?:eql|equal|eq|be\.equal)\((.+)\)$`)
+	reSetVar       = regexp.MustCompile(`^pm\.(?:environment|collectionVariables|globals|variables)\.set\(\s*["']([^"']+)["']\s*,\s*` + jsonSource + jsAccessor + `\s*\)$`)
+	reTestOneLine  = regexp.MustCompile(`^pm\.test\(.*?,\s*` + testFunc + `(.*)\}\s*\)$`)
+	reTestOpen     = regexp.MustCompile(`^pm\.test\(.*,\s*` + testFunc + `$`)
+	reBlockClose   = regexp.MustCompile(`^\}\s*\)?$`)
+)
+
+// translateTestScript pattern-matches the most common pm.test idioms into
+// assertions and extractions. It is deliberately line-oriented: anything
+// beyond simple statements (conditionals, loops, helpers) is reported as
+// unconverted rather than guessed at.
+func translateTestScript(lines []string) scriptResult {
+	var res scriptResult
+	aliases := map[string]bool{}
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if m := reTestOneLine.FindStringSubmatch(strings.TrimSuffix(line, ";")); m != nil {
+			line = m[1]
+		}
+		for _, stmt := range splitStatements(line) {
+			if !res.apply(stmt, aliases) {
+				res.Unconverted = append(res.Unconverted, stmt)
+			}
+		}
+	}
+	return res
+}
+
+// apply records a single statement and reports whether it was understood.
+func (r *scriptResult) apply(stmt string, aliases map[string]bool) bool {
+	if stmt == "" || strings.HasPrefix(stmt, "//") || reTestOpen.MatchString(stmt) || reBlockClose.MatchString(stmt) {
+		return true
+	}
+
+	if m := reJSONAlias.FindStringSubmatch(stmt); m != nil {
+		aliases[m[1]] = true
+		return true
+	}
+
+	for _, re := range []*regexp.Regexp{reStatus, reStatusExpect} {
+		if m := re.FindStringSubmatch(stmt); m != nil {
+			code, _ := strconv.Atoi(m[1])
+			if r.Status != nil && *r.Status != code {
+				return false
+			}
+			r.Status = &code
+			return true
+		}
+	}
+
+	if m := reExpectEq.FindStringSubmatch(stmt); m != nil {
+		if !isJSONSource(m[1], aliases) {
+			return false
+		}
+		want, ok := scriptLiteral(m[3])
+		if !ok {
+			return false
+		}
+		path := "$" + m[2]
+		if prev, exists := r.JSONPath[path]; exists && (prev.Eq == nil || *prev.Eq != want) {
+			return false
+		}
+		if r.JSONPath == nil {
+			r.JSONPath = map[string]domain.ValueAssertion{}
+		}
+		r.JSONPath[path] = domain.ValueAssertion{Eq: &want}
+		return true
+	}
+
+	if m := reSetVar.FindStringSubmatch(stmt); m != nil {
+		if !isJSONSource(m[2], aliases) {
+			return false
+		}
+		if r.Extract == nil {
+			r.Extract = domain.ExtractSpec{}
+		}
+		r.Extract[m[1]] = "$" + m[3]
+		return true
+	}
+
+	return false
+}
+
+func isJSONSource(src string, aliases map[string]bool) bool {
+	return src == "pm.response.json()" || aliases[src]
+}
+
+// scriptLiteral converts a JavaScript literal into the string form used by
+// eq assertions. Only strings, numbers and booleans are accepted; objects,
+// arrays, null and expressions are left for the user to port by hand.
+func scriptLiteral(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		inner := s[1 : len(s)-1]
+		if strings.ContainsAny(inner, `'\`) {
+			return "", false
+		}
+		return inner, true
+	}
+
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return "", false
+	}
+	switch t := v.(type) {
+	case string:
+		return t, true
+	case json.Number:
+		return t.String(), true
+	case bool:
+		return strconv.FormatBool(t), true
+	}
+	return "", false
+}
+
+// splitStatements splits a line on semicolons that are not inside quotes.
+func splitStatements(line string) []string {
+	var (
+		out   []string
+		cur   strings.Builder
+		quote byte
+	)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && i+1 < len(line) {
+				cur.WriteByte(c)
+				i++
+				c = line[i]
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == ';':
+			out = append(out, strings.TrimSpace(cur.String()))
+			cur.Reset()
+			continue
+		}
+		cur.WriteByte(c)
+	}
+	if rest := strings.TrimSpace(cur.String()); rest != "" || len(out) == 0 {
+		out = append(out, rest)
+	}
+	return out
+}
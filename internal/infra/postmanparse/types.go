@@ -1,13 +1,17 @@
 package postmanparse
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"strings"
+)
 
 // PostmanCollection represents a Postman Collection v2.1 export.
 type PostmanCollection struct {
 	Info     PostmanInfo    `json:"info"`
 	Item     []PostmanItem  `json:"item"`
-	Variable []PostmanKV    `json:"variable"`
-	Event    []PostmanEvent `json:"event"`
+	Variable []PostmanKV    `json:"variable,omitempty"`
+	Event    []PostmanEvent `json:"event,omitempty"`
+	Auth     *PostmanAuth   `json:"auth,omitempty"`
 }
 
 // PostmanInfo holds collection metadata.
@@ -19,9 +23,13 @@ type PostmanInfo struct {
 // PostmanItem is a request or folder in the collection.
 type PostmanItem struct {
 	Name    string          `json:"name"`
-	Request *PostmanRequest `json:"request"`
-	Item    []PostmanItem   `json:"item"` // nested folders
-	Event   []PostmanEvent  `json:"event"`
+	Request *PostmanRequest `json:"request,omitempty"`
+	Item    []PostmanItem   `json:"item,omitempty"` // nested folders
+	Event   []PostmanEvent  `json:"event,omitempty"`
+
+	// Folder-level settings; requests inside inherit them.
+	Variable []PostmanKV  `json:"variable,omitempty"`
+	Auth     *PostmanAuth `json:"auth,omitempty"`
 }
 
 // PostmanRequest describes an HTTP request.
@@ -29,14 +37,14 @@ type PostmanRequest struct {
 	Method string       `json:"method"`
 	URL    PostmanURL   `json:"url"`
 	Header []PostmanKV  `json:"header"`
-	Body   *PostmanBody `json:"body"`
-	Auth   *PostmanAuth `json:"auth"`
+	Body   *PostmanBody `json:"body,omitempty"`
+	Auth   *PostmanAuth `json:"auth,omitempty"`
 }
 
 // PostmanURL can be a string or an object in Postman exports.
 type PostmanURL struct {
 	Raw   string      `json:"raw"`
-	Query []PostmanKV `json:"query"`
+	Query []PostmanKV `json:"query,omitempty"`
 }
 
 // UnmarshalJSON handles the case where url is a plain string.
@@ -67,10 +75,10 @@ type PostmanKV struct {
 // PostmanBody describes the request body.
 type PostmanBody struct {
 	Mode       string              `json:"mode"`
-	Raw        string              `json:"raw"`
-	Options    *PostmanBodyOptions `json:"options"`
-	URLEncoded []PostmanKV         `json:"urlencoded"`
-	FormData   []PostmanKV         `json:"formdata"`
+	Raw        string              `json:"raw,omitempty"`
+	Options    *PostmanBodyOptions `json:"options,omitempty"`
+	URLEncoded []PostmanKV         `json:"urlencoded,omitempty"`
+	FormData   []PostmanKV         `json:"formdata,omitempty"`
 }
 
 // PostmanBodyOptions holds body mode options (e.g., raw language).
@@ -80,12 +88,47 @@ type PostmanBodyOptions struct {
 	} `json:"raw"`
 }
 
-// PostmanAuth describes authentication configuration.
+// PostmanAuth describes authentication configuration. Only the parameter
+// lists of the auth types Lynix can translate into headers are decoded.
 type PostmanAuth struct {
-	Type string `json:"type"`
+	Type   string      `json:"type"`
+	Bearer []PostmanKV `json:"bearer,omitempty"`
+	Basic  []PostmanKV `json:"basic,omitempty"`
+	APIKey []PostmanKV `json:"apikey,omitempty"`
 }
 
 // PostmanEvent represents pre-request or test scripts.
 type PostmanEvent struct {
-	Listen string `json:"listen"`
+	Listen string         `json:"listen"`
+	Script *PostmanScript `json:"script,omitempty"`
+}
+
+// PostmanScript holds the source of an event script, one line per entry.
+type PostmanScript struct {
+	Type string   `json:"type,omitempty"`
+	Exec []string `json:"exec"`
+}
+
+// UnmarshalJSON handles exec given as a single string instead of a list of
+// lines, which older exports and hand-written collections use.
+func (s *PostmanScript) UnmarshalJSON(data []byte) error {
+	var obj struct {
+		Type string          `json:"type"`
+		Exec json.RawMessage `json:"exec"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	s.Type = obj.Type
+	s.Exec = nil
+	if len(obj.Exec) == 0 || string(obj.Exec) == "null" {
+		return nil
+	}
+
+	var one string
+	if err := json.Unmarshal(obj.Exec, &one); err == nil {
+		s.Exec = strings.Split(one, "\n")
+		return nil
+	}
+	return json.Unmarshal(obj.Exec, &s.Exec)
 }
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/aalvaropc/lynix/internal/domain"
@@ -16,7 +17,8 @@ import (
 // ErrSecretDetected is returned when an unmasked secret is found in an artifact.
 var ErrSecretDetected = errors.New("detected unmasked secret in artifact")
 
-const maskValue = "********"
+// MaskValue replaces every redacted value (or the secret part of one).
+const MaskValue = domain.RedactedValue
 
 // builtinHeaderPatterns are always masked (case-insensitive substring match on header key).
 var builtinHeaderPatterns = []string{
@@ -114,10 +116,10 @@ func (r *Redactor) scrubText(s string) string {
 		return s
 	}
 	for _, v := range r.secretValues {
-		s = strings.ReplaceAll(s, v, maskValue)
+		s = strings.ReplaceAll(s, v, MaskValue)
 	}
 	for _, p := range secretValuePatterns {
-		s = p.ReplaceAllString(s, maskValue)
+		s = p.ReplaceAllString(s, MaskValue)
 	}
 	return s
 }
@@ -143,72 +145,110 @@ func (r *Redactor) Redact(run domain.RunArtifact) domain.RunArtifact {
 
 	out := run
 	out.Results = make([]domain.RequestResult, 0, len(run.Results))
-
 	for _, rr := range run.Results {
-		c := rr
-
-		// URL surfaces. URL and ResolvedURL carry the same resolved value in
-		// practice, so both must be masked (URL used to leak query secrets).
-		if r.cfg.MaskQueryParams {
-			c.URL = r.maskQueryParams(c.URL)
-			c.ResolvedURL = r.maskQueryParams(c.ResolvedURL)
-		}
-		c.URL = r.scrubText(c.URL)
-		c.ResolvedURL = r.scrubText(c.ResolvedURL)
-
-		// Request headers: key-based masking per config, value scrub always.
-		c.RequestHeaders = r.maskStringMap(rr.RequestHeaders, r.cfg.MaskRequestHeaders, r.isHeaderSensitive)
-
-		// Response headers.
-		c.Response = cloneResponseSnapshot(rr.Response)
-		for k, vals := range c.Response.Headers {
-			for i := range vals {
-				if r.cfg.MaskResponseHeaders && r.isHeaderSensitive(k) {
-					vals[i] = maskValue
-				} else {
-					vals[i] = r.scrubText(vals[i])
-				}
+		out.Results = append(out.Results, r.RedactResult(rr))
+	}
+	return out
+}
+
+// RedactResult is Redact for a single request result, used when results are
+// streamed before the run finishes.
+func (r *Redactor) RedactResult(rr domain.RequestResult) domain.RequestResult {
+	if !r.cfg.Enabled {
+		return rr
+	}
+
+	c := rr
+
+	// URL surfaces. URL and ResolvedURL carry the same resolved value in
+	// practice, so both must be masked (URL used to leak query secrets).
+	if r.cfg.MaskQueryParams {
+		c.URL = r.maskQueryParams(c.URL)
+		c.ResolvedURL = r.maskQueryParams(c.ResolvedURL)
+	}
+	c.URL = r.scrubText(c.URL)
+	c.ResolvedURL = r.scrubText(c.ResolvedURL)
+
+	// Request headers: key-based masking per config, value scrub always.
+	c.RequestHeaders = r.maskStringMap(rr.RequestHeaders, r.cfg.MaskRequestHeaders, r.isHeaderSensitive)
+
+	// Response: the redirect target is a URL surface too, then headers.
+	c.Response = cloneResponseSnapshot(rr.Response)
+	if r.cfg.MaskQueryParams {
+		c.Response.FinalURL = r.maskQueryParams(c.Response.FinalURL)
+	}
+	c.Response.FinalURL = r.scrubText(c.Response.FinalURL)
+
+	for k, vals := range c.Response.Headers {
+		for i := range vals {
+			if r.cfg.MaskResponseHeaders && r.isHeaderSensitive(k) {
+				vals[i] = MaskValue
+			} else {
+				vals[i] = r.scrubText(vals[i])
 			}
 		}
+	}
 
-		// Bodies: key-based masking for JSON/form per config, value scrub always.
-		if r.cfg.MaskRequestBody {
-			c.RequestBody = r.maskBodyBytes(rr.RequestBody)
-		} else {
-			c.RequestBody = r.scrubBytes(rr.RequestBody)
-		}
-		if r.cfg.MaskResponseBody {
-			c.Response.Body = r.maskBodyBytes(c.Response.Body)
-		} else {
-			c.Response.Body = r.scrubBytes(c.Response.Body)
-		}
+	// Bodies: key-based masking for JSON/form per config, value scrub always.
+	if r.cfg.MaskRequestBody {
+		c.RequestBody = r.maskBodyBytes(rr.RequestBody)
+	} else {
+		c.RequestBody = r.scrubBytes(rr.RequestBody)
+	}
+	if r.cfg.MaskResponseBody {
+		c.Response.Body = r.maskBodyBytes(c.Response.Body)
+	} else {
+		c.Response.Body = r.scrubBytes(c.Response.Body)
+	}
 
-		// Extracted vars: key-based masking + value scrub.
-		c.Extracted = r.maskStringMap(rr.Extracted, true, r.isKeySensitive)
+	// Extracted vars: key-based masking + value scrub.
+	c.Extracted = r.maskStringMap(rr.Extracted, true, r.isKeySensitive)
 
-		// Assertion and extract messages embed observed response values
-		// (e.g. `expected "x", got "<token>"`), so they are scrubbed too.
-		c.Extracts = cloneExtractResults(rr.Extracts)
-		for i := range c.Extracts {
-			c.Extracts[i].Message = r.scrubText(c.Extracts[i].Message)
-		}
-		c.Assertions = cloneAssertionResults(rr.Assertions)
-		for i := range c.Assertions {
-			c.Assertions[i].Message = r.scrubText(c.Assertions[i].Message)
+	// Assertion and extract messages embed observed response values
+	// (e.g. `expected "x", got "<token>"`), so they are scrubbed too.
+	c.Extracts = cloneExtractResults(rr.Extracts)
+	for i := range c.Extracts {
+		c.Extracts[i].Message = r.scrubText(c.Extracts[i].Message)
+	}
+	c.Assertions = cloneAssertionResults(rr.Assertions)
+	for i := range c.Assertions {
+		c.Assertions[i].Message = r.scrubText(c.Assertions[i].Message)
+		for j := range c.Assertions[i].Diff {
+			c.Assertions[i].Diff[j] = r.redactDiffEntry(c.Assertions[i].Diff[j])
 		}
+	}
 
-		// Error messages wrap the full request URL (Go's *url.Error), which
-		// leaks query-string secrets into artifacts, stdout, and JUnit XML.
-		if rr.Error != nil {
-			e := *rr.Error
-			e.Message = r.scrubText(r.maskURLsInText(e.Message))
-			c.Error = &e
-		}
+	// Error messages wrap the full request URL (Go's *url.Error), which
+	// leaks query-string secrets into artifacts, stdout, and JUnit XML.
+	if rr.Error != nil {
+		e := *rr.Error
+		e.Message = r.scrubText(r.maskURLsInText(e.Message))
+		c.Error = &e
+	}
+
+	return c
+}
 
-		out.Results = append(out.Results, c)
+// RedactText scrubs free text, such as an error message, on its way to a
+// surface outside the run artifacts: query secrets in URLs it contains and
+// known secret values.
+func (r *Redactor) RedactText(s string) string {
+	if !r.cfg.Enabled {
+		return s
 	}
+	return r.scrubText(r.maskURLsInText(s))
+}
 
-	return out
+// RedactBody masks a response body the way artifacts store it, for copies
+// kept outside of runs (snapshots).
+func (r *Redactor) RedactBody(body []byte) []byte {
+	if !r.cfg.Enabled {
+		return body
+	}
+	if r.cfg.MaskResponseBody {
+		return r.maskBodyBytes(body)
+	}
+	return r.scrubBytes(body)
 }
 
 func (r *Redactor) isHeaderSensitive(key string) bool {
@@ -229,6 +269,12 @@ func (r *Redactor) isHeaderSensitive(key string) bool {
 	return false
 }
 
+// IsKeySensitive reports whether a variable or field name looks like it
+// holds a credential, using the built-in patterns and masking rules.
+func (r *Redactor) IsKeySensitive(key string) bool {
+	return r.isKeySensitive(key)
+}
+
 func (r *Redactor) isKeySensitive(key string) bool {
 	kk := strings.ToLower(strings.TrimSpace(key))
 	for _, p := range builtinKeyPatterns {
@@ -271,7 +317,7 @@ func (r *Redactor) maskStringMap(m map[string]string, keyMasking bool, isSensiti
 	out := make(map[string]string, len(m))
 	for k, v := range m {
 		if keyMasking && isSensitive(k) {
-			out[k] = maskValue
+			out[k] = MaskValue
 		} else {
 			out[k] = r.scrubText(v)
 		}
@@ -288,7 +334,7 @@ func (r *Redactor) maskQueryParams(rawURL string) string {
 	changed := false
 	for k, vals := range q {
 		if r.isQueryParamSensitive(k) {
-			q.Set(k, maskValue)
+			q.Set(k, MaskValue)
 			changed = true
 			continue
 		}
@@ -365,14 +411,14 @@ func (r *Redactor) maskFormBody(body []byte) ([]byte, bool) {
 			key = uk
 		}
 		if r.isKeySensitive(key) {
-			segs[i] = k + "=" + maskValue
+			segs[i] = k + "=" + MaskValue
 			changed = true
 			continue
 		}
 		nv := r.scrubText(v)
 		// The value may carry a known secret percent-encoded.
 		if uv, err := url.QueryUnescape(v); err == nil && r.scrubText(uv) != uv {
-			nv = maskValue
+			nv = MaskValue
 		}
 		if nv != v {
 			segs[i] = k + "=" + nv
@@ -393,7 +439,7 @@ func (r *Redactor) walkAndMask(v any) any {
 	case map[string]any:
 		for k, val := range t {
 			if r.isKeySensitive(k) {
-				t[k] = maskValue
+				t[k] = MaskValue
 			} else {
 				t[k] = r.walkAndMask(val)
 			}
@@ -436,7 +482,7 @@ func (r *Redactor) CheckForSecrets(run domain.RunArtifact) error {
 	for _, rr := range run.Results {
 		// Request headers
 		for k, v := range rr.RequestHeaders {
-			if r.isHeaderSensitive(k) && v != maskValue {
+			if r.isHeaderSensitive(k) && v != MaskValue {
 				return fmt.Errorf("%w: request header %q in request %q", ErrSecretDetected, k, rr.Name)
 			}
 		}
@@ -445,7 +491,7 @@ func (r *Redactor) CheckForSecrets(run domain.RunArtifact) error {
 		for k, vals := range rr.Response.Headers {
 			if r.isHeaderSensitive(k) {
 				for _, v := range vals {
-					if v != maskValue {
+					if v != MaskValue {
 						return fmt.Errorf("%w: response header %q in request %q", ErrSecretDetected, k, rr.Name)
 					}
 				}
@@ -473,10 +519,15 @@ func (r *Redactor) CheckForSecrets(run domain.RunArtifact) error {
 				return err
 			}
 		}
+		if rr.Response.FinalURL != "" {
+			if err := r.checkQuerySecrets(rr.Response.FinalURL, rr.Name); err != nil {
+				return err
+			}
+		}
 
 		// Extracted vars
 		for k, v := range rr.Extracted {
-			if r.isKeySensitive(k) && v != maskValue {
+			if r.isKeySensitive(k) && v != MaskValue {
 				return fmt.Errorf("%w: extracted var %q in request %q", ErrSecretDetected, k, rr.Name)
 			}
 		}
@@ -524,7 +575,7 @@ func (r *Redactor) findUnmaskedKey(v any) string {
 	case map[string]any:
 		for k, val := range t {
 			if r.isKeySensitive(k) {
-				if s, ok := val.(string); !ok || s != maskValue {
+				if s, ok := val.(string); !ok || s != MaskValue {
 					return k
 				}
 			} else {
@@ -551,7 +602,7 @@ func (r *Redactor) checkQuerySecrets(rawURL, reqName string) error {
 	for k, vals := range u.Query() {
 		if r.isQueryParamSensitive(k) {
 			for _, v := range vals {
-				if v != maskValue {
+				if v != MaskValue {
 					return fmt.Errorf("%w: query param %q in request %q", ErrSecretDetected, k, reqName)
 				}
 			}
@@ -560,6 +611,39 @@ func (r *Redactor) checkQuerySecrets(rawURL, reqName string) error {
 	return nil
 }
 
+// redactDiffEntry masks the values of a snapshot diff entry whose field is
+// sensitive and scrubs known secrets from the rest.
+func (r *Redactor) redactDiffEntry(d domain.DiffEntry) domain.DiffEntry {
+	if r.cfg.MaskResponseBody && d.Path != "" && r.isKeySensitive(lastPathKey(d.Path)) {
+		if d.Old != "" {
+			d.Old = strconv.Quote(MaskValue)
+		}
+		if d.New != "" {
+			d.New = strconv.Quote(MaskValue)
+		}
+		return d
+	}
+	d.Old = r.scrubText(d.Old)
+	d.New = r.scrubText(d.New)
+	return d
+}
+
+// lastPathKey returns the last object key of a JSONPath such as
+// $.user.token or $["api key"][0] ("" when it ends in an index only).
+func lastPathKey(path string) string {
+	for strings.HasSuffix(path, "]") {
+		i := strings.LastIndex(path, "[")
+		if i < 0 {
+			return ""
+		}
+		if key, err := strconv.Unquote(path[i+1 : len(path)-1]); err == nil {
+			return key
+		}
+		path = path[:i]
+	}
+	return path[strings.LastIndex(path, ".")+1:]
+}
+
 // --- deep copy helpers ---
 
 func cloneExtractResults(in []domain.ExtractResult) []domain.ExtractResult {
@@ -577,12 +661,18 @@ func cloneAssertionResults(in []domain.AssertionResult) []domain.AssertionResult
 	}
 	out := make([]domain.AssertionResult, len(in))
 	copy(out, in)
+	for i := range out {
+		if in[i].Diff != nil {
+			out[i].Diff = append([]domain.DiffEntry(nil), in[i].Diff...)
+		}
+	}
 	return out
 }
 
 func cloneResponseSnapshot(in domain.ResponseSnapshot) domain.ResponseSnapshot {
 	out := domain.ResponseSnapshot{
 		Truncated: in.Truncated,
+		FinalURL:  in.FinalURL,
 	}
 	if in.Headers != nil {
 		out.Headers = make(map[string][]string, len(in.Headers))
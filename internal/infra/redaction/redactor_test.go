@@ -58,10 +58,10 @@ func TestRedact_RequestHeaders(t *testing.T) {
 	out := r.Redact(run)
 	h := out.Results[0].RequestHeaders
 
-	if h["Authorization"] != maskValue {
+	if h["Authorization"] != MaskValue {
 		t.Errorf("Authorization should be masked, got %q", h["Authorization"])
 	}
-	if h["X-API-Key"] != maskValue {
+	if h["X-API-Key"] != MaskValue {
 		t.Errorf("X-API-Key should be masked, got %q", h["X-API-Key"])
 	}
 	if h["Content-Type"] != "application/json" {
@@ -106,7 +106,7 @@ func TestRedact_ResponseHeaders(t *testing.T) {
 	out := r.Redact(run)
 	rh := out.Results[0].Response.Headers
 
-	if rh["Set-Cookie"][0] != maskValue {
+	if rh["Set-Cookie"][0] != MaskValue {
 		t.Errorf("Set-Cookie should be masked, got %q", rh["Set-Cookie"][0])
 	}
 	if rh["Content-Type"][0] != "application/json" {
@@ -130,10 +130,10 @@ func TestRedact_ExtractedVars(t *testing.T) {
 	out := r.Redact(run)
 	ev := out.Results[0].Extracted
 
-	if ev["auth_token"] != maskValue {
+	if ev["auth_token"] != MaskValue {
 		t.Errorf("auth_token should be masked, got %q", ev["auth_token"])
 	}
-	if ev["password"] != maskValue {
+	if ev["password"] != MaskValue {
 		t.Errorf("password should be masked, got %q", ev["password"])
 	}
 	if ev["user_id"] != "42" {
@@ -147,11 +147,17 @@ func TestRedact_QueryParams(t *testing.T) {
 	run := domain.RunArtifact{
 		Results: []domain.RequestResult{{
 			ResolvedURL: "https://api.example.com/v1?api_key=FAKE_KEY&page=1&token=FAKE_TOK",
+			Response: domain.ResponseSnapshot{
+				FinalURL: "https://api.example.com/v2?token=FAKE_TOK",
+			},
 		}},
 	}
 
 	out := r.Redact(run)
 	u := out.Results[0].ResolvedURL
+	if f := out.Results[0].Response.FinalURL; strings.Contains(f, "FAKE_TOK") {
+		t.Errorf("token value should be masked in the final URL: %s", f)
+	}
 
 	if strings.Contains(u, "FAKE_KEY") {
 		t.Errorf("api_key value should be masked in URL: %s", u)
@@ -198,7 +204,7 @@ func TestRedact_RequestBodyJSON(t *testing.T) {
 		t.Fatalf("masked body is not valid JSON: %v", err)
 	}
 
-	if doc["password"] != maskValue {
+	if doc["password"] != MaskValue {
 		t.Errorf("password should be masked, got %v", doc["password"])
 	}
 	if doc["username"] != "alice" {
@@ -206,7 +212,7 @@ func TestRedact_RequestBodyJSON(t *testing.T) {
 	}
 
 	nested := doc["data"].(map[string]any)
-	if nested["api_key"] != maskValue {
+	if nested["api_key"] != MaskValue {
 		t.Errorf("nested api_key should be masked, got %v", nested["api_key"])
 	}
 	if nested["value"] != float64(42) {
@@ -234,7 +240,7 @@ func TestRedact_ResponseBodyJSON(t *testing.T) {
 		t.Fatalf("masked response body is not valid JSON: %v", err)
 	}
 
-	if doc["access_token"] != maskValue {
+	if doc["access_token"] != MaskValue {
 		t.Errorf("access_token should be masked, got %v", doc["access_token"])
 	}
 	if doc["name"] != "test" {
@@ -284,16 +290,16 @@ func TestRedact_CustomRules(t *testing.T) {
 
 	out := r.Redact(run)
 
-	if out.Results[0].RequestHeaders["X-SSN"] != maskValue {
+	if out.Results[0].RequestHeaders["X-SSN"] != MaskValue {
 		t.Error("custom rule 'ssn' should mask X-SSN header")
 	}
-	if out.Results[0].RequestHeaders["X-Internal-Id"] != maskValue {
+	if out.Results[0].RequestHeaders["X-Internal-Id"] != MaskValue {
 		t.Error("custom rule 'internal_id' should mask X-Internal-Id header")
 	}
 	if out.Results[0].RequestHeaders["X-Safe"] != "ok" {
 		t.Error("X-Safe should not be masked")
 	}
-	if out.Results[0].Extracted["user_ssn"] != maskValue {
+	if out.Results[0].Extracted["user_ssn"] != MaskValue {
 		t.Error("custom rule 'ssn' (scope=all) should mask extracted var user_ssn")
 	}
 	if out.Results[0].Extracted["name"] != "alice" {
@@ -369,13 +375,13 @@ func TestCheckForSecrets_Clean_NoError(t *testing.T) {
 	run := domain.RunArtifact{
 		Results: []domain.RequestResult{{
 			Name:           "test",
-			RequestHeaders: map[string]string{"Authorization": maskValue, "Content-Type": "application/json"},
+			RequestHeaders: map[string]string{"Authorization": MaskValue, "Content-Type": "application/json"},
 			Response: domain.ResponseSnapshot{
-				Headers: map[string][]string{"Set-Cookie": {maskValue}},
-				Body:    []byte(`{"password":"` + maskValue + `","name":"alice"}`),
+				Headers: map[string][]string{"Set-Cookie": {MaskValue}},
+				Body:    []byte(`{"password":"` + MaskValue + `","name":"alice"}`),
 			},
-			ResolvedURL: "https://api.example.com?api_key=" + maskValue + "&page=1",
-			Extracted:   domain.Vars{"token": maskValue, "user_id": "42"},
+			ResolvedURL: "https://api.example.com?api_key=" + MaskValue + "&page=1",
+			Extracted:   domain.Vars{"token": MaskValue, "user_id": "42"},
 		}},
 	}
 
@@ -692,3 +698,80 @@ func TestAddSecretValues_IgnoresCorruptingValues(t *testing.T) {
 		t.Fatalf("mask placeholder must not trip the check: %v", err)
 	}
 }
+
+func TestIsKeySensitive_BuiltinsAndRules(t *testing.T) {
+	cfg := defaultMasking()
+	cfg.Rules = []domain.RedactionRule{{Pattern: "pin", Scope: domain.RedactionScopeAll}}
+	r := New(cfg)
+
+	for key, want := range map[string]bool{
+		"access_token":  true,
+		"DB_PASSWORD":   true,
+		"client_secret": true,
+		"card_pin":      true,
+		"base_url":      false,
+		"page_size":     false,
+	} {
+		if got := r.IsKeySensitive(key); got != want {
+			t.Errorf("IsKeySensitive(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestRedact_SnapshotDiff(t *testing.T) {
+	r := New(defaultMasking())
+	r.AddSecretValues("KNOWN_SECRET_VALUE")
+
+	in := []domain.DiffEntry{
+		{Path: "$.user.token", Kind: "changed", Old: `"a"`, New: `"b"`},
+		{Path: `$["x token"][0]`, Kind: "added", New: `"c"`},
+		{Path: "$.note", Kind: "changed", Old: `"x"`, New: `"KNOWN_SECRET_VALUE"`},
+		{Kind: "removed", Old: "sid=KNOWN_SECRET_VALUE"},
+	}
+	run := domain.RunArtifact{Results: []domain.RequestResult{{
+		Assertions: []domain.AssertionResult{{Name: "snapshot", Diff: in}},
+	}}}
+
+	got := r.Redact(run).Results[0].Assertions[0].Diff
+	masked := `"` + MaskValue + `"`
+	if got[0].Old != masked || got[0].New != masked || got[1].New != masked || got[1].Old != "" {
+		t.Errorf("sensitive keys not masked: %+v", got[:2])
+	}
+	for _, d := range got[2:] {
+		if strings.Contains(d.Old+d.New, "KNOWN_SECRET_VALUE") {
+			t.Errorf("known secret not scrubbed: %+v", d)
+		}
+	}
+	if in[0].Old != `"a"` {
+		t.Error("Redact mutated the input diff")
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	r := New(defaultMasking())
+	if got := string(r.RedactBody([]byte(`{"token":"abc","id":1}`))); got != `{"id":1,"token":"`+MaskValue+`"}` {
+		t.Errorf("RedactBody = %s", got)
+	}
+
+	cfg := defaultMasking()
+	cfg.Enabled = false
+	if got := string(New(cfg).RedactBody([]byte(`{"token":"abc"}`))); got != `{"token":"abc"}` {
+		t.Errorf("RedactBody with masking disabled = %s", got)
+	}
+}
+
+func TestRedactText_MasksURLQueryAndSecretValues(t *testing.T) {
+	r := New(defaultMasking())
+	r.AddSecretValues("FAKE_SECRET_VALUE")
+
+	got := r.RedactText("GET https://api.test/x?api_key=FAKE_KEY failed: token FAKE_SECRET_VALUE rejected")
+	if strings.Contains(got, "FAKE_KEY") || strings.Contains(got, "FAKE_SECRET_VALUE") {
+		t.Errorf("secrets leaked: %q", got)
+	}
+
+	cfg := defaultMasking()
+	cfg.Enabled = false
+	if got := New(cfg).RedactText("token FAKE_SECRET_VALUE"); got != "token FAKE_SECRET_VALUE" {
+		t.Errorf("should not scrub when disabled, got %q", got)
+	}
+}
@@ -0,0 +1,174 @@
+// Package runmeta describes where a run happened: the code under test (git
+// commit, branch, dirty state), the CI job that ran it and the host. The
+// result is stored in domain.RunResult.Metadata.
+package runmeta
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/aalvaropc/lynix/internal/buildinfo"
+)
+
+// Metadata keys set by Collect. User labels (--label) are stored next to
+// them under their own names.
+const (
+	KeyLynixVersion = "lynix.version"
+	KeyHost         = "host"
+	KeyGitCommit    = "git.commit"
+	KeyGitBranch    = "git.branch"
+	KeyGitDirty     = "git.dirty"
+	KeyCIProvider   = "ci.provider"
+	KeyCIPipeline   = "ci.pipeline"
+	KeyCIJob        = "ci.job"
+	KeyCIURL        = "ci.url"
+)
+
+// gitTimeout keeps a slow or hung git (huge repo, network filesystem) from
+// delaying the run.
+const gitTimeout = 2 * time.Second
+
+// GitFunc runs git with args in dir and returns its trimmed stdout.
+type GitFunc func(ctx context.Context, dir string, args ...string) (string, error)
+
+type Collector struct {
+	getenv   func(string) string
+	git      GitFunc
+	hostname func() (string, error)
+}
+
+type Option func(*Collector)
+
+// WithGetenv replaces os.Getenv (useful for tests).
+func WithGetenv(fn func(string) string) Option {
+	return func(c *Collector) { c.getenv = fn }
+}
+
+// WithGit replaces the git command (useful for tests).
+func WithGit(fn GitFunc) Option {
+	return func(c *Collector) { c.git = fn }
+}
+
+// WithHostname replaces os.Hostname (useful for tests).
+func WithHostname(fn func() (string, error)) Option {
+	return func(c *Collector) { c.hostname = fn }
+}
+
+func New(opts ...Option) *Collector {
+	c := &Collector{
+		getenv:   os.Getenv,
+		git:      execGit,
+		hostname: os.Hostname,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Collect gathers metadata for a run of the workspace at dir. Anything that
+// cannot be determined (no git, not in CI) is left out rather than failing.
+func (c *Collector) Collect(ctx context.Context, dir string) map[string]string {
+	out := map[string]string{KeyLynixVersion: buildinfo.Version}
+	if h, err := c.hostname(); err == nil && h != "" {
+		out[KeyHost] = h
+	}
+
+	ci := c.ciInfo()
+	for k, v := range ci.meta {
+		out[k] = v
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, gitTimeout)
+	defer cancel()
+	if sha, err := c.git(ctx, dir, "rev-parse", "HEAD"); err == nil && sha != "" {
+		out[KeyGitCommit] = sha
+		// CI checkouts are often a detached HEAD; the CI variables then
+		// know the branch better than git does.
+		if branch, err := c.git(ctx, dir, "rev-parse", "--abbrev-ref", "HEAD"); err == nil && branch != "" && branch != "HEAD" {
+			out[KeyGitBranch] = branch
+		}
+		if status, err := c.git(ctx, dir, "status", "--porcelain", "--untracked-files=no"); err == nil {
+			out[KeyGitDirty] = boolString(status != "")
+		}
+	}
+	if out[KeyGitCommit] == "" && ci.commit != "" {
+		out[KeyGitCommit] = ci.commit
+	}
+	if out[KeyGitBranch] == "" && ci.branch != "" {
+		out[KeyGitBranch] = ci.branch
+	}
+	return out
+}
+
+type ciInfo struct {
+	meta   map[string]string
+	commit string
+	branch string
+}
+
+// ciInfo reads the variables of the supported CI providers.
+func (c *Collector) ciInfo() ciInfo {
+	env := c.getenv
+	switch {
+	case env("GITHUB_ACTIONS") == "true":
+		info := ciInfo{meta: map[string]string{KeyCIProvider: "github-actions"}, commit: env("GITHUB_SHA")}
+		setIf(info.meta, KeyCIPipeline, env("GITHUB_RUN_ID"))
+		setIf(info.meta, KeyCIJob, env("GITHUB_JOB"))
+		if server, repo, id := env("GITHUB_SERVER_URL"), env("GITHUB_REPOSITORY"), env("GITHUB_RUN_ID"); server != "" && repo != "" && id != "" {
+			info.meta[KeyCIURL] = server + "/" + repo + "/actions/runs/" + id
+		}
+		// GITHUB_HEAD_REF is the PR source branch; GITHUB_REF_NAME is
+		// "123/merge" on pull requests.
+		info.branch = firstNonEmpty(env("GITHUB_HEAD_REF"), env("GITHUB_REF_NAME"))
+		return info
+	case env("GITLAB_CI") == "true":
+		info := ciInfo{meta: map[string]string{KeyCIProvider: "gitlab"}, commit: env("CI_COMMIT_SHA")}
+		setIf(info.meta, KeyCIPipeline, env("CI_PIPELINE_ID"))
+		setIf(info.meta, KeyCIJob, env("CI_JOB_NAME"))
+		setIf(info.meta, KeyCIURL, env("CI_JOB_URL"))
+		info.branch = firstNonEmpty(env("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME"), env("CI_COMMIT_REF_NAME"))
+		return info
+	case env("JENKINS_URL") != "":
+		info := ciInfo{meta: map[string]string{KeyCIProvider: "jenkins"}, commit: env("GIT_COMMIT")}
+		setIf(info.meta, KeyCIPipeline, env("BUILD_NUMBER"))
+		setIf(info.meta, KeyCIJob, env("JOB_NAME"))
+		setIf(info.meta, KeyCIURL, env("BUILD_URL"))
+		info.branch = strings.TrimPrefix(firstNonEmpty(env("BRANCH_NAME"), env("GIT_BRANCH")), "origin/")
+		return info
+	case env("CI") == "true":
+		return ciInfo{meta: map[string]string{KeyCIProvider: "unknown"}}
+	}
+	return ciInfo{}
+}
+
+func execGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+func setIf(m map[string]string, k, v string) {
+	if v != "" {
+		m[k] = v
+	}
+}
+
+func firstNonEmpty(vs ...string) string {
+	for _, v := range vs {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
@@ -0,0 +1,136 @@
+package runmeta
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/buildinfo"
+)
+
+func envMap(m map[string]string) func(string) string {
+	return func(k string) string { return m[k] }
+}
+
+// fakeGit answers the git commands Collect issues.
+func fakeGit(sha, branch, status string) GitFunc {
+	return func(_ context.Context, _ string, args ...string) (string, error) {
+		switch strings.Join(args, " ") {
+		case "rev-parse HEAD":
+			return sha, nil
+		case "rev-parse --abbrev-ref HEAD":
+			return branch, nil
+		case "status --porcelain --untracked-files=no":
+			return status, nil
+		}
+		return "", errors.New("unexpected git call")
+	}
+}
+
+func noGit(context.Context, string, ...string) (string, error) {
+	return "", errors.New("git: not found")
+}
+
+func host() (string, error) { return "runner-1", nil }
+
+func TestCollect_LocalCheckout(t *testing.T) {
+	c := New(
+		WithGetenv(envMap(nil)),
+		WithGit(fakeGit("abc123", "feature/x", " M api.yaml")),
+		WithHostname(host),
+	)
+	got := c.Collect(context.Background(), ".")
+
+	want := map[string]string{
+		KeyLynixVersion: buildinfo.Version,
+		KeyHost:         "runner-1",
+		KeyGitCommit:    "abc123",
+		KeyGitBranch:    "feature/x",
+		KeyGitDirty:     "true",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestCollect_GitHubActionsDetachedHead(t *testing.T) {
+	c := New(
+		WithGetenv(envMap(map[string]string{
+			"GITHUB_ACTIONS":    "true",
+			"GITHUB_RUN_ID":     "42",
+			"GITHUB_JOB":        "api-tests",
+			"GITHUB_SERVER_URL": "https://github.com",
+			"GITHUB_REPOSITORY": "acme/api",
+			"GITHUB_HEAD_REF":   "fix/login",
+			"GITHUB_REF_NAME":   "7/merge",
+			"GITHUB_SHA":        "fromenv",
+		})),
+		WithGit(fakeGit("abc123", "HEAD", "")),
+		WithHostname(host),
+	)
+	got := c.Collect(context.Background(), ".")
+
+	checks := map[string]string{
+		KeyCIProvider: "github-actions",
+		KeyCIPipeline: "42",
+		KeyCIJob:      "api-tests",
+		KeyCIURL:      "https://github.com/acme/api/actions/runs/42",
+		KeyGitCommit:  "abc123",
+		KeyGitBranch:  "fix/login",
+		KeyGitDirty:   "false",
+	}
+	for k, v := range checks {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestCollect_WithoutGitFallsBackToCIVariables(t *testing.T) {
+	c := New(
+		WithGetenv(envMap(map[string]string{
+			"GITLAB_CI":          "true",
+			"CI_PIPELINE_ID":     "900",
+			"CI_JOB_NAME":        "smoke",
+			"CI_JOB_URL":         "https://gitlab.example/jobs/1",
+			"CI_COMMIT_SHA":      "def456",
+			"CI_COMMIT_REF_NAME": "main",
+		})),
+		WithGit(noGit),
+		WithHostname(host),
+	)
+	got := c.Collect(context.Background(), ".")
+
+	if got[KeyCIProvider] != "gitlab" || got[KeyGitCommit] != "def456" || got[KeyGitBranch] != "main" {
+		t.Errorf("unexpected metadata: %v", got)
+	}
+	if _, ok := got[KeyGitDirty]; ok {
+		t.Error("dirty state is unknown without git")
+	}
+}
+
+func TestCollect_Jenkins(t *testing.T) {
+	c := New(
+		WithGetenv(envMap(map[string]string{
+			"JENKINS_URL":  "https://ci.example/",
+			"BUILD_NUMBER": "17",
+			"JOB_NAME":     "api/smoke",
+			"BUILD_URL":    "https://ci.example/job/api/17/",
+			"GIT_COMMIT":   "789abc",
+			"GIT_BRANCH":   "origin/release",
+		})),
+		WithGit(noGit),
+		WithHostname(host),
+	)
+	got := c.Collect(context.Background(), ".")
+
+	if got[KeyCIProvider] != "jenkins" || got[KeyCIPipeline] != "17" || got[KeyGitBranch] != "release" || got[KeyGitCommit] != "789abc" {
+		t.Errorf("unexpected metadata: %v", got)
+	}
+}
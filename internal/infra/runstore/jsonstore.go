@@ -9,7 +9,6 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -39,7 +38,7 @@ type JSONStore struct {
 	saveHeaders    bool
 	saveBody       bool
 	writeIndex     bool
-	maxRuns        int // 0 = unlimited
+	retention      domain.RetentionPolicy
 	redacter       Redacter
 	now            func() time.Time
 	log            *slog.Logger
@@ -80,7 +79,7 @@ func NewJSONStore(root string, cfg domain.Config, opts ...Option) *JSONStore {
 		failOnSecret:   cfg.Masking.FailOnDetectedSecret,
 		saveHeaders:    cfg.Artifacts.SaveResponseHeaders,
 		saveBody:       cfg.Artifacts.SaveResponseBody,
-		maxRuns:        cfg.Artifacts.MaxRuns,
+		retention:      cfg.Artifacts.Retention,
 		writeIndex:     false,
 		now:            time.Now,
 		log:            slog.New(slog.NewJSONHandler(io.Discard, nil)),
@@ -194,15 +193,17 @@ func (s *JSONStore) SaveRun(run domain.RunArtifact) (string, error) {
 		}
 	}
 
+	// Index the saved copy: it carries the defaulted StartedAt and is
+	// redacted like the artifact.
 	if s.writeIndex {
-		if err := s.appendIndex(dir, id, filename, run); err != nil {
+		if err := s.appendIndex(dir, id, filename, toSave); err != nil {
 			s.log.Error("runstore.appendIndex.failed", "err", err, "path", dir)
 		}
 	}
 
-	if s.maxRuns > 0 {
-		if err := s.rotate(dir); err != nil {
-			s.log.Error("runstore.rotate.failed", "err", err, "path", dir)
+	if !s.retention.IsZero() {
+		if _, err := s.Prune(s.retention, false); err != nil {
+			s.log.Error("runstore.prune.failed", "err", err, "path", dir)
 		}
 	}
 
@@ -257,20 +258,7 @@ func uniqueRunFilename(dir, base string) (string, error) {
 }
 
 func (s *JSONStore) appendIndex(dir, id, filename string, run domain.RunArtifact) error {
-	type idx struct {
-		ID         string    `json:"id"`
-		File       string    `json:"file"`
-		Collection string    `json:"collection"`
-		Env        string    `json:"env"`
-		StartedAt  time.Time `json:"started_at"`
-	}
-	line, err := json.Marshal(idx{
-		ID:         id,
-		File:       filename,
-		Collection: run.CollectionName,
-		Env:        run.EnvironmentName,
-		StartedAt:  run.StartedAt,
-	})
+	line, err := json.Marshal(newIndexEntry(filename, summarize(id, run)))
 	if err != nil {
 		return err
 	}
@@ -292,46 +280,6 @@ func (s *JSONStore) appendIndex(dir, id, filename string, run domain.RunArtifact
 // so rotation never deletes unrelated files a user drops into runs/.
 var runFilePattern = regexp.MustCompile(`^\d{8}T\d{6}Z_.+\.json$`)
 
-// rotate removes the oldest run artifacts when the count exceeds maxRuns.
-// Ordering uses the timestamp prefix plus the numeric collision suffix:
-// plain lexicographic order would delete "_10" before "_2".
-func (s *JSONStore) rotate(dir string) error {
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		return err
-	}
-
-	var jsonFiles []string
-	for _, e := range entries {
-		if !e.IsDir() && runFilePattern.MatchString(e.Name()) {
-			jsonFiles = append(jsonFiles, e.Name())
-		}
-	}
-
-	if len(jsonFiles) <= s.maxRuns {
-		return nil
-	}
-
-	sort.Slice(jsonFiles, func(i, j int) bool {
-		return runFileLess(jsonFiles[i], jsonFiles[j])
-	})
-
-	toDelete := jsonFiles[:len(jsonFiles)-s.maxRuns]
-	deleteSet := make(map[string]bool, len(toDelete))
-	for _, f := range toDelete {
-		deleteSet[f] = true
-		if err := os.Remove(filepath.Join(dir, f)); err != nil && !os.IsNotExist(err) {
-			s.log.Error("runstore.rotate.remove", "file", f, "err", err)
-		}
-	}
-
-	if s.writeIndex {
-		s.pruneIndex(dir, deleteSet)
-	}
-
-	return nil
-}
-
 // runFileLess orders artifacts chronologically: timestamp prefix first, then
 // the numeric collision suffix ("_2" ... "_999") as a number.
 func runFileLess(a, b string) bool {
@@ -396,7 +396,7 @@ func TestSaveRun_RotatesOldestWhenMaxRunsExceeded(t *testing.T) {
 	cfg := domain.DefaultConfig()
 	cfg.Paths.RunsDir = "runs"
 	cfg.Masking.Enabled = false
-	cfg.Artifacts.MaxRuns = 2
+	cfg.Artifacts.Retention.MaxRuns = 2
 
 	callNum := 0
 	store := NewJSONStore(tmp, cfg, WithIndex(true), WithNow(func() time.Time {
@@ -438,7 +438,7 @@ func TestSaveRun_RotatePrunesIndex(t *testing.T) {
 	cfg := domain.DefaultConfig()
 	cfg.Paths.RunsDir = "runs"
 	cfg.Masking.Enabled = false
-	cfg.Artifacts.MaxRuns = 1
+	cfg.Artifacts.Retention.MaxRuns = 1
 
 	callNum := 0
 	store := NewJSONStore(tmp, cfg, WithIndex(true), WithNow(func() time.Time {
@@ -540,6 +540,7 @@ func TestListRuns_And_LoadRun(t *testing.T) {
 	id1, err := store.SaveRun(domain.RunArtifact{
 		CollectionName: "Alpha",
 		StartedAt:      start,
+		Metadata:       map[string]string{"git.branch": "main"},
 		Results: []domain.RequestResult{
 			{Name: "ok", StatusCode: 200},
 			{Name: "bad", Assertions: []domain.AssertionResult{{Passed: false}}},
@@ -569,6 +570,9 @@ func TestListRuns_And_LoadRun(t *testing.T) {
 	if summaries[1].Passed != 1 || summaries[1].Failed != 1 {
 		t.Errorf("expected 1 passed / 1 failed for Alpha, got %+v", summaries[1])
 	}
+	if summaries[1].Metadata["git.branch"] != "main" {
+		t.Errorf("expected metadata in the summary, got %+v", summaries[1].Metadata)
+	}
 
 	run, err := store.LoadRun(id1)
 	if err != nil {
@@ -606,3 +610,238 @@ func TestLoadRun_NotFound(t *testing.T) {
 		t.Fatalf("expected KindNotFound, got: %v", err)
 	}
 }
+
+func TestLatestRun_MatchesCollectionAndEnv(t *testing.T) {
+	tmp := t.TempDir()
+	cfg := domain.DefaultConfig()
+	cfg.Masking.Enabled = false
+	store := NewJSONStore(tmp, cfg)
+
+	start := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)
+	save := func(col, env string, offset time.Duration) string {
+		t.Helper()
+		id, err := store.SaveRun(domain.RunArtifact{CollectionName: col, EnvironmentName: env, StartedAt: start.Add(offset)})
+		if err != nil {
+			t.Fatalf("SaveRun: %v", err)
+		}
+		return id
+	}
+	save("Alpha", "dev", 0)
+	want := save("Alpha", "dev", time.Hour)
+	save("Alpha", "prod", 2*time.Hour)
+	save("Beta", "dev", 3*time.Hour)
+
+	got, err := store.LatestRun("alpha", "DEV")
+	if err != nil {
+		t.Fatalf("LatestRun: %v", err)
+	}
+	if got != want {
+		t.Errorf("LatestRun = %q, want %q", got, want)
+	}
+
+	if _, err := store.LatestRun("Alpha", "staging"); !domain.IsKind(err, domain.KindNotFound) {
+		t.Fatalf("expected KindNotFound, got: %v", err)
+	}
+}
+
+func TestQuery_Filters(t *testing.T) {
+	tmp := t.TempDir()
+	cfg := domain.DefaultConfig()
+	cfg.Masking.Enabled = false
+	store := NewJSONStore(tmp, cfg, WithIndex(true))
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	failing := []domain.RequestResult{{Name: "r", Assertions: []domain.AssertionResult{{Passed: false}}}}
+	for i, run := range []domain.RunArtifact{
+		{CollectionName: "Alpha", EnvironmentName: "dev", StartedAt: start},
+		{CollectionName: "Alpha", EnvironmentName: "stg", StartedAt: start.Add(24 * time.Hour), Results: failing},
+		{CollectionName: "Beta", EnvironmentName: "dev", StartedAt: start.Add(48 * time.Hour), Metadata: map[string]string{"team": "payments"}},
+	} {
+		if _, err := store.SaveRun(run); err != nil {
+			t.Fatalf("SaveRun %d: %v", i, err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		q    RunQuery
+		want []string // collections, newest first
+	}{
+		{"all", RunQuery{}, []string{"Beta", "Alpha", "Alpha"}},
+		{"collection", RunQuery{Collection: "alpha"}, []string{"Alpha", "Alpha"}},
+		{"env", RunQuery{Env: "dev"}, []string{"Beta", "Alpha"}},
+		{"failed", RunQuery{Failed: true}, []string{"Alpha"}},
+		{"since", RunQuery{Since: start.Add(time.Hour)}, []string{"Beta", "Alpha"}},
+		{"metadata", RunQuery{Metadata: map[string]string{"team": "payments"}}, []string{"Beta"}},
+		{"limit", RunQuery{Limit: 1}, []string{"Beta"}},
+	}
+	for _, tt := range tests {
+		got, err := store.Query(tt.q)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var cols []string
+		for _, s := range got {
+			cols = append(cols, s.Collection)
+		}
+		if strings.Join(cols, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v, want %v", tt.name, cols, tt.want)
+		}
+	}
+}
+
+func TestQuery_UsesIndexAndFallsBackToArtifacts(t *testing.T) {
+	tmp := t.TempDir()
+	cfg := domain.DefaultConfig()
+	cfg.Masking.Enabled = false
+	store := NewJSONStore(tmp, cfg, WithIndex(true))
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	indexed, err := store.SaveRun(domain.RunArtifact{CollectionName: "Indexed", StartedAt: start})
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy, err := store.SaveRun(domain.RunArtifact{
+		CollectionName: "Legacy",
+		StartedAt:      start.Add(time.Hour),
+		Results:        []domain.RequestResult{{Name: "r", Error: &domain.RunError{Kind: domain.RunErrorTimeout}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The index wins for runs it fully describes; a line written before the
+	// counts existed is ignored in favor of the artifact.
+	dir := filepath.Join(tmp, "runs")
+	index := `{"id":"` + indexed + `","file":"` + indexed + `.json","collection":"From Index","env":"","started_at":"2026-03-01T12:00:00Z","passed":4,"failed":0,"errors":0}
+{"id":"` + legacy + `","file":"` + legacy + `.json","collection":"Stale","env":"","started_at":"2026-03-01T13:00:00Z"}
+`
+	if err := os.WriteFile(filepath.Join(dir, "index.jsonl"), []byte(index), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.ListRuns()
+	if err != nil {
+		t.Fatalf("ListRuns: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 runs, got %+v", got)
+	}
+	if got[0].Collection != "Legacy" || got[0].Errors != 1 {
+		t.Errorf("count-less index line should fall back to the artifact, got %+v", got[0])
+	}
+	if got[1].Collection != "From Index" || got[1].Passed != 4 {
+		t.Errorf("expected the summary from the index, got %+v", got[1])
+	}
+}
+
+func TestPrune_AppliesRetentionPolicy(t *testing.T) {
+	tmp := t.TempDir()
+	cfg := domain.DefaultConfig()
+	cfg.Masking.Enabled = false
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	store := NewJSONStore(tmp, cfg, WithIndex(true), WithNow(func() time.Time { return now }))
+
+	failing := []domain.RequestResult{{Name: "r", Assertions: []domain.AssertionResult{{Passed: false}}}}
+	ids := map[string]string{}
+	for _, r := range []struct {
+		key     string
+		col     string
+		daysAgo int
+		results []domain.RequestResult
+	}{
+		{"old-failed", "Alpha", 20, failing},
+		{"old-passed", "Alpha", 5, nil},
+		{"new-passed", "Alpha", 1, nil},
+		{"beta-1", "Beta", 4, nil},
+		{"beta-2", "Beta", 3, failing},
+		{"beta-3", "Beta", 2, failing},
+	} {
+		id, err := store.SaveRun(domain.RunArtifact{
+			CollectionName: r.col,
+			StartedAt:      now.Add(-time.Duration(r.daysAgo) * 24 * time.Hour),
+			Results:        r.results,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[r.key] = id
+	}
+
+	policy := domain.RetentionPolicy{
+		MaxAgeFailed:         30 * 24 * time.Hour,
+		MaxAgePassed:         3 * 24 * time.Hour,
+		MaxRunsPerCollection: 2,
+	}
+
+	planned, err := store.Prune(policy, true)
+	if err != nil {
+		t.Fatalf("Prune dry run: %v", err)
+	}
+	if all, _ := store.ListRuns(); len(all) != 6 {
+		t.Fatalf("a dry run must not delete anything, %d runs left", len(all))
+	}
+
+	pruned, err := store.Prune(policy, false)
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	var got []string
+	for _, s := range pruned {
+		got = append(got, s.ID)
+	}
+	// old-passed: passing and older than 3 days; old-failed: third Alpha
+	// run; beta-1: passing, older than 3 days and third Beta run.
+	want := []string{ids["beta-1"], ids["old-passed"], ids["old-failed"]}
+	if strings.Join(got, ",") != strings.Join(want, ",") || len(planned) != len(pruned) {
+		t.Fatalf("pruned %v, want %v (dry run planned %d)", got, want, len(planned))
+	}
+
+	left, _ := store.ListRuns()
+	if len(left) != 3 {
+		t.Fatalf("expected 3 runs left, got %+v", left)
+	}
+	b, _ := os.ReadFile(filepath.Join(tmp, "runs", "index.jsonl"))
+	if strings.Count(string(b), "\n") != 3 || strings.Contains(string(b), ids["old-failed"]) {
+		t.Errorf("index not pruned:\n%s", b)
+	}
+}
+
+func TestExpiredRuns_UnreadableArtifacts(t *testing.T) {
+	now := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	summaries := []RunSummary{
+		{ID: "20260330T000000Z_a", Collection: "(unreadable)"},
+		{ID: "20260301T000000Z_b", Collection: "(unreadable)"},
+	}
+
+	if got := expiredRuns(summaries, domain.RetentionPolicy{MaxAgePassed: time.Hour, MaxRunsPerCollection: 1}, now); len(got) != 0 {
+		t.Errorf("outcome and collection rules must not select unreadable runs, got %+v", got)
+	}
+	got := expiredRuns(summaries, domain.RetentionPolicy{MaxAge: 7 * 24 * time.Hour}, now)
+	if len(got) != 1 || got[0].ID != "20260301T000000Z_b" {
+		t.Errorf("expected the old unreadable run to age out by its file name, got %+v", got)
+	}
+}
+
+func TestRunQuery_MatchesMetadata(t *testing.T) {
+	summaries := []RunSummary{
+		{ID: "a", Metadata: map[string]string{"git.branch": "main", "team": "payments"}},
+		{ID: "b", Metadata: map[string]string{"git.branch": "main"}},
+		{ID: "c"},
+	}
+	q := RunQuery{Metadata: map[string]string{"git.branch": "main", "team": "payments"}}
+	var got []string
+	for _, s := range summaries {
+		if q.Matches(s) {
+			got = append(got, s.ID)
+		}
+	}
+	if len(got) != 1 || got[0] != "a" {
+		t.Errorf("expected only run a, got %v", got)
+	}
+	for _, s := range summaries {
+		if !(RunQuery{}).Matches(s) {
+			t.Errorf("no filter should keep run %s", s.ID)
+		}
+	}
+}
@@ -21,11 +21,56 @@ type RunSummary struct {
 	Passed     int       `json:"passed"`
 	Failed     int       `json:"failed"`
 	Errors     int       `json:"errors"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// RunQuery selects saved runs. Zero fields match everything.
+type RunQuery struct {
+	Collection string            // case-insensitive
+	Env        string            // case-insensitive
+	Failed     bool              // only runs with a failed or errored request
+	Since      time.Time         // only runs started at or after this time
+	Metadata   map[string]string // every key=value must match
+	Limit      int               // stop after this many matches (0 = all)
+}
+
+// Matches reports whether s satisfies every filter of q.
+func (q RunQuery) Matches(s RunSummary) bool {
+	if q.Collection != "" && !strings.EqualFold(s.Collection, q.Collection) {
+		return false
+	}
+	if q.Env != "" && !strings.EqualFold(s.Env, q.Env) {
+		return false
+	}
+	if q.Failed && !s.HasFailures() {
+		return false
+	}
+	if !q.Since.IsZero() && s.StartedAt.Before(q.Since) {
+		return false
+	}
+	for k, v := range q.Metadata {
+		if got, ok := s.Metadata[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// HasFailures reports whether any request of the run failed or errored.
+func (s RunSummary) HasFailures() bool {
+	return s.Failed > 0 || s.Errors > 0
 }
 
-// ListRuns scans the runs directory (newest first). The directory is the
-// source of truth — index.jsonl can be stale or missing.
+// ListRuns returns every saved run, newest first.
 func (s *JSONStore) ListRuns() ([]RunSummary, error) {
+	return s.Query(RunQuery{})
+}
+
+// Query returns the saved runs matching q, newest first. The directory is
+// the source of truth — index.jsonl can be stale or missing — but runs it
+// describes are summarized from it instead of reading each artifact.
+func (s *JSONStore) Query(q RunQuery) ([]RunSummary, error) {
 	dir := filepath.Join(s.rootDir, s.runsDirName)
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -48,35 +93,136 @@ func (s *JSONStore) ListRuns() ([]RunSummary, error) {
 	}
 	sort.Slice(names, func(i, j int) bool { return runFileLess(names[j], names[i]) }) // newest first
 
+	index := readIndex(dir)
 	out := make([]RunSummary, 0, len(names))
 	for _, name := range names {
-		run, err := s.loadRunFile(filepath.Join(dir, name))
-		if err != nil {
-			// A corrupt artifact should not hide the rest of the history.
-			out = append(out, RunSummary{ID: strings.TrimSuffix(name, ".json"), Collection: "(unreadable)"})
-			continue
+		summary, ok := index[name]
+		if !ok {
+			summary = s.summarizeFile(dir, name)
 		}
-		summary := RunSummary{
-			ID:         strings.TrimSuffix(name, ".json"),
-			Collection: run.CollectionName,
-			Env:        run.EnvironmentName,
-			StartedAt:  run.StartedAt,
-		}
-		for _, r := range run.Results {
-			switch {
-			case r.Error != nil:
-				summary.Errors++
-			case r.Failed():
-				summary.Failed++
-			default:
-				summary.Passed++
-			}
+		if !q.Matches(summary) {
+			continue
 		}
 		out = append(out, summary)
+		if q.Limit > 0 && len(out) == q.Limit {
+			break
+		}
 	}
 	return out, nil
 }
 
+func (s *JSONStore) summarizeFile(dir, name string) RunSummary {
+	id := strings.TrimSuffix(name, ".json")
+	run, err := s.loadRunFile(filepath.Join(dir, name))
+	if err != nil {
+		// A corrupt artifact should not hide the rest of the history.
+		return RunSummary{ID: id, Collection: "(unreadable)"}
+	}
+	return summarize(id, run)
+}
+
+func summarize(id string, run domain.RunArtifact) RunSummary {
+	summary := RunSummary{
+		ID:         id,
+		Collection: run.CollectionName,
+		Env:        run.EnvironmentName,
+		StartedAt:  run.StartedAt,
+		Metadata:   run.Metadata,
+	}
+	for _, r := range run.Results {
+		switch {
+		case r.Error != nil:
+			summary.Errors++
+		case r.Failed():
+			summary.Failed++
+		default:
+			summary.Passed++
+		}
+	}
+	return summary
+}
+
+// indexEntry is one line of index.jsonl. The counts are pointers so lines
+// written before they existed are told apart from runs with zero failures.
+type indexEntry struct {
+	ID         string            `json:"id"`
+	File       string            `json:"file"`
+	Collection string            `json:"collection"`
+	Env        string            `json:"env"`
+	StartedAt  time.Time         `json:"started_at"`
+	Passed     *int              `json:"passed"`
+	Failed     *int              `json:"failed"`
+	Errors     *int              `json:"errors"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+func newIndexEntry(file string, sum RunSummary) indexEntry {
+	return indexEntry{
+		ID:         sum.ID,
+		File:       file,
+		Collection: sum.Collection,
+		Env:        sum.Env,
+		StartedAt:  sum.StartedAt,
+		Passed:     &sum.Passed,
+		Failed:     &sum.Failed,
+		Errors:     &sum.Errors,
+		Metadata:   sum.Metadata,
+	}
+}
+
+// readIndex returns the complete index entries by file name. Malformed and
+// older count-less lines are skipped; their runs are read from disk.
+func readIndex(dir string) map[string]RunSummary {
+	b, err := os.ReadFile(filepath.Join(dir, "index.jsonl"))
+	if err != nil {
+		return nil
+	}
+	out := map[string]RunSummary{}
+	for _, line := range strings.Split(string(b), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var e indexEntry
+		if json.Unmarshal([]byte(line), &e) != nil || e.File == "" || e.Passed == nil || e.Failed == nil || e.Errors == nil {
+			continue
+		}
+		out[e.File] = RunSummary{
+			ID:         e.ID,
+			Collection: e.Collection,
+			Env:        e.Env,
+			StartedAt:  e.StartedAt,
+			Passed:     *e.Passed,
+			Failed:     *e.Failed,
+			Errors:     *e.Errors,
+			Metadata:   e.Metadata,
+		}
+	}
+	return out
+}
+
+// LatestRun returns the ID of the newest readable run of collection against
+// env (names compared case-insensitively; an empty env matches runs without
+// one). It returns a KindNotFound error when there is none.
+func (s *JSONStore) LatestRun(collection, env string) (string, error) {
+	summaries, err := s.ListRuns()
+	if err != nil {
+		return "", err
+	}
+	for _, sum := range summaries {
+		if sum.StartedAt.IsZero() {
+			continue // unreadable artifact
+		}
+		if strings.EqualFold(sum.Collection, collection) && strings.EqualFold(sum.Env, env) {
+			return sum.ID, nil
+		}
+	}
+	return "", &domain.OpError{
+		Op:   "runstore.latest",
+		Kind: domain.KindNotFound,
+		Err:  fmt.Errorf("%w: no saved run of %q against %q", domain.ErrNotFound, collection, env),
+	}
+}
+
 // LoadRun reads a stored artifact by its ID (the filename without .json).
 func (s *JSONStore) LoadRun(id string) (domain.RunArtifact, error) {
 	if strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
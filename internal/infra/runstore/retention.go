@@ -0,0 +1,93 @@
+package runstore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// Prune deletes the saved runs that policy selects and returns them, newest
+// first. With dryRun nothing is deleted. Only Lynix's own timestamp-prefixed
+// artifacts are ever considered, and index.jsonl is rewritten to match.
+func (s *JSONStore) Prune(policy domain.RetentionPolicy, dryRun bool) ([]RunSummary, error) {
+	summaries, err := s.Query(RunQuery{})
+	if err != nil {
+		return nil, err
+	}
+	expired := expiredRuns(summaries, policy, s.now())
+	if dryRun || len(expired) == 0 {
+		return expired, nil
+	}
+
+	dir := filepath.Join(s.rootDir, s.runsDirName)
+	deleted := make(map[string]bool, len(expired))
+	out := expired[:0:0]
+	var firstErr error
+	for _, sum := range expired {
+		file := sum.ID + ".json"
+		if err := os.Remove(filepath.Join(dir, file)); err != nil && !os.IsNotExist(err) {
+			s.log.Error("runstore.prune.remove", "file", file, "err", err)
+			if firstErr == nil {
+				firstErr = &domain.OpError{
+					Op:   "runstore.prune",
+					Kind: domain.KindExecution,
+					Path: filepath.Join(dir, file),
+					Err:  err,
+				}
+			}
+			continue
+		}
+		deleted[file] = true
+		out = append(out, sum)
+	}
+	s.pruneIndex(dir, deleted)
+	return out, firstErr
+}
+
+// expiredRuns applies policy to summaries (newest first). Unreadable
+// artifacts count towards MaxRuns and age out under MaxAge (dated by their
+// file name) but, with no known collection or outcome, are never selected by
+// the per-collection or outcome-specific rules.
+func expiredRuns(summaries []RunSummary, p domain.RetentionPolicy, now time.Time) []RunSummary {
+	var out []RunSummary
+	perCollection := map[string]int{}
+	for i, sum := range summaries {
+		readable := !sum.StartedAt.IsZero()
+		expired := p.MaxRuns > 0 && i >= p.MaxRuns
+
+		if readable && p.MaxRunsPerCollection > 0 {
+			key := strings.ToLower(sum.Collection)
+			perCollection[key]++
+			expired = expired || perCollection[key] > p.MaxRunsPerCollection
+		}
+
+		limit := p.MaxAge
+		started := sum.StartedAt
+		if readable {
+			limit = p.AgeLimit(sum.HasFailures())
+		} else {
+			started = idTime(sum.ID)
+		}
+		if limit > 0 && !started.IsZero() && now.Sub(started) > limit {
+			expired = true
+		}
+
+		if expired {
+			out = append(out, sum)
+		}
+	}
+	return out
+}
+
+// idTime reads the UTC timestamp prefix of a run ID.
+func idTime(id string) time.Time {
+	prefix, _, _ := strings.Cut(id, "_")
+	t, err := time.Parse("20060102T150405Z", prefix)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,154 @@
+// Package snapshotstore keeps snapshot assertion bodies as files in a
+// __snapshots__ directory next to each collection, meant to be committed.
+package snapshotstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/ports"
+)
+
+// DirName is the directory, next to the collection file, holding snapshots.
+const DirName = "__snapshots__"
+
+// Redacter masks a body before it is written (avoids an import cycle).
+type Redacter interface {
+	RedactBody(body []byte) []byte
+}
+
+// FSStore stores each snapshot at
+// <collection dir>/__snapshots__/<collection file stem>/<name>.json (or .txt
+// for bodies that are not JSON).
+type FSStore struct {
+	redacter Redacter
+}
+
+type Option func(*FSStore)
+
+// WithRedacter masks bodies before they are written: snapshots live in the
+// repository, so secrets in responses must not end up there.
+func WithRedacter(r Redacter) Option {
+	return func(s *FSStore) { s.redacter = r }
+}
+
+func New(opts ...Option) *FSStore {
+	s := &FSStore{}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+var _ ports.SnapshotStore = (*FSStore)(nil)
+
+// Path returns where a snapshot is stored, without its extension.
+func Path(collectionPath, name string) string {
+	dir := filepath.Dir(collectionPath)
+	stem := strings.TrimSuffix(filepath.Base(collectionPath), filepath.Ext(collectionPath))
+	return filepath.Join(dir, DirName, fileName(stem), fileName(name))
+}
+
+func (s *FSStore) LoadSnapshot(collectionPath, name string) ([]byte, bool, error) {
+	base := Path(collectionPath, name)
+	for _, ext := range []string{".json", ".txt"} {
+		b, err := os.ReadFile(base + ext)
+		if err == nil {
+			return b, true, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, false, &domain.OpError{
+				Op:   "snapshotstore.load",
+				Kind: domain.KindExecution,
+				Path: base + ext,
+				Err:  err,
+			}
+		}
+	}
+	return nil, false, nil
+}
+
+// SaveSnapshot writes body atomically. JSON bodies are indented, so
+// snapshots review well in diffs.
+func (s *FSStore) SaveSnapshot(collectionPath, name string, body []byte) error {
+	if s.redacter != nil {
+		body = s.redacter.RedactBody(body)
+	}
+	ext, stale := ".txt", ".json"
+	var indented bytes.Buffer
+	if json.Valid(body) {
+		ext, stale = stale, ext
+		if err := json.Indent(&indented, body, "", "  "); err == nil {
+			body = append(indented.Bytes(), '\n')
+		}
+	}
+
+	base := Path(collectionPath, name)
+	if err := writeAtomic(base+ext, body); err != nil {
+		return &domain.OpError{
+			Op:   "snapshotstore.save",
+			Kind: domain.KindExecution,
+			Path: base + ext,
+			Err:  err,
+		}
+	}
+	// A body that changed format leaves its previous file behind otherwise,
+	// and LoadSnapshot would keep finding the JSON one.
+	if err := os.Remove(base + stale); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return &domain.OpError{
+			Op:   "snapshotstore.save",
+			Kind: domain.KindExecution,
+			Path: base + stale,
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+func writeAtomic(path string, b []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// fileName keeps letters, digits, '.', '-' and '_' and replaces anything
+// else, so request names like "GET /users" make portable file names.
+func fileName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		case r == '.' && b.Len() > 0:
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package snapshotstore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type fakeRedacter struct{}
+
+func (fakeRedacter) RedactBody(b []byte) []byte {
+	return []byte(strings.ReplaceAll(string(b), "secret", "********"))
+}
+
+func TestFSStore_SaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	col := filepath.Join(dir, "collections", "users.yaml")
+	s := New(WithRedacter(fakeRedacter{}))
+
+	if _, found, err := s.LoadSnapshot(col, "list users"); err != nil || found {
+		t.Fatalf("empty store: found=%v err=%v", found, err)
+	}
+
+	if err := s.SaveSnapshot(col, "list users", []byte(`{"a":1,"token":"secret"}`)); err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(dir, "collections", "__snapshots__", "users", "list_users.json")
+	b, err := os.ReadFile(want)
+	if err != nil {
+		t.Fatalf("snapshot not at %s: %v", want, err)
+	}
+	if string(b) != "{\n  \"a\": 1,\n  \"token\": \"********\"\n}\n" {
+		t.Errorf("stored JSON = %q", b)
+	}
+
+	got, found, err := s.LoadSnapshot(col, "list users")
+	if err != nil || !found || string(got) != string(b) {
+		t.Fatalf("LoadSnapshot = %q, %v, %v", got, found, err)
+	}
+}
+
+func TestFSStore_FormatChangeReplacesFile(t *testing.T) {
+	col := filepath.Join(t.TempDir(), "c.yaml")
+	s := New()
+
+	_ = s.SaveSnapshot(col, "page", []byte(`{"a":1}`))
+	if err := s.SaveSnapshot(col, "page", []byte("<html>")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(Path(col, "page") + ".json"); !os.IsNotExist(err) {
+		t.Errorf("stale .json snapshot left behind: %v", err)
+	}
+	got, found, _ := s.LoadSnapshot(col, "page")
+	if !found || string(got) != "<html>" {
+		t.Errorf("LoadSnapshot = %q, %v", got, found)
+	}
+}
+
+func TestFileName(t *testing.T) {
+	cases := map[string]string{
+		"GET /users/{id}": "GET__users__id_",
+		"v1.2-list_all":   "v1.2-list_all",
+		"..":              "_.",
+		"":                "_",
+		"café":            "caf_",
+	}
+	for in, want := range cases {
+		if got := fileName(in); got != want {
+			t.Errorf("fileName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
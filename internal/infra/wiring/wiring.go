@@ -6,6 +6,7 @@ import (
 	"github.com/aalvaropc/lynix/internal/infra/httprunner"
 	"github.com/aalvaropc/lynix/internal/infra/redaction"
 	"github.com/aalvaropc/lynix/internal/infra/runstore"
+	"github.com/aalvaropc/lynix/internal/infra/snapshotstore"
 	"github.com/aalvaropc/lynix/internal/infra/yamlcollection"
 	"github.com/aalvaropc/lynix/internal/infra/yamlenv"
 	"github.com/aalvaropc/lynix/internal/ports"
@@ -19,6 +20,7 @@ type Adapters struct {
 	Envs        *yamlenv.Loader
 	Runner      ports.RequestRunner
 	Store       ports.ArtifactStore
+	Snapshots   ports.SnapshotStore
 	Redactor    *redaction.Redactor
 	Config      domain.Config
 }
@@ -84,6 +86,7 @@ func NewAdapters(root string, cfg domain.Config, opts Opts) (Adapters, error) {
 		Envs:        envLoader,
 		Runner:      runner,
 		Store:       store,
+		Snapshots:   snapshotstore.New(snapshotstore.WithRedacter(redactor)),
 		Redactor:    redactor,
 		Config:      cfg,
 	}, nil
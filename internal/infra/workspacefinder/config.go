@@ -110,7 +110,15 @@ func LoadConfig(root string) (domain.Config, error) {
 		cfg.Artifacts.SaveResponseBody = *y.Lynix.Artifacts.SaveResponseBody
 	}
 	if y.Lynix.Artifacts.MaxRuns != nil && *y.Lynix.Artifacts.MaxRuns > 0 {
-		cfg.Artifacts.MaxRuns = *y.Lynix.Artifacts.MaxRuns
+		cfg.Artifacts.Retention.MaxRuns = *y.Lynix.Artifacts.MaxRuns
+	}
+	if err := applyRetention(&cfg.Artifacts.Retention, y.Lynix.Artifacts.Retention); err != nil {
+		return cfg, &domain.OpError{
+			Op:   "workspacefinder.loadconfig",
+			Kind: domain.KindInvalidConfig,
+			Path: path,
+			Err:  fmt.Errorf("%w: artifacts.retention.%w", domain.ErrInvalidConfig, err),
+		}
 	}
 	if y.Lynix.Run.TimeoutSeconds > 0 {
 		cfg.Run.Timeout = time.Duration(y.Lynix.Run.TimeoutSeconds) * time.Second
@@ -139,6 +147,9 @@ func LoadConfig(root string) (domain.Config, error) {
 		}
 		cfg.Run.TLS.CAFile = caFile
 	}
+	cfg.Run.OTel.Endpoint = y.Lynix.Run.OTel.Endpoint
+	cfg.Run.OTel.Headers = y.Lynix.Run.OTel.Headers
+	cfg.Run.OTel.ServiceName = y.Lynix.Run.OTel.ServiceName
 
 	return cfg, nil
 }
@@ -174,9 +185,10 @@ type yamlConfig struct {
 		} `yaml:"paths"`
 
 		Artifacts struct {
-			SaveResponseHeaders *bool `yaml:"save_response_headers"`
-			SaveResponseBody    *bool `yaml:"save_response_body"`
-			MaxRuns             *int  `yaml:"max_runs"`
+			SaveResponseHeaders *bool         `yaml:"save_response_headers"`
+			SaveResponseBody    *bool         `yaml:"save_response_body"`
+			MaxRuns             *int          `yaml:"max_runs"`
+			Retention           yamlRetention `yaml:"retention"`
 		} `yaml:"artifacts"`
 
 		Run struct {
@@ -190,6 +202,43 @@ type yamlConfig struct {
 			TLS            struct {
 				CAFile string `yaml:"ca_file"`
 			} `yaml:"tls"`
+			OTel struct {
+				Endpoint    string            `yaml:"endpoint"`
+				Headers     map[string]string `yaml:"headers"`
+				ServiceName string            `yaml:"service_name"`
+			} `yaml:"otel"`
 		} `yaml:"run"`
 	} `yaml:"lynix"`
 }
+
+type yamlRetention struct {
+	MaxAge               string `yaml:"max_age"`
+	FailedMaxAge         string `yaml:"failed_max_age"`
+	PassedMaxAge         string `yaml:"passed_max_age"`
+	MaxRunsPerCollection *int   `yaml:"max_runs_per_collection"`
+}
+
+func applyRetention(p *domain.RetentionPolicy, y yamlRetention) error {
+	for _, f := range []struct {
+		key string
+		in  string
+		out *time.Duration
+	}{
+		{"max_age", y.MaxAge, &p.MaxAge},
+		{"failed_max_age", y.FailedMaxAge, &p.MaxAgeFailed},
+		{"passed_max_age", y.PassedMaxAge, &p.MaxAgePassed},
+	} {
+		if f.in == "" {
+			continue
+		}
+		d, err := domain.ParseAge(f.in)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.key, err)
+		}
+		*f.out = d
+	}
+	if y.MaxRunsPerCollection != nil && *y.MaxRunsPerCollection > 0 {
+		p.MaxRunsPerCollection = *y.MaxRunsPerCollection
+	}
+	return nil
+}
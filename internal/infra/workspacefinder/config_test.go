@@ -3,6 +3,7 @@ package workspacefinder
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -342,8 +343,8 @@ func TestLoadConfig_MaxRuns(t *testing.T) {
 		t.Fatalf("LoadConfig error: %v", err)
 	}
 
-	if cfg.Artifacts.MaxRuns != 50 {
-		t.Fatalf("expected max_runs=50, got=%d", cfg.Artifacts.MaxRuns)
+	if cfg.Artifacts.Retention.MaxRuns != 50 {
+		t.Fatalf("expected max_runs=50, got=%d", cfg.Artifacts.Retention.MaxRuns)
 	}
 }
 
@@ -367,8 +368,49 @@ func TestLoadConfig_MaxRunsDefault_Zero(t *testing.T) {
 		t.Fatalf("LoadConfig error: %v", err)
 	}
 
-	if cfg.Artifacts.MaxRuns != 0 {
-		t.Fatalf("expected max_runs=0 (unlimited) by default, got=%d", cfg.Artifacts.MaxRuns)
+	if cfg.Artifacts.Retention.MaxRuns != 0 {
+		t.Fatalf("expected max_runs=0 (unlimited) by default, got=%d", cfg.Artifacts.Retention.MaxRuns)
+	}
+}
+
+func TestLoadConfig_Retention(t *testing.T) {
+	root := t.TempDir()
+	content := []byte(`lynix:
+  artifacts:
+    max_runs: 200
+    retention:
+      max_age: 2w
+      failed_max_age: 30d
+      passed_max_age: 3d
+      max_runs_per_collection: 20
+`)
+	if err := os.WriteFile(filepath.Join(root, "lynix.yaml"), content, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cfg, err := LoadConfig(root)
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	day := 24 * time.Hour
+	want := domain.RetentionPolicy{
+		MaxRuns:              200,
+		MaxRunsPerCollection: 20,
+		MaxAge:               14 * day,
+		MaxAgeFailed:         30 * day,
+		MaxAgePassed:         3 * day,
+	}
+	if cfg.Artifacts.Retention != want {
+		t.Fatalf("retention = %+v, want %+v", cfg.Artifacts.Retention, want)
+	}
+
+	bad := []byte("lynix:\n  artifacts:\n    retention:\n      passed_max_age: soon\n")
+	if err := os.WriteFile(filepath.Join(root, "lynix.yaml"), bad, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = LoadConfig(root)
+	if !domain.IsKind(err, domain.KindInvalidConfig) || !strings.Contains(err.Error(), "passed_max_age") {
+		t.Fatalf("expected an invalid config error naming the field, got %v", err)
 	}
 }
 
@@ -387,3 +429,22 @@ func TestLoadConfig_UnknownFieldRejected(t *testing.T) {
 		t.Fatalf("expected KindInvalidConfig, got: %v", err)
 	}
 }
+
+func TestLoadConfig_OTel(t *testing.T) {
+	root := writeWorkspaceConfig(t, `lynix:
+  run:
+    otel:
+      endpoint: http://localhost:4318
+      service_name: api-tests
+      headers:
+        x-honeycomb-team: abc
+`)
+	cfg, err := LoadConfig(root)
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	otel := cfg.Run.OTel
+	if otel.Endpoint != "http://localhost:4318" || otel.ServiceName != "api-tests" || otel.Headers["x-honeycomb-team"] != "abc" {
+		t.Fatalf("unexpected otel config: %+v", otel)
+	}
+}
@@ -2,13 +2,17 @@ package yamlcollection
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/aalvaropc/lynix/internal/domain"
 	"github.com/aalvaropc/lynix/internal/ports"
@@ -52,15 +56,19 @@ func (l *Loader) LoadCollection(path string) (domain.Collection, error) {
 	dec.KnownFields(true)
 	var yc yamlCollection
 	if err := dec.Decode(&yc); err != nil && !errors.Is(err, io.EOF) {
-		return domain.Collection{}, &domain.OpError{
+		var out error = &domain.OpError{
 			Op:   "yamlcollection.load",
 			Kind: domain.KindInvalidConfig,
 			Path: path,
 			Err:  fmt.Errorf("%w: %w", domain.ErrInvalidConfig, err),
 		}
+		if pos := decodeErrorPos(err); !pos.IsZero() {
+			out = &domain.SourceError{Path: path, Pos: pos, Err: out}
+		}
+		return domain.Collection{}, out
 	}
 
-	col, err := mapAndValidate(path, yc)
+	col, err := mapAndValidate(path, yc, indexPositions(b))
 	if err != nil {
 		return domain.Collection{}, err
 	}
@@ -130,28 +138,52 @@ type yamlRequest struct {
 	URL     string            `yaml:"url"`
 	Headers map[string]string `yaml:"headers"`
 
-	JSON            any               `yaml:"json"`
-	Form            map[string]string `yaml:"form"`
-	Raw             string            `yaml:"raw"`
-	DelayMS         *int              `yaml:"delay_ms"`
-	TimeoutMS       *int              `yaml:"timeout_ms"`
-	FollowRedirects *bool             `yaml:"follow_redirects"`
-	Assert          yamlAssertions    `yaml:"assert"`
-	Extract         map[string]string `yaml:"extract"`
-	ExtractHeaders  map[string]string `yaml:"extract_headers"`
-	Tags            []string          `yaml:"tags"`
+	JSON            any                `yaml:"json"`
+	Form            map[string]string  `yaml:"form"`
+	Raw             string             `yaml:"raw"`
+	DelayMS         *int               `yaml:"delay_ms"`
+	TimeoutMS       *int               `yaml:"timeout_ms"`
+	FollowRedirects *bool              `yaml:"follow_redirects"`
+	Assert          yamlAssertions     `yaml:"assert"`
+	Extract         map[string]any     `yaml:"extract"`
+	ExtractHeaders  map[string]string  `yaml:"extract_headers"`
+	ExtractXPath    map[string]string  `yaml:"extract_xpath"`
+	ExtractCSS      map[string]string  `yaml:"extract_css"`
+	ExtractRegex    []yamlExtractRegex `yaml:"extract_regex"`
+	ExtractCookies  map[string]string  `yaml:"extract_cookies"`
+	ExtractResponse map[string]string  `yaml:"extract_response"`
+	Tags            []string           `yaml:"tags"`
+}
+
+// yamlExtractRegex matches Pattern against the body, or the Header values
+// when set; each named capture group becomes a variable.
+type yamlExtractRegex struct {
+	Pattern string `yaml:"pattern"`
+	Header  string `yaml:"header"`
 }
 
 type yamlAssertions struct {
 	// Status accepts a single code (status: 200) or a list (status: [200, 201]).
-	Status any  `yaml:"status"`
-	MaxMS  *int `yaml:"max_ms"`
+	Status          any  `yaml:"status"`
+	MaxMS           *int `yaml:"max_ms"`
+	MaxMSRegression *int `yaml:"max_ms_regression"`
 
 	Body         *yamlBodyAssertion               `yaml:"body"`
 	JSONPath     map[string]yamlJSONPathAssertion `yaml:"jsonpath"`
 	Headers      map[string]yamlJSONPathAssertion `yaml:"headers"`
+	XPath        map[string]yamlJSONPathAssertion `yaml:"xpath"`
+	CSS          map[string]yamlJSONPathAssertion `yaml:"css"`
 	Schema       *string                          `yaml:"schema"`
 	SchemaInline map[string]any                   `yaml:"schema_inline"`
+
+	// Snapshot accepts true, a snapshot name, or {name, ignore}.
+	Snapshot any `yaml:"snapshot"`
+
+	// JSONEq accepts one comparison mapping or a list of them.
+	JSONEq any `yaml:"json_eq"`
+
+	// Expr lists boolean expressions; validate parses them.
+	Expr []string `yaml:"expr"`
 }
 
 type yamlBodyAssertion struct {
@@ -175,19 +207,49 @@ type yamlJSONPathAssertion struct {
 	NotEq       *string  `yaml:"not_eq"`
 	NotContains *string  `yaml:"not_contains"`
 	Len         *int     `yaml:"len"`
+	Type        *string  `yaml:"type"`
+	In          []string `yaml:"in"`
+	NotIn       []string `yaml:"not_in"`
+	StartsWith  *string  `yaml:"starts_with"`
+	EndsWith    *string  `yaml:"ends_with"`
+	EqCI        *string  `yaml:"eq_ci"`
+
+	Format *string     `yaml:"format"`
+	Before *string     `yaml:"before"`
+	After  *string     `yaml:"after"`
+	Within *string     `yaml:"within"`
+	Approx *yamlApprox `yaml:"approx"`
+
+	All         *yamlJSONPathAssertion `yaml:"all"`
+	Any         *yamlJSONPathAssertion `yaml:"any"`
+	None        *yamlJSONPathAssertion `yaml:"none"`
+	Count       *yamlCountAssertion    `yaml:"count"`
+	Unique      *bool                  `yaml:"unique"`
+	Sorted      *string                `yaml:"sorted"`
+	ContainsAll []string               `yaml:"contains_all"`
+}
+
+type yamlCountAssertion struct {
+	Where                 *yamlJSONPathAssertion `yaml:"where"`
+	yamlJSONPathAssertion `yaml:",inline"`
 }
 
-func mapAndValidate(path string, yc yamlCollection) (domain.Collection, error) {
+type yamlApprox struct {
+	Value     *float64 `yaml:"value"`
+	Tolerance *float64 `yaml:"tolerance"`
+}
+
+func mapAndValidate(path string, yc yamlCollection, pos positions) (domain.Collection, error) {
 	schemaVersion := 1
 	if yc.SchemaVersion != nil {
 		schemaVersion = *yc.SchemaVersion
 	}
 	if schemaVersion < 1 {
-		return domain.Collection{}, invalidField(path, "schema_version", "must be >= 1")
+		return domain.Collection{}, pos.invalidField(path, "schema_version", "must be >= 1")
 	}
 
 	if strings.TrimSpace(yc.Name) == "" {
-		return domain.Collection{}, invalidField(path, "name", "collection name is required")
+		return domain.Collection{}, pos.invalidField(path, "name", "collection name is required")
 	}
 
 	col := domain.Collection{
@@ -203,37 +265,49 @@ func mapAndValidate(path string, yc yamlCollection) (domain.Collection, error) {
 		fieldPrefix := fmt.Sprintf("requests[%d]", i)
 
 		if strings.TrimSpace(r.Name) == "" {
-			return domain.Collection{}, invalidField(path, fieldPrefix+".name", "request name is required")
+			return domain.Collection{}, pos.invalidField(path, fieldPrefix+".name", "request name is required")
 		}
 		if _, dup := seenNames[r.Name]; dup {
-			return domain.Collection{}, invalidField(path, fieldPrefix+".name",
+			return domain.Collection{}, pos.invalidField(path, fieldPrefix+".name",
 				fmt.Sprintf("duplicate request name %q", r.Name))
 		}
 		seenNames[r.Name] = struct{}{}
 		if strings.TrimSpace(r.URL) == "" {
-			return domain.Collection{}, invalidField(path, fieldPrefix+".url", "request url is required")
+			return domain.Collection{}, pos.invalidField(path, fieldPrefix+".url", "request url is required")
 		}
 
 		method, err := parseMethod(r.Method)
 		if err != nil {
-			return domain.Collection{}, invalidField(path, fieldPrefix+".method", err.Error())
+			return domain.Collection{}, pos.invalidField(path, fieldPrefix+".method", err.Error())
 		}
 
 		if r.Assert.Schema != nil && r.Assert.SchemaInline != nil {
-			return domain.Collection{}, invalidField(path, fieldPrefix+".assert",
+			return domain.Collection{}, pos.invalidField(path, fieldPrefix+".assert",
 				"schema and schema_inline cannot be used together")
 		}
 
 		for expr, a := range r.Assert.JSONPath {
-			if !assertionHasOperator(a) {
-				return domain.Collection{}, invalidField(path,
-					fmt.Sprintf("%s.assert.jsonpath[%q]", fieldPrefix, expr), noOperatorMsg)
+			field := fmt.Sprintf("%s.assert.jsonpath[%q]", fieldPrefix, expr)
+			if err := checkValueAssertion(a); err != nil {
+				return domain.Collection{}, pos.invalidField(path, field, err.Error())
 			}
 		}
 		for header, a := range r.Assert.Headers {
-			if !assertionHasOperator(a) {
-				return domain.Collection{}, invalidField(path,
-					fmt.Sprintf("%s.assert.headers[%q]", fieldPrefix, header), noOperatorMsg)
+			field := fmt.Sprintf("%s.assert.headers[%q]", fieldPrefix, header)
+			if err := checkValueAssertion(a); err != nil {
+				return domain.Collection{}, pos.invalidField(path, field, err.Error())
+			}
+		}
+		for expr, a := range r.Assert.XPath {
+			field := fmt.Sprintf("%s.assert.xpath[%q]", fieldPrefix, expr)
+			if err := checkValueAssertion(a); err != nil {
+				return domain.Collection{}, pos.invalidField(path, field, err.Error())
+			}
+		}
+		for selector, a := range r.Assert.CSS {
+			field := fmt.Sprintf("%s.assert.css[%q]", fieldPrefix, selector)
+			if err := checkValueAssertion(a); err != nil {
+				return domain.Collection{}, pos.invalidField(path, field, err.Error())
 			}
 		}
 
@@ -249,14 +323,71 @@ func mapAndValidate(path string, yc yamlCollection) (domain.Collection, error) {
 
 		status, statusIn, err := parseStatusSpec(r.Assert.Status)
 		if err != nil {
-			return domain.Collection{}, invalidField(path, fieldPrefix+".assert.status", err.Error())
+			return domain.Collection{}, pos.invalidField(path, fieldPrefix+".assert.status", err.Error())
+		}
+
+		snapshot, err := parseSnapshotSpec(r.Assert.Snapshot, r.Name)
+		if err != nil {
+			return domain.Collection{}, pos.invalidField(path, fieldPrefix+".assert.snapshot", err.Error())
+		}
+
+		jsonEq, err := parseJSONEqSpec(r.Assert.JSONEq, filepath.Dir(path))
+		if err != nil {
+			return domain.Collection{}, pos.invalidField(path, fieldPrefix+".assert.json_eq", err.Error())
+		}
+
+		var exprs []domain.ExprAssertion
+		for j, src := range r.Assert.Expr {
+			if strings.TrimSpace(src) == "" {
+				return domain.Collection{}, pos.invalidField(path, fmt.Sprintf("%s.assert.expr[%d]", fieldPrefix, j), "expression cannot be empty")
+			}
+			exprs = append(exprs, domain.ExprAssertion{Source: src})
+		}
+
+		var extract domain.ExtractSpec
+		var extractOpts map[string]domain.ExtractOptions
+		for name, v := range r.Extract {
+			expr, opts, err := parseExtractRule(v)
+			if err != nil {
+				return domain.Collection{}, pos.invalidField(path, fmt.Sprintf("%s.extract[%q]", fieldPrefix, name), err.Error())
+			}
+			if extract == nil {
+				extract = domain.ExtractSpec{}
+			}
+			extract[name] = expr
+			if opts != nil {
+				if extractOpts == nil {
+					extractOpts = map[string]domain.ExtractOptions{}
+				}
+				extractOpts[name] = *opts
+			}
+		}
+
+		var extractRegex []domain.ExtractRegexRule
+		for j, rule := range r.ExtractRegex {
+			field := fmt.Sprintf("%s.extract_regex[%d]", fieldPrefix, j)
+			if err := checkExtractRegex(rule); err != nil {
+				return domain.Collection{}, pos.invalidField(path, field, err.Error())
+			}
+			extractRegex = append(extractRegex, domain.ExtractRegexRule{Pattern: rule.Pattern, Header: strings.TrimSpace(rule.Header)})
+		}
+		for name, rule := range r.ExtractCookies {
+			if _, _, err := domain.ParseCookieRule(rule); err != nil {
+				return domain.Collection{}, pos.invalidField(path, fmt.Sprintf("%s.extract_cookies[%q]", fieldPrefix, name), err.Error())
+			}
+		}
+		for name, source := range r.ExtractResponse {
+			if source != domain.ResponseStatus && source != domain.ResponseURL {
+				return domain.Collection{}, pos.invalidField(path, fmt.Sprintf("%s.extract_response[%q]", fieldPrefix, name),
+					fmt.Sprintf("unknown source %q (expected one of: status, url)", source))
+			}
 		}
 
 		var bodyAssert *domain.BodyAssertion
 		if r.Assert.Body != nil {
 			b := r.Assert.Body
 			if b.Eq == nil && b.Contains == nil && b.NotContains == nil && b.Matches == nil && b.NotMatches == nil {
-				return domain.Collection{}, invalidField(path, fieldPrefix+".assert.body",
+				return domain.Collection{}, pos.invalidField(path, fieldPrefix+".assert.body",
 					"body assertion has no operators (expected one of: eq, contains, not_contains, matches, not_matches)")
 			}
 			bodyAssert = &domain.BodyAssertion{
@@ -275,18 +406,31 @@ func mapAndValidate(path string, yc yamlCollection) (domain.Collection, error) {
 			Headers: domain.Headers(r.Headers),
 			Tags:    r.Tags,
 			Assert: domain.AssertionsSpec{
-				Status:       status,
-				StatusIn:     statusIn,
-				MaxLatencyMS: r.Assert.MaxMS,
-				Body:         bodyAssert,
-				JSONPath:     mapJSONPath(r.Assert.JSONPath),
-				Headers:      mapJSONPath(r.Assert.Headers),
-				Schema:       schemaPtr,
-				SchemaInline: r.Assert.SchemaInline,
+				Status:          status,
+				StatusIn:        statusIn,
+				MaxLatencyMS:    r.Assert.MaxMS,
+				MaxMSRegression: r.Assert.MaxMSRegression,
+				Body:            bodyAssert,
+				JSONPath:        mapJSONPath(r.Assert.JSONPath),
+				Headers:         mapJSONPath(r.Assert.Headers),
+				XPath:           mapJSONPath(r.Assert.XPath),
+				CSS:             mapJSONPath(r.Assert.CSS),
+				Schema:          schemaPtr,
+				SchemaInline:    r.Assert.SchemaInline,
+				Snapshot:        snapshot,
+				JSONEq:          jsonEq,
+				Expr:            exprs,
 			},
-			Extract:        domain.ExtractSpec(r.Extract),
-			ExtractHeaders: domain.ExtractHeaderSpec(r.ExtractHeaders),
+			Extract:         extract,
+			ExtractOptions:  extractOpts,
+			ExtractHeaders:  domain.ExtractHeaderSpec(r.ExtractHeaders),
+			ExtractXPath:    domain.ExtractSpec(r.ExtractXPath),
+			ExtractCSS:      domain.ExtractSpec(r.ExtractCSS),
+			ExtractRegex:    extractRegex,
+			ExtractCookies:  domain.ExtractSpec(r.ExtractCookies),
+			ExtractResponse: domain.ExtractSpec(r.ExtractResponse),
 		}
+		req.Pos, req.FieldPos = pos.request(i)
 
 		if req.Headers == nil {
 			req.Headers = domain.Headers{}
@@ -316,14 +460,14 @@ func mapAndValidate(path string, yc yamlCollection) (domain.Collection, error) {
 			bodyCount++
 		}
 		if bodyCount > 1 {
-			return domain.Collection{}, invalidField(path, fieldPrefix+".body",
+			return domain.Collection{}, pos.invalidField(path, fieldPrefix+".body",
 				"only one body type allowed (json, form, or raw)")
 		}
 
 		req.Body = domain.BodySpec{Type: domain.BodyNone}
 		if r.JSON != nil {
 			if err := domain.ValidateJSONBody(r.JSON); err != nil {
-				return domain.Collection{}, invalidField(path, fieldPrefix+".json", err.Error())
+				return domain.Collection{}, pos.invalidField(path, fieldPrefix+".json", err.Error())
 			}
 			req.Body = domain.BodySpec{Type: domain.BodyJSON, JSON: r.JSON}
 		} else if r.Form != nil {
@@ -332,18 +476,22 @@ func mapAndValidate(path string, yc yamlCollection) (domain.Collection, error) {
 			req.Body = domain.BodySpec{Type: domain.BodyRaw, Raw: r.Raw}
 		}
 		if err := req.Body.Validate(); err != nil {
-			return domain.Collection{}, invalidField(path, fieldPrefix+".body", err.Error())
+			return domain.Collection{}, pos.invalidField(path, fieldPrefix+".body", err.Error())
 		}
 
 		if r.DelayMS != nil && *r.DelayMS < 0 {
-			return domain.Collection{}, invalidField(path, fieldPrefix+".delay_ms", "must be >= 0")
+			return domain.Collection{}, pos.invalidField(path, fieldPrefix+".delay_ms", "must be >= 0")
 		}
 		req.DelayMS = r.DelayMS
 
 		if r.TimeoutMS != nil && *r.TimeoutMS <= 0 {
-			return domain.Collection{}, invalidField(path, fieldPrefix+".timeout_ms", "must be > 0")
+			return domain.Collection{}, pos.invalidField(path, fieldPrefix+".timeout_ms", "must be > 0")
 		}
 		req.TimeoutMS = r.TimeoutMS
+
+		if r.Assert.MaxMSRegression != nil && *r.Assert.MaxMSRegression < 0 {
+			return domain.Collection{}, pos.invalidField(path, fieldPrefix+".assert.max_ms_regression", "must be >= 0")
+		}
 		req.FollowRedirects = r.FollowRedirects
 
 		col.Requests = append(col.Requests, req)
@@ -352,12 +500,196 @@ func mapAndValidate(path string, yc yamlCollection) (domain.Collection, error) {
 	return col, nil
 }
 
-const noOperatorMsg = "assertion has no operators (expected one of: exists, eq, not_eq, contains, not_contains, matches, not_matches, gt, lt, gte, lte, len)"
+const noOperatorMsg = "assertion has no operators (expected one of: exists, eq, not_eq, eq_ci, contains, not_contains, starts_with, ends_with, matches, not_matches, in, not_in, gt, lt, gte, lte, len, type, format, before, after, within, approx, all, any, none, count, unique, sorted, contains_all)"
 
 func assertionHasOperator(a yamlJSONPathAssertion) bool {
 	return a.Exists != nil || a.Eq != nil || a.Contains != nil || a.Matches != nil ||
 		a.NotMatches != nil || a.Gt != nil || a.Lt != nil || a.Gte != nil || a.Lte != nil ||
-		a.NotEq != nil || a.NotContains != nil || a.Len != nil
+		a.NotEq != nil || a.NotContains != nil || a.Len != nil || a.Type != nil ||
+		a.In != nil || a.NotIn != nil || a.StartsWith != nil || a.EndsWith != nil || a.EqCI != nil ||
+		a.Format != nil || a.Before != nil || a.After != nil || a.Within != nil || a.Approx != nil ||
+		a.All != nil || a.Any != nil || a.None != nil || a.Count != nil || a.Unique != nil ||
+		a.Sorted != nil || a.ContainsAll != nil
+}
+
+// checkValueAssertion rejects assertions without operators and operator
+// values that could never pass.
+func checkValueAssertion(a yamlJSONPathAssertion) error {
+	if !assertionHasOperator(a) {
+		return errors.New(noOperatorMsg)
+	}
+	if a.Type != nil && !slices.Contains(domain.ValueTypes, *a.Type) {
+		return fmt.Errorf("unknown type %q (expected one of: %s)", *a.Type, strings.Join(domain.ValueTypes, ", "))
+	}
+	if a.In != nil && len(a.In) == 0 {
+		return errors.New("in list cannot be empty")
+	}
+	if a.NotIn != nil && len(a.NotIn) == 0 {
+		return errors.New("not_in list cannot be empty")
+	}
+	if a.ContainsAll != nil && len(a.ContainsAll) == 0 {
+		return errors.New("contains_all list cannot be empty")
+	}
+	if a.Sorted != nil && *a.Sorted != "asc" && *a.Sorted != "desc" {
+		return fmt.Errorf("sorted must be asc or desc, got %q", *a.Sorted)
+	}
+	if err := checkTimeOperators(a); err != nil {
+		return err
+	}
+	if a.Approx != nil {
+		if a.Approx.Value == nil || a.Approx.Tolerance == nil {
+			return errors.New("approx needs value and tolerance")
+		}
+		if *a.Approx.Tolerance < 0 {
+			return fmt.Errorf("approx tolerance must be >= 0, got %v", *a.Approx.Tolerance)
+		}
+	}
+	for _, nested := range []struct {
+		op string
+		a  *yamlJSONPathAssertion
+	}{{"all", a.All}, {"any", a.Any}, {"none", a.None}} {
+		if nested.a == nil {
+			continue
+		}
+		if err := checkValueAssertion(*nested.a); err != nil {
+			return fmt.Errorf("%s: %w", nested.op, err)
+		}
+	}
+	if c := a.Count; c != nil {
+		if c.Where != nil {
+			if err := checkValueAssertion(*c.Where); err != nil {
+				return fmt.Errorf("count.where: %w", err)
+			}
+		}
+		if err := checkValueAssertion(c.yamlJSONPathAssertion); err != nil {
+			return fmt.Errorf("count: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkTimeOperators parses the date operators up front, so a typo fails
+// the load instead of every run. Values with {{var}} references are only
+// known at run time.
+func checkTimeOperators(a yamlJSONPathAssertion) error {
+	var format string
+	if a.Format != nil {
+		format = strings.TrimSpace(*a.Format)
+		if format == "" {
+			return errors.New("format cannot be empty")
+		}
+	}
+	for _, op := range []struct {
+		name string
+		expr *string
+	}{{"before", a.Before}, {"after", a.After}} {
+		if op.expr == nil || strings.Contains(*op.expr, "{{") {
+			continue
+		}
+		if _, err := domain.ParseTimeExpr(*op.expr, format, time.Now()); err != nil {
+			return fmt.Errorf("%s: %w", op.name, err)
+		}
+	}
+	if a.Within != nil && !strings.Contains(*a.Within, "{{") {
+		_, ref, err := domain.ParseWithin(*a.Within)
+		if err != nil {
+			return err
+		}
+		if _, err := domain.ParseTimeExpr(ref, format, time.Now()); err != nil {
+			return fmt.Errorf("within: %w", err)
+		}
+	}
+	return nil
+}
+
+// parseExtractRule accepts a JSONPath, or a mapping with the path and the
+// type, optional and default options.
+func parseExtractRule(v any) (string, *domain.ExtractOptions, error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil, nil
+	case map[string]any:
+		var expr string
+		var opts domain.ExtractOptions
+		for k, val := range t {
+			switch k {
+			case "path":
+				p, ok := val.(string)
+				if !ok || strings.TrimSpace(p) == "" {
+					return "", nil, fmt.Errorf("extract path must be a non-empty JSONPath")
+				}
+				expr = p
+			case "type":
+				typ, err := domain.ParseVarType(fmt.Sprint(val))
+				if err != nil {
+					return "", nil, fmt.Errorf("extract type: %w", err)
+				}
+				opts.Type = typ
+			case "optional":
+				b, ok := val.(bool)
+				if !ok {
+					return "", nil, fmt.Errorf("extract optional must be true or false")
+				}
+				opts.Optional = b
+			case "default":
+			default:
+				return "", nil, fmt.Errorf("unknown extract field %q (expected path, type, optional, default)", k)
+			}
+		}
+		if expr == "" {
+			return "", nil, fmt.Errorf("extract path is required")
+		}
+		if def, ok := t["default"]; ok {
+			d, err := extractDefault(def, opts.Type)
+			if err != nil {
+				return "", nil, err
+			}
+			opts.Default = &d
+		}
+		return expr, &opts, nil
+	default:
+		return "", nil, fmt.Errorf("extract must be a JSONPath or a mapping with path, got %T", v)
+	}
+}
+
+// extractDefault renders a default as the extracted value would be: JSON
+// text for a json extract, otherwise a scalar as written.
+func extractDefault(v any, typ domain.VarType) (string, error) {
+	if typ == domain.VarJSON {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("extract default: %w", err)
+		}
+		return string(b), nil
+	}
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case int, float64, bool:
+		return fmt.Sprint(t), nil
+	case nil:
+		return "", fmt.Errorf("extract default cannot be null unless type is json")
+	default:
+		return "", fmt.Errorf("extract default must be a scalar unless type is json")
+	}
+}
+
+// checkExtractRegex requires a pattern that compiles and names at least one
+// capture group, since the group names are the extracted variables.
+func checkExtractRegex(rule yamlExtractRegex) error {
+	if strings.TrimSpace(rule.Pattern) == "" {
+		return fmt.Errorf("pattern cannot be empty")
+	}
+	re, err := regexp.Compile(rule.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid regex: %w", err)
+	}
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("pattern has no named capture group, e.g. (?P<id>[0-9]+)")
 }
 
 // parseStatusSpec accepts a single status code or a list of codes.
@@ -385,30 +717,216 @@ func parseStatusSpec(v any) (*int, []int, error) {
 	}
 }
 
+// parseSnapshotSpec accepts true (a snapshot named after the request), a
+// snapshot name, or a mapping with name and ignore.
+func parseSnapshotSpec(v any, requestName string) (*domain.SnapshotAssertion, error) {
+	switch t := v.(type) {
+	case nil:
+		return nil, nil
+	case bool:
+		if !t {
+			return nil, nil
+		}
+		return &domain.SnapshotAssertion{Name: requestName}, nil
+	case string:
+		if strings.TrimSpace(t) == "" {
+			return nil, fmt.Errorf("snapshot name cannot be empty")
+		}
+		return &domain.SnapshotAssertion{Name: t}, nil
+	case map[string]any:
+		out := &domain.SnapshotAssertion{Name: requestName}
+		for k, val := range t {
+			switch k {
+			case "name":
+				name, ok := val.(string)
+				if !ok || strings.TrimSpace(name) == "" {
+					return nil, fmt.Errorf("snapshot name must be a non-empty string")
+				}
+				out.Name = name
+			case "ignore":
+				list, ok := val.([]any)
+				if !ok {
+					return nil, fmt.Errorf("snapshot ignore must be a list of JSONPaths")
+				}
+				for _, item := range list {
+					expr, ok := item.(string)
+					if !ok || strings.TrimSpace(expr) == "" {
+						return nil, fmt.Errorf("snapshot ignore must be a list of JSONPaths")
+					}
+					out.Ignore = append(out.Ignore, expr)
+				}
+			default:
+				return nil, fmt.Errorf("unknown snapshot field %q (expected name, ignore)", k)
+			}
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("snapshot must be true, a name, or a mapping with name and ignore, got %T", v)
+	}
+}
+
+// parseJSONEqSpec accepts a mapping or a list of mappings, each with an
+// inline value or a fixture file (relative to dir) and comparison options.
+func parseJSONEqSpec(v any, dir string) ([]domain.JSONEqAssertion, error) {
+	var items []any
+	switch t := v.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		items = []any{t}
+	case []any:
+		items = t
+	default:
+		return nil, fmt.Errorf("json_eq must be a mapping or a list of mappings, got %T", v)
+	}
+
+	out := make([]domain.JSONEqAssertion, 0, len(items))
+	for i, item := range items {
+		m, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("json_eq[%d] must be a mapping, got %T", i, item)
+		}
+		a, err := parseJSONEqItem(m, dir)
+		if err != nil {
+			if len(items) > 1 {
+				return nil, fmt.Errorf("json_eq[%d]: %w", i, err)
+			}
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+func parseJSONEqItem(m map[string]any, dir string) (domain.JSONEqAssertion, error) {
+	var a domain.JSONEqAssertion
+	_, hasValue := m["value"]
+	for k, val := range m {
+		switch k {
+		case "value":
+			a.Value = val
+		case "path":
+			p, ok := val.(string)
+			if !ok || strings.TrimSpace(p) == "" {
+				return a, fmt.Errorf("path must be a non-empty JSONPath")
+			}
+			a.Path = p
+		case "file":
+			f, ok := val.(string)
+			if !ok || strings.TrimSpace(f) == "" {
+				return a, fmt.Errorf("file must be a non-empty path")
+			}
+			if !filepath.IsAbs(f) {
+				f = filepath.Join(dir, f)
+			}
+			a.File = &f
+		case "ignore":
+			list, ok := val.([]any)
+			if !ok {
+				return a, fmt.Errorf("ignore must be a list of JSONPaths")
+			}
+			for _, item := range list {
+				expr, ok := item.(string)
+				if !ok || strings.TrimSpace(expr) == "" {
+					return a, fmt.Errorf("ignore must be a list of JSONPaths")
+				}
+				a.Ignore = append(a.Ignore, expr)
+			}
+		case "ignore_order":
+			b, ok := val.(bool)
+			if !ok {
+				return a, fmt.Errorf("ignore_order must be a boolean")
+			}
+			a.IgnoreOrder = b
+		case "tolerance":
+			var f float64
+			switch n := val.(type) {
+			case int:
+				f = float64(n)
+			case float64:
+				f = n
+			default:
+				return a, fmt.Errorf("tolerance must be a number")
+			}
+			if f < 0 {
+				return a, fmt.Errorf("tolerance must be >= 0")
+			}
+			a.Tolerance = f
+		default:
+			return a, fmt.Errorf("unknown json_eq field %q (expected path, value, file, ignore, ignore_order, tolerance)", k)
+		}
+	}
+	switch {
+	case hasValue && a.File != nil:
+		return a, fmt.Errorf("value and file cannot be used together")
+	case !hasValue && a.File == nil:
+		return a, fmt.Errorf("json_eq needs an expected value or file")
+	}
+	return a, nil
+}
+
 func mapJSONPath(in map[string]yamlJSONPathAssertion) map[string]domain.ValueAssertion {
 	if in == nil {
 		return nil
 	}
 	out := make(map[string]domain.ValueAssertion, len(in))
 	for k, v := range in {
-		out[k] = domain.ValueAssertion{
-			Exists:      v.Exists,
-			Eq:          v.Eq,
-			Contains:    v.Contains,
-			Matches:     v.Matches,
-			NotMatches:  v.NotMatches,
-			Gt:          v.Gt,
-			Lt:          v.Lt,
-			Gte:         v.Gte,
-			Lte:         v.Lte,
-			NotEq:       v.NotEq,
-			NotContains: v.NotContains,
-			Len:         v.Len,
+		out[k] = mapValueAssertion(v)
+	}
+	return out
+}
+
+func mapValueAssertion(v yamlJSONPathAssertion) domain.ValueAssertion {
+	out := domain.ValueAssertion{
+		Exists:      v.Exists,
+		Eq:          v.Eq,
+		Contains:    v.Contains,
+		Matches:     v.Matches,
+		NotMatches:  v.NotMatches,
+		Gt:          v.Gt,
+		Lt:          v.Lt,
+		Gte:         v.Gte,
+		Lte:         v.Lte,
+		NotEq:       v.NotEq,
+		NotContains: v.NotContains,
+		Len:         v.Len,
+		Type:        v.Type,
+		In:          v.In,
+		NotIn:       v.NotIn,
+		StartsWith:  v.StartsWith,
+		EndsWith:    v.EndsWith,
+		EqCI:        v.EqCI,
+		Format:      v.Format,
+		Before:      v.Before,
+		After:       v.After,
+		Within:      v.Within,
+		All:         mapNestedAssertion(v.All),
+		Any:         mapNestedAssertion(v.Any),
+		None:        mapNestedAssertion(v.None),
+		Unique:      v.Unique,
+		Sorted:      v.Sorted,
+		ContainsAll: v.ContainsAll,
+	}
+	if v.Approx != nil && v.Approx.Value != nil && v.Approx.Tolerance != nil {
+		out.Approx = &domain.ApproxAssertion{Value: *v.Approx.Value, Tolerance: *v.Approx.Tolerance}
+	}
+	if v.Count != nil {
+		out.Count = &domain.CountAssertion{
+			Where:          mapNestedAssertion(v.Count.Where),
+			ValueAssertion: mapValueAssertion(v.Count.yamlJSONPathAssertion),
 		}
 	}
 	return out
 }
 
+func mapNestedAssertion(v *yamlJSONPathAssertion) *domain.ValueAssertion {
+	if v == nil {
+		return nil
+	}
+	out := mapValueAssertion(*v)
+	return &out
+}
+
 func parseMethod(m string) (domain.HTTPMethod, error) {
 	up := strings.ToUpper(strings.TrimSpace(m))
 	switch domain.HTTPMethod(up) {
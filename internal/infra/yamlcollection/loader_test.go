@@ -1,8 +1,10 @@
 package yamlcollection
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -685,6 +687,267 @@ requests:
 	}
 }
 
+func TestLoadCollection_MaxMSRegression(t *testing.T) {
+	tmp := t.TempDir()
+	p := filepath.Join(tmp, "r.yaml")
+
+	write := func(budget string) {
+		t.Helper()
+		content := []byte(`
+name: Regression
+requests:
+  - name: list
+    method: GET
+    url: "http://x"
+    assert:
+      max_ms_regression: ` + budget + `
+`)
+		if err := os.WriteFile(p, content, 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	write("75")
+	c, err := NewLoader().LoadCollection(p)
+	if err != nil {
+		t.Fatalf("LoadCollection error: %v", err)
+	}
+	if got := c.Requests[0].Assert.MaxMSRegression; got == nil || *got != 75 {
+		t.Fatalf("max_ms_regression not mapped: %v", got)
+	}
+
+	write("-1")
+	_, err = NewLoader().LoadCollection(p)
+	if err == nil || !strings.Contains(err.Error(), "max_ms_regression") {
+		t.Fatalf("expected a max_ms_regression error, got %v", err)
+	}
+}
+
+func TestLoadCollection_Snapshot(t *testing.T) {
+	tmp := t.TempDir()
+	p := filepath.Join(tmp, "s.yaml")
+
+	load := func(spec string) (*domain.SnapshotAssertion, error) {
+		t.Helper()
+		content := []byte(`
+name: Snapshots
+requests:
+  - name: list users
+    method: GET
+    url: "http://x"
+    assert:
+      snapshot: ` + spec + `
+`)
+		if err := os.WriteFile(p, content, 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		c, err := NewLoader().LoadCollection(p)
+		if err != nil {
+			return nil, err
+		}
+		return c.Requests[0].Assert.Snapshot, nil
+	}
+
+	cases := []struct {
+		spec string
+		want *domain.SnapshotAssertion
+	}{
+		{"true", &domain.SnapshotAssertion{Name: "list users"}},
+		{"false", nil},
+		{"users", &domain.SnapshotAssertion{Name: "users"}},
+		{`{ignore: ["$.meta.timestamp", "$..id"]}`, &domain.SnapshotAssertion{Name: "list users", Ignore: []string{"$.meta.timestamp", "$..id"}}},
+		{`{name: users, ignore: ["$.at"]}`, &domain.SnapshotAssertion{Name: "users", Ignore: []string{"$.at"}}},
+	}
+	for _, tc := range cases {
+		got, err := load(tc.spec)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.spec, err)
+		}
+		if (got == nil) != (tc.want == nil) || got != nil && (got.Name != tc.want.Name || strings.Join(got.Ignore, ",") != strings.Join(tc.want.Ignore, ",")) {
+			t.Errorf("%s: snapshot = %+v, want %+v", tc.spec, got, tc.want)
+		}
+	}
+
+	for _, spec := range []string{`""`, "1", "{ignore: $.a}", "{names: x}"} {
+		if _, err := load(spec); err == nil || !strings.Contains(err.Error(), "assert.snapshot") {
+			t.Errorf("%s: expected an assert.snapshot error, got %v", spec, err)
+		}
+	}
+}
+
+func TestLoadCollection_ValueOperators(t *testing.T) {
+	tmp := t.TempDir()
+	p := filepath.Join(tmp, "ops.yaml")
+
+	load := func(ops string) (domain.ValueAssertion, error) {
+		t.Helper()
+		content := []byte(`
+name: Ops
+requests:
+  - name: user
+    method: GET
+    url: "http://x"
+    assert:
+      jsonpath:
+        "$.status": ` + ops + `
+`)
+		if err := os.WriteFile(p, content, 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		c, err := NewLoader().LoadCollection(p)
+		if err != nil {
+			return domain.ValueAssertion{}, err
+		}
+		return c.Requests[0].Assert.JSONPath["$.status"], nil
+	}
+
+	a, err := load(`{type: string, in: [active, pending], not_in: [1, true], starts_with: ac, ends_with: ve, eq_ci: ACTIVE}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *a.Type != "string" || !reflect.DeepEqual(a.In, []string{"active", "pending"}) || !reflect.DeepEqual(a.NotIn, []string{"1", "true"}) ||
+		*a.StartsWith != "ac" || *a.EndsWith != "ve" || *a.EqCI != "ACTIVE" {
+		t.Fatalf("operators = %+v", a)
+	}
+
+	a, err = load(`{all: {in: [active, pending]}, none: {eq: deleted}, count: {where: {eq: active}, gte: 2}, unique: true, sorted: desc, contains_all: [active]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.All == nil || !reflect.DeepEqual(a.All.In, []string{"active", "pending"}) || a.None == nil || *a.None.Eq != "deleted" ||
+		a.Count == nil || a.Count.Where == nil || *a.Count.Where.Eq != "active" || *a.Count.Gte != 2 ||
+		!*a.Unique || *a.Sorted != "desc" || a.ContainsAll[0] != "active" {
+		t.Fatalf("array operators = %+v", a)
+	}
+
+	a, err = load(`{format: rfc3339, after: "now-1h", before: "{{deadline}}", within: "5m of 2026-01-02T00:00:00Z", approx: {value: 3.14, tolerance: 0.01}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *a.Format != "rfc3339" || *a.After != "now-1h" || *a.Before != "{{deadline}}" || *a.Within != "5m of 2026-01-02T00:00:00Z" ||
+		a.Approx == nil || a.Approx.Value != 3.14 || a.Approx.Tolerance != 0.01 {
+		t.Fatalf("date operators = %+v", a)
+	}
+
+	for spec, want := range map[string]string{
+		`{before: "yesterday"}`:               `before: "yesterday" is not a recognized timestamp`,
+		`{after: "now*2"}`:                    "invalid time expression",
+		`{within: "soon"}`:                    `invalid within "soon"`,
+		`{format: ""}`:                        "format cannot be empty",
+		`{approx: {value: 1}}`:                "approx needs value and tolerance",
+		`{approx: {value: 1, tolerance: -1}}`: "approx tolerance must be >= 0",
+		"{type: int}":                         `unknown type "int"`,
+		"{in: []}":                            "in list cannot be empty",
+		"{}":                                  "no operators",
+		"{sorted: up}":                        "sorted must be asc or desc",
+		"{all: {}}":                           "all: assertion has no operators",
+		"{count: {where: {eq: x}}}":           "count: assertion has no operators",
+		"{any: {type: str}}":                  `any: unknown type "str"`,
+		"{contains_all: []}":                  "contains_all list cannot be empty",
+	} {
+		if _, err := load(spec); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected an error containing %q, got %v", spec, want, err)
+		}
+	}
+}
+
+func TestLoadCollection_Expr(t *testing.T) {
+	tmp := t.TempDir()
+	p := filepath.Join(tmp, "e.yaml")
+	write := func(exprs string) {
+		t.Helper()
+		content := []byte(`
+name: Expr
+requests:
+  - name: order
+    method: GET
+    url: "http://x"
+    assert:
+      expr:
+` + exprs)
+		if err := os.WriteFile(p, content, 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	write(`        - "$.total == length($.items)"
+        - $.end > $.start
+`)
+	c, err := NewLoader().LoadCollection(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []domain.ExprAssertion{{Source: "$.total == length($.items)"}, {Source: "$.end > $.start"}}
+	if got := c.Requests[0].Assert.Expr; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expr = %#v, want %#v", got, want)
+	}
+	if pos := c.Requests[0].PosOf("assert.expr[1]"); pos.Line != 10 {
+		t.Errorf("assert.expr[1] at line %d, want 10", pos.Line)
+	}
+
+	write(`        - "$.a == 1"
+        - "  "
+`)
+	if _, err := NewLoader().LoadCollection(p); err == nil || !strings.Contains(err.Error(), "requests[0].assert.expr[1]: expression cannot be empty") {
+		t.Fatalf("expected an empty expression error, got %v", err)
+	}
+}
+
+func TestLoadCollection_JSONEq(t *testing.T) {
+	tmp := t.TempDir()
+	p := filepath.Join(tmp, "j.yaml")
+
+	load := func(spec string) ([]domain.JSONEqAssertion, error) {
+		t.Helper()
+		content := []byte(`
+name: JSONEq
+requests:
+  - name: user
+    method: GET
+    url: "http://x"
+    assert:
+      json_eq: ` + spec + `
+`)
+		if err := os.WriteFile(p, content, 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		c, err := NewLoader().LoadCollection(p)
+		if err != nil {
+			return nil, err
+		}
+		return c.Requests[0].Assert.JSONEq, nil
+	}
+
+	got, err := load(`{path: $.user, value: {id: 1, tags: [a]}, ignore: ["$.at"], ignore_order: true, tolerance: 0.5}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []domain.JSONEqAssertion{{
+		Path:        "$.user",
+		Value:       map[string]any{"id": 1, "tags": []any{"a"}},
+		Ignore:      []string{"$.at"},
+		IgnoreOrder: true,
+		Tolerance:   0.5,
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("json_eq = %#v, want %#v", got, want)
+	}
+
+	got, err = load(`[{file: fixtures/user.json}, {value: null}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].File == nil || *got[0].File != filepath.Join(tmp, "fixtures", "user.json") || got[1].Value != nil {
+		t.Fatalf("json_eq = %+v", got)
+	}
+
+	for _, spec := range []string{"1", "{path: $.a}", "{value: 1, file: x.json}", "{value: 1, tolerance: -1}", "{value: 1, ignore_order: yes please}", "{value: 1, extra: 2}", "[{value: 1}, 2]"} {
+		if _, err := load(spec); err == nil || !strings.Contains(err.Error(), "assert.json_eq") {
+			t.Errorf("%s: expected an assert.json_eq error, got %v", spec, err)
+		}
+	}
+}
+
 func TestLoadCollection_EmptyBodyAssertionRejected(t *testing.T) {
 	tmp := t.TempDir()
 	p := filepath.Join(tmp, "b.yaml")
@@ -706,3 +969,279 @@ requests:
 		t.Fatal("expected error for body assertion without operators")
 	}
 }
+
+func TestLoadCollection_RecordsSourcePositions(t *testing.T) {
+	tmp := t.TempDir()
+	p := filepath.Join(tmp, "pos.yaml")
+
+	content := []byte(`name: Pos
+requests:
+  - name: first
+    method: GET
+    url: "https://x"
+  - name: second
+    method: GET
+    url: "https://x"
+    assert:
+      status: 200
+      jsonpath:
+        "$.items[0].id":
+          eq: 7
+    extract:
+      token: "$.token"
+`)
+	if err := os.WriteFile(p, content, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	c, err := NewLoader().LoadCollection(p)
+	if err != nil {
+		t.Fatalf("LoadCollection error: %v", err)
+	}
+
+	req := c.Requests[1]
+	if req.Pos.Line != 6 || req.Pos.Column != 5 {
+		t.Fatalf("request pos: got %+v", req.Pos)
+	}
+	checks := map[string]int{
+		"assert.status":                     10,
+		"assert.jsonpath[$.items[0].id]":    12,
+		"assert.jsonpath[$.items[0].id].eq": 13,
+		`extract["token"]`:                  15,
+		"headers":                           6,
+	}
+	for field, line := range checks {
+		if got := req.PosOf(field).Line; got != line {
+			t.Errorf("PosOf(%q) line = %d, want %d", field, got, line)
+		}
+	}
+}
+
+func TestLoadCollection_InvalidFieldCarriesPosition(t *testing.T) {
+	tmp := t.TempDir()
+	p := filepath.Join(tmp, "bad.yaml")
+
+	content := []byte(`name: Bad
+requests:
+  - name: ok
+    method: GET
+    url: "https://x"
+  - name: broken
+    method: FETCH
+    url: "https://x"
+`)
+	if err := os.WriteFile(p, content, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	_, err := NewLoader().LoadCollection(p)
+	var se *domain.SourceError
+	if !errors.As(err, &se) {
+		t.Fatalf("expected SourceError, got %v", err)
+	}
+	if se.Path != p || se.Pos.Line != 7 {
+		t.Fatalf("unexpected location: %s %+v", se.Path, se.Pos)
+	}
+	var oe *domain.OpError
+	if !errors.As(err, &oe) || oe.Kind != domain.KindInvalidConfig {
+		t.Fatalf("expected invalid config OpError, got %v", err)
+	}
+}
+
+func TestLoadCollection_DecodeErrorCarriesLine(t *testing.T) {
+	tmp := t.TempDir()
+	p := filepath.Join(tmp, "unknown.yaml")
+
+	content := []byte(`name: Bad
+requests:
+  - name: x
+    metod: GET
+`)
+	if err := os.WriteFile(p, content, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	_, err := NewLoader().LoadCollection(p)
+	var se *domain.SourceError
+	if !errors.As(err, &se) || se.Pos.Line != 4 {
+		t.Fatalf("expected SourceError at line 4, got %v", err)
+	}
+}
+
+func TestLoadCollection_MarkupQueries(t *testing.T) {
+	tmp := t.TempDir()
+	p := filepath.Join(tmp, "m.yaml")
+	content := []byte(`
+name: Markup
+requests:
+  - name: soap
+    method: POST
+    url: "http://x"
+    assert:
+      xpath:
+        "//Order/@status": { eq: paid }
+      css:
+        "a.next @href": { exists: true }
+    extract_xpath:
+      order_id: "//Order/@id"
+    extract_css:
+      csrf: 'input[name="csrf"] @value'
+`)
+	if err := os.WriteFile(p, content, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	c, err := NewLoader().LoadCollection(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := c.Requests[0]
+	if a, ok := r.Assert.XPath["//Order/@status"]; !ok || a.Eq == nil || *a.Eq != "paid" {
+		t.Fatalf("xpath assertion not loaded: %#v", r.Assert.XPath)
+	}
+	if a, ok := r.Assert.CSS["a.next @href"]; !ok || a.Exists == nil || !*a.Exists {
+		t.Fatalf("css assertion not loaded: %#v", r.Assert.CSS)
+	}
+	if r.ExtractXPath["order_id"] != "//Order/@id" || r.ExtractCSS["csrf"] != `input[name="csrf"] @value` {
+		t.Fatalf("extract blocks not loaded: %v %v", r.ExtractXPath, r.ExtractCSS)
+	}
+	if pos := r.PosOf("assert.xpath[//Order/@status].eq"); pos.Line != 9 {
+		t.Errorf("assert.xpath eq at line %d, want 9", pos.Line)
+	}
+	if pos := r.PosOf("extract_css[csrf]"); pos.Line != 15 {
+		t.Errorf("extract_css[csrf] at line %d, want 15", pos.Line)
+	}
+
+	content = []byte(`
+name: Markup
+requests:
+  - name: page
+    method: GET
+    url: "http://x"
+    assert:
+      css:
+        "h1": {}
+`)
+	if err := os.WriteFile(p, content, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := NewLoader().LoadCollection(p); err == nil || !strings.Contains(err.Error(), `assert.css["h1"]`) {
+		t.Fatalf("expected an empty css assertion error, got %v", err)
+	}
+}
+
+func TestLoadCollection_RegexCookieAndResponseExtraction(t *testing.T) {
+	tmp := t.TempDir()
+	p := filepath.Join(tmp, "x.yaml")
+	load := func(extract string) (domain.Collection, error) {
+		t.Helper()
+		content := []byte(`
+name: Extract
+requests:
+  - name: login
+    method: POST
+    url: "http://x"
+` + extract)
+		if err := os.WriteFile(p, content, 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		return NewLoader().LoadCollection(p)
+	}
+
+	c, err := load(`    extract_regex:
+      - pattern: 'Order #(?P<order_no>\d+)'
+      - pattern: '/orders/(?P<order_id>\d+)'
+        header: Location
+    extract_cookies:
+      session: sid
+      session_expires: "sid @expires"
+    extract_response:
+      landing: url
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := c.Requests[0]
+	wantRegex := []domain.ExtractRegexRule{
+		{Pattern: `Order #(?P<order_no>\d+)`},
+		{Pattern: `/orders/(?P<order_id>\d+)`, Header: "Location"},
+	}
+	if !reflect.DeepEqual(r.ExtractRegex, wantRegex) {
+		t.Fatalf("extract_regex = %#v", r.ExtractRegex)
+	}
+	if r.ExtractCookies["session_expires"] != "sid @expires" || r.ExtractResponse["landing"] != "url" {
+		t.Fatalf("extract blocks not loaded: %v %v", r.ExtractCookies, r.ExtractResponse)
+	}
+	if pos := r.PosOf("extract_regex[1]"); pos.Line != 9 {
+		t.Errorf("extract_regex[1] at line %d, want 9", pos.Line)
+	}
+
+	errs := []struct{ extract, want string }{
+		{"    extract_regex:\n      - pattern: 'id=(\\d+)'\n", "requests[0].extract_regex[0]: pattern has no named capture group"},
+		{"    extract_regex:\n      - header: Location\n", "requests[0].extract_regex[0]: pattern cannot be empty"},
+		{"    extract_cookies:\n      s: \"sid @size\"\n", `requests[0].extract_cookies["s"]: unknown cookie attribute "size"`},
+		{"    extract_response:\n      code: status_code\n", `requests[0].extract_response["code"]: unknown source "status_code"`},
+	}
+	for _, tc := range errs {
+		if _, err := load(tc.extract); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("expected %q, got %v", tc.want, err)
+		}
+	}
+}
+
+func TestLoadCollection_ExtractOptions(t *testing.T) {
+	tmp := t.TempDir()
+	p := filepath.Join(tmp, "o.yaml")
+	load := func(extract string) (domain.Collection, error) {
+		t.Helper()
+		content := []byte(`
+name: Options
+requests:
+  - name: list
+    method: GET
+    url: "http://x"
+    extract:
+` + extract)
+		if err := os.WriteFile(p, content, 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		return NewLoader().LoadCollection(p)
+	}
+
+	c, err := load(`      token: $.token
+      items: { path: $.items, type: json }
+      cursor: { path: $.next, optional: true }
+      tags: { path: $.tags, type: json, default: [] }
+      limit: { path: $.limit, default: 50 }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := c.Requests[0]
+	wantExtract := domain.ExtractSpec{"token": "$.token", "items": "$.items", "cursor": "$.next", "tags": "$.tags", "limit": "$.limit"}
+	if !reflect.DeepEqual(r.Extract, wantExtract) {
+		t.Fatalf("extract = %v", r.Extract)
+	}
+	empty, fifty := "[]", "50"
+	wantOpts := map[string]domain.ExtractOptions{
+		"items":  {Type: domain.VarJSON},
+		"cursor": {Optional: true},
+		"tags":   {Type: domain.VarJSON, Default: &empty},
+		"limit":  {Default: &fifty},
+	}
+	if !reflect.DeepEqual(r.ExtractOptions, wantOpts) {
+		t.Fatalf("extract options = %#v", r.ExtractOptions)
+	}
+
+	errs := []struct{ extract, want string }{
+		{"      items: { type: json }\n", `requests[0].extract["items"]: extract path is required`},
+		{"      items: { path: $.items, type: integer }\n", `extract type: unknown type "integer"`},
+		{"      items: { path: $.items, required: true }\n", `unknown extract field "required"`},
+		{"      items: { path: $.items, default: [1] }\n", "default must be a scalar unless type is json"},
+		{"      items: [1]\n", "extract must be a JSONPath or a mapping with path"},
+	}
+	for _, tc := range errs {
+		if _, err := load(tc.extract); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("expected %q, got %v", tc.want, err)
+		}
+	}
+}
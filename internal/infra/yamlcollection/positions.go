@@ -0,0 +1,122 @@
+package yamlcollection
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// positions indexes the fields of a collection document by path, using the
+// same paths as validation errors: "name", "requests[2]",
+// "requests[2].assert.jsonpath[$.id].eq". Keys of user-defined maps (vars,
+// headers, jsonpath, …) are bracketed; json bodies, inline schemas and
+// json_eq values are not indexed.
+type positions map[string]domain.SourcePos
+
+// bracketedKeys are the maps whose keys are user data rather than fields.
+var bracketedKeys = map[string]bool{
+	"vars":             true,
+	"headers":          true,
+	"form":             true,
+	"jsonpath":         true,
+	"xpath":            true,
+	"css":              true,
+	"extract":          true,
+	"extract_headers":  true,
+	"extract_xpath":    true,
+	"extract_css":      true,
+	"extract_cookies":  true,
+	"extract_response": true,
+}
+
+var opaqueKeys = map[string]bool{"json": true, "schema_inline": true, "value": true}
+
+func indexPositions(b []byte) positions {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil || len(doc.Content) == 0 {
+		return nil
+	}
+	p := positions{}
+	p.walk("", doc.Content[0], false)
+	return p
+}
+
+func (p positions) walk(path string, n *yaml.Node, bracketed bool) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			child := k.Value
+			switch {
+			case bracketed:
+				child = path + "[" + k.Value + "]"
+			case path != "":
+				child = path + "." + k.Value
+			}
+			p[child] = nodePos(k)
+			if bracketed {
+				p.walk(child, v, false)
+			} else if !opaqueKeys[k.Value] {
+				p.walk(child, v, bracketedKeys[k.Value])
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			child := fmt.Sprintf("%s[%d]", path, i)
+			p[child] = nodePos(item)
+			p.walk(child, item, false)
+		}
+	}
+}
+
+// request returns the position of requests[i] and its fields relative to it.
+func (p positions) request(i int) (domain.SourcePos, map[string]domain.SourcePos) {
+	prefix := fmt.Sprintf("requests[%d]", i)
+	pos, ok := p[prefix]
+	if !ok {
+		return domain.SourcePos{}, nil
+	}
+	fields := map[string]domain.SourcePos{}
+	for k, v := range p {
+		if len(k) > len(prefix)+1 && k[:len(prefix)] == prefix && k[len(prefix)] == '.' {
+			fields[k[len(prefix)+1:]] = v
+		}
+	}
+	return pos, fields
+}
+
+// invalidField is the package-level invalidField, located in the file when
+// the field (or a parent) is indexed.
+func (p positions) invalidField(path, field, msg string) error {
+	err := invalidField(path, field, msg)
+	if at, ok := domain.LookupPos(p, field); ok {
+		return &domain.SourceError{Path: path, Pos: at, Err: err}
+	}
+	return err
+}
+
+func nodePos(n *yaml.Node) domain.SourcePos {
+	return domain.SourcePos{Line: n.Line, Column: n.Column}
+}
+
+var yamlErrLine = regexp.MustCompile(`\bline (\d+)\b`)
+
+// decodeErrorPos extracts the first line number yaml.v3 reports in a decode
+// error; it does not expose positions in a structured way.
+func decodeErrorPos(err error) domain.SourcePos {
+	var te *yaml.TypeError
+	msg := err.Error()
+	if errors.As(err, &te) && len(te.Errors) > 0 {
+		msg = te.Errors[0]
+	}
+	m := yamlErrLine.FindStringSubmatch(msg)
+	if m == nil {
+		return domain.SourcePos{}
+	}
+	line, _ := strconv.Atoi(m[1])
+	return domain.SourcePos{Line: line}
+}
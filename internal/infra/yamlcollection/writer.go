@@ -30,8 +30,57 @@ type writeRequest struct {
 }
 
 type writeAssertions struct {
-	Status  *int                             `yaml:"status,omitempty"`
-	Headers map[string]yamlJSONPathAssertion `yaml:"headers,omitempty"`
+	// Status is a single code or a list, mirroring yamlAssertions.Status.
+	Status          any                            `yaml:"status,omitempty"`
+	MaxMS           *int                           `yaml:"max_ms,omitempty"`
+	MaxMSRegression *int                           `yaml:"max_ms_regression,omitempty"`
+	JSONPath        map[string]writeValueAssertion `yaml:"jsonpath,omitempty"`
+	Headers         map[string]writeValueAssertion `yaml:"headers,omitempty"`
+}
+
+type writeValueAssertion struct {
+	Exists      *bool    `yaml:"exists,omitempty"`
+	Eq          *string  `yaml:"eq,omitempty"`
+	Contains    *string  `yaml:"contains,omitempty"`
+	Matches     *string  `yaml:"matches,omitempty"`
+	NotMatches  *string  `yaml:"not_matches,omitempty"`
+	Gt          *float64 `yaml:"gt,omitempty"`
+	Lt          *float64 `yaml:"lt,omitempty"`
+	Gte         *float64 `yaml:"gte,omitempty"`
+	Lte         *float64 `yaml:"lte,omitempty"`
+	NotEq       *string  `yaml:"not_eq,omitempty"`
+	NotContains *string  `yaml:"not_contains,omitempty"`
+	Len         *int     `yaml:"len,omitempty"`
+	Type        *string  `yaml:"type,omitempty"`
+	In          []string `yaml:"in,omitempty"`
+	NotIn       []string `yaml:"not_in,omitempty"`
+	StartsWith  *string  `yaml:"starts_with,omitempty"`
+	EndsWith    *string  `yaml:"ends_with,omitempty"`
+	EqCI        *string  `yaml:"eq_ci,omitempty"`
+
+	Format *string      `yaml:"format,omitempty"`
+	Before *string      `yaml:"before,omitempty"`
+	After  *string      `yaml:"after,omitempty"`
+	Within *string      `yaml:"within,omitempty"`
+	Approx *writeApprox `yaml:"approx,omitempty"`
+
+	All         *writeValueAssertion `yaml:"all,omitempty"`
+	Any         *writeValueAssertion `yaml:"any,omitempty"`
+	None        *writeValueAssertion `yaml:"none,omitempty"`
+	Count       *writeCountAssertion `yaml:"count,omitempty"`
+	Unique      *bool                `yaml:"unique,omitempty"`
+	Sorted      *string              `yaml:"sorted,omitempty"`
+	ContainsAll []string             `yaml:"contains_all,omitempty"`
+}
+
+type writeApprox struct {
+	Value     float64 `yaml:"value"`
+	Tolerance float64 `yaml:"tolerance"`
+}
+
+type writeCountAssertion struct {
+	Where               *writeValueAssertion `yaml:"where,omitempty"`
+	writeValueAssertion `yaml:",inline"`
 }
 
 // MarshalCollection serializes a domain.Collection into YAML bytes.
@@ -74,23 +123,7 @@ func MarshalCollection(col domain.Collection) ([]byte, error) {
 			wr.Tags = r.Tags
 		}
 
-		if r.Assert.Status != nil || len(r.Assert.Headers) > 0 {
-			wa := &writeAssertions{Status: r.Assert.Status}
-			if len(r.Assert.Headers) > 0 {
-				wa.Headers = make(map[string]yamlJSONPathAssertion, len(r.Assert.Headers))
-				for k, v := range r.Assert.Headers {
-					wa.Headers[k] = yamlJSONPathAssertion{
-						Exists:      v.Exists,
-						Eq:          v.Eq,
-						Contains:    v.Contains,
-						Matches:     v.Matches,
-						Gt:          v.Gt,
-						Lt:          v.Lt,
-						NotEq:       v.NotEq,
-						NotContains: v.NotContains,
-					}
-				}
-			}
+		if wa := marshalAssertions(r.Assert); wa != nil {
 			wr.Assert = wa
 		}
 
@@ -106,3 +139,86 @@ func MarshalCollection(col domain.Collection) ([]byte, error) {
 
 	return yaml.Marshal(wc)
 }
+
+// marshalAssertions returns nil when there is nothing the writer supports,
+// so the assert block is omitted entirely.
+func marshalAssertions(a domain.AssertionsSpec) *writeAssertions {
+	wa := &writeAssertions{
+		MaxMS:           a.MaxLatencyMS,
+		MaxMSRegression: a.MaxMSRegression,
+		JSONPath:        marshalValueAssertions(a.JSONPath),
+		Headers:         marshalValueAssertions(a.Headers),
+	}
+	switch {
+	case a.Status != nil:
+		wa.Status = *a.Status
+	case len(a.StatusIn) > 0:
+		wa.Status = a.StatusIn
+	}
+	if wa.Status == nil && wa.MaxMS == nil && wa.MaxMSRegression == nil && wa.JSONPath == nil && wa.Headers == nil {
+		return nil
+	}
+	return wa
+}
+
+func marshalValueAssertions(in map[string]domain.ValueAssertion) map[string]writeValueAssertion {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]writeValueAssertion, len(in))
+	for k, v := range in {
+		out[k] = marshalValueAssertion(v)
+	}
+	return out
+}
+
+func marshalValueAssertion(v domain.ValueAssertion) writeValueAssertion {
+	out := writeValueAssertion{
+		Exists:      v.Exists,
+		Eq:          v.Eq,
+		Contains:    v.Contains,
+		Matches:     v.Matches,
+		NotMatches:  v.NotMatches,
+		Gt:          v.Gt,
+		Lt:          v.Lt,
+		Gte:         v.Gte,
+		Lte:         v.Lte,
+		NotEq:       v.NotEq,
+		NotContains: v.NotContains,
+		Len:         v.Len,
+		Type:        v.Type,
+		In:          v.In,
+		NotIn:       v.NotIn,
+		StartsWith:  v.StartsWith,
+		EndsWith:    v.EndsWith,
+		EqCI:        v.EqCI,
+		Format:      v.Format,
+		Before:      v.Before,
+		After:       v.After,
+		Within:      v.Within,
+		All:         marshalNestedAssertion(v.All),
+		Any:         marshalNestedAssertion(v.Any),
+		None:        marshalNestedAssertion(v.None),
+		Unique:      v.Unique,
+		Sorted:      v.Sorted,
+		ContainsAll: v.ContainsAll,
+	}
+	if v.Approx != nil {
+		out.Approx = &writeApprox{Value: v.Approx.Value, Tolerance: v.Approx.Tolerance}
+	}
+	if v.Count != nil {
+		out.Count = &writeCountAssertion{
+			Where:               marshalNestedAssertion(v.Count.Where),
+			writeValueAssertion: marshalValueAssertion(v.Count.ValueAssertion),
+		}
+	}
+	return out
+}
+
+func marshalNestedAssertion(v *domain.ValueAssertion) *writeValueAssertion {
+	if v == nil {
+		return nil
+	}
+	out := marshalValueAssertion(*v)
+	return &out
+}
@@ -3,6 +3,7 @@ package yamlcollection
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -439,6 +440,96 @@ func TestMarshalCollection_ExtractHeaders_RoundTrip(t *testing.T) {
 	}
 }
 
+func TestMarshalCollection_Assertions_RoundTrip(t *testing.T) {
+	eq := "alice"
+	gte := 1.0
+	n := 2
+	maxMS := 500
+	regression := 50
+	typ := "integer"
+	prefix := "usr_"
+	format, after := "rfc3339", "now-1h"
+	col := domain.Collection{
+		Name: "assertions",
+		Requests: []domain.RequestSpec{
+			{
+				Name:   "list",
+				Method: domain.MethodGet,
+				URL:    "https://example.com/users",
+				Assert: domain.AssertionsSpec{
+					StatusIn:        []int{200, 204},
+					MaxLatencyMS:    &maxMS,
+					MaxMSRegression: &regression,
+					JSONPath: map[string]domain.ValueAssertion{
+						"$.users[0].name": {Eq: &eq},
+						"$.count":         {Gte: &gte},
+						"$.users":         {Len: &n},
+						"$.users[0].id":   {Type: &typ, StartsWith: &prefix},
+						"$.status":        {In: []string{"active", "pending"}},
+						"$.users[*].role": {All: &domain.ValueAssertion{NotEq: &eq}, Count: &domain.CountAssertion{Where: &domain.ValueAssertion{Eq: &eq}, ValueAssertion: domain.ValueAssertion{Gte: &gte}}},
+						"$.created_at":    {Format: &format, After: &after},
+						"$.ratio":         {Approx: &domain.ApproxAssertion{Value: 0.5, Tolerance: 0}},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := MarshalCollection(col)
+	if err != nil {
+		t.Fatalf("MarshalCollection failed: %v", err)
+	}
+	if strings.Contains(string(b), "null") {
+		t.Errorf("unset operators should be omitted, got:\n%s", string(b))
+	}
+
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "assert.yaml")
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := NewLoader().LoadCollection(path)
+	if err != nil {
+		t.Fatalf("LoadCollection: %v\nYAML:\n%s", err, string(b))
+	}
+	a := loaded.Requests[0].Assert
+	if len(a.StatusIn) != 2 || a.StatusIn[1] != 204 {
+		t.Errorf("status list: got %v", a.StatusIn)
+	}
+	if a.MaxLatencyMS == nil || *a.MaxLatencyMS != 500 {
+		t.Errorf("max_ms: got %v", a.MaxLatencyMS)
+	}
+	if a.MaxMSRegression == nil || *a.MaxMSRegression != 50 {
+		t.Errorf("max_ms_regression: got %v", a.MaxMSRegression)
+	}
+	if got := a.JSONPath["$.users[0].name"].Eq; got == nil || *got != "alice" {
+		t.Errorf("jsonpath eq: got %v", got)
+	}
+	if got := a.JSONPath["$.count"].Gte; got == nil || *got != 1 {
+		t.Errorf("jsonpath gte: got %v", got)
+	}
+	if got := a.JSONPath["$.users"].Len; got == nil || *got != 2 {
+		t.Errorf("jsonpath len: got %v", got)
+	}
+	if got := a.JSONPath["$.users[0].id"]; got.Type == nil || *got.Type != "integer" || got.StartsWith == nil || *got.StartsWith != "usr_" {
+		t.Errorf("jsonpath type/starts_with: got %+v", got)
+	}
+	if got := a.JSONPath["$.status"].In; !reflect.DeepEqual(got, []string{"active", "pending"}) {
+		t.Errorf("jsonpath in: got %v", got)
+	}
+	if got := a.JSONPath["$.users[*].role"]; got.All == nil || *got.All.NotEq != "alice" ||
+		got.Count == nil || got.Count.Where == nil || *got.Count.Where.Eq != "alice" || *got.Count.Gte != 1 {
+		t.Errorf("jsonpath all/count: got %+v", got)
+	}
+	if got := a.JSONPath["$.created_at"]; got.Format == nil || *got.Format != "rfc3339" || got.After == nil || *got.After != "now-1h" {
+		t.Errorf("jsonpath format/after: got %+v", got)
+	}
+	if got := a.JSONPath["$.ratio"].Approx; got == nil || got.Value != 0.5 || got.Tolerance != 0 {
+		t.Errorf("jsonpath approx (zero tolerance kept): got %+v", got)
+	}
+}
+
 func TestMarshalCollection_BodyNone_NoBodyKeysInYAML(t *testing.T) {
 	col := domain.Collection{
 		Name: "no-body",
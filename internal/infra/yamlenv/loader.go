@@ -103,6 +103,7 @@ func (l *Loader) LoadEnvironment(nameOrPath string) (domain.Environment, error)
 		SchemaVersion: env.SchemaVersion,
 		Name:          envName,
 		Vars:          merged,
+		Types:         env.Types,
 		SecretValues:  secretValues,
 	}, nil
 }
@@ -152,11 +153,13 @@ func (l *Loader) ListEnvironments(_ context.Context, root string) ([]domain.Envi
 type yamlEnv struct {
 	SchemaVersion *int              `yaml:"schema_version"`
 	Vars          map[string]string `yaml:"vars"`
+	Types         map[string]string `yaml:"types"`
 }
 
 type parsedEnv struct {
 	SchemaVersion int
 	Vars          domain.Vars
+	Types         map[string]domain.VarType
 }
 
 func readEnv(path string) (parsedEnv, error) {
@@ -188,6 +191,23 @@ func readEnv(path string) (parsedEnv, error) {
 		y.Vars = map[string]string{}
 	}
 
+	var types map[string]domain.VarType
+	for name, typ := range y.Types {
+		t, err := domain.ParseVarType(typ)
+		if err != nil {
+			return parsedEnv{}, &domain.OpError{
+				Op:   "yamlenv.load",
+				Kind: domain.KindInvalidConfig,
+				Path: path,
+				Err:  fmt.Errorf("%w: types.%s: %w", domain.ErrInvalidConfig, name, err),
+			}
+		}
+		if types == nil {
+			types = map[string]domain.VarType{}
+		}
+		types[name] = t
+	}
+
 	sv := 1
 	if y.SchemaVersion != nil {
 		sv = *y.SchemaVersion
@@ -196,6 +216,7 @@ func readEnv(path string) (parsedEnv, error) {
 	return parsedEnv{
 		SchemaVersion: sv,
 		Vars:          domain.Vars(y.Vars),
+		Types:         types,
 	}, nil
 }
 
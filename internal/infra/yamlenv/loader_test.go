@@ -148,6 +148,38 @@ func TestLoadEnvironment_UnknownFieldRejected(t *testing.T) {
 	}
 }
 
+func TestLoadEnvironment_Types(t *testing.T) {
+	tmp := t.TempDir()
+	envDir := filepath.Join(tmp, "env")
+	if err := os.MkdirAll(envDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	content := []byte("vars:\n  limit: \"10\"\ntypes:\n  limit: int\n  filters: json\n")
+	if err := os.WriteFile(filepath.Join(envDir, "dev.yaml"), content, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(envDir, "bad.yaml"), []byte("types:\n  limit: integer\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	l := NewLoader(tmp)
+	env, err := l.LoadEnvironment("dev")
+	if err != nil {
+		t.Fatalf("LoadEnvironment error: %v", err)
+	}
+	if env.Types["limit"] != domain.VarInt || env.Types["filters"] != domain.VarJSON {
+		t.Fatalf("unexpected types: %v", env.Types)
+	}
+	if len(env.Vars) != 1 {
+		t.Fatalf("expected types to stay out of vars, got %v", env.Vars)
+	}
+
+	_, err = l.LoadEnvironment("bad")
+	if !domain.IsKind(err, domain.KindInvalidConfig) || !strings.Contains(err.Error(), `types.limit: unknown type "integer"`) {
+		t.Fatalf("expected an unknown type error, got %v", err)
+	}
+}
+
 func TestLoadEnvironment_NotFoundMentionsBothExtensions(t *testing.T) {
 	tmp := t.TempDir()
 	if err := os.MkdirAll(filepath.Join(tmp, "env"), 0o755); err != nil {
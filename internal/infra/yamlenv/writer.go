@@ -0,0 +1,80 @@
+package yamlenv
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+type writeEnv struct {
+	SchemaVersion int               `yaml:"schema_version"`
+	Vars          map[string]string `yaml:"vars"`
+}
+
+// MarshalVars serializes vars in the env file format. The secrets file uses
+// the same layout, so this is used for both.
+func MarshalVars(vars domain.Vars) ([]byte, error) {
+	we := writeEnv{SchemaVersion: 1, Vars: map[string]string(vars)}
+	if we.Vars == nil {
+		we.Vars = map[string]string{}
+	}
+	return yaml.Marshal(we)
+}
+
+var envNameUnsafe = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// NameFromTitle turns a display name from another tool ("Staging (EU)") into
+// a file-friendly env name ("staging-eu"). It returns "" when nothing usable
+// is left.
+func NameFromTitle(title string) string {
+	s := strings.ToLower(strings.TrimSpace(title))
+	s = envNameUnsafe.ReplaceAllString(s, "-")
+	return strings.Trim(s, "-.")
+}
+
+// EnvPath returns the file an environment name maps to (<env_dir>/<name>.yaml).
+func (l *Loader) EnvPath(name string) string {
+	return filepath.Join(l.rootDir, l.envDir, name+".yaml")
+}
+
+// SecretsPath returns the secrets file shared by every environment in the
+// env directory.
+func (l *Loader) SecretsPath() string {
+	return filepath.Join(l.rootDir, l.envDir, l.secretsFile)
+}
+
+// ReadSecrets returns the current contents of the secrets file, or empty
+// vars when it does not exist yet.
+func (l *Loader) ReadSecrets() (domain.Vars, error) {
+	return readVarsOptional(l.SecretsPath())
+}
+
+// WriteEnvironment writes vars to <env_dir>/<name>.yaml, replacing any
+// existing file. Callers decide whether overwriting is allowed.
+func (l *Loader) WriteEnvironment(name string, vars domain.Vars) error {
+	return writeVarsFile(l.EnvPath(name), vars, 0o644)
+}
+
+// WriteSecrets replaces the secrets file. It is created owner-only, like the
+// one `lynix init` scaffolds.
+func (l *Loader) WriteSecrets(vars domain.Vars) error {
+	return writeVarsFile(l.SecretsPath(), vars, 0o600)
+}
+
+func writeVarsFile(path string, vars domain.Vars, mode os.FileMode) error {
+	b, err := MarshalVars(vars)
+	if err != nil {
+		return &domain.OpError{Op: "yamlenv.write", Kind: domain.KindInvalidConfig, Path: path, Err: err}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return &domain.OpError{Op: "yamlenv.write", Kind: domain.KindExecution, Path: path, Err: err}
+	}
+	if err := os.WriteFile(path, b, mode); err != nil {
+		return &domain.OpError{Op: "yamlenv.write", Kind: domain.KindExecution, Path: path, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package yamlenv
+
+import (
+	"os"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+func TestWriteEnvironment_RoundTripWithSecrets(t *testing.T) {
+	root := t.TempDir()
+	l := NewLoader(root)
+
+	if err := l.WriteEnvironment("dev", domain.Vars{"base_url": "https://dev.example.com"}); err != nil {
+		t.Fatalf("WriteEnvironment: %v", err)
+	}
+	if err := l.WriteSecrets(domain.Vars{"token": "abc123"}); err != nil {
+		t.Fatalf("WriteSecrets: %v", err)
+	}
+
+	env, err := l.LoadEnvironment("dev")
+	if err != nil {
+		t.Fatalf("LoadEnvironment: %v", err)
+	}
+	if env.Vars["base_url"] != "https://dev.example.com" || env.Vars["token"] != "abc123" {
+		t.Errorf("vars: got %v", env.Vars)
+	}
+	if len(env.SecretValues) != 1 || env.SecretValues[0] != "abc123" {
+		t.Errorf("secret values: got %v", env.SecretValues)
+	}
+
+	info, err := os.Stat(l.SecretsPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("secrets file mode: got %o, want 600", perm)
+	}
+}
+
+func TestReadSecrets_MissingFileIsEmpty(t *testing.T) {
+	got, err := NewLoader(t.TempDir()).ReadSecrets()
+	if err != nil {
+		t.Fatalf("ReadSecrets: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected empty vars, got %v", got)
+	}
+}
+
+func TestMarshalVars_NilWritesEmptyMap(t *testing.T) {
+	b, err := MarshalVars(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "schema_version: 1\nvars: {}\n" {
+		t.Errorf("got %q", string(b))
+	}
+}
+
+func TestNameFromTitle(t *testing.T) {
+	cases := map[string]string{
+		"Dev":                "dev",
+		"Staging (EU)":       "staging-eu",
+		"  prod.v2  ":        "prod.v2",
+		"QA / Smoke Tests!!": "qa-smoke-tests",
+		"!!!":                "",
+	}
+	for in, want := range cases {
+		if got := NameFromTitle(in); got != want {
+			t.Errorf("NameFromTitle(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 )
 
 // RequestRunner executes a single request with a resolved variable set.
+// types holds the types of the variables that are not strings; it may be nil.
 type RequestRunner interface {
-	Run(ctx context.Context, req domain.RequestSpec, vars domain.Vars) (domain.RequestResult, error)
+	Run(ctx context.Context, req domain.RequestSpec, vars domain.Vars, types map[string]domain.VarType) (domain.RequestResult, error)
 }
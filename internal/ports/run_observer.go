@@ -0,0 +1,33 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// RunObserver receives progress events while a collection runs. In parallel
+// mode request events arrive from several goroutines, so implementations must
+// be safe for concurrent use. Index is the request's position in the
+// (filtered) collection.
+type RunObserver interface {
+	RunStarted(run domain.RunResult, requests []domain.RequestSpec)
+	RequestStarted(index int, req domain.RequestSpec)
+	RequestFinished(index int, result domain.RequestResult)
+	RunFinished(run domain.RunResult, runID string, err error)
+}
+
+type requestIndexKey struct{}
+
+// ContextWithRequestIndex tags the context a request runs with its observer
+// index, so runner wrappers can match the request to its events even when
+// two requests share a name.
+func ContextWithRequestIndex(ctx context.Context, index int) context.Context {
+	return context.WithValue(ctx, requestIndexKey{}, index)
+}
+
+// RequestIndexFromContext returns the index set by ContextWithRequestIndex.
+func RequestIndexFromContext(ctx context.Context) (int, bool) {
+	i, ok := ctx.Value(requestIndexKey{}).(int)
+	return i, ok
+}
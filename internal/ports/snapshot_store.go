@@ -0,0 +1,9 @@
+package ports
+
+// SnapshotStore keeps the response bodies that snapshot assertions compare
+// against. Snapshots belong to a collection and are identified by name.
+type SnapshotStore interface {
+	// LoadSnapshot returns the stored body; found is false when there is none.
+	LoadSnapshot(collectionPath, name string) (body []byte, found bool, err error)
+	SaveSnapshot(collectionPath, name string, body []byte) error
+}
@@ -0,0 +1,203 @@
+package assert
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// arrayChecks evaluates the quantifiers and set-shape operators, which need
+// the value to be an array.
+func arrayChecks(ctx checkContext, a domain.ValueAssertion, val any, getErr error) []domain.AssertionResult {
+	var out []domain.AssertionResult
+	check := func(op string, run func(items []any) (bool, string)) {
+		name := ctx.kind + "." + op
+		if getErr != nil {
+			out = append(out, domain.AssertionResult{
+				Name:    name,
+				Passed:  false,
+				Message: fmt.Sprintf("%s %q: %v", ctx.kind, ctx.key, getErr),
+			})
+			return
+		}
+		items, ok := val.([]any)
+		if !ok {
+			out = append(out, domain.AssertionResult{
+				Name:    name,
+				Passed:  false,
+				Message: fmt.Sprintf("%s %q: %s needs an array, got %s", ctx.kind, ctx.key, op, jsonType(val)),
+			})
+			return
+		}
+		passed, msg := run(items)
+		if passed {
+			msg = fmt.Sprintf("%s %q %s", ctx.kind, ctx.key, msg)
+		} else {
+			msg = fmt.Sprintf("%s %q: %s", ctx.kind, ctx.key, msg)
+		}
+		out = append(out, domain.AssertionResult{Name: name, Passed: passed, Message: msg})
+	}
+
+	if a.All != nil {
+		check("all", func(items []any) (bool, string) {
+			for i, item := range items {
+				if ok, why := elementPasses(ctx, *a.All, i, item); !ok {
+					return false, fmt.Sprintf("element %d failed: %s", i, why)
+				}
+			}
+			return true, fmt.Sprintf("all %d element(s) pass", len(items))
+		})
+	}
+	if a.Any != nil {
+		check("any", func(items []any) (bool, string) {
+			for i, item := range items {
+				if ok, _ := elementPasses(ctx, *a.Any, i, item); ok {
+					return true, fmt.Sprintf("element %d passes", i)
+				}
+			}
+			return false, fmt.Sprintf("none of %d element(s) pass", len(items))
+		})
+	}
+	if a.None != nil {
+		check("none", func(items []any) (bool, string) {
+			for i, item := range items {
+				if ok, _ := elementPasses(ctx, *a.None, i, item); ok {
+					return false, fmt.Sprintf("element %d passes, expected none to", i)
+				}
+			}
+			return true, fmt.Sprintf("has none of %d element(s) passing", len(items))
+		})
+	}
+	if c := a.Count; c != nil {
+		check("count", func(items []any) (bool, string) {
+			n := 0
+			for i, item := range items {
+				if c.Where == nil {
+					n++
+				} else if ok, _ := elementPasses(ctx, *c.Where, i, item); ok {
+					n++
+				}
+			}
+			countCtx := checkContext{kind: ctx.kind, key: ctx.key + " count"}
+			for _, r := range valueChecks(countCtx, c.ValueAssertion, float64(n), nil) {
+				if !r.Passed {
+					return false, "count: " + trimContext(countCtx, r.Message)
+				}
+			}
+			return true, fmt.Sprintf("count is %d", n)
+		})
+	}
+	if a.Unique != nil {
+		check("unique", func(items []any) (bool, string) {
+			seen := make(map[string]int, len(items))
+			for i, item := range items {
+				key := canonicalJSON(item)
+				if j, dup := seen[key]; dup {
+					if *a.Unique {
+						return false, fmt.Sprintf("element %d duplicates element %d (%s)", i, j, truncateForMessage(key, 60))
+					}
+					return true, fmt.Sprintf("has duplicates (elements %d and %d)", j, i)
+				}
+				seen[key] = i
+			}
+			if *a.Unique {
+				return true, fmt.Sprintf("has %d unique element(s)", len(items))
+			}
+			return false, "expected duplicates, all elements are unique"
+		})
+	}
+	if a.Sorted != nil {
+		check("sorted", func(items []any) (bool, string) {
+			return checkSorted(items, *a.Sorted)
+		})
+	}
+	if len(a.ContainsAll) > 0 {
+		check("contains_all", func(items []any) (bool, string) {
+			have := make(map[string]bool, len(items))
+			for _, item := range items {
+				if s, err := valueToString(item); err == nil {
+					have[s] = true
+				}
+			}
+			var missing []string
+			for _, want := range a.ContainsAll {
+				if !have[want] {
+					missing = append(missing, want)
+				}
+			}
+			if len(missing) > 0 {
+				return false, fmt.Sprintf("missing %s", quoteList(missing))
+			}
+			return true, fmt.Sprintf("contains all of %s", quoteList(a.ContainsAll))
+		})
+	}
+	return out
+}
+
+// elementPasses applies a nested assertion to one element. The reason of a
+// failure is the first failing check's message, without its context prefix.
+func elementPasses(ctx checkContext, a domain.ValueAssertion, i int, item any) (bool, string) {
+	elemCtx := checkContext{kind: ctx.kind, key: fmt.Sprintf("%s[%d]", ctx.key, i)}
+	for _, r := range valueChecks(elemCtx, a, item, nil) {
+		if !r.Passed {
+			return false, trimContext(elemCtx, r.Message)
+		}
+	}
+	return true, ""
+}
+
+func trimContext(ctx checkContext, msg string) string {
+	msg = strings.TrimPrefix(msg, fmt.Sprintf("%s %q: ", ctx.kind, ctx.key))
+	return strings.TrimPrefix(msg, fmt.Sprintf("%s %q ", ctx.kind, ctx.key))
+}
+
+// checkSorted accepts arrays of numbers or of strings; equal neighbours are
+// in order either way.
+func checkSorted(items []any, order string) (bool, string) {
+	var less func(a, b any) bool
+	switch {
+	case allOfType(items, "number"):
+		less = func(a, b any) bool {
+			fa, _ := valueToFloat64(a)
+			fb, _ := valueToFloat64(b)
+			return fa < fb
+		}
+	case allOfType(items, "string"):
+		less = func(a, b any) bool { return a.(string) < b.(string) }
+	default:
+		return false, "sorted needs all numbers or all strings"
+	}
+	for i := 1; i < len(items); i++ {
+		prev, cur := items[i-1], items[i]
+		if order == "desc" {
+			prev, cur = cur, prev
+		}
+		if less(cur, prev) {
+			return false, fmt.Sprintf("element %d (%s) is out of %s order after %s",
+				i, canonicalJSON(items[i]), order, canonicalJSON(items[i-1]))
+		}
+	}
+	return true, fmt.Sprintf("is sorted %s", order)
+}
+
+func allOfType(items []any, typ string) bool {
+	for _, item := range items {
+		t := jsonType(item)
+		if t != typ && !(typ == "number" && t == "integer") {
+			return false
+		}
+	}
+	return true
+}
+
+// canonicalJSON encodes v with sorted object keys, so equal values encode
+// alike.
+func canonicalJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
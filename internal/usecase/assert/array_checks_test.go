@@ -0,0 +1,98 @@
+package assert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+const itemsBody = `{"items":[
+	{"id":1,"status":"active","tags":["a"]},
+	{"id":2,"status":"active","tags":["a","b"]},
+	{"id":3,"status":"archived","tags":[]}
+]}`
+
+func TestArrayChecks_Quantifiers(t *testing.T) {
+	active := domain.ValueAssertion{Eq: strPtr("active")}
+	cases := []struct {
+		name string
+		a    domain.ValueAssertion
+		pass bool
+		msg  string
+	}{
+		{"jsonpath.all", domain.ValueAssertion{All: &active}, false, `element 2 failed: expected "active", got "archived"`},
+		{"jsonpath.all", domain.ValueAssertion{All: &domain.ValueAssertion{In: []string{"active", "archived"}}}, true, "all 3 element(s) pass"},
+		{"jsonpath.any", domain.ValueAssertion{Any: &domain.ValueAssertion{Eq: strPtr("archived")}}, true, "element 2 passes"},
+		{"jsonpath.any", domain.ValueAssertion{Any: &domain.ValueAssertion{Eq: strPtr("deleted")}}, false, "none of 3 element(s) pass"},
+		{"jsonpath.none", domain.ValueAssertion{None: &domain.ValueAssertion{Eq: strPtr("deleted")}}, true, ""},
+		{"jsonpath.none", domain.ValueAssertion{None: &active}, false, "element 0 passes, expected none to"},
+	}
+	for _, tc := range cases {
+		res := evalJSONPath(t, itemsBody, "$.items[*].status", tc.a)
+		if len(res) != 1 || res[0].Name != tc.name || res[0].Passed != tc.pass || !strings.Contains(res[0].Message, tc.msg) {
+			t.Errorf("%s: got %+v, want passed=%v with %q", tc.name, res, tc.pass, tc.msg)
+		}
+	}
+}
+
+func TestArrayChecks_NestedOnObjects(t *testing.T) {
+	a := domain.ValueAssertion{All: &domain.ValueAssertion{Type: strPtr("object")}}
+	if res := evalJSONPath(t, itemsBody, "$.items", a); !res[0].Passed {
+		t.Fatalf("all objects: %+v", res)
+	}
+
+	n := 1
+	a = domain.ValueAssertion{Any: &domain.ValueAssertion{Len: &n}}
+	if res := evalJSONPath(t, itemsBody, "$.items[*].tags", a); !res[0].Passed {
+		t.Fatalf("arrays of arrays: %+v", res)
+	}
+}
+
+func TestArrayChecks_Count(t *testing.T) {
+	two, three := 2.0, 3.0
+	cases := []struct {
+		c    domain.CountAssertion
+		pass bool
+		msg  string
+	}{
+		{domain.CountAssertion{ValueAssertion: domain.ValueAssertion{Gte: &three}}, true, "count is 3"},
+		{domain.CountAssertion{Where: &domain.ValueAssertion{Eq: strPtr("active")}, ValueAssertion: domain.ValueAssertion{Eq: strPtr("2")}}, true, "count is 2"},
+		{domain.CountAssertion{Where: &domain.ValueAssertion{Eq: strPtr("active")}, ValueAssertion: domain.ValueAssertion{Gt: &two}}, false, "count: expected > 2, got 2"},
+	}
+	for _, tc := range cases {
+		c := tc.c
+		res := evalJSONPath(t, itemsBody, "$.items[*].status", domain.ValueAssertion{Count: &c})
+		if len(res) != 1 || res[0].Name != "jsonpath.count" || res[0].Passed != tc.pass || !strings.Contains(res[0].Message, tc.msg) {
+			t.Errorf("count %+v: got %+v, want passed=%v with %q", tc.c, res, tc.pass, tc.msg)
+		}
+	}
+}
+
+func TestArrayChecks_SetShape(t *testing.T) {
+	yes, no := true, false
+	asc, desc := "asc", "desc"
+	cases := []struct {
+		expr string
+		a    domain.ValueAssertion
+		pass bool
+		msg  string
+	}{
+		{"$.items[*].id", domain.ValueAssertion{Unique: &yes}, true, ""},
+		{"$.items[*].status", domain.ValueAssertion{Unique: &yes}, false, "element 1 duplicates element 0"},
+		{"$.items[*].status", domain.ValueAssertion{Unique: &no}, true, ""},
+		{"$.items[*].id", domain.ValueAssertion{Sorted: &asc}, true, ""},
+		{"$.items[*].id", domain.ValueAssertion{Sorted: &desc}, false, "element 1 (2) is out of desc order after 1"},
+		{"$.items[*].status", domain.ValueAssertion{Sorted: &asc}, true, ""},
+		{"$.items", domain.ValueAssertion{Sorted: &asc}, false, "all numbers or all strings"},
+		{"$.items[*].id", domain.ValueAssertion{ContainsAll: []string{"3", "1"}}, true, ""},
+		{"$.items[*].status", domain.ValueAssertion{ContainsAll: []string{"active", "deleted"}}, false, `missing ["deleted"]`},
+		{"$.items[0].id", domain.ValueAssertion{Unique: &yes}, false, "unique needs an array, got integer"},
+	}
+	for _, tc := range cases {
+		res := evalJSONPath(t, itemsBody, tc.expr, tc.a)
+		if len(res) != 1 || res[0].Passed != tc.pass || !strings.Contains(res[0].Message, tc.msg) {
+			t.Errorf("%s %+v: got %+v, want passed=%v with %q", tc.expr, tc.a, res, tc.pass, tc.msg)
+		}
+	}
+}
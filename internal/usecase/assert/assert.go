@@ -41,8 +41,8 @@ func compilePattern(pattern string) (*regexp.Regexp, error) {
 // (e.g. JSONPath expression or header name) so the 8 check functions can produce
 // correctly-labelled results without hard-coding a single target.
 type checkContext struct {
-	kind string // "jsonpath" or "header"
-	key  string // JSONPath expression or header name
+	kind string // "jsonpath", "xpath", "css" or "header"
+	key  string // JSONPath/XPath expression, CSS selector or header name
 }
 
 // StatusIn passes when the observed status is one of the accepted codes.
@@ -95,6 +95,34 @@ func MaxLatency(maxMs int, latencyMs int64) domain.AssertionResult {
 	}
 }
 
+// LatencyRegression checks that latencyMs is at most budgetMs above the
+// baseline run's latency. hasBaseline is false when no baseline run (or no
+// completed request of the same name in it) exists; the check then passes.
+func LatencyRegression(budgetMs int, latencyMs, baselineMs int64, hasBaseline bool) domain.AssertionResult {
+	if !hasBaseline {
+		return domain.AssertionResult{
+			Name:    "max_ms_regression",
+			Passed:  true,
+			Message: "no baseline latency; skipped",
+		}
+	}
+
+	limit := baselineMs + int64(budgetMs)
+	if latencyMs <= limit {
+		return domain.AssertionResult{
+			Name:    "max_ms_regression",
+			Passed:  true,
+			Message: fmt.Sprintf("latency %dms <= %dms (baseline %dms + %dms)", latencyMs, limit, baselineMs, budgetMs),
+		}
+	}
+
+	return domain.AssertionResult{
+		Name:    "max_ms_regression",
+		Passed:  false,
+		Message: fmt.Sprintf("expected latency <= %dms (baseline %dms + %dms), got %dms", limit, baselineMs, budgetMs, latencyMs),
+	}
+}
+
 // Evaluate applies the assertions spec against the observed response data.
 // It parses JSON only if JSONPath assertions are present.
 // schemaBytes is the pre-loaded JSON Schema content (nil if no schema assertion).
@@ -120,6 +148,14 @@ func Evaluate(spec domain.AssertionsSpec, status int, latencyMs int64, body []by
 		out = append(out, SchemaValidate(schemaBytes, body, truncated))
 	}
 
+	for _, a := range spec.JSONEq {
+		out = append(out, JSONEq(a, body, truncated))
+	}
+
+	for _, a := range spec.Expr {
+		out = append(out, Expr(a, body, truncated))
+	}
+
 	if len(spec.JSONPath) > 0 {
 		doc, err := parseJSON(body)
 		if err != nil {
@@ -150,6 +186,13 @@ func Evaluate(spec domain.AssertionsSpec, status int, latencyMs int64, body []by
 		}
 	}
 
+	if len(spec.XPath) > 0 {
+		out = append(out, xpathChecks(spec.XPath, body, truncated)...)
+	}
+	if len(spec.CSS) > 0 {
+		out = append(out, cssChecks(spec.CSS, body, truncated)...)
+	}
+
 	for name, a := range spec.Headers {
 		ctx := checkContext{kind: "header", key: name}
 		val, found := lookupHeader(headers, name)
@@ -218,6 +261,45 @@ func valueChecks(ctx checkContext, a domain.ValueAssertion, val any, getErr erro
 	if a.Len != nil {
 		out = append(out, checkLen(ctx, val, getErr, *a.Len))
 	}
+	if a.Type != nil {
+		out = append(out, checkType(ctx, val, getErr, *a.Type))
+	}
+	if len(a.In) > 0 {
+		out = append(out, checkIn(ctx, val, getErr, a.In, false))
+	}
+	if len(a.NotIn) > 0 {
+		out = append(out, checkIn(ctx, val, getErr, a.NotIn, true))
+	}
+	if a.StartsWith != nil {
+		out = append(out, checkStartsWith(ctx, val, getErr, *a.StartsWith))
+	}
+	if a.EndsWith != nil {
+		out = append(out, checkEndsWith(ctx, val, getErr, *a.EndsWith))
+	}
+	if a.EqCI != nil {
+		out = append(out, checkEqCI(ctx, val, getErr, *a.EqCI))
+	}
+	var format string
+	if a.Format != nil {
+		format = *a.Format
+		out = append(out, checkFormat(ctx, val, getErr, format))
+	}
+	if a.Before != nil {
+		out = append(out, checkBeforeAfter(ctx, val, getErr, format, *a.Before, false))
+	}
+	if a.After != nil {
+		out = append(out, checkBeforeAfter(ctx, val, getErr, format, *a.After, true))
+	}
+	if a.Within != nil {
+		out = append(out, checkWithin(ctx, val, getErr, format, *a.Within))
+	}
+	if a.Approx != nil {
+		out = append(out, checkApprox(ctx, val, getErr, *a.Approx))
+	}
+	out = append(out, arrayChecks(ctx, a, val, getErr)...)
+	for i := range out {
+		out[i].Key = ctx.key
+	}
 	return out
 }
 
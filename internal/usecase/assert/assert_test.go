@@ -65,6 +65,32 @@ func TestMaxLatency_FailMessage(t *testing.T) {
 	}
 }
 
+// --- LatencyRegression ---
+
+func TestLatencyRegression_WithinBudget(t *testing.T) {
+	r := LatencyRegression(50, 150, 100, true)
+	if !r.Passed || r.Name != "max_ms_regression" {
+		t.Fatalf("expected a passing max_ms_regression, got %+v", r)
+	}
+}
+
+func TestLatencyRegression_FailMessage(t *testing.T) {
+	r := LatencyRegression(50, 151, 100, true)
+	if r.Passed {
+		t.Fatalf("expected fail")
+	}
+	if r.Message != "expected latency <= 150ms (baseline 100ms + 50ms), got 151ms" {
+		t.Fatalf("unexpected message: %q", r.Message)
+	}
+}
+
+func TestLatencyRegression_NoBaselineSkips(t *testing.T) {
+	r := LatencyRegression(0, 9999, 0, false)
+	if !r.Passed || !strings.Contains(r.Message, "skipped") {
+		t.Fatalf("expected a skipped pass without baseline, got %+v", r)
+	}
+}
+
 // --- Evaluate ---
 
 func TestEvaluate_NoAssertions(t *testing.T) {
@@ -0,0 +1,734 @@
+package assert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// An assert.expr expression is a small, side-effect free language:
+//
+//	expr    = or
+//	or      = and { "or" and }
+//	and     = not { "and" not }
+//	not     = "not" not | compare
+//	compare = sum [ ( "==" | "!=" | "<" | "<=" | ">" | ">=" ) sum ]
+//	sum     = product { ( "+" | "-" ) product }
+//	product = unary { ( "*" | "/" | "%" ) unary }
+//	unary   = "-" unary | primary
+//	primary = number | string | true | false | null | jsonpath | "{{" var "}}"
+//	        | "length" "(" expr ")" | "(" expr ")"
+//
+// A JSONPath starts with "$" and runs until whitespace, "(", ")", "," or an
+// operator outside brackets. Keys with such characters are bracketed:
+// $["x-id"].
+
+// ValidateExpr parses an assert.expr expression and compiles its JSONPath
+// references.
+func ValidateExpr(src string) error {
+	_, err := parseExpr(src)
+	return err
+}
+
+// Expr evaluates an assert.expr expression against the response body. The
+// expression must yield a boolean.
+func Expr(a domain.ExprAssertion, body []byte, truncated bool) domain.AssertionResult {
+	res := domain.AssertionResult{Name: "expr", Key: a.Source}
+	fail := func(msg string) domain.AssertionResult {
+		res.Message = fmt.Sprintf("expr %q: %s", a.Source, msg)
+		return res
+	}
+
+	root, err := parseExpr(a.Source)
+	if err != nil {
+		return fail(err.Error())
+	}
+	env := &exprEnv{vars: a.Vars, body: body, truncated: truncated}
+	v, err := root.eval(env)
+	if err != nil {
+		return fail(err.Error())
+	}
+	ok, isBool := v.(bool)
+	if !isBool {
+		return fail(fmt.Sprintf("expression must yield a boolean, got %s", jsonType(v)))
+	}
+	if !ok {
+		return fail("got " + explain(root, env))
+	}
+	res.Passed = true
+	res.Message = fmt.Sprintf("expr %q holds", a.Source)
+	return res
+}
+
+// exprEnv is what references resolve against. The body is parsed on the
+// first JSONPath reference.
+type exprEnv struct {
+	vars      domain.Vars
+	body      []byte
+	truncated bool
+
+	parsed bool
+	doc    any
+	docErr error
+}
+
+func (e *exprEnv) document() (any, error) {
+	if !e.parsed {
+		e.parsed = true
+		e.doc, e.docErr = parseJSON(e.body)
+		if e.docErr != nil {
+			e.docErr = errors.New("response body is not valid JSON")
+			if e.truncated {
+				e.docErr = errors.New("response body was truncated (>256KB) and is not valid JSON")
+			}
+		}
+	}
+	return e.doc, e.docErr
+}
+
+type exprNode interface {
+	eval(env *exprEnv) (any, error)
+	String() string
+}
+
+type litNode struct {
+	v    any
+	text string
+}
+
+func (n *litNode) eval(*exprEnv) (any, error) { return n.v, nil }
+func (n *litNode) String() string             { return n.text }
+
+type pathNode struct {
+	path string
+	get  func(context.Context, any) (any, error)
+}
+
+func (n *pathNode) eval(env *exprEnv) (any, error) {
+	doc, err := env.document()
+	if err != nil {
+		return nil, err
+	}
+	v, err := n.get(context.Background(), doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.path, err)
+	}
+	return v, nil
+}
+
+func (n *pathNode) String() string { return n.path }
+
+type varNode struct{ name string }
+
+func (n *varNode) eval(env *exprEnv) (any, error) {
+	v, ok := env.vars[n.name]
+	if !ok {
+		return nil, fmt.Errorf("variable {{%s}} is not resolved", n.name)
+	}
+	return v, nil
+}
+
+func (n *varNode) String() string { return "{{" + n.name + "}}" }
+
+type notNode struct{ x exprNode }
+
+func (n *notNode) eval(env *exprEnv) (any, error) {
+	b, err := evalBool(n.x, env, "not")
+	if err != nil {
+		return nil, err
+	}
+	return !b, nil
+}
+
+func (n *notNode) String() string { return "not " + n.x.String() }
+
+type negNode struct{ x exprNode }
+
+func (n *negNode) eval(env *exprEnv) (any, error) {
+	v, err := n.x.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	f, err := exprNumber(v, "-")
+	if err != nil {
+		return nil, err
+	}
+	return -f, nil
+}
+
+func (n *negNode) String() string { return "-" + n.x.String() }
+
+type parenNode struct{ x exprNode }
+
+func (n *parenNode) eval(env *exprEnv) (any, error) { return n.x.eval(env) }
+func (n *parenNode) String() string                 { return "(" + n.x.String() + ")" }
+
+type lengthNode struct{ x exprNode }
+
+func (n *lengthNode) eval(env *exprEnv) (any, error) {
+	v, err := n.x.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch t := v.(type) {
+	case string:
+		return float64(utf8.RuneCountInString(t)), nil
+	case []any:
+		return float64(len(t)), nil
+	case map[string]any:
+		return float64(len(t)), nil
+	}
+	return nil, fmt.Errorf("length needs a string, array or object, got %s", jsonType(v))
+}
+
+func (n *lengthNode) String() string { return "length(" + n.x.String() + ")" }
+
+type binaryNode struct {
+	op   string
+	l, r exprNode
+}
+
+func (n *binaryNode) String() string { return n.l.String() + " " + n.op + " " + n.r.String() }
+
+func (n *binaryNode) eval(env *exprEnv) (any, error) {
+	switch n.op {
+	case "and", "or":
+		l, err := evalBool(n.l, env, n.op)
+		if err != nil {
+			return nil, err
+		}
+		if l == (n.op == "or") {
+			return l, nil
+		}
+		return evalBool(n.r, env, n.op)
+	}
+
+	l, err := n.l.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	r, err := n.r.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return exprEqual(l, r), nil
+	case "!=":
+		return !exprEqual(l, r), nil
+	case "<", "<=", ">", ">=":
+		c, err := exprCompare(l, r, n.op)
+		if err != nil {
+			return nil, err
+		}
+		switch n.op {
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		}
+		return c >= 0, nil
+	}
+
+	a, err := exprNumber(l, n.op)
+	if err != nil {
+		return nil, err
+	}
+	b, err := exprNumber(r, n.op)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	}
+	if b == 0 {
+		return nil, fmt.Errorf("division by zero in %s", n)
+	}
+	if n.op == "/" {
+		return a / b, nil
+	}
+	return math.Mod(a, b), nil
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
+
+func evalBool(n exprNode, env *exprEnv, op string) (bool, error) {
+	v, err := n.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s needs booleans, got %s (%s)", op, jsonType(v), n)
+	}
+	return b, nil
+}
+
+// explain describes why a false expression is false: the operand values of
+// the failing comparison(s).
+func explain(n exprNode, env *exprEnv) string {
+	switch t := n.(type) {
+	case *binaryNode:
+		switch {
+		case isComparison(t.op):
+			l, _ := t.l.eval(env)
+			r, _ := t.r.eval(env)
+			return fmt.Sprintf("%s %s %s", exprValue(l), t.op, exprValue(r))
+		case t.op == "and":
+			if b, _ := evalBool(t.l, env, t.op); !b {
+				return explain(t.l, env)
+			}
+			return explain(t.r, env)
+		case t.op == "or":
+			return explain(t.l, env) + " and " + explain(t.r, env)
+		}
+	case *parenNode:
+		return explain(t.x, env)
+	case *notNode:
+		return "not (" + t.x.String() + ")"
+	}
+	return n.String() + " is false"
+}
+
+func exprValue(v any) string {
+	if f, ok := v.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return truncateForMessage(canonicalJSON(v), 60)
+}
+
+// scalar unwraps the single match of a wildcard or filter, like eq does.
+func scalar(v any) any {
+	if arr, ok := v.([]any); ok && len(arr) == 1 {
+		return arr[0]
+	}
+	return v
+}
+
+// exprNumber converts an arithmetic operand. Strings holding numbers (such
+// as extracted vars) count as numbers.
+func exprNumber(v any, op string) (float64, error) {
+	switch t := scalar(v).(type) {
+	case float64:
+		return t, nil
+	case json.Number:
+		return t.Float64()
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(t), 64); err == nil {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("%s needs numbers, got %s %s", op, jsonType(v), exprValue(v))
+}
+
+// exprEqual compares deeply. A string equals a number or boolean when it
+// spells it, so {{count}} == $.count works with extracted (string) vars.
+func exprEqual(l, r any) bool {
+	if _, ok := l.([]any); !ok {
+		r = scalar(r)
+	}
+	if _, ok := r.([]any); !ok {
+		l = scalar(l)
+	}
+	if s, ok := l.(string); ok {
+		l, r = r, s
+	}
+	if s, ok := r.(string); ok {
+		switch t := l.(type) {
+		case float64, json.Number:
+			f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+			lf, _ := exprNumber(t, "==")
+			return err == nil && f == lf
+		case bool:
+			return s == strconv.FormatBool(t)
+		}
+	}
+	if lf, ok := numberOf(l); ok {
+		rf, ok := numberOf(r)
+		return ok && lf == rf
+	}
+	return canonicalJSON(l) == canonicalJSON(r)
+}
+
+func numberOf(v any) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case json.Number:
+		f, err := t.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// exprCompare orders two numbers or two strings; a string and a number
+// compare as numbers when the string is numeric.
+func exprCompare(l, r any, op string) (int, error) {
+	l, r = scalar(l), scalar(r)
+	ls, lStr := l.(string)
+	rs, rStr := r.(string)
+	if lStr && rStr {
+		return strings.Compare(ls, rs), nil
+	}
+	a, errA := exprNumber(l, op)
+	b, errB := exprNumber(r, op)
+	if errA != nil || errB != nil {
+		return 0, fmt.Errorf("%s needs two numbers or two strings, got %s %s and %s %s",
+			op, jsonType(l), exprValue(l), jsonType(r), exprValue(r))
+	}
+	switch {
+	case a < b:
+		return -1, nil
+	case a > b:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// --- parsing ---
+
+type exprTokenKind int
+
+const (
+	tokEOF exprTokenKind = iota
+	tokNumber
+	tokString
+	tokPath
+	tokVar
+	tokIdent
+	tokOp
+)
+
+type exprToken struct {
+	kind exprTokenKind
+	text string // source text; the name for vars, the value for strings
+	pos  int    // 1-based column
+}
+
+func (t exprToken) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+// pathStops are the characters that end a JSONPath outside brackets.
+const pathStops = " \t\r\n,()=!<>+-*/%"
+
+func lexExpr(src string) ([]exprToken, error) {
+	var toks []exprToken
+	for i := 0; i < len(src); {
+		c := src[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+			continue
+		case c == '$':
+			depth, quote := 0, byte(0)
+			for i < len(src) {
+				c := src[i]
+				if quote != 0 {
+					if c == '\\' {
+						i++
+					} else if c == quote {
+						quote = 0
+					}
+					i++
+					continue
+				}
+				if depth == 0 && strings.IndexByte(pathStops, c) >= 0 {
+					break
+				}
+				switch c {
+				case '[', '(':
+					depth++
+				case ']', ')':
+					depth--
+				case '\'', '"':
+					if depth > 0 {
+						quote = c
+					}
+				}
+				i++
+			}
+			if depth != 0 || quote != 0 {
+				return nil, fmt.Errorf("unbalanced brackets in jsonpath at column %d", start+1)
+			}
+			toks = append(toks, exprToken{kind: tokPath, text: src[start:i], pos: start + 1})
+			continue
+		case strings.HasPrefix(src[i:], "{{"):
+			end := strings.Index(src[i+2:], "}}")
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed {{ at column %d", start+1)
+			}
+			name := strings.TrimSpace(src[i+2 : i+2+end])
+			if name == "" {
+				return nil, fmt.Errorf("empty {{}} at column %d", start+1)
+			}
+			i += end + 4
+			toks = append(toks, exprToken{kind: tokVar, text: name, pos: start + 1})
+			continue
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			for i < len(src) {
+				c := src[i]
+				if c >= '0' && c <= '9' || c == '.' || c == 'e' || c == 'E' ||
+					(c == '+' || c == '-') && (src[i-1] == 'e' || src[i-1] == 'E') {
+					i++
+					continue
+				}
+				break
+			}
+			if _, err := strconv.ParseFloat(src[start:i], 64); err != nil {
+				return nil, fmt.Errorf("invalid number %q at column %d", src[start:i], start+1)
+			}
+			toks = append(toks, exprToken{kind: tokNumber, text: src[start:i], pos: start + 1})
+			continue
+		case c == '"' || c == '\'':
+			i++
+			for i < len(src) && src[i] != c {
+				if src[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(src) {
+				return nil, fmt.Errorf("unterminated string at column %d", start+1)
+			}
+			i++
+			lit := src[start:i]
+			if c == '\'' {
+				lit = `"` + strings.ReplaceAll(strings.ReplaceAll(lit[1:len(lit)-1], `\'`, `'`), `"`, `\"`) + `"`
+			}
+			s, err := strconv.Unquote(lit)
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %s at column %d", src[start:i], start+1)
+			}
+			toks = append(toks, exprToken{kind: tokString, text: s, pos: start + 1})
+			continue
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			for i < len(src) && (src[i] == '_' || src[i] >= 'a' && src[i] <= 'z' || src[i] >= 'A' && src[i] <= 'Z' || src[i] >= '0' && src[i] <= '9') {
+				i++
+			}
+			toks = append(toks, exprToken{kind: tokIdent, text: src[start:i], pos: start + 1})
+			continue
+		}
+		for _, op := range []string{"==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "%", "(", ")"} {
+			if strings.HasPrefix(src[i:], op) {
+				toks = append(toks, exprToken{kind: tokOp, text: op, pos: start + 1})
+				i += len(op)
+				break
+			}
+		}
+		if i == start {
+			switch c {
+			case '=':
+				return nil, fmt.Errorf("unexpected \"=\" at column %d (use == to compare)", start+1)
+			case '&', '|', '!':
+				return nil, fmt.Errorf("unexpected %q at column %d (use and, or, not)", c, start+1)
+			}
+			return nil, fmt.Errorf("unexpected %q at column %d", c, start+1)
+		}
+	}
+	return append(toks, exprToken{kind: tokEOF, pos: len(src) + 1}), nil
+}
+
+type exprParser struct {
+	toks []exprToken
+	i    int
+}
+
+func parseExpr(src string) (exprNode, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, errors.New("empty expression")
+	}
+	toks, err := lexExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{toks: toks}
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %s at column %d", t, t.pos)
+	}
+	return n, nil
+}
+
+func (p *exprParser) peek() exprToken { return p.toks[p.i] }
+
+func (p *exprParser) next() exprToken {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of the given operators or
+// keywords.
+func (p *exprParser) accept(kind exprTokenKind, texts ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != kind {
+		return "", false
+	}
+	for _, s := range texts {
+		if t.text == s {
+			p.i++
+			return s, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) or() (exprNode, error) {
+	return p.binary(p.and, tokIdent, "or")
+}
+
+func (p *exprParser) and() (exprNode, error) {
+	return p.binary(p.not, tokIdent, "and")
+}
+
+func (p *exprParser) not() (exprNode, error) {
+	if _, ok := p.accept(tokIdent, "not"); ok {
+		x, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{x: x}, nil
+	}
+	return p.compare()
+}
+
+func (p *exprParser) compare() (exprNode, error) {
+	l, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept(tokOp, "==", "!=", "<=", ">=", "<", ">")
+	if !ok {
+		return l, nil
+	}
+	r, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind == tokOp && isComparison(t.text) {
+		return nil, fmt.Errorf("comparisons cannot be chained (column %d); join them with and", t.pos)
+	}
+	return &binaryNode{op: op, l: l, r: r}, nil
+}
+
+func (p *exprParser) sum() (exprNode, error) {
+	return p.binary(p.product, tokOp, "+", "-")
+}
+
+func (p *exprParser) product() (exprNode, error) {
+	return p.binary(p.unary, tokOp, "*", "/", "%")
+}
+
+// binary parses a left-associative chain of operand (op operand)*.
+func (p *exprParser) binary(operand func() (exprNode, error), kind exprTokenKind, ops ...string) (exprNode, error) {
+	l, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(kind, ops...)
+		if !ok {
+			return l, nil
+		}
+		r, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryNode{op: op, l: l, r: r}
+	}
+}
+
+func (p *exprParser) unary() (exprNode, error) {
+	if _, ok := p.accept(tokOp, "-"); ok {
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &negNode{x: x}, nil
+	}
+	return p.primary()
+}
+
+func (p *exprParser) primary() (exprNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		f, _ := strconv.ParseFloat(t.text, 64)
+		return &litNode{v: f, text: t.text}, nil
+	case tokString:
+		return &litNode{v: t.text, text: strconv.Quote(t.text)}, nil
+	case tokVar:
+		return &varNode{name: t.text}, nil
+	case tokPath:
+		get, err := jsonpath.New(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jsonpath %q at column %d: %w", t.text, t.pos, err)
+		}
+		return &pathNode{path: t.text, get: get}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return &litNode{v: true, text: t.text}, nil
+		case "false":
+			return &litNode{v: false, text: t.text}, nil
+		case "null":
+			return &litNode{v: nil, text: t.text}, nil
+		case "length":
+			if _, ok := p.accept(tokOp, "("); !ok {
+				return nil, fmt.Errorf("expected ( after length at column %d", p.peek().pos)
+			}
+			x, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(tokOp, ")"); !ok {
+				return nil, fmt.Errorf("expected ) at column %d, got %s", p.peek().pos, p.peek())
+			}
+			return &lengthNode{x: x}, nil
+		}
+		return nil, fmt.Errorf("unknown name %q at column %d (JSONPaths start with $, variables are {{name}})", t.text, t.pos)
+	case tokOp:
+		if t.text == "(" {
+			x, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(tokOp, ")"); !ok {
+				return nil, fmt.Errorf("expected ) at column %d, got %s", p.peek().pos, p.peek())
+			}
+			return &parenNode{x: x}, nil
+		}
+	}
+	return nil, fmt.Errorf("expected a value at column %d, got %s", t.pos, t)
+}
@@ -0,0 +1,95 @@
+package assert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+const orderBody = `{
+	"total": 3, "count": 2, "start": 100, "end": 250, "status": "paid",
+	"price": 10.5, "qty": 2, "amount": 21,
+	"items": [{"id":1,"sku":"a"},{"id":2,"sku":"b"},{"id":3,"sku":"c"}],
+	"name": "Zoë", "x-id": 7, "created": "2026-03-01", "updated": "2026-03-02"
+}`
+
+func TestExpr(t *testing.T) {
+	cases := []struct {
+		src  string
+		vars domain.Vars
+		pass bool
+		msg  string
+	}{
+		{"$.total == length($.items)", nil, true, "holds"},
+		{"$.end > $.start", nil, true, ""},
+		{"$.end - $.start == 150", nil, true, ""},
+		{"$.price * $.qty == $.amount", nil, true, ""},
+		{"$.amount % 2 == 1 and $.qty / 2 == 1", nil, true, ""},
+		{"-$.start < 0", nil, true, ""},
+		{"($.start + $.end) / 2 == 175", nil, true, ""},
+		{`$.status == "paid" or $.status == 'refunded'`, nil, true, ""},
+		{"not ($.count >= $.total)", nil, true, ""},
+		{"length($.name) == 3", nil, true, ""},
+		{`$["x-id"] == 7`, nil, true, ""},
+		{"$.updated > $.created", nil, true, ""},
+		{"$.items[?(@.id == 2)].sku == 'b'", nil, true, ""},
+		{"$.status != null and true", nil, true, ""},
+		{"$.total == {{expected}}", domain.Vars{"expected": "3"}, true, ""},
+		{"{{order_id}} == $.items[0].id", domain.Vars{"order_id": "1"}, true, ""},
+
+		{"$.total == $.count", nil, false, "got 3 == 2"},
+		{"$.start < $.end and $.total == length($.items) - 1", nil, false, "got 3 == 2"},
+		{`$.status == "open" or $.count > 5`, nil, false, `got "paid" == "open" and 2 > 5`},
+		{"$.total", nil, false, "expression must yield a boolean, got integer"},
+		{"$.missing == 1", nil, false, "$.missing: unknown key missing"},
+		{"$.status > 1", nil, false, "> needs two numbers or two strings"},
+		{"$.total / 0 == 1", nil, false, "division by zero"},
+		{"$.total and true", nil, false, "and needs booleans, got integer ($.total)"},
+		{"$.total == {{expected}}", nil, false, "variable {{expected}} is not resolved"},
+	}
+	for _, tc := range cases {
+		r := Expr(domain.ExprAssertion{Source: tc.src, Vars: tc.vars}, []byte(orderBody), false)
+		if r.Name != "expr" || r.Key != tc.src || r.Passed != tc.pass || !strings.Contains(r.Message, tc.msg) {
+			t.Errorf("%s: got %+v, want passed=%v with %q", tc.src, r, tc.pass, tc.msg)
+		}
+	}
+}
+
+func TestExpr_InvalidBody(t *testing.T) {
+	r := Expr(domain.ExprAssertion{Source: "$.a == 1"}, []byte("<html>"), true)
+	if r.Passed || !strings.Contains(r.Message, "truncated") {
+		t.Fatalf("got %+v", r)
+	}
+	// Without JSONPath references the body is never parsed.
+	if r := Expr(domain.ExprAssertion{Source: "1 + 1 == 2"}, []byte("<html>"), false); !r.Passed {
+		t.Fatalf("literals only: %+v", r)
+	}
+}
+
+func TestValidateExpr(t *testing.T) {
+	for src, want := range map[string]string{
+		"":                         "empty expression",
+		"$.a = 1":                  `unexpected "=" at column 5 (use == to compare)`,
+		"$.a == 1 && $.b":          "use and, or, not",
+		"$.a == ":                  "expected a value at column 8, got end of expression",
+		"1 < $.a < 3":              "comparisons cannot be chained",
+		"size($.a) == 1":           `unknown name "size"`,
+		"length $.a":               "expected ( after length",
+		"($.a == 1":                "expected ) at column 10",
+		"$.a[0 == 1":               "unbalanced brackets",
+		"$.a == 'x":                "unterminated string",
+		"$.a == {{}}":              "empty {{}}",
+		"$.a == 1 2":               `unexpected "2" at column 10`,
+		"$.a..[ == 1":              "unbalanced brackets",
+		"$.a == $.b[?(@.x == 1)]]": "unbalanced brackets",
+		"$.a == $.b[?(@.x > 1)]":   "invalid jsonpath",
+	} {
+		if err := ValidateExpr(src); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected an error containing %q, got %v", src, want, err)
+		}
+	}
+	if err := ValidateExpr(`$.items[?(@.sku == "a")] != null and {{x}} >= 1e3`); err != nil {
+		t.Errorf("valid expression: %v", err)
+	}
+}
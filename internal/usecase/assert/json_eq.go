@@ -0,0 +1,78 @@
+package assert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/usecase/bodydiff"
+)
+
+// JSONEq compares the body, or the value its Path selects, with the
+// expected document. Ignore paths are relative to the compared value;
+// reported paths are relative to the body.
+func JSONEq(a domain.JSONEqAssertion, body []byte, truncated bool) domain.AssertionResult {
+	path := strings.TrimSpace(a.Path)
+	if path == "" {
+		path = "$"
+	}
+	res := domain.AssertionResult{Name: "json_eq", Key: path}
+	fail := func(format string, args ...any) domain.AssertionResult {
+		res.Message = fmt.Sprintf(format, args...)
+		return res
+	}
+
+	if truncated {
+		return fail("response body was truncated (>256KB), cannot compare")
+	}
+	doc, err := parseJSON(body)
+	if err != nil {
+		return fail("response body is not valid JSON")
+	}
+	if path != "$" {
+		eval, err := jsonpath.New(path)
+		if err != nil {
+			return fail("invalid jsonpath expression: %v", err)
+		}
+		if doc, err = eval(context.Background(), doc); err != nil {
+			return fail("path not found: %v", err)
+		}
+	}
+
+	expectedJSON, err := json.Marshal(a.Value)
+	if err != nil {
+		return fail("expected value is not JSON: %v", err)
+	}
+	expected, err := parseJSON(expectedJSON)
+	if err != nil {
+		return fail("expected value is not JSON: %v", err)
+	}
+	ignore, err := bodydiff.ParsePatterns(a.Ignore)
+	if err != nil {
+		return fail("ignore: %v", err)
+	}
+
+	opts := []bodydiff.Option{bodydiff.WithIgnore(ignore...), bodydiff.WithTolerance(a.Tolerance)}
+	if a.IgnoreOrder {
+		opts = append(opts, bodydiff.WithIgnoreOrder())
+	}
+	changes := bodydiff.Values(expected, doc, opts...)
+	if len(changes) == 0 {
+		res.Passed = true
+		res.Message = fmt.Sprintf("%s equals expected", path)
+		return res
+	}
+
+	paths := make([]string, 0, len(changes))
+	for i := range changes {
+		if path != "$" {
+			changes[i].Path = path + strings.TrimPrefix(changes[i].Path, "$")
+		}
+		paths = append(paths, changes[i].Path)
+	}
+	res.Diff = bodydiff.Entries(changes)
+	return fail("%d path(s) differ: %s", len(changes), strings.Join(paths, ", "))
+}
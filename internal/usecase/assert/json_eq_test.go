@@ -0,0 +1,88 @@
+package assert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+func TestJSONEq_WholeBody(t *testing.T) {
+	body := []byte(`{"id":1,"name":"alice","tags":["a","b"]}`)
+	expected := map[string]any{"id": 1, "name": "alice", "tags": []any{"a", "b"}}
+
+	res := JSONEq(domain.JSONEqAssertion{Value: expected}, body, false)
+	if !res.Passed || res.Name != "json_eq" || res.Key != "$" {
+		t.Fatalf("expected a pass, got %+v", res)
+	}
+}
+
+func TestJSONEq_ListsEveryDifference(t *testing.T) {
+	body := []byte(`{"data":{"id":2,"name":"bob","extra":true}}`)
+	expected := map[string]any{"id": 1, "name": "alice", "role": "admin"}
+
+	res := JSONEq(domain.JSONEqAssertion{Path: "$.data", Value: expected}, body, false)
+	if res.Passed {
+		t.Fatal("expected a failure")
+	}
+	want := []domain.DiffEntry{
+		{Path: "$.data.extra", Kind: "added", New: "true"},
+		{Path: "$.data.id", Kind: "changed", Old: "1", New: "2"},
+		{Path: "$.data.name", Kind: "changed", Old: `"alice"`, New: `"bob"`},
+		{Path: "$.data.role", Kind: "removed", Old: `"admin"`},
+	}
+	if len(res.Diff) != len(want) {
+		t.Fatalf("diff = %+v, want %+v", res.Diff, want)
+	}
+	for i := range want {
+		if res.Diff[i] != want[i] {
+			t.Errorf("diff[%d] = %+v, want %+v", i, res.Diff[i], want[i])
+		}
+	}
+	if res.Message != "4 path(s) differ: $.data.extra, $.data.id, $.data.name, $.data.role" {
+		t.Errorf("message = %q", res.Message)
+	}
+}
+
+func TestJSONEq_Options(t *testing.T) {
+	body := []byte(`{"items":[{"id":2,"at":"t2","price":10.001},{"id":1,"at":"t1","price":5}]}`)
+	a := domain.JSONEqAssertion{
+		Path: "$.items",
+		Value: []any{
+			map[string]any{"id": 1, "price": 5},
+			map[string]any{"id": 2, "price": 10},
+		},
+		Ignore:      []string{"$[*].at"},
+		IgnoreOrder: true,
+		Tolerance:   0.01,
+	}
+	if res := JSONEq(a, body, false); !res.Passed {
+		t.Fatalf("expected a pass, got %+v", res)
+	}
+
+	a.Tolerance = 0
+	if res := JSONEq(a, body, false); res.Passed || !strings.Contains(res.Message, "$.items[0].price") {
+		t.Fatalf("expected the price to differ, got %+v", res)
+	}
+}
+
+func TestJSONEq_Failures(t *testing.T) {
+	cases := []struct {
+		name      string
+		a         domain.JSONEqAssertion
+		body      string
+		truncated bool
+		want      string
+	}{
+		{"not json", domain.JSONEqAssertion{Value: 1}, "<html>", false, "not valid JSON"},
+		{"truncated", domain.JSONEqAssertion{Value: 1}, `{"a":`, true, "truncated"},
+		{"missing path", domain.JSONEqAssertion{Path: "$.nope", Value: 1}, `{}`, false, "path not found"},
+		{"bad ignore", domain.JSONEqAssertion{Value: 1, Ignore: []string{"nope"}}, `1`, false, "ignore"},
+	}
+	for _, tc := range cases {
+		res := JSONEq(tc.a, []byte(tc.body), tc.truncated)
+		if res.Passed || !strings.Contains(res.Message, tc.want) {
+			t.Errorf("%s: got %+v, want a failure mentioning %q", tc.name, res, tc.want)
+		}
+	}
+}
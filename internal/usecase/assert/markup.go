@@ -0,0 +1,48 @@
+package assert
+
+import (
+	"errors"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/usecase/markup"
+)
+
+// xpathChecks evaluates XPath assertions against an XML body.
+func xpathChecks(spec map[string]domain.ValueAssertion, body []byte, truncated bool) []domain.AssertionResult {
+	doc, err := markup.ParseXML(body)
+	return queryChecks("xpath", spec, err, truncated, func(expr string) (any, error) {
+		return markup.XPath(doc, expr)
+	})
+}
+
+// cssChecks evaluates CSS selector assertions against an HTML body.
+func cssChecks(spec map[string]domain.ValueAssertion, body []byte, truncated bool) []domain.AssertionResult {
+	doc, err := markup.ParseHTML(body)
+	return queryChecks("css", spec, err, truncated, func(selector string) (any, error) {
+		return markup.CSS(doc, selector)
+	})
+}
+
+// queryChecks runs the value checks of each expression on its query result.
+// Only "no match" counts as absence (exists: false); an unparsable body or
+// an invalid expression fails every operator.
+func queryChecks(kind string, spec map[string]domain.ValueAssertion, docErr error, truncated bool, query func(string) (any, error)) []domain.AssertionResult {
+	var out []domain.AssertionResult
+	for key, a := range spec {
+		ctx := checkContext{kind: kind, key: key}
+		if docErr != nil {
+			msg := docErr.Error()
+			if truncated {
+				msg = "response body was truncated (>256KB): " + msg
+			}
+			out = append(out, valueChecks(ctx, a, nil, &bodyError{msg: msg})...)
+			continue
+		}
+		val, err := query(key)
+		if err != nil && !errors.Is(err, markup.ErrNoMatch) {
+			err = &bodyError{msg: err.Error()}
+		}
+		out = append(out, valueChecks(ctx, a, val, err)...)
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package assert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+func TestXPathChecks(t *testing.T) {
+	body := `<order id="42"><item>Pen</item><item>Ink</item></order>`
+	spec := domain.AssertionsSpec{XPath: map[string]domain.ValueAssertion{
+		"/order/@id":    {Eq: strPtr("42")},
+		"count(//item)": {Gte: float64Ptr(2)},
+		"//item":        {Len: intPtr2(2), ContainsAll: []string{"Ink"}},
+		"//refund":      {Exists: boolPtr(false)},
+	}}
+	for _, r := range Evaluate(spec, 200, 0, []byte(body), nil, nil, false) {
+		if !r.Passed {
+			t.Errorf("%s failed: %s", r.Name, r.Message)
+		}
+	}
+}
+
+func TestXPathChecks_InvalidXMLFailsEveryOperator(t *testing.T) {
+	spec := domain.AssertionsSpec{XPath: map[string]domain.ValueAssertion{
+		"//refund": {Exists: boolPtr(false)},
+	}}
+	res := Evaluate(spec, 200, 0, []byte(`{"not":"xml"`), nil, nil, true)
+	if len(res) != 1 || res[0].Passed || res[0].Name != "xpath.exists" {
+		t.Fatalf("expected one failed xpath.exists, got %+v", res)
+	}
+	if !strings.Contains(res[0].Message, "truncated") || !strings.Contains(res[0].Message, "not valid XML") {
+		t.Fatalf("unexpected message: %s", res[0].Message)
+	}
+}
+
+func TestCSSChecks(t *testing.T) {
+	body := `<html><head><title>Orders</title></head><body>
+		<h1 class="title">  Your   orders </h1>
+		<a class="next" href="/orders?page=2">Next</a>
+	</body></html>`
+	spec := domain.AssertionsSpec{CSS: map[string]domain.ValueAssertion{
+		"h1.title":     {Eq: strPtr("Your orders")},
+		"a.next @href": {StartsWith: strPtr("/orders")},
+		"a.prev":       {Exists: boolPtr(false)},
+	}}
+	for _, r := range Evaluate(spec, 200, 0, []byte(body), nil, nil, false) {
+		if !r.Passed {
+			t.Errorf("%s failed: %s", r.Name, r.Message)
+		}
+	}
+
+	spec = domain.AssertionsSpec{CSS: map[string]domain.ValueAssertion{"title": {Eq: strPtr("Home")}}}
+	res := Evaluate(spec, 200, 0, []byte(body), nil, nil, false)
+	if len(res) != 1 || res[0].Passed || res[0].Name != "css.eq" || !strings.Contains(res[0].Message, `css "title"`) {
+		t.Fatalf("expected a failed css.eq, got %+v", res)
+	}
+}
@@ -0,0 +1,126 @@
+package assert
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// now is the clock of relative time expressions; tests replace it.
+var now = time.Now
+
+// checkTime runs a date operator: the value is parsed as a timestamp in
+// format (auto-detected when empty), then pass decides.
+func checkTime(ctx checkContext, op string, val any, getErr error, format string, pass func(t time.Time) (bool, string)) domain.AssertionResult {
+	name := ctx.kind + "." + op
+	fail := func(msg string) domain.AssertionResult {
+		return domain.AssertionResult{
+			Name:    name,
+			Passed:  false,
+			Message: fmt.Sprintf("%s %q: %s", ctx.kind, ctx.key, msg),
+		}
+	}
+	if getErr != nil {
+		return fail(getErr.Error())
+	}
+	s, err := valueToString(val)
+	if err != nil {
+		return fail(err.Error())
+	}
+	t, err := domain.ParseTime(s, format)
+	if err != nil {
+		return fail(err.Error())
+	}
+	ok, msg := pass(t)
+	if !ok {
+		return fail(msg)
+	}
+	return domain.AssertionResult{
+		Name:    name,
+		Passed:  true,
+		Message: fmt.Sprintf("%s %q %s", ctx.kind, ctx.key, msg),
+	}
+}
+
+func checkFormat(ctx checkContext, val any, getErr error, format string) domain.AssertionResult {
+	return checkTime(ctx, "format", val, getErr, format, func(time.Time) (bool, string) {
+		return true, fmt.Sprintf("is a %s timestamp", format)
+	})
+}
+
+func checkBeforeAfter(ctx checkContext, val any, getErr error, format, expr string, after bool) domain.AssertionResult {
+	op := "before"
+	if after {
+		op = "after"
+	}
+	return checkTime(ctx, op, val, getErr, format, func(t time.Time) (bool, string) {
+		ref, err := domain.ParseTimeExpr(expr, format, now())
+		if err != nil {
+			return false, err.Error()
+		}
+		ok := t.Before(ref)
+		if after {
+			ok = t.After(ref)
+		}
+		if !ok {
+			return false, fmt.Sprintf("expected %s %s, got %s", op, formatTime(ref), formatTime(t))
+		}
+		return true, fmt.Sprintf("%s is %s %s", formatTime(t), op, formatTime(ref))
+	})
+}
+
+func checkWithin(ctx checkContext, val any, getErr error, format, within string) domain.AssertionResult {
+	return checkTime(ctx, "within", val, getErr, format, func(t time.Time) (bool, string) {
+		window, expr, err := domain.ParseWithin(within)
+		if err != nil {
+			return false, err.Error()
+		}
+		ref, err := domain.ParseTimeExpr(expr, format, now())
+		if err != nil {
+			return false, err.Error()
+		}
+		off := t.Sub(ref).Abs()
+		if off > window {
+			return false, fmt.Sprintf("expected within %s of %s, got %s (off by %s)",
+				window, formatTime(ref), formatTime(t), off.Round(time.Millisecond))
+		}
+		return true, fmt.Sprintf("%s is within %s of %s", formatTime(t), window, formatTime(ref))
+	})
+}
+
+func checkApprox(ctx checkContext, val any, getErr error, approx domain.ApproxAssertion) domain.AssertionResult {
+	name := ctx.kind + ".approx"
+	if getErr != nil {
+		return domain.AssertionResult{
+			Name:    name,
+			Passed:  false,
+			Message: fmt.Sprintf("%s %q: %v", ctx.kind, ctx.key, getErr),
+		}
+	}
+	f, err := valueToFloat64(val)
+	if err != nil {
+		return domain.AssertionResult{
+			Name:    name,
+			Passed:  false,
+			Message: fmt.Sprintf("%s %q: %v", ctx.kind, ctx.key, err),
+		}
+	}
+	if math.Abs(f-approx.Value) <= approx.Tolerance {
+		return domain.AssertionResult{
+			Name:    name,
+			Passed:  true,
+			Message: fmt.Sprintf("%s %q: %v ≈ %v (±%v)", ctx.kind, ctx.key, f, approx.Value, approx.Tolerance),
+		}
+	}
+	return domain.AssertionResult{
+		Name:    name,
+		Passed:  false,
+		Message: fmt.Sprintf("%s %q: expected %v ±%v, got %v", ctx.kind, ctx.key, approx.Value, approx.Tolerance, f),
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
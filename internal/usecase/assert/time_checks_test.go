@@ -0,0 +1,67 @@
+package assert
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+func fixNow(t *testing.T, at time.Time) {
+	t.Helper()
+	prev := now
+	now = func() time.Time { return at }
+	t.Cleanup(func() { now = prev })
+}
+
+func TestTimeChecks(t *testing.T) {
+	fixNow(t, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	body := `{"created":"2026-03-01T11:58:00Z","expires":1772452800,"day":"01/03/2026"}`
+	cases := []struct {
+		name string
+		expr string
+		a    domain.ValueAssertion
+		pass bool
+		msg  string
+	}{
+		{"jsonpath.format", "$.created", domain.ValueAssertion{Format: strPtr("rfc3339")}, true, "is a rfc3339 timestamp"},
+		{"jsonpath.format", "$.expires", domain.ValueAssertion{Format: strPtr("rfc3339")}, false, `"1772452800" is not a rfc3339 timestamp`},
+		{"jsonpath.before", "$.created", domain.ValueAssertion{Before: strPtr("now")}, true, ""},
+		{"jsonpath.after", "$.created", domain.ValueAssertion{After: strPtr("now-1m")}, false, "expected after 2026-03-01T11:59:00Z, got 2026-03-01T11:58:00Z"},
+		{"jsonpath.within", "$.created", domain.ValueAssertion{Within: strPtr("5m")}, true, "is within 5m0s of 2026-03-01T12:00:00Z"},
+		{"jsonpath.within", "$.created", domain.ValueAssertion{Within: strPtr("1m")}, false, "(off by 2m0s)"},
+		{"jsonpath.after", "$.expires", domain.ValueAssertion{After: strPtr("now+1d")}, false, ""},
+		{"jsonpath.within", "$.expires", domain.ValueAssertion{Within: strPtr("1h of 2026-03-02T12:30:00Z")}, true, ""},
+		{"jsonpath.before", "$.day", domain.ValueAssertion{Format: strPtr("02/01/2006"), Before: strPtr("2026-03-02")}, true, ""},
+		{"jsonpath.before", "$.created", domain.ValueAssertion{Before: strPtr("{{deadline}}")}, false, "not a recognized timestamp"},
+	}
+	for _, tc := range cases {
+		res := evalJSONPath(t, body, tc.expr, tc.a)
+		r := res[len(res)-1]
+		if r.Name != tc.name || r.Passed != tc.pass || !strings.Contains(r.Message, tc.msg) {
+			t.Errorf("%s on %s: got %+v, want passed=%v with %q", tc.name, tc.expr, res, tc.pass, tc.msg)
+		}
+	}
+}
+
+func TestApproxCheck(t *testing.T) {
+	body := `{"pi":3.14159,"name":"pi"}`
+	cases := []struct {
+		expr string
+		a    domain.ApproxAssertion
+		pass bool
+		msg  string
+	}{
+		{"$.pi", domain.ApproxAssertion{Value: 3.14, Tolerance: 0.01}, true, ""},
+		{"$.pi", domain.ApproxAssertion{Value: 3.14, Tolerance: 0.001}, false, "expected 3.14 ±0.001, got 3.14159"},
+		{"$.name", domain.ApproxAssertion{Value: 3.14, Tolerance: 1}, false, ""},
+	}
+	for _, tc := range cases {
+		approx := tc.a
+		res := evalJSONPath(t, body, tc.expr, domain.ValueAssertion{Approx: &approx})
+		if len(res) != 1 || res[0].Name != "jsonpath.approx" || res[0].Passed != tc.pass || !strings.Contains(res[0].Message, tc.msg) {
+			t.Errorf("approx %+v on %s: got %+v, want passed=%v with %q", tc.a, tc.expr, res, tc.pass, tc.msg)
+		}
+	}
+}
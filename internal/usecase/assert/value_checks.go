@@ -0,0 +1,143 @@
+package assert
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// checkString runs a string operator: the value is converted like eq does,
+// then pass decides. okMsg and failMsg receive the converted value.
+func checkString(ctx checkContext, op string, val any, getErr error, pass func(s string) bool, okMsg, failMsg func(s string) string) domain.AssertionResult {
+	name := ctx.kind + "." + op
+	if getErr != nil {
+		return domain.AssertionResult{
+			Name:    name,
+			Passed:  false,
+			Message: fmt.Sprintf("%s %q: %v", ctx.kind, ctx.key, getErr),
+		}
+	}
+	s, err := valueToString(val)
+	if err != nil {
+		return domain.AssertionResult{
+			Name:    name,
+			Passed:  false,
+			Message: fmt.Sprintf("%s %q: %v", ctx.kind, ctx.key, err),
+		}
+	}
+	if pass(s) {
+		return domain.AssertionResult{
+			Name:    name,
+			Passed:  true,
+			Message: fmt.Sprintf("%s %q %s", ctx.kind, ctx.key, okMsg(s)),
+		}
+	}
+	return domain.AssertionResult{
+		Name:    name,
+		Passed:  false,
+		Message: fmt.Sprintf("%s %q: %s", ctx.kind, ctx.key, failMsg(s)),
+	}
+}
+
+func checkIn(ctx checkContext, val any, getErr error, set []string, negate bool) domain.AssertionResult {
+	op, verb := "in", "is one of"
+	if negate {
+		op, verb = "not_in", "is none of"
+	}
+	return checkString(ctx, op, val, getErr,
+		func(s string) bool { return slices.Contains(set, s) != negate },
+		func(s string) string { return fmt.Sprintf("%s %s", verb, quoteList(set)) },
+		func(s string) string {
+			if negate {
+				return fmt.Sprintf("expected none of %s, got %q", quoteList(set), s)
+			}
+			return fmt.Sprintf("expected one of %s, got %q", quoteList(set), s)
+		})
+}
+
+func checkStartsWith(ctx checkContext, val any, getErr error, prefix string) domain.AssertionResult {
+	return checkString(ctx, "starts_with", val, getErr,
+		func(s string) bool { return strings.HasPrefix(s, prefix) },
+		func(string) string { return fmt.Sprintf("starts with %q", prefix) },
+		func(s string) string { return fmt.Sprintf("%q does not start with %q", s, prefix) })
+}
+
+func checkEndsWith(ctx checkContext, val any, getErr error, suffix string) domain.AssertionResult {
+	return checkString(ctx, "ends_with", val, getErr,
+		func(s string) bool { return strings.HasSuffix(s, suffix) },
+		func(string) string { return fmt.Sprintf("ends with %q", suffix) },
+		func(s string) string { return fmt.Sprintf("%q does not end with %q", s, suffix) })
+}
+
+func checkEqCI(ctx checkContext, val any, getErr error, expected string) domain.AssertionResult {
+	return checkString(ctx, "eq_ci", val, getErr,
+		func(s string) bool { return strings.EqualFold(s, expected) },
+		func(string) string { return fmt.Sprintf("eq %q (ignoring case)", expected) },
+		func(s string) string { return fmt.Sprintf("expected %q (ignoring case), got %q", expected, s) })
+}
+
+// checkType asserts the JSON type of the value itself; unlike eq, a
+// one-element array is an array, not its element.
+func checkType(ctx checkContext, val any, getErr error, expected string) domain.AssertionResult {
+	name := ctx.kind + ".type"
+	if getErr != nil {
+		return domain.AssertionResult{
+			Name:    name,
+			Passed:  false,
+			Message: fmt.Sprintf("%s %q: %v", ctx.kind, ctx.key, getErr),
+		}
+	}
+	got := jsonType(val)
+	if got == expected || expected == "number" && got == "integer" {
+		return domain.AssertionResult{
+			Name:    name,
+			Passed:  true,
+			Message: fmt.Sprintf("%s %q is %s", ctx.kind, ctx.key, expected),
+		}
+	}
+	return domain.AssertionResult{
+		Name:    name,
+		Passed:  false,
+		Message: fmt.Sprintf("%s %q: expected %s, got %s", ctx.kind, ctx.key, expected, got),
+	}
+}
+
+// jsonType names the JSON type of a decoded value. Whole numbers are
+// "integer", which the "number" type accepts too.
+func jsonType(val any) string {
+	switch v := val.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case json.Number:
+		if !strings.ContainsAny(v.String(), ".eE") {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", val)
+}
+
+func quoteList(set []string) string {
+	parts := make([]string, len(set))
+	for i, s := range set {
+		parts[i] = fmt.Sprintf("%q", s)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
@@ -0,0 +1,95 @@
+package assert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+func evalJSONPath(t *testing.T, body, expr string, a domain.ValueAssertion) []domain.AssertionResult {
+	t.Helper()
+	spec := domain.AssertionsSpec{JSONPath: map[string]domain.ValueAssertion{expr: a}}
+	return Evaluate(spec, 200, 0, []byte(body), nil, nil, false)
+}
+
+func TestValueChecks_Type(t *testing.T) {
+	body := `{"s":"x","i":3,"f":1.5,"b":false,"a":[1],"o":{},"n":null,"big":12345678901234567890}`
+	cases := []struct {
+		expr, typ string
+		pass      bool
+	}{
+		{"$.s", "string", true},
+		{"$.i", "integer", true},
+		{"$.i", "number", true},
+		{"$.f", "number", true},
+		{"$.f", "integer", false},
+		{"$.b", "boolean", true},
+		{"$.a", "array", true},
+		{"$.o", "object", true},
+		{"$.n", "null", true},
+		{"$.big", "integer", true},
+		{"$.s", "number", false},
+		{"$.missing", "null", false},
+	}
+	for _, tc := range cases {
+		res := evalJSONPath(t, body, tc.expr, domain.ValueAssertion{Type: strPtr(tc.typ)})
+		if len(res) != 1 || res[0].Name != "jsonpath.type" || res[0].Passed != tc.pass {
+			t.Errorf("%s type %s: got %+v, want passed=%v", tc.expr, tc.typ, res, tc.pass)
+		}
+	}
+
+	res := evalJSONPath(t, body, "$.f", domain.ValueAssertion{Type: strPtr("integer")})
+	if !strings.Contains(res[0].Message, "expected integer, got number") {
+		t.Errorf("message = %q", res[0].Message)
+	}
+}
+
+func TestValueChecks_InNotIn(t *testing.T) {
+	body := `{"status":"pending","code":2}`
+	set := []string{"active", "pending"}
+
+	if res := evalJSONPath(t, body, "$.status", domain.ValueAssertion{In: set}); !res[0].Passed || res[0].Name != "jsonpath.in" {
+		t.Errorf("in: %+v", res)
+	}
+	res := evalJSONPath(t, body, "$.status", domain.ValueAssertion{NotIn: set})
+	if res[0].Passed || res[0].Name != "jsonpath.not_in" || !strings.Contains(res[0].Message, `expected none of ["active", "pending"], got "pending"`) {
+		t.Errorf("not_in: %+v", res)
+	}
+	if res := evalJSONPath(t, body, "$.code", domain.ValueAssertion{In: []string{"1", "2"}}); !res[0].Passed {
+		t.Errorf("numbers compare as strings, like eq: %+v", res)
+	}
+}
+
+func TestValueChecks_StringOperators(t *testing.T) {
+	body := `{"id":"usr_123","email":"Alice@Example.com"}`
+	cases := []struct {
+		name string
+		expr string
+		a    domain.ValueAssertion
+		pass bool
+	}{
+		{"jsonpath.starts_with", "$.id", domain.ValueAssertion{StartsWith: strPtr("usr_")}, true},
+		{"jsonpath.starts_with", "$.id", domain.ValueAssertion{StartsWith: strPtr("org_")}, false},
+		{"jsonpath.ends_with", "$.email", domain.ValueAssertion{EndsWith: strPtr("@Example.com")}, true},
+		{"jsonpath.ends_with", "$.email", domain.ValueAssertion{EndsWith: strPtr("@example.com")}, false},
+		{"jsonpath.eq_ci", "$.email", domain.ValueAssertion{EqCI: strPtr("alice@example.COM")}, true},
+		{"jsonpath.eq_ci", "$.email", domain.ValueAssertion{EqCI: strPtr("bob@example.com")}, false},
+	}
+	for _, tc := range cases {
+		res := evalJSONPath(t, body, tc.expr, tc.a)
+		if len(res) != 1 || res[0].Name != tc.name || res[0].Passed != tc.pass || res[0].Key != tc.expr {
+			t.Errorf("%s on %s: got %+v, want passed=%v", tc.name, tc.expr, res, tc.pass)
+		}
+	}
+}
+
+func TestValueChecks_NewOperatorsOnHeaders(t *testing.T) {
+	spec := domain.AssertionsSpec{Headers: map[string]domain.ValueAssertion{
+		"Content-Type": {StartsWith: strPtr("application/json"), EqCI: strPtr("APPLICATION/JSON; charset=utf-8")},
+	}}
+	res := Evaluate(spec, 200, 0, nil, nil, map[string][]string{"Content-Type": {"application/json; charset=utf-8"}}, false)
+	if len(res) != 2 || !res[0].Passed || !res[1].Passed {
+		t.Fatalf("header operators: %+v", res)
+	}
+}
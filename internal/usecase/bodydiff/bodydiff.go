@@ -0,0 +1,384 @@
+// Package bodydiff compares response bodies: a path-based structural diff
+// for JSON and a line diff for anything else.
+package bodydiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+type Kind string
+
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Changed Kind = "changed"
+)
+
+// Change is one difference between two JSON documents. Path is a JSONPath
+// such as $.items[0].id; Old is unset for Added and New for Removed.
+type Change struct {
+	Path string `json:"path"`
+	Kind Kind   `json:"kind"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+type options struct {
+	ignore      []Pattern
+	mask        string
+	ignoreOrder bool
+	tolerance   float64
+}
+
+type Option func(*options)
+
+// WithIgnore skips the given paths and everything below them.
+func WithIgnore(patterns ...Pattern) Option {
+	return func(o *options) { o.ignore = append(o.ignore, patterns...) }
+}
+
+// WithMask makes mask a wildcard: a value that is the mask, or a string in
+// which part was replaced by it, equals whatever it may have hidden. Runs are
+// stored redacted, so a masked token must not show up as a change.
+func WithMask(mask string) Option {
+	return func(o *options) { o.mask = mask }
+}
+
+// WithIgnoreOrder compares arrays as multisets: each element of a is paired
+// with an equal element of b wherever it sits. Elements left without a match
+// are paired in order and compared field by field; the rest are reported as
+// removed or added.
+func WithIgnoreOrder() Option {
+	return func(o *options) { o.ignoreOrder = true }
+}
+
+// WithTolerance makes numbers at most tolerance apart compare equal.
+func WithTolerance(tolerance float64) Option {
+	return func(o *options) { o.tolerance = tolerance }
+}
+
+// ErrNotJSON is returned by JSON when either body does not parse.
+var ErrNotJSON = errors.New("body is not valid JSON")
+
+// JSON parses both bodies and compares them structurally.
+func JSON(a, b []byte, opts ...Option) ([]Change, error) {
+	va, err := decode(a)
+	if err != nil {
+		return nil, err
+	}
+	vb, err := decode(b)
+	if err != nil {
+		return nil, err
+	}
+	return Values(va, vb, opts...), nil
+}
+
+func decode(b []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotJSON, err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("%w: trailing data", ErrNotJSON)
+	}
+	return v, nil
+}
+
+// Values compares two decoded JSON values. Changes are ordered by path;
+// arrays are compared index by index.
+func Values(a, b any, opts ...Option) []Change {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var out []Change
+	o.walk(nil, a, b, &out)
+	return out
+}
+
+// Entries converts changes to their reported form, with values encoded as
+// JSON.
+func Entries(changes []Change) []domain.DiffEntry {
+	out := make([]domain.DiffEntry, 0, len(changes))
+	for _, ch := range changes {
+		e := domain.DiffEntry{Path: ch.Path, Kind: string(ch.Kind)}
+		if ch.Kind != Added {
+			e.Old = encodeValue(ch.Old)
+		}
+		if ch.Kind != Removed {
+			e.New = encodeValue(ch.New)
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+func encodeValue(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// Omit returns a copy of v without the paths patterns select. Object keys
+// are dropped; array elements become null so later elements keep their
+// index.
+func Omit(v any, patterns ...Pattern) any {
+	if len(patterns) == 0 {
+		return v
+	}
+	o := options{ignore: patterns}
+	return o.omit(nil, v)
+}
+
+func (o *options) omit(path []segment, v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			child := append(path[:len(path):len(path)], segment{key: k})
+			if !o.ignored(child) {
+				out[k] = o.omit(child, val)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, val := range t {
+			child := append(path[:len(path):len(path)], segment{index: i, isIndex: true})
+			if !o.ignored(child) {
+				out[i] = o.omit(child, val)
+			}
+		}
+		return out
+	}
+	return v
+}
+
+func (o *options) walk(path []segment, a, b any, out *[]Change) {
+	if o.ignored(path) {
+		return
+	}
+	switch ta := a.(type) {
+	case map[string]any:
+		if tb, ok := b.(map[string]any); ok {
+			keys := make([]string, 0, len(ta)+len(tb))
+			for k := range ta {
+				keys = append(keys, k)
+			}
+			for k := range tb {
+				if _, ok := ta[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				va, inA := ta[k]
+				vb, inB := tb[k]
+				child := append(path[:len(path):len(path)], segment{key: k})
+				o.child(child, va, inA, vb, inB, out)
+			}
+			return
+		}
+	case []any:
+		if tb, ok := b.([]any); ok {
+			if o.ignoreOrder {
+				o.walkUnordered(path, ta, tb, out)
+				return
+			}
+			for i := 0; i < max(len(ta), len(tb)); i++ {
+				child := append(path[:len(path):len(path)], segment{index: i, isIndex: true})
+				var va, vb any
+				if i < len(ta) {
+					va = ta[i]
+				}
+				if i < len(tb) {
+					vb = tb[i]
+				}
+				o.child(child, va, i < len(ta), vb, i < len(tb), out)
+			}
+			return
+		}
+	}
+	if !o.equal(a, b) {
+		*out = append(*out, Change{Path: formatPath(path), Kind: Changed, Old: a, New: b})
+	}
+}
+
+// walkUnordered pairs every element of a with the first equal, still
+// unpaired element of b. Paths of the leftovers use their own side's index:
+// a for removals, b for additions and changes.
+func (o *options) walkUnordered(path []segment, a, b []any, out *[]Change) {
+	paired := make([]bool, len(b))
+	var restA []int
+	for i, va := range a {
+		found := false
+		for j, vb := range b {
+			if paired[j] {
+				continue
+			}
+			child := append(path[:len(path):len(path)], segment{index: j, isIndex: true})
+			var changes []Change
+			o.walk(child, va, vb, &changes)
+			if len(changes) == 0 {
+				paired[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			restA = append(restA, i)
+		}
+	}
+	var restB []int
+	for j := range b {
+		if !paired[j] {
+			restB = append(restB, j)
+		}
+	}
+	for k := 0; k < max(len(restA), len(restB)); k++ {
+		switch {
+		case k < len(restA) && k < len(restB):
+			child := append(path[:len(path):len(path)], segment{index: restB[k], isIndex: true})
+			o.walk(child, a[restA[k]], b[restB[k]], out)
+		case k < len(restA):
+			child := append(path[:len(path):len(path)], segment{index: restA[k], isIndex: true})
+			o.child(child, a[restA[k]], true, nil, false, out)
+		default:
+			child := append(path[:len(path):len(path)], segment{index: restB[k], isIndex: true})
+			o.child(child, nil, false, b[restB[k]], true, out)
+		}
+	}
+}
+
+func (o *options) child(path []segment, a any, inA bool, b any, inB bool, out *[]Change) {
+	switch {
+	case inA && inB:
+		o.walk(path, a, b, out)
+	case o.ignored(path):
+	case inA:
+		*out = append(*out, Change{Path: formatPath(path), Kind: Removed, Old: a})
+	default:
+		*out = append(*out, Change{Path: formatPath(path), Kind: Added, New: b})
+	}
+}
+
+func (o *options) ignored(path []segment) bool {
+	for _, p := range o.ignore {
+		if p.matchesPrefix(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// equal compares scalars (and mismatched types). Numbers compare by value,
+// so 1.0 equals 1, and within the tolerance.
+func (o *options) equal(a, b any) bool {
+	if o.mask != "" && (a == o.mask || b == o.mask) {
+		return true
+	}
+	switch ta := a.(type) {
+	case json.Number, float64:
+		if a == b {
+			return true
+		}
+		fa, okA := number(ta)
+		fb, okB := number(b)
+		return okA && okB && (fa == fb || math.Abs(fa-fb) <= o.tolerance)
+	case string:
+		tb, ok := b.(string)
+		return ok && o.stringsEqual(ta, tb)
+	}
+	return a == b
+}
+
+// number accepts both decodings of a JSON number: json.Number (UseNumber)
+// and float64.
+func number(v any) (float64, bool) {
+	switch t := v.(type) {
+	case json.Number:
+		f, err := t.Float64()
+		return f, err == nil
+	case float64:
+		return t, true
+	}
+	return 0, false
+}
+
+func (o *options) stringsEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if o.mask == "" {
+		return false
+	}
+	return maskMatch(a, b, o.mask) || maskMatch(b, a, o.mask)
+}
+
+// maskMatch reports whether s could be the original of masked, where every
+// occurrence of mask stands for any (possibly empty) text.
+func maskMatch(masked, s, mask string) bool {
+	if !strings.Contains(masked, mask) {
+		return false
+	}
+	parts := strings.Split(masked, mask)
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, p := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, p)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(p):]
+	}
+	return strings.HasSuffix(s, last)
+}
+
+// segment is one step of a concrete path: an object key or an array index.
+type segment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+func formatPath(path []segment) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, s := range path {
+		switch {
+		case s.isIndex:
+			b.WriteString("[" + strconv.Itoa(s.index) + "]")
+		case isIdentifier(s.key):
+			b.WriteString("." + s.key)
+		default:
+			b.WriteString("[" + strconv.Quote(s.key) + "]")
+		}
+	}
+	return b.String()
+}
+
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		alpha := r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+		digit := r >= '0' && r <= '9'
+		if !alpha && (i == 0 || !digit) {
+			return false
+		}
+	}
+	return s != ""
+}
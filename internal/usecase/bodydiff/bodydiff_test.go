@@ -0,0 +1,200 @@
+package bodydiff
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func paths(changes []Change) []string {
+	out := make([]string, 0, len(changes))
+	for _, c := range changes {
+		out = append(out, string(c.Kind)+" "+c.Path)
+	}
+	return out
+}
+
+func mustPatterns(t *testing.T, exprs ...string) []Pattern {
+	t.Helper()
+	ps, err := ParsePatterns(exprs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ps
+}
+
+func TestJSON_AddedRemovedChanged(t *testing.T) {
+	a := `{"id":1,"name":"alice","tags":["a","b"],"meta":{"v":1.0},"gone":true,"odd key":1}`
+	b := `{"id":1,"name":"bob","tags":["a"],"meta":{"v":1,"extra":null},"odd key":2}`
+
+	changes, err := JSON([]byte(a), []byte(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"removed $.gone",
+		"added $.meta.extra",
+		"changed $.name",
+		`changed $["odd key"]`,
+		"removed $.tags[1]",
+	}
+	if got := paths(changes); !reflect.DeepEqual(got, want) {
+		t.Fatalf("changes = %v, want %v", got, want)
+	}
+	if changes[2].Old != "alice" || changes[2].New != "bob" {
+		t.Errorf("name change = %+v", changes[2])
+	}
+}
+
+func TestJSON_TypeChangeIsSingleChange(t *testing.T) {
+	changes, _ := JSON([]byte(`{"a":{"b":1}}`), []byte(`{"a":[1]}`))
+	if got := paths(changes); !reflect.DeepEqual(got, []string{"changed $.a"}) {
+		t.Fatalf("changes = %v", got)
+	}
+}
+
+func TestJSON_NotJSON(t *testing.T) {
+	for _, body := range []string{"<html>", `{"a":1} trailing`, ""} {
+		if _, err := JSON([]byte(body), []byte(`{}`)); !errors.Is(err, ErrNotJSON) {
+			t.Errorf("%q: err = %v, want ErrNotJSON", body, err)
+		}
+	}
+}
+
+func TestJSON_Ignore(t *testing.T) {
+	a := `{"meta":{"timestamp":1,"id":"x"},"items":[{"at":1,"v":1},{"at":2,"v":2}],"deep":{"x":{"at":3}}}`
+	b := `{"meta":{"timestamp":2,"id":"x"},"items":[{"at":5,"v":1},{"at":6,"v":3}],"deep":{"x":{"at":4}}}`
+
+	cases := []struct {
+		ignore []string
+		want   []string
+	}{
+		{nil, []string{"changed $.deep.x.at", "changed $.items[0].at", "changed $.items[1].at", "changed $.items[1].v", "changed $.meta.timestamp"}},
+		{[]string{"$.meta.timestamp", "$.items[*].at"}, []string{"changed $.deep.x.at", "changed $.items[1].v"}},
+		{[]string{"$..at", "$.meta"}, []string{"changed $.items[1].v"}},
+		{[]string{"$.items[1]", "$['deep']"}, []string{"changed $.items[0].at", "changed $.meta.timestamp"}},
+	}
+	for _, tc := range cases {
+		changes, err := JSON([]byte(a), []byte(b), WithIgnore(mustPatterns(t, tc.ignore...)...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := paths(changes); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ignore %v: changes = %v, want %v", tc.ignore, got, tc.want)
+		}
+	}
+}
+
+func TestJSON_IgnoreAddedKey(t *testing.T) {
+	changes, _ := JSON([]byte(`{}`), []byte(`{"trace":{"id":1}}`), WithIgnore(mustPatterns(t, "$.trace")...))
+	if len(changes) != 0 {
+		t.Fatalf("changes = %v", paths(changes))
+	}
+}
+
+func TestJSON_MaskComparesEqual(t *testing.T) {
+	const mask = "********"
+	a := `{"token":"********","auth":"Bearer ********","n":"********","name":"alice"}`
+	b := `{"token":"abc","auth":"Bearer xyz","n":42,"name":"bob"}`
+
+	changes, _ := JSON([]byte(a), []byte(b), WithMask(mask))
+	if got := paths(changes); !reflect.DeepEqual(got, []string{"changed $.name"}) {
+		t.Fatalf("changes = %v", got)
+	}
+
+	changes, _ = JSON([]byte(`{"auth":"Basic ********"}`), []byte(`{"auth":"Bearer xyz"}`), WithMask(mask))
+	if len(changes) != 1 {
+		t.Fatalf("a differing unmasked prefix must still count, got %v", paths(changes))
+	}
+}
+
+func TestJSON_IgnoreOrder(t *testing.T) {
+	a := `{"tags":["a","b","c"],"users":[{"id":1,"n":"x"},{"id":2,"n":"y"}]}`
+	b := `{"tags":["c","a","d"],"users":[{"id":2,"n":"y"},{"id":1,"n":"z"}]}`
+
+	changes, _ := JSON([]byte(a), []byte(b), WithIgnoreOrder())
+	want := []string{"changed $.tags[2]", "changed $.users[1].n"}
+	if got := paths(changes); !reflect.DeepEqual(got, want) {
+		t.Fatalf("changes = %v, want %v", got, want)
+	}
+
+	changes, _ = JSON([]byte(`[1,2,2]`), []byte(`[2,1]`), WithIgnoreOrder())
+	if got := paths(changes); !reflect.DeepEqual(got, []string{"removed $[2]"}) {
+		t.Fatalf("duplicates must be counted, got %v", got)
+	}
+}
+
+func TestJSON_Tolerance(t *testing.T) {
+	a := `{"price":9.99,"qty":3}`
+	b := `{"price":10.01,"qty":4}`
+	changes, _ := JSON([]byte(a), []byte(b), WithTolerance(0.05))
+	if got := paths(changes); !reflect.DeepEqual(got, []string{"changed $.qty"}) {
+		t.Fatalf("changes = %v", got)
+	}
+	if changes := Values(map[string]any{"n": 1.0}, map[string]any{"n": "1"}, WithTolerance(1)); len(changes) != 1 {
+		t.Fatalf("a string never equals a number, got %v", paths(changes))
+	}
+}
+
+func TestOmit(t *testing.T) {
+	v := map[string]any{
+		"meta":  map[string]any{"at": 1, "id": "x"},
+		"items": []any{map[string]any{"at": 1, "v": 1}, "keep"},
+	}
+	got := Omit(v, mustPatterns(t, "$..at", "$.items[1]")...)
+	want := map[string]any{
+		"meta":  map[string]any{"id": "x"},
+		"items": []any{map[string]any{"v": 1}, nil},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Omit = %#v, want %#v", got, want)
+	}
+	if _, ok := v["meta"].(map[string]any)["at"]; !ok {
+		t.Error("Omit modified its input")
+	}
+}
+
+func TestParsePattern_Errors(t *testing.T) {
+	for _, expr := range []string{"meta.x", "$.", "$[", "$[x]", "$[-1]", "$.a[0"} {
+		if _, err := ParsePattern(expr); err == nil {
+			t.Errorf("ParsePattern(%q): expected an error", expr)
+		}
+	}
+	p, err := ParsePattern(" $.a ")
+	if err != nil || p.String() != " $.a " {
+		t.Errorf("ParsePattern: %v, %q", err, p.String())
+	}
+}
+
+func TestLines(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\n"
+	b := "one\n2\nthree\nfour\nfive\n"
+
+	got := Lines(a, b)
+	want := []Line{{'-', "two"}, {'+', "2"}, {'+', "five"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Lines = %v, want %v", got, want)
+	}
+
+	if got := Lines("same\r\n", "same\n"); len(got) != 0 {
+		t.Errorf("line endings should not differ, got %v", got)
+	}
+	if got := Lines("token=********", "token=abc", WithMask("********")); len(got) != 0 {
+		t.Errorf("masked line should compare equal, got %v", got)
+	}
+}
+
+func TestLines_LargeInputFallsBack(t *testing.T) {
+	var a, b strings.Builder
+	for i := range 2100 {
+		a.WriteString("a")
+		a.WriteString(strings.Repeat("x", i%7))
+		a.WriteString("\n")
+		b.WriteString("b\n")
+	}
+	got := Lines(a.String(), b.String())
+	if len(got) != 4200 || got[0].Op != '-' || got[len(got)-1].Op != '+' {
+		t.Fatalf("unexpected fallback diff: %d lines", len(got))
+	}
+}
@@ -0,0 +1,92 @@
+package bodydiff
+
+import "strings"
+
+// maxLineCells bounds the LCS table; beyond it the differing middle section
+// is reported as removed then added instead of aligned line by line.
+const maxLineCells = 4_000_000
+
+// Line is one changed line of a text diff: Op is '-' for a line only in a
+// and '+' for a line only in b.
+type Line struct {
+	Op   byte
+	Text string
+}
+
+// Lines diffs two bodies line by line and returns only the changed lines.
+// Ignore patterns do not apply; WithMask does.
+func Lines(a, b string, opts ...Option) []Line {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	la, lb := splitLines(a), splitLines(b)
+
+	for len(la) > 0 && len(lb) > 0 && o.stringsEqual(la[0], lb[0]) {
+		la, lb = la[1:], lb[1:]
+	}
+	for len(la) > 0 && len(lb) > 0 && o.stringsEqual(la[len(la)-1], lb[len(lb)-1]) {
+		la, lb = la[:len(la)-1], lb[:len(lb)-1]
+	}
+
+	if len(la)*len(lb) > maxLineCells {
+		out := make([]Line, 0, len(la)+len(lb))
+		for _, l := range la {
+			out = append(out, Line{Op: '-', Text: l})
+		}
+		for _, l := range lb {
+			out = append(out, Line{Op: '+', Text: l})
+		}
+		return out
+	}
+	return o.lcsDiff(la, lb)
+}
+
+func (o *options) lcsDiff(a, b []string) []Line {
+	n, m := len(a), len(b)
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if o.stringsEqual(a[i], b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []Line
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case o.stringsEqual(a[i], b[j]):
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, Line{Op: '-', Text: a[i]})
+			i++
+		default:
+			out = append(out, Line{Op: '+', Text: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		out = append(out, Line{Op: '-', Text: a[i]})
+	}
+	for ; j < m; j++ {
+		out = append(out, Line{Op: '+', Text: b[j]})
+	}
+	return out
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,126 @@
+package bodydiff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pattern selects paths to ignore. It is the path subset of JSONPath:
+// $.a.b, $.items[0], $.items[*].id, $.*, $["odd key"] and recursive descent
+// ($..timestamp matches the key at any depth).
+type Pattern struct {
+	expr string
+	segs []patternSegment
+}
+
+type patternSegment struct {
+	descent bool // reached through ".." (any number of levels above)
+	any     bool // * or [*]
+	key     string
+	index   int
+	isIndex bool
+}
+
+func (p Pattern) String() string { return p.expr }
+
+// ParsePattern parses an ignore path.
+func ParsePattern(expr string) (Pattern, error) {
+	s := strings.TrimSpace(expr)
+	if !strings.HasPrefix(s, "$") {
+		return Pattern{}, fmt.Errorf("invalid path %q: must start with $", expr)
+	}
+	s = s[1:]
+	p := Pattern{expr: expr}
+	for s != "" {
+		var seg patternSegment
+		switch {
+		case strings.HasPrefix(s, ".."):
+			seg.descent = true
+			s = s[2:]
+			if !strings.HasPrefix(s, "[") {
+				s = "." + s
+			}
+		}
+		switch {
+		case strings.HasPrefix(s, "."):
+			name := s[1:]
+			end := strings.IndexAny(name, ".[")
+			if end < 0 {
+				end = len(name)
+			}
+			name, s = name[:end], name[end:]
+			if name == "" {
+				return Pattern{}, fmt.Errorf("invalid path %q: empty name", expr)
+			}
+			seg.any = name == "*"
+			seg.key = name
+		case strings.HasPrefix(s, "["):
+			end := strings.Index(s, "]")
+			if end < 0 {
+				return Pattern{}, fmt.Errorf("invalid path %q: unclosed [", expr)
+			}
+			inner := strings.TrimSpace(s[1:end])
+			s = s[end+1:]
+			switch {
+			case inner == "*":
+				seg.any = true
+			case len(inner) >= 2 && (inner[0] == '"' || inner[0] == '\'') && inner[len(inner)-1] == inner[0]:
+				seg.key = inner[1 : len(inner)-1]
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil || n < 0 {
+					return Pattern{}, fmt.Errorf("invalid path %q: unsupported selector [%s]", expr, inner)
+				}
+				seg.index, seg.isIndex = n, true
+			}
+		default:
+			return Pattern{}, fmt.Errorf("invalid path %q: unexpected %q", expr, s)
+		}
+		p.segs = append(p.segs, seg)
+	}
+	return p, nil
+}
+
+// ParsePatterns parses several ignore paths.
+func ParsePatterns(exprs []string) ([]Pattern, error) {
+	out := make([]Pattern, 0, len(exprs))
+	for _, e := range exprs {
+		p, err := ParsePattern(e)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// matchesPrefix reports whether the pattern selects path or one of its
+// ancestors.
+func (p Pattern) matchesPrefix(path []segment) bool {
+	return matchSegments(p.segs, path)
+}
+
+func matchSegments(pat []patternSegment, path []segment) bool {
+	if len(pat) == 0 {
+		return true
+	}
+	if len(path) == 0 {
+		return false
+	}
+	if pat[0].matches(path[0]) && matchSegments(pat[1:], path[1:]) {
+		return true
+	}
+	return pat[0].descent && matchSegments(pat, path[1:])
+}
+
+func (p patternSegment) matches(s segment) bool {
+	switch {
+	case p.any:
+		return true
+	case p.isIndex:
+		return s.isIndex && s.index == p.index
+	default:
+		return !s.isIndex && s.key == p.key
+	}
+}
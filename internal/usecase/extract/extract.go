@@ -17,6 +17,14 @@ import (
 // truncated indicates the response body was cut off (>256KB) and may not be valid JSON.
 // If body is not JSON, every extract rule fails. If a single rule fails, others still run.
 func Apply(body []byte, rules domain.ExtractSpec, truncated bool) (domain.Vars, []domain.ExtractResult) {
+	return ApplyWithOptions(body, rules, nil, truncated)
+}
+
+// ApplyWithOptions is Apply with per-variable options: a VarJSON rule stores
+// the value as JSON text, and a missing value (absent path, null, or a body
+// that is not JSON) falls back to the default or, when optional, is skipped
+// without failing.
+func ApplyWithOptions(body []byte, rules domain.ExtractSpec, opts map[string]domain.ExtractOptions, truncated bool) (domain.Vars, []domain.ExtractResult) {
 	if len(rules) == 0 {
 		return domain.Vars{}, []domain.ExtractResult{}
 	}
@@ -27,27 +35,37 @@ func Apply(body []byte, rules domain.ExtractSpec, truncated bool) (domain.Vars,
 	}
 	sort.Strings(keys) // stable output for tests/UI
 
+	extracted := domain.Vars{}
+	results := make([]domain.ExtractResult, 0, len(keys))
+
+	// missing reports a rule that found no value, unless its options
+	// provide a default or make it optional.
+	missing := func(name, expr, reason string) {
+		msg := fmt.Sprintf("extract %q (%s): %s", name, expr, reason)
+		o := opts[name]
+		switch {
+		case o.Default != nil:
+			extracted[name] = *o.Default
+			results = append(results, domain.ExtractResult{Name: name, Success: true, Message: msg + ", using the default"})
+		case o.Optional:
+			results = append(results, domain.ExtractResult{Name: name, Success: true, Message: msg + ", skipped (optional)"})
+		default:
+			results = append(results, domain.ExtractResult{Name: name, Success: false, Message: msg})
+		}
+	}
+
 	doc, err := parseJSON(body)
 	if err != nil {
 		jsonErrMsg := "response body is not valid JSON"
 		if truncated {
 			jsonErrMsg = "response body was truncated (>256KB) and is not valid JSON"
 		}
-		out := make([]domain.ExtractResult, 0, len(keys))
 		for _, name := range keys {
-			expr := strings.TrimSpace(rules[name])
-			out = append(out, domain.ExtractResult{
-				Name:    name,
-				Success: false,
-				Message: fmt.Sprintf("extract %q (%s): %s", name, expr, jsonErrMsg),
-			})
+			missing(name, strings.TrimSpace(rules[name]), jsonErrMsg)
 		}
-		return domain.Vars{}, out
+		return extracted, results
 	}
 
-	extracted := domain.Vars{}
-	results := make([]domain.ExtractResult, 0, len(keys))
-
 	for _, name := range keys {
 		expr := strings.TrimSpace(rules[name])
 		if expr == "" {
@@ -61,24 +79,20 @@ func Apply(body []byte, rules domain.ExtractSpec, truncated bool) (domain.Vars,
 
 		val, getErr := jsonpath.Get(expr, doc)
 		if getErr != nil {
-			results = append(results, domain.ExtractResult{
-				Name:    name,
-				Success: false,
-				Message: fmt.Sprintf("extract %q (%s): jsonpath error: %v", name, expr, getErr),
-			})
+			missing(name, expr, fmt.Sprintf("jsonpath error: %v", getErr))
 			continue
 		}
 
 		if isEmptyValue(val) {
-			results = append(results, domain.ExtractResult{
-				Name:    name,
-				Success: false,
-				Message: fmt.Sprintf("extract %q (%s): no value found", name, expr),
-			})
+			missing(name, expr, "no value found")
 			continue
 		}
 
-		s, convErr := toString(val)
+		convert := toString
+		if opts[name].Type == domain.VarJSON {
+			convert = toJSON
+		}
+		s, convErr := convert(val)
 		if convErr != nil {
 			results = append(results, domain.ExtractResult{
 				Name:    name,
@@ -149,13 +163,22 @@ func ApplyHeaders(headers map[string][]string, rules domain.ExtractHeaderSpec) (
 
 // lookupHeader finds a header value case-insensitively, returning the first value.
 func lookupHeader(headers map[string][]string, name string) (string, bool) {
-	lower := strings.ToLower(name)
+	vals := headerValues(headers, name)
+	if len(vals) == 0 {
+		return "", false
+	}
+	return vals[0], true
+}
+
+// headerValues returns every value of a header, matched case-insensitively.
+func headerValues(headers map[string][]string, name string) []string {
+	var out []string
 	for k, vals := range headers {
-		if strings.ToLower(k) == lower && len(vals) > 0 {
-			return vals[0], true
+		if strings.EqualFold(k, name) {
+			out = append(out, vals...)
 		}
 	}
-	return "", false
+	return out
 }
 
 // parseJSON decodes with UseNumber so large integers (e.g. int64 IDs) keep
@@ -209,6 +232,16 @@ func isEmptyValue(v any) bool {
 	return v == nil
 }
 
+// toJSON encodes an extracted value as JSON text, as it is: unlike toString,
+// strings keep their quotes and one-element arrays stay arrays.
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
 func toString(v any) (string, error) {
 	// Common case: jsonpath returns a slice with 1 element
 	if arr, ok := v.([]any); ok {
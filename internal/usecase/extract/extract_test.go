@@ -1,6 +1,7 @@
 package extract
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/aalvaropc/lynix/internal/domain"
@@ -382,3 +383,66 @@ func TestApply_FloatFormattingUnchanged(t *testing.T) {
 		t.Fatalf("expected float64 formatting \"3.1\", got %q", vars["v"])
 	}
 }
+
+func TestApplyWithOptions_TypedJSON(t *testing.T) {
+	body := []byte(`{"items":[{"id":1}],"count":2,"name":"alice","big":12345678901234567890}`)
+	rules := domain.ExtractSpec{"items": "$.items", "count": "$.count", "name": "$.name", "big": "$.big", "plain": "$.items"}
+	opts := map[string]domain.ExtractOptions{
+		"items": {Type: domain.VarJSON},
+		"count": {Type: domain.VarJSON},
+		"name":  {Type: domain.VarJSON},
+		"big":   {Type: domain.VarJSON},
+	}
+
+	vars, res := ApplyWithOptions(body, rules, opts, false)
+	want := domain.Vars{
+		"items": `[{"id":1}]`,
+		"count": "2",
+		"name":  `"alice"`,
+		"big":   "12345678901234567890",
+		"plain": `{"id":1}`, // untyped: a one-element array is unwrapped
+	}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("%s: expected %s, got %s", k, v, vars[k])
+		}
+	}
+	for _, r := range res {
+		if !r.Success {
+			t.Fatalf("expected success, got %+v", r)
+		}
+	}
+}
+
+func TestApplyWithOptions_OptionalAndDefault(t *testing.T) {
+	empty := "[]"
+	region := "eu-west-1"
+	rules := domain.ExtractSpec{"cursor": "$.next", "tags": "$.tags", "region": "$.region", "id": "$.id"}
+	opts := map[string]domain.ExtractOptions{
+		"cursor": {Optional: true},
+		"tags":   {Type: domain.VarJSON, Default: &empty},
+		"region": {Default: &region},
+	}
+
+	for _, body := range []string{`{"next":null}`, ``} {
+		vars, res := ApplyWithOptions([]byte(body), rules, opts, false)
+		if _, ok := vars["cursor"]; ok {
+			t.Fatalf("body %q: an optional missing value must not be set: %v", body, vars)
+		}
+		if vars["tags"] != "[]" || vars["region"] != "eu-west-1" {
+			t.Fatalf("body %q: expected defaults, got %v", body, vars)
+		}
+		failed := map[string]bool{}
+		for _, r := range res {
+			failed[r.Name] = !r.Success
+		}
+		if failed["cursor"] || failed["tags"] || failed["region"] || !failed["id"] {
+			t.Fatalf("body %q: only id should fail, got %+v", body, res)
+		}
+	}
+
+	_, res := ApplyWithOptions([]byte(`{}`), domain.ExtractSpec{"cursor": "$.next"}, opts, false)
+	if !strings.HasSuffix(res[0].Message, ", skipped (optional)") {
+		t.Fatalf("unexpected message: %s", res[0].Message)
+	}
+}
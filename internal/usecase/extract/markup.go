@@ -0,0 +1,83 @@
+package extract
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/usecase/markup"
+)
+
+// ApplyXPath extracts variables from an XML body using XPath rules
+// (map[varName]expression). Node sets convert like JSONPath results: one
+// match is its text, several a JSON array.
+func ApplyXPath(body []byte, rules domain.ExtractSpec, truncated bool) (domain.Vars, []domain.ExtractResult) {
+	if len(rules) == 0 {
+		return domain.Vars{}, []domain.ExtractResult{}
+	}
+	doc, err := markup.ParseXML(body)
+	return applyQueries("extract_xpath", rules, err, truncated, func(expr string) (any, error) {
+		return markup.XPath(doc, expr)
+	})
+}
+
+// ApplyCSS extracts variables from an HTML body using CSS selector rules
+// (map[varName]selector); "a.next @href" reads an attribute.
+func ApplyCSS(body []byte, rules domain.ExtractSpec, truncated bool) (domain.Vars, []domain.ExtractResult) {
+	if len(rules) == 0 {
+		return domain.Vars{}, []domain.ExtractResult{}
+	}
+	doc, err := markup.ParseHTML(body)
+	return applyQueries("extract_css", rules, err, truncated, func(selector string) (any, error) {
+		return markup.CSS(doc, selector)
+	})
+}
+
+func applyQueries(block string, rules domain.ExtractSpec, docErr error, truncated bool, query func(string) (any, error)) (domain.Vars, []domain.ExtractResult) {
+	keys := make([]string, 0, len(rules))
+	for k := range rules {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	extracted := domain.Vars{}
+	results := make([]domain.ExtractResult, 0, len(keys))
+	fail := func(name, msg string) {
+		results = append(results, domain.ExtractResult{Name: name, Success: false, Message: msg})
+	}
+
+	for _, name := range keys {
+		expr := strings.TrimSpace(rules[name])
+		if docErr != nil {
+			msg := docErr.Error()
+			if truncated {
+				msg = "response body was truncated (>256KB): " + msg
+			}
+			fail(name, fmt.Sprintf("%s %q (%s): %s", block, name, expr, msg))
+			continue
+		}
+		val, err := query(expr)
+		if errors.Is(err, markup.ErrNoMatch) {
+			fail(name, fmt.Sprintf("%s %q (%s): no value found", block, name, expr))
+			continue
+		}
+		if err != nil {
+			fail(name, fmt.Sprintf("%s %q (%s): %v", block, name, expr, err))
+			continue
+		}
+		s, err := toString(val)
+		if err != nil {
+			fail(name, fmt.Sprintf("%s %q (%s): cannot convert value to string: %v", block, name, expr, err))
+			continue
+		}
+		extracted[name] = s
+		results = append(results, domain.ExtractResult{
+			Name:    name,
+			Success: true,
+			Message: fmt.Sprintf("extracted %q", name),
+		})
+	}
+	return extracted, results
+}
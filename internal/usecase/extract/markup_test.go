@@ -0,0 +1,56 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+func TestApplyXPath(t *testing.T) {
+	body := []byte(`<order id="42"><item>Pen</item><item>Ink</item></order>`)
+	rules := domain.ExtractSpec{
+		"order_id": "/order/@id",
+		"items":    "//item",
+		"total":    "count(//item)",
+		"refund":   "//refund",
+	}
+
+	vars, res := ApplyXPath(body, rules, false)
+	want := domain.Vars{"order_id": "42", "items": `["Pen","Ink"]`, "total": "2"}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, vars[k])
+		}
+	}
+	if len(res) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(res))
+	}
+	// Results are sorted by name: items, order_id, refund, total.
+	if res[2].Success || !strings.Contains(res[2].Message, `extract_xpath "refund" (//refund): no value found`) {
+		t.Fatalf("expected refund to fail, got %+v", res[2])
+	}
+}
+
+func TestApplyXPath_InvalidXMLFailsAll(t *testing.T) {
+	vars, res := ApplyXPath([]byte("hello <"), domain.ExtractSpec{"id": "/order/@id"}, false)
+	if len(vars) != 0 || len(res) != 1 || res[0].Success || !strings.Contains(res[0].Message, "not valid XML") {
+		t.Fatalf("expected one XML failure, got vars=%v res=%+v", vars, res)
+	}
+}
+
+func TestApplyCSS(t *testing.T) {
+	body := []byte(`<form><input name="csrf" value="t0k3n"><h1> Sign   in </h1></form>`)
+	vars, res := ApplyCSS(body, domain.ExtractSpec{
+		"csrf":  `input[name="csrf"] @value`,
+		"title": "h1",
+	}, false)
+	if vars["csrf"] != "t0k3n" || vars["title"] != "Sign in" {
+		t.Fatalf("unexpected vars: %v", vars)
+	}
+	for _, r := range res {
+		if !r.Success {
+			t.Fatalf("expected success, got %+v", r)
+		}
+	}
+}
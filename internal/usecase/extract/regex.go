@@ -0,0 +1,86 @@
+package extract
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// ApplyRegex extracts one variable per named capture group of each rule,
+// from the first match in the body or in the values of the rule's header.
+// A group that did not take part in the match fails like an empty value.
+func ApplyRegex(body []byte, headers map[string][]string, rules []domain.ExtractRegexRule, truncated bool) (domain.Vars, []domain.ExtractResult) {
+	extracted := domain.Vars{}
+	results := []domain.ExtractResult{}
+
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			results = append(results, domain.ExtractResult{
+				Name:    rule.Pattern,
+				Success: false,
+				Message: fmt.Sprintf("extract_regex (%s): invalid regex: %v", rule.Pattern, err),
+			})
+			continue
+		}
+		names := rule.Vars()
+		sort.Strings(names)
+
+		source := "body"
+		subjects := []string{string(body)}
+		if rule.Header != "" {
+			source = "header " + rule.Header
+			subjects = headerValues(headers, rule.Header)
+		}
+
+		var subject string
+		var match []int
+		for _, s := range subjects {
+			if match = re.FindStringSubmatchIndex(s); match != nil {
+				subject = s
+				break
+			}
+		}
+
+		for _, name := range names {
+			if match == nil {
+				msg := fmt.Sprintf("extract_regex %q (%s): no match in %s", name, rule.Pattern, source)
+				if rule.Header == "" && truncated {
+					msg += " (response body was truncated (>256KB))"
+				}
+				results = append(results, domain.ExtractResult{Name: name, Success: false, Message: msg})
+				continue
+			}
+			val := groupValue(re, match, subject, name)
+			if val == "" {
+				results = append(results, domain.ExtractResult{
+					Name:    name,
+					Success: false,
+					Message: fmt.Sprintf("extract_regex %q (%s): no value found", name, rule.Pattern),
+				})
+				continue
+			}
+			extracted[name] = val
+			results = append(results, domain.ExtractResult{
+				Name:    name,
+				Success: true,
+				Message: fmt.Sprintf("extracted %q from %s", name, source),
+			})
+		}
+	}
+
+	return extracted, results
+}
+
+// groupValue returns the text of the first group called name that took part
+// in the match (Go allows several groups with one name).
+func groupValue(re *regexp.Regexp, match []int, subject, name string) string {
+	for i, n := range re.SubexpNames() {
+		if n == name && match[2*i] >= 0 {
+			return subject[match[2*i]:match[2*i+1]]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+func TestApplyRegex(t *testing.T) {
+	body := []byte(`<p>Order #1042 created by alice</p>`)
+	headers := map[string][]string{"location": {"/orders/1042?v=2"}}
+	rules := []domain.ExtractRegexRule{
+		{Pattern: `Order #(?P<order_no>\d+) created by (?P<author>\w+)`},
+		{Pattern: `/orders/(?P<order_id>\d+)(?:\?v=(?P<version>\d+))?`, Header: "Location"},
+	}
+
+	vars, res := ApplyRegex(body, headers, rules, false)
+	want := domain.Vars{"order_no": "1042", "author": "alice", "order_id": "1042", "version": "2"}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, vars[k])
+		}
+	}
+	if len(res) != 4 {
+		t.Fatalf("expected 4 results, got %d: %+v", len(res), res)
+	}
+	if res[0].Name != "author" || res[0].Message != `extracted "author" from body` {
+		t.Fatalf("unexpected first result: %+v", res[0])
+	}
+	if res[2].Message != `extracted "order_id" from header Location` {
+		t.Fatalf("unexpected header result: %+v", res[2])
+	}
+}
+
+func TestApplyRegex_NoMatchFailsEveryGroup(t *testing.T) {
+	rules := []domain.ExtractRegexRule{{Pattern: `id=(?P<id>\d+)&v=(?P<v>\d+)`}}
+	vars, res := ApplyRegex([]byte("nothing here"), nil, rules, true)
+	if len(vars) != 0 || len(res) != 2 {
+		t.Fatalf("expected 2 failures, got vars=%v res=%+v", vars, res)
+	}
+	for _, r := range res {
+		if r.Success || !strings.Contains(r.Message, "no match in body (response body was truncated") {
+			t.Fatalf("unexpected result: %+v", r)
+		}
+	}
+}
+
+func TestApplyRegex_UnmatchedOptionalGroupFails(t *testing.T) {
+	rules := []domain.ExtractRegexRule{{Pattern: `/orders/(?P<id>\d+)(?:\?v=(?P<v>\d+))?`, Header: "Location"}}
+	vars, res := ApplyRegex(nil, map[string][]string{"Location": {"/orders/7"}}, rules, false)
+	if vars["id"] != "7" || len(res) != 2 || res[1].Success || res[1].Message != `extract_regex "v" (`+rules[0].Pattern+`): no value found` {
+		t.Fatalf("unexpected outcome: vars=%v res=%+v", vars, res)
+	}
+}
@@ -0,0 +1,152 @@
+package extract
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+// cookieAttrs reads each of domain.CookieAttrs; ok is false when the
+// cookie does not carry the attribute.
+var cookieAttrs = map[string]func(c *http.Cookie) (string, bool){
+	"value":  func(c *http.Cookie) (string, bool) { return c.Value, c.Value != "" },
+	"domain": func(c *http.Cookie) (string, bool) { return c.Domain, c.Domain != "" },
+	"path":   func(c *http.Cookie) (string, bool) { return c.Path, c.Path != "" },
+	"expires": func(c *http.Cookie) (string, bool) {
+		return c.Expires.UTC().Format(time.RFC3339), !c.Expires.IsZero()
+	},
+	"max-age": func(c *http.Cookie) (string, bool) {
+		if c.MaxAge < 0 {
+			return "0", true // Max-Age=0 or negative: delete now
+		}
+		return strconv.Itoa(c.MaxAge), c.MaxAge > 0
+	},
+	"secure":   func(c *http.Cookie) (string, bool) { return strconv.FormatBool(c.Secure), true },
+	"httponly": func(c *http.Cookie) (string, bool) { return strconv.FormatBool(c.HttpOnly), true },
+	"samesite": func(c *http.Cookie) (string, bool) {
+		switch c.SameSite {
+		case http.SameSiteLaxMode:
+			return "Lax", true
+		case http.SameSiteStrictMode:
+			return "Strict", true
+		case http.SameSiteNoneMode:
+			return "None", true
+		}
+		return "", false
+	},
+}
+
+// ApplyCookies extracts variables from the response's Set-Cookie headers.
+// rules: map[varName]"cookie [@attr]". When a cookie is set twice, the last
+// one wins, as in a browser.
+func ApplyCookies(headers map[string][]string, rules domain.ExtractSpec) (domain.Vars, []domain.ExtractResult) {
+	if len(rules) == 0 {
+		return domain.Vars{}, []domain.ExtractResult{}
+	}
+
+	cookies := map[string]*http.Cookie{}
+	for _, line := range headerValues(headers, "Set-Cookie") {
+		if c, err := http.ParseSetCookie(line); err == nil {
+			cookies[c.Name] = c
+		}
+	}
+
+	extracted := domain.Vars{}
+	results := make([]domain.ExtractResult, 0, len(rules))
+	for _, name := range sortedKeys(rules) {
+		rule := strings.TrimSpace(rules[name])
+		fail := func(msg string) {
+			results = append(results, domain.ExtractResult{
+				Name:    name,
+				Success: false,
+				Message: fmt.Sprintf("extract_cookie %q (%s): %s", name, rule, msg),
+			})
+		}
+
+		cookie, attr, err := domain.ParseCookieRule(rule)
+		if err != nil {
+			fail(err.Error())
+			continue
+		}
+		c, ok := cookies[cookie]
+		if !ok {
+			fail("cookie not found")
+			continue
+		}
+		val, ok := cookieAttrs[attr](c)
+		if !ok {
+			fail("no " + attr + " attribute")
+			continue
+		}
+
+		extracted[name] = val
+		results = append(results, domain.ExtractResult{
+			Name:    name,
+			Success: true,
+			Message: fmt.Sprintf("extracted %q from cookie %s", name, cookie),
+		})
+	}
+	return extracted, results
+}
+
+// ApplyResponse extracts the status code or the final URL of the response.
+// rules: map[varName]domain.ResponseStatus or domain.ResponseURL.
+func ApplyResponse(rr domain.RequestResult, rules domain.ExtractSpec) (domain.Vars, []domain.ExtractResult) {
+	if len(rules) == 0 {
+		return domain.Vars{}, []domain.ExtractResult{}
+	}
+
+	extracted := domain.Vars{}
+	results := make([]domain.ExtractResult, 0, len(rules))
+	for _, name := range sortedKeys(rules) {
+		source := strings.TrimSpace(rules[name])
+		var val string
+		switch source {
+		case domain.ResponseStatus:
+			if rr.StatusCode != 0 {
+				val = strconv.Itoa(rr.StatusCode)
+			}
+		case domain.ResponseURL:
+			if rr.StatusCode != 0 {
+				val = rr.FinalURL()
+			}
+		default:
+			results = append(results, domain.ExtractResult{
+				Name:    name,
+				Success: false,
+				Message: fmt.Sprintf("extract_response %q: unknown source %q (want status or url)", name, source),
+			})
+			continue
+		}
+		if val == "" {
+			results = append(results, domain.ExtractResult{
+				Name:    name,
+				Success: false,
+				Message: fmt.Sprintf("extract_response %q (%s): no response", name, source),
+			})
+			continue
+		}
+
+		extracted[name] = val
+		results = append(results, domain.ExtractResult{
+			Name:    name,
+			Success: true,
+			Message: fmt.Sprintf("extracted %q from response %s", name, source),
+		})
+	}
+	return extracted, results
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,75 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+func TestApplyCookies(t *testing.T) {
+	headers := map[string][]string{"Set-Cookie": {
+		"sid=old; Path=/",
+		"sid=abc123; Path=/app; Domain=example.com; Expires=Wed, 21 Oct 2026 07:28:00 GMT; HttpOnly; SameSite=Lax",
+		"theme=dark; Max-Age=3600",
+	}}
+	rules := domain.ExtractSpec{
+		"session":      "sid",
+		"session_path": "sid @path",
+		"session_exp":  "sid @Expires",
+		"session_http": "sid @httponly",
+		"session_same": "sid @samesite",
+		"theme_age":    "theme @max-age",
+		"theme_secure": "theme @secure",
+		"theme_domain": "theme @domain",
+		"csrf":         "csrf",
+	}
+
+	vars, res := ApplyCookies(headers, rules)
+	want := domain.Vars{
+		"session":      "abc123",
+		"session_path": "/app",
+		"session_exp":  "2026-10-21T07:28:00Z",
+		"session_http": "true",
+		"session_same": "Lax",
+		"theme_age":    "3600",
+		"theme_secure": "false",
+	}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, vars[k])
+		}
+	}
+	failures := map[string]string{}
+	for _, r := range res {
+		if !r.Success {
+			failures[r.Name] = r.Message
+		}
+	}
+	if len(failures) != 2 ||
+		failures["csrf"] != `extract_cookie "csrf" (csrf): cookie not found` ||
+		failures["theme_domain"] != `extract_cookie "theme_domain" (theme @domain): no domain attribute` {
+		t.Fatalf("unexpected failures: %v", failures)
+	}
+}
+
+func TestApplyResponse(t *testing.T) {
+	rr := domain.RequestResult{
+		StatusCode:  201,
+		ResolvedURL: "http://api.test/login",
+		Response:    domain.ResponseSnapshot{FinalURL: "http://api.test/home?welcome=1"},
+	}
+	vars, res := ApplyResponse(rr, domain.ExtractSpec{"code": "status", "landing": "url"})
+	if vars["code"] != "201" || vars["landing"] != "http://api.test/home?welcome=1" || len(res) != 2 {
+		t.Fatalf("unexpected outcome: vars=%v res=%+v", vars, res)
+	}
+
+	rr.Response.FinalURL = ""
+	if vars, _ := ApplyResponse(rr, domain.ExtractSpec{"landing": "url"}); vars["landing"] != "http://api.test/login" {
+		t.Fatalf("expected the request URL without redirects, got %q", vars["landing"])
+	}
+
+	_, res = ApplyResponse(domain.RequestResult{}, domain.ExtractSpec{"code": "status"})
+	if len(res) != 1 || res[0].Success || res[0].Message != `extract_response "code" (status): no response` {
+		t.Fatalf("expected a failure without a response, got %+v", res)
+	}
+}
@@ -0,0 +1,143 @@
+// Package markup queries XML bodies with XPath and HTML bodies with CSS
+// selectors, for assertions and extraction on non-JSON responses.
+//
+// Node sets become a []any of strings (text content, or attribute values),
+// like a JSONPath wildcard result, so the same value operators apply.
+package markup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+	"golang.org/x/net/html"
+)
+
+// ErrNoMatch is returned when an expression selects nothing.
+var ErrNoMatch = errors.New("no match")
+
+// ParseXML parses an XML (or SOAP) response body.
+func ParseXML(body []byte) (*xmlquery.Node, error) {
+	doc, err := xmlquery.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("response body is not valid XML: %w", err)
+	}
+	return doc, nil
+}
+
+// ValidateXPath compiles an XPath expression.
+func ValidateXPath(expr string) error {
+	_, err := xpath.Compile(expr)
+	return err
+}
+
+// XPath evaluates expr against doc. Node sets yield the text of each node
+// (the value, for attributes); functions such as count() or string() yield
+// their number, string or boolean.
+func XPath(doc *xmlquery.Node, expr string) (any, error) {
+	compiled, err := xpath.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid xpath: %w", err)
+	}
+	switch v := compiled.Evaluate(xmlquery.CreateXPathNavigator(doc)).(type) {
+	case *xpath.NodeIterator:
+		var out []any
+		for v.MoveNext() {
+			out = append(out, strings.TrimSpace(v.Current().Value()))
+		}
+		if len(out) == 0 {
+			return nil, ErrNoMatch
+		}
+		return out, nil
+	case float64, string, bool:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unsupported xpath result %T", v)
+	}
+}
+
+// ParseHTML parses an HTML response body. Like browsers, the parser accepts
+// any input.
+func ParseHTML(body []byte) (*html.Node, error) {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("response body is not valid HTML: %w", err)
+	}
+	return doc, nil
+}
+
+// cssQuery is a CSS selector, optionally followed by "@attr" to read an
+// attribute instead of the text: "a.next @href".
+type cssQuery struct {
+	sel  cascadia.Selector
+	attr string
+}
+
+var attrSuffix = regexp.MustCompile(`(?:^|\s)@([\w:.-]+)\s*$`)
+
+func parseCSS(query string) (cssQuery, error) {
+	sel, attr := strings.TrimSpace(query), ""
+	if m := attrSuffix.FindStringSubmatchIndex(sel); m != nil {
+		sel, attr = strings.TrimSpace(sel[:m[0]]), sel[m[2]:m[3]]
+	}
+	if sel == "" {
+		return cssQuery{}, errors.New("empty selector")
+	}
+	compiled, err := cascadia.Compile(sel)
+	if err != nil {
+		return cssQuery{}, err
+	}
+	return cssQuery{sel: compiled, attr: attr}, nil
+}
+
+// ValidateCSS compiles a CSS selector with its optional @attr suffix.
+func ValidateCSS(query string) error {
+	_, err := parseCSS(query)
+	return err
+}
+
+// CSS selects from doc: the text of each matched element with whitespace
+// collapsed as a browser renders it, or the value of the @attr of those
+// that have it.
+func CSS(doc *html.Node, query string) (any, error) {
+	q, err := parseCSS(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid css selector: %w", err)
+	}
+	var out []any
+	for _, n := range q.sel.MatchAll(doc) {
+		if q.attr == "" {
+			out = append(out, strings.Join(strings.Fields(textContent(n)), " "))
+			continue
+		}
+		for _, a := range n.Attr {
+			if a.Key == q.attr {
+				out = append(out, a.Val)
+				break
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil, ErrNoMatch
+	}
+	return out, nil
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && (c.Data == "script" || c.Data == "style") {
+			continue
+		}
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package markup
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const soapBody = `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <GetOrderResponse>
+      <Order id="42" status="paid">
+        <Item sku="a">  Pen  </Item>
+        <Item sku="b">Ink</Item>
+      </Order>
+    </GetOrderResponse>
+  </soap:Body>
+</soap:Envelope>`
+
+func TestXPath(t *testing.T) {
+	doc, err := ParseXML([]byte(soapBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		expr string
+		want any
+	}{
+		{"//Order/@id", []any{"42"}},
+		{"//Item", []any{"Pen", "Ink"}},
+		{"//soap:Body//Item[@sku='b']", []any{"Ink"}},
+		{"//*[local-name()='Order']/@status", []any{"paid"}},
+		{"count(//Item)", 2.0},
+		{"string(//Order/@status)", "paid"},
+		{"boolean(//Refund)", false},
+	}
+	for _, tt := range tests {
+		got, err := XPath(doc, tt.expr)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("XPath(%q) = %#v, %v; want %#v", tt.expr, got, err, tt.want)
+		}
+	}
+
+	if _, err := XPath(doc, "//Refund"); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("missing node: got %v", err)
+	}
+	if _, err := ParseXML([]byte("<a><b></a>")); err == nil {
+		t.Error("expected an XML parse error")
+	}
+	if err := ValidateXPath("//Order[@id="); err == nil {
+		t.Error("expected an XPath compile error")
+	}
+}
+
+const pageBody = `<!doctype html>
+<html><head><title>Orders</title><script>var x = "<li>";</script></head>
+<body>
+  <h1 class="title">
+    Your   <em>orders</em>
+  </h1>
+  <ul>
+    <li class="order" data-id="1">Pen</li>
+    <li class="order" data-id="2">Ink</li>
+    <li class="order">Paper</li>
+  </ul>
+  <a class="next" href="/orders?page=2">Next</a>
+  <a href="mailto:help@example.com">Help</a>
+</body></html>`
+
+func TestCSS(t *testing.T) {
+	doc, err := ParseHTML([]byte(pageBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		query string
+		want  []any
+	}{
+		{"h1.title", []any{"Your orders"}},
+		{"li.order", []any{"Pen", "Ink", "Paper"}},
+		{"li.order @data-id", []any{"1", "2"}},
+		{"a.next @href", []any{"/orders?page=2"}},
+		{`a[href^="mailto:help@example"]`, []any{"Help"}},
+		{"head", []any{"Orders"}},
+	}
+	for _, tt := range tests {
+		got, err := CSS(doc, tt.query)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("CSS(%q) = %#v, %v; want %#v", tt.query, got, err, tt.want)
+		}
+	}
+
+	if _, err := CSS(doc, "table"); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("missing element: got %v", err)
+	}
+	for _, bad := range []string{"li[", "@href", ""} {
+		if err := ValidateCSS(bad); err == nil {
+			t.Errorf("ValidateCSS(%q): expected an error", bad)
+		}
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -29,6 +31,23 @@ type RunOpts struct {
 	// Vars are CLI-level overrides (--var key=value). Highest precedence:
 	// they win over secrets, environment, and collection vars.
 	Vars domain.Vars
+
+	// Observer is notified as the run progresses (optional).
+	Observer ports.RunObserver
+
+	// Metadata is copied into RunResult.Metadata (optional).
+	Metadata map[string]string
+
+	// Baseline is the earlier run that max_ms_regression assertions compare
+	// against (optional). Only requests that completed in it count.
+	Baseline *domain.RunResult
+
+	// Snapshots stores the bodies snapshot assertions compare against
+	// (optional; without it those assertions fail).
+	Snapshots ports.SnapshotStore
+
+	// UpdateSnapshots rewrites snapshots that differ instead of failing.
+	UpdateSnapshots bool
 }
 
 type RunCollection struct {
@@ -45,6 +64,11 @@ type RunCollection struct {
 	dryRun      bool
 	parallel    bool
 	extraVars   domain.Vars
+	observer    ports.RunObserver
+	metadata    map[string]string
+	baseline    map[string]int64 // request name → latency in the baseline run
+	snapshots   ports.SnapshotStore
+	updateSnaps bool
 	resolver    *domain.VarResolver
 }
 
@@ -55,6 +79,10 @@ func NewRunCollection(
 	store ports.ArtifactStore,
 	opts RunOpts,
 ) *RunCollection {
+	observer := opts.Observer
+	if observer == nil {
+		observer = nopObserver{}
+	}
 	return &RunCollection{
 		collections: cl,
 		envs:        el,
@@ -69,6 +97,11 @@ func NewRunCollection(
 		dryRun:      opts.DryRun,
 		parallel:    opts.Parallel,
 		extraVars:   opts.Vars,
+		observer:    observer,
+		metadata:    opts.Metadata,
+		baseline:    baselineLatencies(opts.Baseline),
+		snapshots:   opts.Snapshots,
+		updateSnaps: opts.UpdateSnapshots,
 		resolver:    domain.NewVarResolver(),
 	}
 }
@@ -76,8 +109,8 @@ func NewRunCollection(
 // evaluateAssertions resolves {{var}} references in expected values and runs
 // the assertion engine. A resolution failure (e.g. a typo'd variable) surfaces
 // as a failing assertion instead of silently comparing against the raw text.
-func (uc *RunCollection) evaluateAssertions(req domain.RequestSpec, rr domain.RequestResult, schemaBytes []byte, vars domain.Vars) []domain.AssertionResult {
-	spec, err := uc.resolver.ResolveAssertionValues(vars, req.Assert)
+func (uc *RunCollection) evaluateAssertions(collectionPath string, req domain.RequestSpec, rr domain.RequestResult, schemaBytes []byte, vars domain.Vars, types map[string]domain.VarType) []domain.AssertionResult {
+	spec, err := uc.resolver.ResolveAssertionValues(vars, types, req.Assert)
 	if err != nil {
 		return []domain.AssertionResult{{
 			Name:    "assert.resolve",
@@ -85,7 +118,31 @@ func (uc *RunCollection) evaluateAssertions(req domain.RequestSpec, rr domain.Re
 			Message: fmt.Sprintf("cannot resolve assertion value: %v", err),
 		}}
 	}
-	return ucassert.Evaluate(spec, rr.StatusCode, rr.LatencyMS, rr.Response.Body, schemaBytes, rr.Response.Headers, rr.Response.Truncated)
+	out := ucassert.Evaluate(spec, rr.StatusCode, rr.LatencyMS, rr.Response.Body, schemaBytes, rr.Response.Headers, rr.Response.Truncated)
+	if spec.MaxMSRegression != nil {
+		base, ok := uc.baseline[req.Name]
+		out = append(out, ucassert.LatencyRegression(*spec.MaxMSRegression, rr.LatencyMS, base, ok))
+	}
+	if spec.Snapshot != nil {
+		out = append(out, uc.snapshotAssertion(collectionPath, *spec.Snapshot, rr))
+	}
+	return out
+}
+
+// baselineLatencies indexes the latencies of the baseline's completed
+// requests; errored requests report 0ms and would make any latency a
+// regression.
+func baselineLatencies(run *domain.RunResult) map[string]int64 {
+	if run == nil {
+		return nil
+	}
+	out := make(map[string]int64, len(run.Results))
+	for _, r := range run.Results {
+		if r.Error == nil {
+			out[r.Name] = r.LatencyMS
+		}
+	}
+	return out
 }
 
 // Execute runs a collection and (optionally) persists the artifact via ArtifactStore.
@@ -94,6 +151,19 @@ func (uc *RunCollection) Execute(
 	ctx context.Context,
 	collectionPath string,
 	envNameOrPath string,
+) (domain.RunResult, string, error) {
+	run, id, err := uc.execute(ctx, collectionPath, envNameOrPath)
+	// Runs that never started (load/filter errors) produce no events.
+	if !run.StartedAt.IsZero() {
+		uc.observer.RunFinished(run, id, err)
+	}
+	return run, id, err
+}
+
+func (uc *RunCollection) execute(
+	ctx context.Context,
+	collectionPath string,
+	envNameOrPath string,
 ) (domain.RunResult, string, error) {
 	col, err := uc.collections.LoadCollection(collectionPath)
 	if err != nil {
@@ -112,7 +182,10 @@ func (uc *RunCollection) Execute(
 
 	// Pre-load schema files AFTER filtering so indices match the slice that
 	// actually runs (a mismatch would validate the wrong request's schema).
+	// json_eq fixtures load here too, into a copy of the requests so the
+	// loader's collection is left as it was.
 	schemaCache := make(map[int][]byte) // request index → schema bytes
+	col.Requests = slices.Clone(col.Requests)
 	for i, req := range col.Requests {
 		sb, err := loadSchemaBytes(req.Assert)
 		if err != nil {
@@ -121,21 +194,28 @@ func (uc *RunCollection) Execute(
 		if sb != nil {
 			schemaCache[i] = sb
 		}
+		if col.Requests[i].Assert.JSONEq, err = loadJSONEqFixtures(req.Assert.JSONEq); err != nil {
+			return domain.RunResult{}, "", fmt.Errorf("request %q: %w", req.Name, err)
+		}
 	}
 
 	// collection vars < env vars < CLI --var overrides < extracted runtime vars
 	vars := domain.Merge(domain.Merge(col.Vars, env.Vars), uc.extraVars)
+	types := map[string]domain.VarType{}
+	env.ApplyTypes(types)
 
 	run := domain.RunResult{
 		CollectionName:  col.Name,
 		CollectionPath:  collectionPath,
 		EnvironmentName: env.Name,
 		StartedAt:       time.Now(),
+		Metadata:        maps.Clone(uc.metadata),
 		Results:         make([]domain.RequestResult, 0, len(col.Requests)),
 	}
+	uc.observer.RunStarted(run, col.Requests)
 
 	if uc.parallel && !uc.dryRun {
-		if err := uc.executeParallel(ctx, col.Requests, vars, schemaCache, &run); err != nil {
+		if err := uc.executeParallel(ctx, col.Requests, vars, types, schemaCache, &run); err != nil {
 			run.EndedAt = time.Now()
 			return run, "", err
 		}
@@ -158,11 +238,13 @@ func (uc *RunCollection) Execute(
 		}
 
 		if uc.dryRun {
-			rr, resolveErr := uc.resolveOnly(vars, req)
+			uc.observer.RequestStarted(i, req)
+			rr, resolveErr := uc.resolveOnly(vars, types, req)
 			if resolveErr != nil {
 				rr.Error = domain.NewRunError(resolveErr)
 			}
 			run.Results = append(run.Results, rr)
+			uc.observer.RequestFinished(i, rr)
 			continue
 		}
 
@@ -171,30 +253,22 @@ func (uc *RunCollection) Execute(
 			case <-ctx.Done():
 				// Record the interrupted request (parity with parallel mode)
 				// so it never vanishes from the report.
-				run.Results = append(run.Results, erroredResult(req, ctx.Err()))
+				rr := erroredResult(req, ctx.Err())
+				run.Results = append(run.Results, rr)
+				uc.observer.RequestFinished(i, rr)
 				run.EndedAt = time.Now()
 				return run, "", ctx.Err()
 			case <-time.After(time.Duration(*req.DelayMS) * time.Millisecond):
 			}
 		}
 
-		rr, runErr := uc.runWithRetries(ctx, req, vars)
+		uc.observer.RequestStarted(i, req)
+		rr, runErr := uc.runWithRetries(ports.ContextWithRequestIndex(ctx, i), req, vars, types)
 		if runErr != nil {
 			// Runner error (config-level): continue but mark the request as failed.
-			run.Results = append(run.Results, domain.RequestResult{
-				Name:           req.Name,
-				Method:         req.Method,
-				URL:            req.URL,
-				RequestHeaders: map[string]string{},
-				Assertions:     []domain.AssertionResult{},
-				Extracts:       []domain.ExtractResult{},
-				Extracted:      domain.Vars{},
-				Response: domain.ResponseSnapshot{
-					Headers: map[string][]string{},
-				},
-				Error:    domain.NewRunError(runErr),
-				Attempts: 1,
-			})
+			rr = erroredResult(req, runErr)
+			run.Results = append(run.Results, rr)
+			uc.observer.RequestFinished(i, rr)
 			if uc.failFast {
 				break
 			}
@@ -202,24 +276,15 @@ func (uc *RunCollection) Execute(
 		}
 
 		// Assertions (always evaluated, even if rr.Error != nil)
-		rr.Assertions = uc.evaluateAssertions(req, rr, schemaCache[i], vars)
-
-		extracted, extractResults := ucextract.Apply(rr.Response.Body, req.Extract, rr.Response.Truncated)
-		headerExtracted, headerExtractResults := ucextract.ApplyHeaders(rr.Response.Headers, req.ExtractHeaders)
-		rr.Extracts = append(extractResults, headerExtractResults...)
-		rr.Extracted = extracted
+		rr.Assertions = uc.evaluateAssertions(collectionPath, req, rr, schemaCache[i], vars, types)
 
-		// Merge header-extracted vars into extracted map.
-		for k, v := range headerExtracted {
-			rr.Extracted[k] = v
-		}
+		rr.Extracted, rr.Extracts = applyExtraction(req, rr)
 
 		// Update runtime vars for next request (even if extract had failures, extracted map may be partial).
-		for k, v := range rr.Extracted {
-			vars[k] = v
-		}
+		mergeExtracted(vars, types, req, rr.Extracted)
 
 		run.Results = append(run.Results, rr)
+		uc.observer.RequestFinished(i, rr)
 
 		if uc.failFast && rr.Failed() {
 			break
@@ -251,6 +316,7 @@ func (uc *RunCollection) runWithRetries(
 	ctx context.Context,
 	req domain.RequestSpec,
 	vars domain.Vars,
+	types map[string]domain.VarType,
 ) (domain.RequestResult, error) {
 	maxAttempts := 1 + uc.retries
 	var rr domain.RequestResult
@@ -260,7 +326,7 @@ func (uc *RunCollection) runWithRetries(
 		}
 
 		var runErr error
-		rr, runErr = uc.runner.Run(ctx, req, vars)
+		rr, runErr = uc.runner.Run(ctx, req, vars, types)
 		rr.Attempts = attempt
 
 		// Config-level error: never retry.
@@ -368,10 +434,37 @@ func loadSchemaBytes(spec domain.AssertionsSpec) ([]byte, error) {
 	return nil, nil
 }
 
+// loadJSONEqFixtures reads the fixture files of json_eq assertions into
+// their expected values. The input slice is never modified.
+func loadJSONEqFixtures(in []domain.JSONEqAssertion) ([]domain.JSONEqAssertion, error) {
+	var out []domain.JSONEqAssertion
+	for i, a := range in {
+		if a.File == nil {
+			continue
+		}
+		b, err := os.ReadFile(*a.File)
+		if err != nil {
+			return nil, fmt.Errorf("json_eq file %q: %w", *a.File, err)
+		}
+		var v any
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, fmt.Errorf("json_eq file %q: %w", *a.File, err)
+		}
+		if out == nil {
+			out = slices.Clone(in)
+		}
+		out[i].Value = v
+	}
+	if out == nil {
+		return in, nil
+	}
+	return out, nil
+}
+
 // resolveOnly resolves variables in a request without executing it (dry-run mode).
-func (uc *RunCollection) resolveOnly(vars domain.Vars, req domain.RequestSpec) (domain.RequestResult, error) {
+func (uc *RunCollection) resolveOnly(vars domain.Vars, types map[string]domain.VarType, req domain.RequestSpec) (domain.RequestResult, error) {
 	resolver := domain.NewVarResolver()
-	rt, err := resolver.NewRuntime(vars)
+	rt, err := resolver.NewRuntime(vars, types)
 	if err != nil {
 		return domain.RequestResult{Name: req.Name, Method: req.Method, URL: req.URL}, err
 	}
@@ -401,6 +494,7 @@ func (uc *RunCollection) executeParallel(
 	ctx context.Context,
 	requests []domain.RequestSpec,
 	vars domain.Vars,
+	types map[string]domain.VarType,
 	schemaCache map[int][]byte,
 	run *domain.RunResult,
 ) error {
@@ -414,6 +508,7 @@ func (uc *RunCollection) executeParallel(
 
 		// Snapshot vars for this level — goroutines only read from this.
 		levelVars := cloneVars(vars)
+		levelTypes := maps.Clone(types)
 
 		g, gctx := errgroup.WithContext(ctx)
 
@@ -426,31 +521,29 @@ func (uc *RunCollection) executeParallel(
 					select {
 					case <-gctx.Done():
 						results[idx] = erroredResult(req, gctx.Err())
+						uc.observer.RequestFinished(idx, results[idx])
 						return gctx.Err()
 					case <-time.After(time.Duration(*req.DelayMS) * time.Millisecond):
 					}
 				}
 
-				rr, runErr := uc.runWithRetries(gctx, req, levelVars)
+				uc.observer.RequestStarted(idx, req)
+				rr, runErr := uc.runWithRetries(ports.ContextWithRequestIndex(gctx, idx), req, levelVars, levelTypes)
 				if runErr != nil {
 					results[idx] = erroredResult(req, runErr)
+					uc.observer.RequestFinished(idx, results[idx])
 					if uc.failFast {
 						return fmt.Errorf("request %q failed: %w", req.Name, runErr)
 					}
 					return nil
 				}
 
-				rr.Assertions = uc.evaluateAssertions(req, rr, schemaCache[idx], levelVars)
+				rr.Assertions = uc.evaluateAssertions(run.CollectionPath, req, rr, schemaCache[idx], levelVars, levelTypes)
 
-				extracted, extractResults := ucextract.Apply(rr.Response.Body, req.Extract, rr.Response.Truncated)
-				headerExtracted, headerExtractResults := ucextract.ApplyHeaders(rr.Response.Headers, req.ExtractHeaders)
-				rr.Extracts = append(extractResults, headerExtractResults...)
-				rr.Extracted = extracted
-				for k, v := range headerExtracted {
-					rr.Extracted[k] = v
-				}
+				rr.Extracted, rr.Extracts = applyExtraction(req, rr)
 
 				results[idx] = rr
+				uc.observer.RequestFinished(idx, rr)
 
 				if uc.failFast && rr.Failed() {
 					return fmt.Errorf("request %q failed assertions", req.Name)
@@ -466,9 +559,7 @@ func (uc *RunCollection) executeParallel(
 
 		// Single-threaded merge of extracted vars for the next level.
 		for _, idx := range level {
-			for k, v := range results[idx].Extracted {
-				vars[k] = v
-			}
+			mergeExtracted(vars, types, requests[idx], results[idx].Extracted)
 		}
 	}
 
@@ -483,6 +574,47 @@ func (uc *RunCollection) executeParallel(
 	return ctx.Err()
 }
 
+// nopObserver is used when RunOpts.Observer is nil.
+type nopObserver struct{}
+
+func (nopObserver) RunStarted(domain.RunResult, []domain.RequestSpec) {}
+func (nopObserver) RequestStarted(int, domain.RequestSpec)            {}
+func (nopObserver) RequestFinished(int, domain.RequestResult)         {}
+func (nopObserver) RunFinished(domain.RunResult, string, error)       {}
+
+// applyExtraction runs every extract block of req against the response, in
+// a fixed order: JSONPath, headers, XPath, CSS, regex, cookies, response.
+// On a name clash the later block wins.
+func applyExtraction(req domain.RequestSpec, rr domain.RequestResult) (domain.Vars, []domain.ExtractResult) {
+	body, truncated := rr.Response.Body, rr.Response.Truncated
+	extracted, results := ucextract.ApplyWithOptions(body, req.Extract, req.ExtractOptions, truncated)
+	merge := func(vars domain.Vars, res []domain.ExtractResult) {
+		results = append(results, res...)
+		for k, v := range vars {
+			extracted[k] = v
+		}
+	}
+	merge(ucextract.ApplyHeaders(rr.Response.Headers, req.ExtractHeaders))
+	merge(ucextract.ApplyXPath(body, req.ExtractXPath, truncated))
+	merge(ucextract.ApplyCSS(body, req.ExtractCSS, truncated))
+	merge(ucextract.ApplyRegex(body, rr.Response.Headers, req.ExtractRegex, truncated))
+	merge(ucextract.ApplyCookies(rr.Response.Headers, req.ExtractCookies))
+	merge(ucextract.ApplyResponse(rr, req.ExtractResponse))
+	return extracted, results
+}
+
+// mergeExtracted adds the variables req extracted to vars. A type from the
+// extract options replaces the one in types; without one, a type annotated
+// in the environment stays.
+func mergeExtracted(vars domain.Vars, types map[string]domain.VarType, req domain.RequestSpec, extracted domain.Vars) {
+	for k, v := range extracted {
+		vars[k] = v
+		if t := req.ExtractOptions[k].Type; t != "" {
+			types[k] = t
+		}
+	}
+}
+
 // erroredResult builds a placeholder result for a request that could not
 // complete (runner error or cancellation) so it never vanishes from reports.
 func erroredResult(req domain.RequestSpec, err error) domain.RequestResult {
@@ -13,7 +13,7 @@ type countingRunner struct {
 	calls int
 }
 
-func (r *countingRunner) Run(_ context.Context, _ domain.RequestSpec, _ domain.Vars) (domain.RequestResult, error) {
+func (r *countingRunner) Run(_ context.Context, _ domain.RequestSpec, _ domain.Vars, _ map[string]domain.VarType) (domain.RequestResult, error) {
 	r.calls++
 	return domain.RequestResult{
 		Name:     "ok",
@@ -23,7 +23,7 @@ type safeCallCounter struct {
 	cur   int32
 }
 
-func (s *safeCallCounter) Run(ctx context.Context, req domain.RequestSpec, vars domain.Vars) (domain.RequestResult, error) {
+func (s *safeCallCounter) Run(ctx context.Context, req domain.RequestSpec, vars domain.Vars, types map[string]domain.VarType) (domain.RequestResult, error) {
 	s.calls.Add(1)
 	s.mu.Lock()
 	s.cur++
@@ -38,7 +38,7 @@ func (s *safeCallCounter) Run(ctx context.Context, req domain.RequestSpec, vars
 		}
 	}
 
-	res, err := s.inner.Run(ctx, req, vars)
+	res, err := s.inner.Run(ctx, req, vars, types)
 
 	s.mu.Lock()
 	s.cur--
@@ -306,3 +306,44 @@ func TestRunCollection_Parallel_AssertVarDependencyScheduled(t *testing.T) {
 		}
 	}
 }
+
+// echoRunner answers 200 for every request and names the result after it, as
+// the HTTP runner does. It is stateless, so safe for parallel runs.
+type echoRunner struct{}
+
+func (echoRunner) Run(_ context.Context, req domain.RequestSpec, _ domain.Vars, _ map[string]domain.VarType) (domain.RequestResult, error) {
+	return domain.RequestResult{Name: req.Name, Method: req.Method, StatusCode: 200}, nil
+}
+
+func TestParallel_ObserverSeesEveryRequest(t *testing.T) {
+	col := domain.Collection{
+		Name: "parallel",
+		Requests: []domain.RequestSpec{
+			{Name: "a", Method: domain.MethodGet, URL: "http://example.com/a"},
+			{Name: "b", Method: domain.MethodGet, URL: "http://example.com/b"},
+			{Name: "c", Method: domain.MethodGet, URL: "http://example.com/c"},
+		},
+	}
+	obs := &recordingObserver{}
+	uc := NewRunCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{}, echoRunner{}, nil, RunOpts{Parallel: true, Observer: obs})
+
+	if _, _, err := uc.Execute(context.Background(), "col.yaml", "env.yaml"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(obs.events) != 8 {
+		t.Fatalf("expected 8 events, got %v", obs.events)
+	}
+	if obs.events[0] != "run_started:3" || obs.events[7] != "run_finished:3::<nil>" {
+		t.Errorf("run events out of place: %v", obs.events)
+	}
+	seen := map[string]bool{}
+	for _, ev := range obs.events[1:7] {
+		seen[ev] = true
+	}
+	for _, name := range []string{"0:a", "1:b", "2:c"} {
+		if !seen["request_started:"+name] || !seen["request_finished:"+name] {
+			t.Errorf("missing events for %s: %v", name, obs.events)
+		}
+	}
+}
@@ -3,9 +3,15 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -71,7 +77,7 @@ type stubRunner struct {
 	calls  int
 }
 
-func (s *stubRunner) Run(_ context.Context, _ domain.RequestSpec, _ domain.Vars) (domain.RequestResult, error) {
+func (s *stubRunner) Run(_ context.Context, _ domain.RequestSpec, _ domain.Vars, _ map[string]domain.VarType) (domain.RequestResult, error) {
 	s.calls++
 	return s.result, s.err
 }
@@ -84,7 +90,7 @@ type multiCallRunner struct {
 	idx          int
 }
 
-func (m *multiCallRunner) Run(_ context.Context, _ domain.RequestSpec, vars domain.Vars) (domain.RequestResult, error) {
+func (m *multiCallRunner) Run(_ context.Context, _ domain.RequestSpec, vars domain.Vars, _ map[string]domain.VarType) (domain.RequestResult, error) {
 	snap := make(domain.Vars, len(vars))
 	for k, v := range vars {
 		snap[k] = v
@@ -111,7 +117,7 @@ type ctxCancelRunner struct {
 	called int
 }
 
-func (r *ctxCancelRunner) Run(_ context.Context, _ domain.RequestSpec, _ domain.Vars) (domain.RequestResult, error) {
+func (r *ctxCancelRunner) Run(_ context.Context, _ domain.RequestSpec, _ domain.Vars, _ map[string]domain.VarType) (domain.RequestResult, error) {
 	r.called++
 	if r.called == 1 {
 		r.cancel()
@@ -151,6 +157,62 @@ func TestRunCollection_Execute_StoreNil(t *testing.T) {
 	}
 }
 
+func TestRunCollection_Execute_CopiesMetadata(t *testing.T) {
+	col := domain.Collection{
+		Name:     "test",
+		Requests: []domain.RequestSpec{{Name: "req1", Method: domain.MethodGet, URL: "http://example.com"}},
+	}
+	meta := map[string]string{"git.commit": "abc123", "team": "payments"}
+	uc := NewRunCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{}, &stubRunner{}, nil, RunOpts{Metadata: meta})
+
+	run, _, err := uc.Execute(context.Background(), "col.yaml", "env.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(run.Metadata, meta) {
+		t.Fatalf("expected metadata %v, got %v", meta, run.Metadata)
+	}
+	run.Metadata["team"] = "changed"
+	if meta["team"] != "payments" {
+		t.Fatal("the run must hold its own copy of the metadata")
+	}
+}
+
+func TestRunCollection_Execute_MaxMSRegressionUsesBaseline(t *testing.T) {
+	budget := 50
+	col := domain.Collection{
+		Name: "test",
+		Requests: []domain.RequestSpec{
+			{Name: "fast", Method: domain.MethodGet, URL: "http://x", Assert: domain.AssertionsSpec{MaxMSRegression: &budget}},
+			{Name: "slow", Method: domain.MethodGet, URL: "http://x", Assert: domain.AssertionsSpec{MaxMSRegression: &budget}},
+			{Name: "new", Method: domain.MethodGet, URL: "http://x", Assert: domain.AssertionsSpec{MaxMSRegression: &budget}},
+		},
+	}
+	baseline := &domain.RunResult{Results: []domain.RequestResult{
+		{Name: "fast", LatencyMS: 120},
+		{Name: "slow", LatencyMS: 100},
+		{Name: "new", Error: &domain.RunError{Kind: domain.RunErrorTimeout}},
+	}}
+	runner := &stubRunner{result: domain.RequestResult{StatusCode: 200, LatencyMS: 160}}
+	uc := NewRunCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{}, runner, nil, RunOpts{Baseline: baseline})
+
+	run, _, err := uc.Execute(context.Background(), "col.yaml", "env.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// fast: 160 <= 120+50; slow: 160 > 100+50; new: no completed baseline.
+	want := []bool{true, false, true}
+	for i, r := range run.Results {
+		name := col.Requests[i].Name
+		if len(r.Assertions) != 1 || r.Assertions[0].Name != "max_ms_regression" {
+			t.Fatalf("%s: expected one max_ms_regression assertion, got %+v", name, r.Assertions)
+		}
+		if got := r.Assertions[0].Passed; got != want[i] {
+			t.Errorf("%s: passed = %v, want %v (%s)", name, got, want[i], r.Assertions[0].Message)
+		}
+	}
+}
+
 func TestRunCollection_Execute_StoreCalled(t *testing.T) {
 	col := domain.Collection{
 		Name: "test",
@@ -348,6 +410,48 @@ func TestRunCollection_Execute_VarChainingViaExtract(t *testing.T) {
 	}
 }
 
+func TestRunCollection_Execute_VarChainingViaRegexCookieAndResponse(t *testing.T) {
+	col := domain.Collection{
+		Requests: []domain.RequestSpec{
+			{
+				Name:            "create",
+				Method:          domain.MethodPost,
+				URL:             "http://example.com/orders",
+				ExtractRegex:    []domain.ExtractRegexRule{{Pattern: `/orders/(?P<order_id>\d+)`, Header: "Location"}},
+				ExtractCookies:  domain.ExtractSpec{"session": "sid"},
+				ExtractResponse: domain.ExtractSpec{"created_status": "status"},
+			},
+			{
+				Name:   "get",
+				Method: domain.MethodGet,
+				URL:    "http://example.com/orders/{{order_id}}",
+			},
+		},
+	}
+	runner := &multiCallRunner{
+		results: []domain.RequestResult{
+			{StatusCode: 201, Response: domain.ResponseSnapshot{Headers: map[string][]string{
+				"Location":   {"/orders/77"},
+				"Set-Cookie": {"sid=s3cr3t; HttpOnly"},
+			}}},
+			{StatusCode: 200},
+		},
+	}
+	uc := NewRunCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{}, runner, nil, RunOpts{})
+
+	run, _, err := uc.Execute(context.Background(), "col.yaml", "env.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := runner.capturedVars[1]
+	if got["order_id"] != "77" || got["session"] != "s3cr3t" || got["created_status"] != "201" {
+		t.Fatalf("expected extracted vars in the second request, got %v", got)
+	}
+	if n := len(run.Results[0].Extracts); n != 3 {
+		t.Fatalf("expected 3 extract results, got %d", n)
+	}
+}
+
 // --- integration tests (real HTTP) ---
 
 func TestRunCollection_ExtractsAndChainsVars(t *testing.T) {
@@ -462,6 +566,127 @@ func TestRunCollection_ExtractsAndChainsVars(t *testing.T) {
 	}
 }
 
+func TestRunCollection_TypedExtractKeepsJSONTypeInBody(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/cart":
+			w.Write([]byte(`{"items":[{"sku":"a","qty":2}],"total":9.5}`))
+		case "/orders":
+			b, _ := io.ReadAll(r.Body)
+			got = string(b)
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer srv.Close()
+
+	col := domain.Collection{
+		Vars: domain.Vars{"base_url": srv.URL},
+		Requests: []domain.RequestSpec{
+			{
+				Name:    "cart",
+				Method:  domain.MethodGet,
+				URL:     "{{base_url}}/cart",
+				Body:    domain.BodySpec{Type: domain.BodyNone},
+				Extract: domain.ExtractSpec{"items": "$.items", "total": "$.total", "coupon": "$.coupon"},
+				ExtractOptions: map[string]domain.ExtractOptions{
+					"items":  {Type: domain.VarJSON},
+					"total":  {Type: domain.VarJSON},
+					"coupon": {Optional: true},
+				},
+			},
+			{
+				Name:   "order",
+				Method: domain.MethodPost,
+				URL:    "{{base_url}}/orders",
+				Body: domain.BodySpec{Type: domain.BodyJSON, JSON: map[string]any{
+					"items": "{{items}}",
+					"total": "{{total}}",
+					"note":  "total {{total}}",
+				}},
+			},
+		},
+	}
+
+	r := httprunner.New(httpclient.New(httpclient.DefaultConfig()))
+	uc := NewRunCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{}, r, nil, RunOpts{})
+
+	run, _, err := uc.Execute(context.Background(), "col.yaml", "")
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if run.Results[0].Failed() {
+		t.Fatalf("an optional missing extract must not fail the request: %+v %+v", run.Results[0].Extracts, run.Results[0].Error)
+	}
+	want := `{"items":[{"qty":2,"sku":"a"}],"note":"total 9.5","total":9.5}`
+	if got != want {
+		t.Fatalf("request body = %s, want %s", got, want)
+	}
+}
+
+func TestRunCollection_TypedPlaceholdersInBody(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/me":
+			w.Write([]byte(`{"id":"42"}`))
+		case "/search":
+			b, _ := io.ReadAll(r.Body)
+			got = string(b)
+		}
+	}))
+	defer srv.Close()
+
+	col := domain.Collection{
+		Vars: domain.Vars{"base_url": srv.URL},
+		Requests: []domain.RequestSpec{
+			{
+				Name:    "me",
+				Method:  domain.MethodGet,
+				URL:     "{{base_url}}/me",
+				Body:    domain.BodySpec{Type: domain.BodyNone},
+				Extract: domain.ExtractSpec{"user_id": "$.id"},
+			},
+			{
+				Name:   "search",
+				Method: domain.MethodPost,
+				URL:    "{{base_url}}/search?limit={{limit}}",
+				Body: domain.BodySpec{Type: domain.BodyJSON, JSON: map[string]any{
+					"limit":   "{{limit}}",
+					"owner":   "{{user_id}}",
+					"debug":   "{{debug:bool}}",
+					"filters": "{{filters:json}}",
+				}},
+			},
+		},
+	}
+	env := domain.Environment{
+		Vars: domain.Vars{"limit": "10", "debug": "true", "filters": `{"tags":["a"]}`},
+		// An annotation also types the value a request extracts later.
+		Types: map[string]domain.VarType{"limit": domain.VarInt, "user_id": domain.VarInt},
+	}
+
+	r := httprunner.New(httpclient.New(httpclient.DefaultConfig()))
+	uc := NewRunCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{env: env}, r, nil, RunOpts{})
+
+	run, _, err := uc.Execute(context.Background(), "col.yaml", "dev")
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if run.Results[1].Failed() {
+		t.Fatalf("search failed: %+v", run.Results[1].Error)
+	}
+	if run.Results[1].URL != srv.URL+"/search?limit=10" {
+		t.Fatalf("expected the URL to use the value as text, got %s", run.Results[1].URL)
+	}
+	want := `{"debug":true,"filters":{"tags":["a"]},"limit":10,"owner":42}`
+	if got != want {
+		t.Fatalf("request body = %s, want %s", got, want)
+	}
+}
+
 func TestRunCollection_ExtractFail_AllowsNextRequestToFailMissingVar(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/auth" {
@@ -1185,9 +1410,9 @@ type countingRunnerStub struct {
 	calls int
 }
 
-func (c *countingRunnerStub) Run(ctx context.Context, req domain.RequestSpec, vars domain.Vars) (domain.RequestResult, error) {
+func (c *countingRunnerStub) Run(ctx context.Context, req domain.RequestSpec, vars domain.Vars, types map[string]domain.VarType) (domain.RequestResult, error) {
 	c.calls++
-	return c.inner.Run(ctx, req, vars)
+	return c.inner.Run(ctx, req, vars, types)
 }
 
 // compile-time checks
@@ -1242,6 +1467,45 @@ func TestRunCollection_Execute_OnlyFilter_SchemaStillApplied(t *testing.T) {
 	}
 }
 
+func TestRunCollection_Execute_JSONEqFixture(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "user.json")
+	if err := os.WriteFile(fixture, []byte(`{"id":"{{user_id}}","name":"alice"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	col := domain.Collection{
+		Vars: domain.Vars{"user_id": "u-1"},
+		Requests: []domain.RequestSpec{{
+			Name:   "user",
+			Method: domain.MethodGet,
+			URL:    "http://a.com",
+			Assert: domain.AssertionsSpec{JSONEq: []domain.JSONEqAssertion{{File: &fixture}}},
+		}},
+	}
+	runner := &stubRunner{result: domain.RequestResult{
+		StatusCode: 200,
+		Response:   domain.ResponseSnapshot{Body: []byte(`{"name":"alice","id":"u-1"}`)},
+	}}
+	uc := NewRunCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{}, runner, nil, RunOpts{})
+
+	run, _, err := uc.Execute(context.Background(), "col.yaml", "env.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a := run.Results[0].Assertions; len(a) != 1 || a[0].Name != "json_eq" || !a[0].Passed {
+		t.Fatalf("expected a passing json_eq assertion, got %+v", a)
+	}
+	if col.Requests[0].Assert.JSONEq[0].Value != nil {
+		t.Error("the loaded collection was modified")
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	col.Requests[0].Assert.JSONEq = []domain.JSONEqAssertion{{File: &missing}}
+	uc = NewRunCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{}, runner, nil, RunOpts{})
+	if _, _, err := uc.Execute(context.Background(), "col.yaml", "env.yaml"); err == nil || !strings.Contains(err.Error(), "json_eq file") {
+		t.Fatalf("expected a fixture error, got %v", err)
+	}
+}
+
 func TestRunCollection_ParallelCancel_ResultsNotLost(t *testing.T) {
 	// Regression: requests interrupted while waiting on delay_ms vanished
 	// from the report entirely in parallel mode.
@@ -1369,3 +1633,75 @@ func TestRunCollection_Execute_AssertionValueMissingVarFails(t *testing.T) {
 		t.Fatalf("expected failing assert.resolve for typo'd var, got %+v", asserts)
 	}
 }
+
+// recordingObserver records observer calls as short strings.
+type recordingObserver struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (o *recordingObserver) add(ev string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, ev)
+}
+
+func (o *recordingObserver) RunStarted(_ domain.RunResult, reqs []domain.RequestSpec) {
+	o.add(fmt.Sprintf("run_started:%d", len(reqs)))
+}
+
+func (o *recordingObserver) RequestStarted(i int, req domain.RequestSpec) {
+	o.add(fmt.Sprintf("request_started:%d:%s", i, req.Name))
+}
+
+func (o *recordingObserver) RequestFinished(i int, rr domain.RequestResult) {
+	o.add(fmt.Sprintf("request_finished:%d:%s", i, rr.Name))
+}
+
+func (o *recordingObserver) RunFinished(run domain.RunResult, id string, err error) {
+	o.add(fmt.Sprintf("run_finished:%d:%s:%v", len(run.Results), id, err))
+}
+
+func TestRunCollection_Execute_ObserverSequential(t *testing.T) {
+	col := domain.Collection{
+		Name: "test",
+		Requests: []domain.RequestSpec{
+			{Name: "a", Method: domain.MethodGet, URL: "http://example.com/a"},
+			{Name: "b", Method: domain.MethodGet, URL: "http://example.com/b"},
+		},
+	}
+	runner := &multiCallRunner{
+		results: []domain.RequestResult{{Name: "a", StatusCode: 200}},
+		errs:    []error{nil, errors.New("bad request spec")},
+	}
+	obs := &recordingObserver{}
+	uc := NewRunCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{}, runner, &fakeStore{}, RunOpts{Observer: obs})
+
+	if _, _, err := uc.Execute(context.Background(), "col.yaml", "env.yaml"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"run_started:2",
+		"request_started:0:a",
+		"request_finished:0:a",
+		"request_started:1:b",
+		"request_finished:1:b",
+		"run_finished:2:run-123:<nil>",
+	}
+	if !reflect.DeepEqual(obs.events, want) {
+		t.Errorf("events:\n got %v\nwant %v", obs.events, want)
+	}
+}
+
+func TestRunCollection_Execute_ObserverSilentWhenRunNeverStarts(t *testing.T) {
+	obs := &recordingObserver{}
+	uc := NewRunCollection(errCollectionLoader{err: errors.New("boom")}, fakeEnvLoader{}, &stubRunner{}, nil, RunOpts{Observer: obs})
+
+	if _, _, err := uc.Execute(context.Background(), "col.yaml", "env.yaml"); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(obs.events) != 0 {
+		t.Errorf("expected no events, got %v", obs.events)
+	}
+}
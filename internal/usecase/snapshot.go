@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/usecase/bodydiff"
+)
+
+// maxSnapshotMessageChanges is how many changes a failing snapshot lists in
+// its message; the full list is in AssertionResult.Diff.
+const maxSnapshotMessageChanges = 3
+
+// snapshotAssertion compares the response body with its stored snapshot. A
+// missing snapshot is written and passes; with UpdateSnapshots a differing
+// one is rewritten and passes too.
+func (uc *RunCollection) snapshotAssertion(collectionPath string, spec domain.SnapshotAssertion, rr domain.RequestResult) domain.AssertionResult {
+	res := domain.AssertionResult{Name: "snapshot", Key: spec.Name}
+	fail := func(format string, args ...any) domain.AssertionResult {
+		res.Message = fmt.Sprintf(format, args...)
+		return res
+	}
+
+	switch {
+	case uc.snapshots == nil:
+		return fail("snapshots are not available in this run")
+	case rr.Error != nil:
+		return fail("no response body to compare (request failed)")
+	case rr.Response.Truncated:
+		return fail("response body was truncated; raise run.max_body_kb to snapshot it")
+	}
+	patterns, err := bodydiff.ParsePatterns(spec.Ignore)
+	if err != nil {
+		return fail("snapshot ignore: %v", err)
+	}
+
+	actual := normalizeSnapshotBody(rr.Response.Body, patterns)
+	stored, found, err := uc.snapshots.LoadSnapshot(collectionPath, spec.Name)
+	if err != nil {
+		return fail("cannot read snapshot: %v", err)
+	}
+
+	var diff []domain.DiffEntry
+	if found {
+		diff = snapshotDiff(stored, actual, patterns)
+		if len(diff) == 0 {
+			res.Passed = true
+			res.Message = "matches snapshot"
+			return res
+		}
+	}
+	if found && !uc.updateSnaps {
+		res.Diff = diff
+		return fail("body differs from snapshot (%d change(s)): %s; rerun with --update-snapshots to accept",
+			len(diff), summarizeDiff(diff))
+	}
+
+	if err := uc.snapshots.SaveSnapshot(collectionPath, spec.Name, actual); err != nil {
+		return fail("cannot write snapshot: %v", err)
+	}
+	res.Passed = true
+	res.Message = "snapshot written"
+	if found {
+		res.Message = fmt.Sprintf("snapshot updated (%d change(s))", len(diff))
+	}
+	return res
+}
+
+// normalizeSnapshotBody re-encodes JSON bodies with sorted keys and without
+// the ignored paths, so volatile fields never reach the stored copy. Other
+// bodies only get their line endings normalized.
+func normalizeSnapshotBody(body []byte, ignore []bodydiff.Pattern) []byte {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err == nil && !dec.More() {
+		if b, err := json.Marshal(bodydiff.Omit(v, ignore...)); err == nil {
+			return b
+		}
+	}
+	return bytes.ReplaceAll(body, []byte("\r\n"), []byte("\n"))
+}
+
+// snapshotDiff compares a stored snapshot (expected) with a normalized body.
+// Snapshots are stored redacted, so masked values match anything.
+func snapshotDiff(stored, actual []byte, ignore []bodydiff.Pattern) []domain.DiffEntry {
+	opts := []bodydiff.Option{bodydiff.WithMask(domain.RedactedValue), bodydiff.WithIgnore(ignore...)}
+
+	if changes, err := bodydiff.JSON(stored, actual, opts...); err == nil {
+		return bodydiff.Entries(changes)
+	}
+
+	var out []domain.DiffEntry
+	for _, l := range bodydiff.Lines(string(stored), string(actual), opts...) {
+		if l.Op == '-' {
+			out = append(out, domain.DiffEntry{Kind: string(bodydiff.Removed), Old: l.Text})
+		} else {
+			out = append(out, domain.DiffEntry{Kind: string(bodydiff.Added), New: l.Text})
+		}
+	}
+	return out
+}
+
+func summarizeDiff(diff []domain.DiffEntry) string {
+	parts := make([]string, 0, maxSnapshotMessageChanges+1)
+	for i, d := range diff {
+		if i == maxSnapshotMessageChanges {
+			parts = append(parts, fmt.Sprintf("… %d more", len(diff)-i))
+			break
+		}
+		parts = append(parts, d.String())
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aalvaropc/lynix/internal/domain"
+)
+
+type memSnapshots struct {
+	mu    sync.Mutex
+	files map[string]string
+}
+
+func (m *memSnapshots) LoadSnapshot(col, name string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := m.files[col+"/"+name]
+	return []byte(b), ok, nil
+}
+
+func (m *memSnapshots) SaveSnapshot(col, name string, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.files == nil {
+		m.files = map[string]string{}
+	}
+	m.files[col+"/"+name] = string(body)
+	return nil
+}
+
+func runSnapshot(t *testing.T, store *memSnapshots, body string, update bool, spec domain.SnapshotAssertion) domain.AssertionResult {
+	t.Helper()
+	col := domain.Collection{
+		Name: "test",
+		Requests: []domain.RequestSpec{
+			{Name: "users", Method: domain.MethodGet, URL: "http://x", Assert: domain.AssertionsSpec{Snapshot: &spec}},
+		},
+	}
+	runner := &stubRunner{result: domain.RequestResult{StatusCode: 200, Response: domain.ResponseSnapshot{Body: []byte(body)}}}
+	uc := NewRunCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{}, runner, nil,
+		RunOpts{Snapshots: store, UpdateSnapshots: update})
+
+	run, _, err := uc.Execute(context.Background(), "col.yaml", "env.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a := run.Results[0].Assertions
+	if len(a) != 1 || a[0].Name != "snapshot" || a[0].Key != spec.Name {
+		t.Fatalf("expected one snapshot assertion, got %+v", a)
+	}
+	return a[0]
+}
+
+func TestRunCollection_Snapshot_WriteCompareUpdate(t *testing.T) {
+	store := &memSnapshots{}
+	spec := domain.SnapshotAssertion{Name: "users", Ignore: []string{"$.meta.at"}}
+
+	res := runSnapshot(t, store, `{"name":"alice","meta":{"at":1,"v":2}}`, false, spec)
+	if !res.Passed || res.Message != "snapshot written" {
+		t.Fatalf("first run: %+v", res)
+	}
+	if got := store.files["col.yaml/users"]; got != `{"meta":{"v":2},"name":"alice"}` {
+		t.Fatalf("stored snapshot = %s (ignored paths must be left out)", got)
+	}
+
+	if res := runSnapshot(t, store, `{"meta":{"v":2,"at":9},"name":"alice"}`, false, spec); !res.Passed || res.Message != "matches snapshot" {
+		t.Fatalf("unchanged body: %+v", res)
+	}
+
+	res = runSnapshot(t, store, `{"name":"bob","meta":{"at":1,"v":2},"extra":true}`, false, spec)
+	if res.Passed {
+		t.Fatal("a changed body must fail")
+	}
+	want := []domain.DiffEntry{
+		{Path: "$.extra", Kind: "added", New: "true"},
+		{Path: "$.name", Kind: "changed", Old: `"alice"`, New: `"bob"`},
+	}
+	if len(res.Diff) != 2 || res.Diff[0] != want[0] || res.Diff[1] != want[1] {
+		t.Fatalf("diff = %+v, want %+v", res.Diff, want)
+	}
+	if !strings.Contains(res.Message, `~ $.name: "alice" → "bob"`) || !strings.Contains(res.Message, "--update-snapshots") {
+		t.Errorf("message = %q", res.Message)
+	}
+
+	res = runSnapshot(t, store, `{"name":"bob","meta":{"v":2}}`, true, spec)
+	if !res.Passed || !strings.HasPrefix(res.Message, "snapshot updated") {
+		t.Fatalf("update: %+v", res)
+	}
+	if got := store.files["col.yaml/users"]; got != `{"meta":{"v":2},"name":"bob"}` {
+		t.Fatalf("updated snapshot = %s", got)
+	}
+}
+
+func TestRunCollection_Snapshot_MaskedValuesMatch(t *testing.T) {
+	store := &memSnapshots{files: map[string]string{"col.yaml/users": `{"token":"********","id":1}`}}
+	res := runSnapshot(t, store, `{"token":"abc","id":1}`, false, domain.SnapshotAssertion{Name: "users"})
+	if !res.Passed {
+		t.Fatalf("a redacted value must match: %+v", res)
+	}
+}
+
+func TestRunCollection_Snapshot_TextBody(t *testing.T) {
+	store := &memSnapshots{files: map[string]string{"col.yaml/users": "line one\nline two\n"}}
+	res := runSnapshot(t, store, "line one\r\nline 2\r\n", false, domain.SnapshotAssertion{Name: "users"})
+	want := []domain.DiffEntry{{Kind: "removed", Old: "line two"}, {Kind: "added", New: "line 2"}}
+	if res.Passed || len(res.Diff) != 2 || res.Diff[0] != want[0] || res.Diff[1] != want[1] {
+		t.Fatalf("text diff: %+v", res)
+	}
+}
+
+func TestRunCollection_Snapshot_NoStoreFails(t *testing.T) {
+	col := domain.Collection{
+		Name: "test",
+		Requests: []domain.RequestSpec{
+			{Name: "users", Method: domain.MethodGet, URL: "http://x", Assert: domain.AssertionsSpec{Snapshot: &domain.SnapshotAssertion{Name: "users"}}},
+		},
+	}
+	runner := &stubRunner{result: domain.RequestResult{StatusCode: 200}}
+	uc := NewRunCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{}, runner, nil, RunOpts{})
+	run, _, _ := uc.Execute(context.Background(), "col.yaml", "env.yaml")
+	if a := run.Results[0].Assertions; len(a) != 1 || a[0].Passed {
+		t.Fatalf("expected a failing snapshot assertion, got %+v", a)
+	}
+}
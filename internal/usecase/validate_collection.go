@@ -3,13 +3,18 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/PaesslerAG/jsonpath"
 	"github.com/aalvaropc/lynix/internal/domain"
 	"github.com/aalvaropc/lynix/internal/ports"
+	ucassert "github.com/aalvaropc/lynix/internal/usecase/assert"
+	"github.com/aalvaropc/lynix/internal/usecase/bodydiff"
+	"github.com/aalvaropc/lynix/internal/usecase/markup"
 )
 
 type ValidateCollection struct {
@@ -62,54 +67,110 @@ func (uc *ValidateCollection) Execute(ctx context.Context, collectionPath string
 
 	// collection vars < env vars < CLI --var overrides < extracted vars
 	vars := domain.Merge(domain.Merge(col.Vars, env.Vars), uc.extraVars)
+	types := map[string]domain.VarType{}
+	env.ApplyTypes(types)
+	if err := checkTypedVars(env, vars, col.Requests); err != nil {
+		return err
+	}
+
+	// Extract keys become available to subsequent requests. Their values
+	// are unknown until run time, so placeholders of them are deferred:
+	// they must be defined, but no value is checked against their type.
+	extracted := map[string]bool{}
 
 	for _, req := range col.Requests {
 		if err := ctx.Err(); err != nil {
 			return err
 		}
 
-		rt, err := uc.resolver.NewRuntime(vars)
+		resolver := uc.resolver.Deferring(extracted)
+		rt, err := resolver.NewRuntime(vars, types)
 		if err != nil {
 			return err
 		}
 
 		if _, err := rt.ResolveRequest(req); err != nil {
-			return fmt.Errorf("request %q: %w", req.Name, err)
+			return atRequest(collectionPath, req, "", fmt.Errorf("request %q: %w", req.Name, err))
 		}
 
 		// Validate schema file exists if referenced.
 		if req.Assert.Schema != nil {
 			if _, err := os.Stat(*req.Assert.Schema); err != nil {
-				return fmt.Errorf("request %q: schema file %q: %w", req.Name, *req.Assert.Schema, err)
+				return atRequest(collectionPath, req, "assert.schema",
+					fmt.Errorf("request %q: schema file %q: %w", req.Name, *req.Assert.Schema, err))
 			}
 		}
 
+		// json_eq fixtures must exist and hold JSON.
+		if _, err := loadJSONEqFixtures(req.Assert.JSONEq); err != nil {
+			return atRequest(collectionPath, req, "assert.json_eq",
+				fmt.Errorf("request %q: %w", req.Name, err))
+		}
+
 		// Compile JSONPath expressions and static regex patterns so typos
 		// fail here, not at runtime. Patterns with {{var}} placeholders are
 		// only resolvable at runtime and are skipped.
-		if err := validateAssertionExpressions(req); err != nil {
-			return fmt.Errorf("request %q: %w", req.Name, err)
+		if field, err := validateAssertionExpressions(req); err != nil {
+			return atRequest(collectionPath, req, field, fmt.Errorf("request %q: %w", req.Name, err))
 		}
 
-		// Assume extract keys become available for subsequent requests.
-		for k := range req.Extract {
-			if _, ok := vars[k]; !ok {
-				vars[k] = "x"
-			}
-		}
-		for k := range req.ExtractHeaders {
-			if _, ok := vars[k]; !ok {
-				vars[k] = "x"
-			}
+		// Expression variables must be known, like those of the request.
+		if _, err := resolver.ResolveAssertionValues(vars, types, domain.AssertionsSpec{Expr: req.Assert.Expr}); err != nil {
+			return atRequest(collectionPath, req, "assert.expr", fmt.Errorf("request %q: assert.expr: %w", req.Name, err))
 		}
+
+		maps.Copy(extracted, req.ProducedVars())
 	}
 
 	return nil
 }
 
-// validateAssertionExpressions compiles JSONPath expressions (assert + extract)
-// and regex patterns without {{var}} placeholders.
-func validateAssertionExpressions(req domain.RequestSpec) error {
+// checkTypedVars reports the environment's types: entries that name no
+// variable of the collection, the environment or a request's extract block.
+func checkTypedVars(env domain.Environment, vars domain.Vars, requests []domain.RequestSpec) error {
+	defined := map[string]bool{}
+	for k := range vars {
+		defined[k] = true
+	}
+	for _, req := range requests {
+		maps.Copy(defined, req.ProducedVars())
+	}
+
+	var undefined []string
+	for name := range env.Types {
+		if !defined[name] {
+			undefined = append(undefined, name)
+		}
+	}
+	if len(undefined) == 0 {
+		return nil
+	}
+	slices.Sort(undefined)
+	return &domain.OpError{
+		Op:   "validate.types",
+		Kind: domain.KindInvalidConfig,
+		Err:  fmt.Errorf("environment %q: types name undefined variables: %s", env.Name, strings.Join(undefined, ", ")),
+	}
+}
+
+// atRequest locates err at a field of req (or the request itself when field
+// is empty) for tools that point at the collection file.
+func atRequest(path string, req domain.RequestSpec, field string, err error) error {
+	pos := req.Pos
+	if field != "" {
+		pos = req.PosOf(field)
+	}
+	if pos.IsZero() {
+		return err
+	}
+	return &domain.SourceError{Path: path, Pos: pos, Err: err}
+}
+
+// validateAssertionExpressions compiles JSONPath, XPath and CSS expressions
+// (assert + extract), assert.expr expressions and regex patterns without
+// {{var}} placeholders. It returns the path of
+// the offending field along with the error.
+func validateAssertionExpressions(req domain.RequestSpec) (string, error) {
 	checkPath := func(where, expr string) error {
 		// {{var}} in a JSONPath expression is never resolved at runtime
 		// (only expected VALUES are): rejecting it here beats an opaque
@@ -122,6 +183,24 @@ func validateAssertionExpressions(req domain.RequestSpec) error {
 		}
 		return nil
 	}
+	checkXPath := func(where, expr string) error {
+		if strings.Contains(expr, "{{") {
+			return fmt.Errorf("%s: variables are not supported in XPath expressions (%q)", where, expr)
+		}
+		if err := markup.ValidateXPath(expr); err != nil {
+			return fmt.Errorf("%s: invalid xpath %q: %w", where, expr, err)
+		}
+		return nil
+	}
+	checkCSS := func(where, selector string) error {
+		if strings.Contains(selector, "{{") {
+			return fmt.Errorf("%s: variables are not supported in CSS selectors (%q)", where, selector)
+		}
+		if err := markup.ValidateCSS(selector); err != nil {
+			return fmt.Errorf("%s: invalid css selector %q: %w", where, selector, err)
+		}
+		return nil
+	}
 	checkRegex := func(where string, p *string) error {
 		if p == nil || strings.Contains(*p, "{{") {
 			return nil
@@ -132,37 +211,109 @@ func validateAssertionExpressions(req domain.RequestSpec) error {
 		return nil
 	}
 
+	// checkValue also walks the nested assertions of all/any/none/count.
+	var checkValue func(field string, a domain.ValueAssertion) (string, error)
+	checkValue = func(field string, a domain.ValueAssertion) (string, error) {
+		if err := checkRegex(field+".matches", a.Matches); err != nil {
+			return field + ".matches", err
+		}
+		if err := checkRegex(field+".not_matches", a.NotMatches); err != nil {
+			return field + ".not_matches", err
+		}
+		for op, nested := range map[string]*domain.ValueAssertion{"all": a.All, "any": a.Any, "none": a.None} {
+			if nested == nil {
+				continue
+			}
+			if f, err := checkValue(field+"."+op, *nested); err != nil {
+				return f, err
+			}
+		}
+		if c := a.Count; c != nil {
+			if c.Where != nil {
+				if f, err := checkValue(field+".count.where", *c.Where); err != nil {
+					return f, err
+				}
+			}
+			return checkValue(field+".count", c.ValueAssertion)
+		}
+		return "", nil
+	}
+
 	for expr, a := range req.Assert.JSONPath {
+		field := "assert.jsonpath[" + expr + "]"
 		if err := checkPath("assert.jsonpath", expr); err != nil {
-			return err
+			return field, err
 		}
-		if err := checkRegex("assert.jsonpath["+expr+"].matches", a.Matches); err != nil {
-			return err
-		}
-		if err := checkRegex("assert.jsonpath["+expr+"].not_matches", a.NotMatches); err != nil {
-			return err
+		if f, err := checkValue(field, a); err != nil {
+			return f, err
 		}
 	}
 	for name, a := range req.Assert.Headers {
-		if err := checkRegex("assert.headers["+name+"].matches", a.Matches); err != nil {
-			return err
+		if f, err := checkValue("assert.headers["+name+"]", a); err != nil {
+			return f, err
 		}
-		if err := checkRegex("assert.headers["+name+"].not_matches", a.NotMatches); err != nil {
-			return err
+	}
+	for expr, a := range req.Assert.XPath {
+		field := "assert.xpath[" + expr + "]"
+		if err := checkXPath("assert.xpath", expr); err != nil {
+			return field, err
+		}
+		if f, err := checkValue(field, a); err != nil {
+			return f, err
+		}
+	}
+	for selector, a := range req.Assert.CSS {
+		field := "assert.css[" + selector + "]"
+		if err := checkCSS("assert.css", selector); err != nil {
+			return field, err
+		}
+		if f, err := checkValue(field, a); err != nil {
+			return f, err
 		}
 	}
 	if b := req.Assert.Body; b != nil {
 		if err := checkRegex("assert.body.matches", b.Matches); err != nil {
-			return err
+			return "assert.body.matches", err
 		}
 		if err := checkRegex("assert.body.not_matches", b.NotMatches); err != nil {
-			return err
+			return "assert.body.not_matches", err
+		}
+	}
+	if snap := req.Assert.Snapshot; snap != nil {
+		if _, err := bodydiff.ParsePatterns(snap.Ignore); err != nil {
+			return "assert.snapshot", fmt.Errorf("assert.snapshot.ignore: %w", err)
+		}
+	}
+	for _, a := range req.Assert.JSONEq {
+		if a.Path != "" {
+			if err := checkPath("assert.json_eq.path", a.Path); err != nil {
+				return "assert.json_eq", err
+			}
+		}
+		if _, err := bodydiff.ParsePatterns(a.Ignore); err != nil {
+			return "assert.json_eq", fmt.Errorf("assert.json_eq.ignore: %w", err)
+		}
+	}
+	for i, e := range req.Assert.Expr {
+		field := fmt.Sprintf("assert.expr[%d]", i)
+		if err := ucassert.ValidateExpr(e.Source); err != nil {
+			return field, fmt.Errorf("%s: %w", field, err)
 		}
 	}
 	for name, expr := range req.Extract {
 		if err := checkPath("extract."+name, expr); err != nil {
-			return err
+			return "extract[" + name + "]", err
 		}
 	}
-	return nil
+	for name, expr := range req.ExtractXPath {
+		if err := checkXPath("extract_xpath."+name, expr); err != nil {
+			return "extract_xpath[" + name + "]", err
+		}
+	}
+	for name, selector := range req.ExtractCSS {
+		if err := checkCSS("extract_css."+name, selector); err != nil {
+			return "extract_css[" + name + "]", err
+		}
+	}
+	return "", nil
 }
@@ -3,10 +3,14 @@ package usecase
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/aalvaropc/lynix/internal/domain"
+	"github.com/aalvaropc/lynix/internal/infra/yamlenv"
 )
 
 func TestValidateCollection_PassesWithExtractedVarChain(t *testing.T) {
@@ -161,6 +165,118 @@ func TestValidateCollection_ExtractHeadersSeedsVars(t *testing.T) {
 	}
 }
 
+func TestValidateCollection_RegexCookieAndResponseSeedVars(t *testing.T) {
+	col := domain.Collection{
+		Name: "seed",
+		Requests: []domain.RequestSpec{
+			{
+				Name: "login", Method: domain.MethodPost, URL: "http://x/login",
+				ExtractRegex:    []domain.ExtractRegexRule{{Pattern: `user=(?P<user_id>\d+)`}},
+				ExtractCookies:  domain.ExtractSpec{"session": "sid"},
+				ExtractResponse: domain.ExtractSpec{"home": "url"},
+			},
+			{
+				Name: "me", Method: domain.MethodGet, URL: "{{home}}/users/{{user_id}}",
+				Headers: domain.Headers{"Cookie": "sid={{session}}"},
+			},
+		},
+	}
+
+	uc := NewValidateCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{})
+	if err := uc.Execute(context.Background(), "col.yaml", ""); err != nil {
+		t.Fatalf("expected extracted vars to satisfy later requests, got: %v", err)
+	}
+}
+
+func TestValidateCollection_TypedPlaceholders(t *testing.T) {
+	col := domain.Collection{
+		Name: "typed",
+		Requests: []domain.RequestSpec{
+			{
+				Name: "list", Method: domain.MethodGet, URL: "http://x/items",
+				Extract: domain.ExtractSpec{"next_page": "$.next", "filters": "$.filters"},
+			},
+			{
+				Name: "search", Method: domain.MethodPost, URL: "http://x/search",
+				Body: domain.BodySpec{Type: domain.BodyJSON, JSON: map[string]any{
+					"limit":   "{{limit}}",
+					"page":    "{{next_page:int}}",
+					"filters": "{{filters:json}}",
+					"debug":   "{{debug:bool}}",
+				}},
+			},
+		},
+	}
+	env := func(limit string) fakeEnvLoader {
+		return fakeEnvLoader{env: domain.Environment{
+			Vars:  domain.Vars{"limit": limit, "debug": "false"},
+			Types: map[string]domain.VarType{"limit": domain.VarInt},
+		}}
+	}
+
+	// Extracted values are unknown until run time and never fail a type.
+	uc := NewValidateCollection(fakeCollectionLoader{col: col}, env("10"))
+	if err := uc.Execute(context.Background(), "col.yaml", "dev"); err != nil {
+		t.Fatalf("expected typed placeholders to validate, got: %v", err)
+	}
+
+	uc = NewValidateCollection(fakeCollectionLoader{col: col}, env("ten"))
+	err := uc.Execute(context.Background(), "col.yaml", "dev")
+	if err == nil || !strings.Contains(err.Error(), `variable limit is typed int but its value "ten" is not a number`) {
+		t.Fatalf("expected a conversion error, got: %v", err)
+	}
+}
+
+func TestValidateCollection_TypesMustNameDefinedVars(t *testing.T) {
+	col := domain.Collection{
+		Name: "typed",
+		Vars: domain.Vars{"base_url": "http://x"},
+		Requests: []domain.RequestSpec{
+			{
+				Name: "me", Method: domain.MethodGet, URL: "{{base_url}}/me",
+				Extract: domain.ExtractSpec{"user_id": "$.id"},
+			},
+		},
+	}
+	env := domain.Environment{
+		Name: "dev",
+		Vars: domain.Vars{"limit": "10"},
+		// limit and user_id are defined; lmit and page are typos or leftovers.
+		Types: map[string]domain.VarType{"limit": domain.VarInt, "user_id": domain.VarInt, "lmit": domain.VarInt, "page": domain.VarInt},
+	}
+
+	uc := NewValidateCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{env: env})
+	err := uc.Execute(context.Background(), "col.yaml", "dev")
+	if !domain.IsKind(err, domain.KindInvalidConfig) || !strings.Contains(err.Error(), `environment "dev": types name undefined variables: lmit, page`) {
+		t.Fatalf("expected undefined types entries to be reported, got: %v", err)
+	}
+
+	delete(env.Types, "lmit")
+	delete(env.Types, "page")
+	uc = NewValidateCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{env: env})
+	if err := uc.Execute(context.Background(), "col.yaml", "dev"); err != nil {
+		t.Fatalf("expected types of defined and extracted vars to pass, got: %v", err)
+	}
+}
+
+func TestValidateCollection_UnknownTypeRejected(t *testing.T) {
+	tmp := t.TempDir()
+	envDir := filepath.Join(tmp, "env")
+	if err := os.MkdirAll(envDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(envDir, "dev.yaml"), []byte("vars:\n  limit: \"10\"\ntypes:\n  limit: integer\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	col := domain.Collection{Name: "typed", Requests: []domain.RequestSpec{{Name: "r", Method: domain.MethodGet, URL: "http://x/?limit={{limit}}"}}}
+
+	uc := NewValidateCollection(fakeCollectionLoader{col: col}, yamlenv.NewLoader(tmp))
+	err := uc.Execute(context.Background(), "col.yaml", "dev")
+	if !domain.IsKind(err, domain.KindInvalidConfig) || !strings.Contains(err.Error(), `types.limit: unknown type "integer"`) {
+		t.Fatalf("expected an unknown type error, got: %v", err)
+	}
+}
+
 func TestValidateCollection_InvalidRegexRejected(t *testing.T) {
 	col := domain.Collection{
 		Name: "re",
@@ -197,4 +313,128 @@ func TestValidateCollection_InvalidJSONPathRejected(t *testing.T) {
 	}
 }
 
+func TestValidateCollection_MarkupQueries(t *testing.T) {
+	validate := func(req domain.RequestSpec) error {
+		t.Helper()
+		req.Name, req.Method, req.URL = "r", domain.MethodGet, "http://x"
+		col := domain.Collection{Name: "markup", Requests: []domain.RequestSpec{req}}
+		uc := NewValidateCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{})
+		return uc.Execute(context.Background(), "col.yaml", "")
+	}
+
+	valid := domain.RequestSpec{
+		Assert: domain.AssertionsSpec{
+			XPath: map[string]domain.ValueAssertion{"count(//item)": {Exists: boolPtr(true)}},
+			CSS:   map[string]domain.ValueAssertion{"a.next @href": {Exists: boolPtr(true)}},
+		},
+		ExtractXPath: domain.ExtractSpec{"id": "/order/@id"},
+		ExtractCSS:   domain.ExtractSpec{"title": "h1"},
+	}
+	if err := validate(valid); err != nil {
+		t.Fatalf("valid queries: %v", err)
+	}
+
+	cases := []struct {
+		req  domain.RequestSpec
+		want string
+	}{
+		{domain.RequestSpec{Assert: domain.AssertionsSpec{XPath: map[string]domain.ValueAssertion{"//item[": {}}}}, "assert.xpath: invalid xpath"},
+		{domain.RequestSpec{Assert: domain.AssertionsSpec{CSS: map[string]domain.ValueAssertion{"div >": {}}}}, "assert.css: invalid css selector"},
+		{domain.RequestSpec{ExtractXPath: domain.ExtractSpec{"id": "//{{name}}"}}, "variables are not supported in XPath"},
+		{domain.RequestSpec{ExtractCSS: domain.ExtractSpec{"next": "@href"}}, "extract_css.next: invalid css selector"},
+	}
+	for _, tc := range cases {
+		if err := validate(tc.req); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("expected %q, got %v", tc.want, err)
+		}
+	}
+}
+
+func TestValidateCollection_InvalidSnapshotIgnoreRejected(t *testing.T) {
+	col := domain.Collection{
+		Name: "snap",
+		Requests: []domain.RequestSpec{
+			{
+				Name: "r", Method: domain.MethodGet, URL: "http://x",
+				Assert: domain.AssertionsSpec{
+					Snapshot: &domain.SnapshotAssertion{Name: "r", Ignore: []string{"meta.timestamp"}},
+				},
+			},
+		},
+	}
+	uc := NewValidateCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{})
+	err := uc.Execute(context.Background(), "col.yaml", "")
+	if err == nil || !strings.Contains(err.Error(), "assert.snapshot.ignore") {
+		t.Fatalf("expected a snapshot ignore error, got %v", err)
+	}
+}
+
+func TestValidateCollection_Expr(t *testing.T) {
+	validate := func(exprs ...string) error {
+		t.Helper()
+		col := domain.Collection{
+			Name: "expr",
+			Requests: []domain.RequestSpec{
+				{Name: "create", Method: domain.MethodPost, URL: "http://x", Extract: domain.ExtractSpec{"total": "$.total"}},
+				{
+					Name: "get", Method: domain.MethodGet, URL: "http://x",
+					FieldPos: map[string]domain.SourcePos{"assert.expr[1]": {Line: 12, Column: 9}},
+				},
+			},
+		}
+		for _, src := range exprs {
+			col.Requests[1].Assert.Expr = append(col.Requests[1].Assert.Expr, domain.ExprAssertion{Source: src})
+		}
+		uc := NewValidateCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{})
+		return uc.Execute(context.Background(), "col.yaml", "")
+	}
+
+	if err := validate("$.total == length($.items)", "{{total}} == $.total"); err != nil {
+		t.Fatalf("valid expressions: %v", err)
+	}
+
+	err := validate("$.total > 0", "$.total => 1")
+	var se *domain.SourceError
+	if !errors.As(err, &se) || se.Pos.Line != 12 || !strings.Contains(err.Error(), "assert.expr[1]: unexpected \"=\"") {
+		t.Fatalf("expected a located syntax error, got %v", err)
+	}
+
+	if err := validate("$.total == {{nope}}"); !domain.IsKind(err, domain.KindMissingVar) {
+		t.Fatalf("expected KindMissingVar, got %v", err)
+	}
+}
+
 func strPtr(s string) *string { return &s }
+
+func TestValidateCollection_ErrorLocatesField(t *testing.T) {
+	col := domain.Collection{
+		Name: "re",
+		Requests: []domain.RequestSpec{
+			{
+				Name: "r", Method: domain.MethodGet, URL: "http://x",
+				Pos: domain.SourcePos{Line: 3, Column: 5},
+				FieldPos: map[string]domain.SourcePos{
+					"assert.jsonpath[$.v]":         {Line: 8, Column: 9},
+					"assert.jsonpath[$.v].matches": {Line: 9, Column: 11},
+				},
+				Assert: domain.AssertionsSpec{
+					JSONPath: map[string]domain.ValueAssertion{
+						"$.v": {Matches: strPtr("([unclosed")},
+					},
+				},
+			},
+		},
+	}
+	uc := NewValidateCollection(fakeCollectionLoader{col: col}, fakeEnvLoader{})
+	err := uc.Execute(context.Background(), "col.yaml", "")
+
+	var se *domain.SourceError
+	if !errors.As(err, &se) {
+		t.Fatalf("expected SourceError, got %v", err)
+	}
+	if se.Path != "col.yaml" || se.Pos != (domain.SourcePos{Line: 9, Column: 11}) {
+		t.Fatalf("unexpected location: %s %+v", se.Path, se.Pos)
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
@@ -48,3 +49,51 @@ func TestSchemaFiles_AreValidJSONSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkspaceSchema_ValidatesConfigs(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	sch, err := c.Compile(filepath.Join(schemasDir(), "workspace.schema.json"))
+	if err != nil {
+		t.Fatalf("compile schema: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		doc   string
+		valid bool
+	}{
+		{
+			name:  "run.otel",
+			doc:   `{"lynix": {"run": {"otel": {"endpoint": "http://localhost:4318", "service_name": "api-tests", "headers": {"x-tenant": "qa"}}}}}`,
+			valid: true,
+		},
+		{
+			name:  "artifacts.retention",
+			doc:   `{"lynix": {"artifacts": {"max_runs": 500, "retention": {"max_runs_per_collection": 50, "max_age": "90d", "failed_max_age": "2w", "passed_max_age": "36h"}}}}`,
+			valid: true,
+		},
+		{
+			name: "artifacts.retention invalid age",
+			doc:  `{"lynix": {"artifacts": {"retention": {"max_age": "3 days"}}}}`,
+		},
+		{
+			name: "run.otel unknown key",
+			doc:  `{"lynix": {"run": {"otel": {"endpoint": "http://localhost:4318", "protocol": "grpc"}}}}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := jsonschema.UnmarshalJSON(strings.NewReader(tc.doc))
+			if err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			err = sch.Validate(doc)
+			if tc.valid && err != nil {
+				t.Fatalf("expected the config to validate, got %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatal("expected a validation error")
+			}
+		})
+	}
+}